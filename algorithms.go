@@ -1,233 +1,192 @@
 package main
 
 import (
+	"context"
 	"errors"
-	"github.com/bytedance/sonic"
+	"fmt"
 	"github.com/wailsapp/wails/v2/pkg/runtime"
 	"golang-moaha-construction/internal/algorithms"
-	"golang-moaha-construction/internal/algorithms/aha"
-	"golang-moaha-construction/internal/algorithms/ga"
-	"golang-moaha-construction/internal/algorithms/gwo"
-	"golang-moaha-construction/internal/algorithms/moaha"
-	"golang-moaha-construction/internal/algorithms/mopso"
-	"golang-moaha-construction/internal/algorithms/nsgaii"
-	"golang-moaha-construction/internal/algorithms/omoaha"
 )
 
+// CreateAlgorithm creates the algorithm registered under
+// algorithmInput.AlgorithmName, see algorithms.Register.
 func (a *App) CreateAlgorithm(algorithmInput AlgorithmInput) error {
 
 	a.algorithmName = algorithmInput.AlgorithmName
+	a.algorithmInput = algorithmInput
 
-	switch algorithmInput.AlgorithmName {
-	case aha.NameType:
-		configBytes, err := sonic.Marshal(algorithmInput.AlgorithmConfig)
-		if err != nil {
-			return err
-		}
-
-		var config ahaConfig
-		err = sonic.Unmarshal(configBytes, &config)
-		if err != nil {
-			return err
-		}
-
-		algo, err := aha.Create(a.problem, aha.Config{
-			NumberOfAgents: config.NumberOfAgents,
-			NumberOfIter:   config.NumberOfIterations,
-		})
-
-		if err != nil {
-			return err
-		}
-
-		a.algorithm = algo
-	case moaha.NameType:
-		configBytes, err := sonic.Marshal(algorithmInput.AlgorithmConfig)
-		if err != nil {
-			return err
-		}
+	algo, info, err := algorithms.New(algorithmInput.AlgorithmName, a.problem, algorithmInput.AlgorithmConfig)
+	if err != nil {
+		return err
+	}
 
-		var config moahaConfig
-		err = sonic.Unmarshal(configBytes, &config)
-		if err != nil {
-			return err
-		}
+	a.algorithm = algo
+	a.algorithmInfo = info
 
-		algo, err := moaha.Create(a.problem, moaha.Configs{
-			NumAgents:     config.NumberOfAgents,
-			NumIterations: config.NumberOfIterations,
-			ArchiveSize:   config.ArchiveSize,
-		})
-
-		if err != nil {
-			return err
-		}
+	return nil
+}
 
-		a.algorithm = algo
-	case omoaha.NameType:
-		configBytes, err := sonic.Marshal(algorithmInput.AlgorithmConfig)
-		if err != nil {
-			return err
-		}
+// AvailableAlgorithms lists the registered algorithms with their config schema.
+func (a *App) AvailableAlgorithms() []algorithms.Registration {
+	return algorithms.Registered()
+}
 
-		var config omoahaConfig
-		err = sonic.Unmarshal(configBytes, &config)
-		if err != nil {
-			return err
-		}
+func (a *App) AlgorithmInfo() (any, error) {
+	return a.algorithmInfo, nil
+}
+func (a *App) RunAlgorithm() error {
+	ctx, finish, err := a.startRun()
+	if err != nil {
+		return err
+	}
+	defer finish()
+
+	results, err := a.runAlgorithm(ctx, func(progressData any) {
+		a.emitEvent(ProgressEvent, progressData)
+	})
+	if errors.Is(err, context.Canceled) {
+		a.emitEvent(StoppedEvent, results)
+		return nil
+	}
+	if err != nil {
+		a.emitEvent(ErrorEvent, err.Error())
+		return err
+	}
 
-		algo, err := omoaha.Create(a.problem, omoaha.Configs{
-			NumAgents:     config.NumberOfAgents,
-			NumIterations: config.NumberOfIterations,
-			ArchiveSize:   config.ArchiveSize,
-		})
+	a.emitEvent(ResultEvent, results)
 
-		if err != nil {
-			return err
-		}
+	return nil
+}
 
-		a.algorithm = algo
+// emitEvent sends event to the frontend.
+func (a *App) emitEvent(event EventType, data any) {
+	if a.emit != nil {
+		a.emit(event, data)
+		return
+	}
 
-	case ga.NameType:
-		configBytes, err := sonic.Marshal(algorithmInput.AlgorithmConfig)
-		if err != nil {
-			return err
-		}
+	runtime.EventsEmit(a.ctx, string(event), data)
+}
 
-		var config gaConfig
-		err = sonic.Unmarshal(configBytes, &config)
-		if err != nil {
-			return err
-		}
+// StopAlgorithm cancels the running optimization, waits for the current
+// iteration to finish and returns the results found so far.
+func (a *App) StopAlgorithm() (any, error) {
+	a.runMu.Lock()
+	cancel, done := a.cancelRun, a.runDone
+	a.runMu.Unlock()
 
-		algo, err := ga.Create(a.problem, ga.Config{
-			Chromosome:    config.Chromosome,
-			Generation:    config.Generation,
-			CrossoverRate: config.CrossoverRate,
-			MutationRate:  config.MutationRate,
-			ElitismCount:  config.ElitismCount,
-		})
+	if cancel == nil {
+		return nil, errors.New("no optimization is running")
+	}
 
-		if err != nil {
-			return err
-		}
+	cancel()
+	<-done
 
-		a.algorithm = algo
-	case gwo.NameType:
-		configBytes, err := sonic.Marshal(algorithmInput.AlgorithmConfig)
-		if err != nil {
-			return err
-		}
+	return a.algorithm.GetResults(), nil
+}
 
-		var config gwoConfig
-		err = sonic.Unmarshal(configBytes, &config)
-		if err != nil {
-			return err
-		}
+// startRun registers a new cancellable run. finish must be called once the
+// run has returned.
+func (a *App) startRun() (ctx context.Context, finish func(), err error) {
+	a.runMu.Lock()
+	defer a.runMu.Unlock()
 
-		algo, err := gwo.Create(a.problem, gwo.Config{
-			NumberOfAgents: config.NumberOfAgents,
-			NumberOfIter:   config.NumberOfIterations,
-			AParam:         config.AParam,
-		})
+	if a.cancelRun != nil {
+		return nil, nil, errors.New("an optimization is already running")
+	}
 
-		if err != nil {
-			return err
-		}
+	parent := a.ctx
+	if parent == nil {
+		parent = context.Background()
+	}
 
-		a.algorithm = algo
-	case mopso.NameType:
-		configBytes, err := sonic.Marshal(algorithmInput.AlgorithmConfig)
-		if err != nil {
-			return err
-		}
+	ctx, cancel := context.WithCancel(parent)
+	done := make(chan struct{})
+	a.cancelRun = cancel
+	a.runDone = done
 
-		var config mopsoConfig
-		err = sonic.Unmarshal(configBytes, &config)
-		if err != nil {
-			return err
-		}
+	return ctx, func() {
+		a.runMu.Lock()
+		defer a.runMu.Unlock()
 
-		algo, err := mopso.Create(a.problem, mopso.Config{
-			NumberOfAgents: config.NumberOfAgents,
-			NumberOfIter:   config.NumberOfIterations,
-			ArchiveSize:    config.ArchiveSize,
-			NumberOfGrids:  config.NumberOfGrids,
-			MutationRate:   config.MutationRate,
-			MaxVelocity:    config.MaxVelocity,
-			C1:             config.C1,
-			C2:             config.C2,
-			W:              config.W,
-		})
+		cancel()
+		close(done)
+		a.cancelRun = nil
+		a.runDone = nil
+	}, nil
+}
 
-		if err != nil {
-			return err
-		}
+// runAlgorithm runs the current algorithm and hands every progress message to
+// onProgress. It is shared by the GUI binding and the headless runner.
+// When ctx is cancelled the partial results are returned together with the
+// context error. A panic inside the algorithm is returned as an error.
+func (a *App) runAlgorithm(ctx context.Context, onProgress func(progressData any)) (algorithms.Result, error) {
+	if a.algorithm == nil {
+		return algorithms.Result{}, errors.New("algorithm has not been created")
+	}
 
-		a.algorithm = algo
-	case nsgaii.NameType:
-		configBytes, err := sonic.Marshal(algorithmInput.AlgorithmConfig)
-		if err != nil {
-			return err
-		}
+	a.problem.ResetErr()
+	a.problem.ResetPenalty()
 
-		var config nsgaiiConfig
-		err = sonic.Unmarshal(configBytes, &config)
-		if err != nil {
-			return err
-		}
+	// a failing checkpoint stops the run so that hours of work are not lost
+	// silently
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
 
-		algo, err := nsgaii.Create(a.problem, nsgaii.Config{
-			PopulationSize:   config.Chromosome,
-			MaxIterations:    config.Generation,
-			CrossoverRate:    config.CrossoverRate,
-			MutationRate:     config.MutationRate,
-			Sigma:            config.Sigma,
-			MutationStrength: config.MutationStrength,
+	var checkpointErr error
+	if checkpoint := a.algorithmInput.Checkpoint; checkpoint != nil && checkpoint.FilePath != "" && checkpoint.Every > 0 {
+		save, err := a.checkpointSaver(*checkpoint, func(err error) {
+			checkpointErr = err
+			cancel()
 		})
-
 		if err != nil {
-			return err
+			return algorithms.Result{}, err
 		}
-
-		a.algorithm = algo
-	default:
-		return errors.New("invalid algorithm name")
+		a.algorithm.SetCheckpoint(checkpoint.Every, save)
+	} else {
+		a.algorithm.SetCheckpoint(0, nil)
 	}
 
-	return nil
-}
-
-func (a *App) AlgorithmInfo() (any, error) {
-	return a.algorithm, nil
-}
-func (a *App) RunAlgorithm() error {
-
 	progressChan := make(chan any)
-	errorChan := make(chan error)
-	doneChan := make(chan struct{})
-	resultChan := make(chan any, 1)
-
-	go func(doneChan chan<- struct{}, channel chan<- any, errChan chan error) {
-		err := a.algorithm.RunWithChannel(doneChan, channel)
-
-		if err != nil {
-			errChan <- err
+	errorChan := make(chan error, 1)
+
+	go func(channel chan<- any, errChan chan<- error) {
+		defer func() {
+			if r := recover(); r != nil {
+				errChan <- fmt.Errorf("%s panicked: %v", a.algorithmName, r)
+			}
+		}()
+
+		errChan <- a.algorithm.RunWithChannel(ctx, channel)
+	}(progressChan, errorChan)
+
+	// progressChan is not closed when the algorithm panics, so wait for the
+	// error instead of ranging over it
+	var err error
+	for running := true; running; {
+		select {
+		case progressData, ok := <-progressChan:
+			if !ok {
+				progressChan = nil
+				continue
+			}
+			onProgress(progressData)
+		case err = <-errorChan:
+			running = false
 		}
-
-		// send results to resultChan
-		resultChan <- a.algorithm.GetResults()
-
-	}(doneChan, progressChan, errorChan)
-
-	// TODO: improve this if it has error
-	for progressData := range progressChan {
-		runtime.EventsEmit(a.ctx, string(ProgressEvent), progressData)
 	}
 
-	runtime.EventsEmit(a.ctx, string(ResultEvent), <-resultChan)
+	if checkpointErr != nil {
+		return algorithms.Result{}, fmt.Errorf("checkpoint: %w", checkpointErr)
+	}
+	if errors.Is(err, context.Canceled) {
+		return a.algorithm.GetResults(), err
+	}
+	if err != nil {
+		return algorithms.Result{}, err
+	}
 
-	return nil
+	return a.algorithm.GetResults(), nil
 }
 
 func (a *App) Result() (any, error) {
@@ -239,56 +198,5 @@ func (a *App) Result() (any, error) {
 type AlgorithmInput struct {
 	AlgorithmName   algorithms.AlgorithmType `json:"algorithmName"`
 	AlgorithmConfig any                      `json:"algorithmConfig"`
-}
-
-type gwoConfig struct {
-	NumberOfIterations int     `json:"iterations"`
-	NumberOfAgents     int     `json:"population"`
-	AParam             float64 `json:"aParam"`
-}
-
-type ahaConfig struct {
-	NumberOfIterations int `json:"iterations"`
-	NumberOfAgents     int `json:"population"`
-}
-
-type gaConfig struct {
-	Chromosome    int     `json:"chromosome"`
-	Generation    int     `json:"generation"`
-	CrossoverRate float64 `json:"crossoverRate"`
-	MutationRate  float64 `json:"mutationRate"`
-	ElitismCount  int     `json:"elitismCount"`
-}
-
-type moahaConfig struct {
-	NumberOfIterations int `json:"iterations"`
-	NumberOfAgents     int `json:"population"`
-	ArchiveSize        int `json:"archiveSize"`
-}
-
-type omoahaConfig struct {
-	NumberOfIterations int `json:"iterations"`
-	NumberOfAgents     int `json:"population"`
-	ArchiveSize        int `json:"archiveSize"`
-}
-
-type mopsoConfig struct {
-	NumberOfIterations int     `json:"iterations"`
-	NumberOfAgents     int     `json:"population"`
-	MutationRate       float64 `json:"mutationRate"`
-	ArchiveSize        int     `json:"archiveSize"`
-	NumberOfGrids      int     `json:"numberOfGrids"`
-	MaxVelocity        float64 `json:"maxVelocity"`
-	C1                 float64 `json:"c1"`
-	C2                 float64 `json:"c2"`
-	W                  float64 `json:"w"`
-}
-
-type nsgaiiConfig struct {
-	Chromosome       int     `json:"chromosome"`
-	Generation       int     `json:"generation"`
-	CrossoverRate    float64 `json:"crossoverRate"`
-	MutationRate     float64 `json:"mutationRate"`
-	MutationStrength float64 `json:"mutationStrength"`
-	Sigma            float64 `json:"sigma"`
+	Checkpoint      *CheckpointInput         `json:"checkpoint,omitempty"`
 }
@@ -0,0 +1,177 @@
+package main
+
+import (
+	"context"
+	"golang-moaha-construction/internal/algorithms"
+	"golang-moaha-construction/internal/constraints"
+	"golang-moaha-construction/internal/objectives/objectives"
+	"math"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+func TestStopAlgorithm(t *testing.T) {
+	const iterations = 100000
+
+	app := newTestApp(t, testMultiObjectiveProject("MOAHA", map[string]any{
+		"iterations":  iterations,
+		"population":  10,
+		"archiveSize": 20,
+		"seed":        1,
+	}))
+
+	var mu sync.Mutex
+	var events []EventType
+	var stoppedResults any
+	progressed := make(chan struct{})
+	var once sync.Once
+	app.emit = func(event EventType, data any) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		events = append(events, event)
+		switch event {
+		case ProgressEvent:
+			once.Do(func() { close(progressed) })
+		case StoppedEvent:
+			stoppedResults = data
+		}
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- app.RunAlgorithm()
+	}()
+
+	<-progressed
+	results, err := app.StopAlgorithm()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := <-done; err != nil {
+		t.Fatalf("expected a stopped run to return no error, got %v", err)
+	}
+
+	if archive := results.(algorithms.Result).Result; len(archive) == 0 {
+		t.Error("expected the archive found so far, got an empty one")
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	if len(events) >= iterations || events[len(events)-1] != StoppedEvent {
+		t.Fatalf("expected the run to stop early with a StoppedEvent, got %d events ending with %s", len(events), events[len(events)-1])
+	}
+	if archive := stoppedResults.(algorithms.Result).Result; len(archive) == 0 {
+		t.Error("expected the StoppedEvent to carry the archive found so far")
+	}
+}
+
+func TestAlgorithm_CancelAfterLastIteration(t *testing.T) {
+	const iterations = 5
+
+	app := newTestApp(t, testMultiObjectiveProject("MOAHA", map[string]any{
+		"iterations":  iterations,
+		"population":  10,
+		"archiveSize": 20,
+		"seed":        1,
+	}))
+
+	runs := map[string]func(ctx context.Context) error{
+		"Run": app.algorithm.Run,
+		"RunWithChannel": func(ctx context.Context) error {
+			channel := make(chan any)
+			go func() {
+				for range channel {
+				}
+			}()
+			return app.algorithm.RunWithChannel(ctx, channel)
+		},
+	}
+
+	for name, run := range runs {
+		t.Run(name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			// a stop arriving once the last iteration is done does not turn
+			// the finished run into a stopped one
+			app.algorithm.SetCheckpoint(1, func(iteration int, state any) {
+				if iteration == iterations {
+					cancel()
+				}
+			})
+
+			err := run(ctx)
+			if err != nil {
+				t.Fatalf("expected the finished run to return no error, got %v", err)
+			}
+			if len(app.algorithm.GetResults().Result) == 0 {
+				t.Error("expected the final archive, got an empty one")
+			}
+		})
+	}
+}
+
+func TestDeathPenalty_ZeroAlpha(t *testing.T) {
+	project := withPenaltySchedule(testMultiObjectiveProject("MOAHA", map[string]any{
+		"iterations":  1,
+		"population":  10,
+		"archiveSize": 20,
+		"seed":        1,
+	}), "death")
+	project["objectives"].([]any)[1].(map[string]any)["objectiveConfig"].(map[string]any)["AlphaTCPenalty"] = 0
+	app := newTestApp(t, project)
+
+	// every facility in the same corner overlaps the others
+	values, valuesWithKey, _, penalty, _ := app.problem.Eval(app.problem.GetLowerBound())
+	if penalty[constraints.ConstraintOverlap] == 0 {
+		t.Fatalf("expected the overlapping layout to be penalised, got %v", penalty)
+	}
+
+	for _, value := range values {
+		if math.IsNaN(value) {
+			t.Fatalf("expected no NaN objective value, got %v", valuesWithKey)
+		}
+	}
+	if value := valuesWithKey[objectives.SafetyObjectiveType]; !math.IsInf(value, 1) {
+		t.Errorf("expected the penalised objective to be rejected, got %v", value)
+	}
+	if value := valuesWithKey[objectives.TransportCostObjectiveType]; math.IsInf(value, 0) {
+		t.Errorf("expected the objective without a penalty coefficient to keep its value, got %v", value)
+	}
+}
+
+func TestAlgorithm_SameResultFromEitherRun(t *testing.T) {
+	config := map[string]any{
+		"iterations": 5, "population": 10, "archiveSize": 20, "numberOfGrids": 5,
+		"mutationRate": 0.5, "maxVelocity": 5, "c1": 2, "c2": 2, "w": 0.4, "seed": 7,
+	}
+
+	app := newTestApp(t, testMultiObjectiveProject("MOPSO-Reimpl", config))
+	err := app.algorithm.Run(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	run := resultValues(app.algorithm.GetResults())
+
+	app = newTestApp(t, testMultiObjectiveProject("MOPSO-Reimpl", config))
+	channel := make(chan any)
+	go func() {
+		for range channel {
+		}
+	}()
+	err = app.algorithm.RunWithChannel(context.Background(), channel)
+	if err != nil {
+		t.Fatal(err)
+	}
+	runWithChannel := resultValues(app.algorithm.GetResults())
+
+	if len(run) == 0 {
+		t.Fatal("expected a non-empty archive")
+	}
+	if !reflect.DeepEqual(run, runWithChannel) {
+		t.Errorf("expected the same seed to give the same archive\nRun:            %v\nRunWithChannel: %v", run, runWithChannel)
+	}
+}
@@ -13,6 +13,7 @@ import (
 	"golang-moaha-construction/internal/objectives"
 	"os"
 	"strings"
+	"sync"
 	"time"
 )
 
@@ -23,7 +24,22 @@ type App struct {
 	problem            objectives.Problem
 	algorithmName      algorithms.AlgorithmType
 	algorithm          algorithms.Algorithm
+	algorithmInfo      algorithms.Info
 	numberOfObjectives int
+
+	// inputs the current setup was built from, kept for SaveProject
+	problemInput     ProblemInput
+	objectivesInput  []ObjectiveInput
+	constraintsInput []ConstraintInput
+	algorithmInput   AlgorithmInput
+
+	// cancellation of the run started by RunAlgorithm
+	runMu     sync.Mutex
+	cancelRun context.CancelFunc
+	runDone   chan struct{}
+
+	// emit replaces the events sent to the frontend, used by tests
+	emit func(event EventType, data any)
 }
 
 // NewApp creates a new App application struct
@@ -42,8 +58,8 @@ func (a *App) SelectFile() (string, error) {
 		Title: "Select a File",
 		Filters: []runtime.FileFilter{
 			{
-				DisplayName: "All Supported Files (*.png;*.jpg;*.jpeg;*.xlsx)",
-				Pattern:     "*.png;*.jpg;*.jpeg;*.xlsx",
+				DisplayName: "All Supported Files (*.png;*.jpg;*.jpeg;*.xlsx;*.xer;*.xml)",
+				Pattern:     "*.png;*.jpg;*.jpeg;*.xlsx;*.xer;*.xml",
 			},
 		},
 		ShowHiddenFiles: false,
@@ -123,70 +139,64 @@ func (a *App) SaveFile(commandType CommandType) error {
 			return err
 		}
 
-		// TODO: export result
-
-		algoInfo, err := a.AlgorithmInfo()
-		if err != nil {
-			return err
-		}
-
-		problemInfo, err := a.ProblemInfo()
-		if err != nil {
-			return err
-		}
+		return a.exportResult(selection)
 
-		objectivesInfo, err := a.ObjectivesInfo()
-		if err != nil {
-			return err
-		}
-
-		constraintsInfo, err := a.ConstraintsInfo()
-		if err != nil {
-			return err
-		}
+	case SaveChart:
+		return errors.New("SaveChart command requires chart data. Use SaveChartImage method instead")
 
-		resultsAny, err := a.Result()
-		if err != nil {
-			return err
-		}
+	default:
+		return errors.New("invalid command type")
+	}
 
-		// Parse the algorithms.Result
-		resultsBytes, err := sonic.Marshal(resultsAny)
-		if err != nil {
-			return err
-		}
+}
 
-		var results algorithms.Result
-		err = sonic.Unmarshal(resultsBytes, &results)
-		if err != nil {
-			return err
-		}
+// exportResult writes the summary and the results of the last run to filePath.
+func (a *App) exportResult(filePath string) error {
+	summary, err := a.summary()
+	if err != nil {
+		return err
+	}
 
-		err = eprs.WriteXlsxResult(eprs.Options{
-			Summary: eprs.Summary{
-				AlgorithmInfo:   algoInfo,
-				ConstraintsInfo: constraintsInfo,
-				ProblemInfo:     problemInfo,
-				ObjectivesInfo:  objectivesInfo,
-			},
-			Results:            results,
-			FilePath:           selection,
-			ProblemName:        a.problemName,
-			AlgorithmName:      a.algorithmName,
-			NumberOfObjectives: a.numberOfObjectives,
-		})
+	resultsAny, err := a.Result()
+	if err != nil {
+		return err
+	}
 
-		if err != nil {
-			return err
-		}
+	// Parse the algorithms.Result
+	resultsBytes, err := sonic.Marshal(resultsAny)
+	if err != nil {
+		return err
+	}
 
-		return nil
+	var results algorithms.Result
+	err = sonic.Unmarshal(resultsBytes, &results)
+	if err != nil {
+		return err
+	}
 
-	case SaveChart:
-		return errors.New("SaveChart command requires chart data. Use SaveChartImage method instead")
+	return eprs.WriteXlsxResult(eprs.Options{
+		Summary:            summary,
+		Results:            results,
+		FilePath:           filePath,
+		ProblemName:        a.problemName,
+		AlgorithmName:      a.algorithmName,
+		NumberOfObjectives: a.numberOfObjectives,
+	})
+}
 
-	default:
-		return errors.New("invalid command type")
+// summary collects the info of the current algorithm, problem, objectives and
+// constraints for the exported workbook.
+func (a *App) summary() (eprs.Summary, error) {
+	problemInfo, err := a.ProblemInfo()
+	if err != nil {
+		return eprs.Summary{}, err
 	}
 
+	return eprs.Summary{
+		AlgorithmInfo: a.algorithmInfo,
+		Constraints:   a.problem.GetConstraints(),
+		ProblemInfo:   problemInfo,
+		Objectives:    a.problem.GetObjectives(),
+		Penalty:       a.problem.PenaltyReport(a.problem.GetConstraints()),
+	}, nil
 }
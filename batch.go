@@ -0,0 +1,398 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"github.com/bytedance/sonic"
+	"github.com/schollz/progressbar/v3"
+	"golang-moaha-construction/internal/algorithms"
+	eprs "golang-moaha-construction/internal/export-result"
+	"golang-moaha-construction/internal/util"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+)
+
+// BatchVersion is the schema version written into every batch file.
+const BatchVersion = 1
+
+// Batch describes an experiment: every algorithm config is run Runs times on
+// the same problem, run k with seed Seed+k.
+type Batch struct {
+	Version     int               `json:"version"`
+	Problem     ProblemInput      `json:"problem"`
+	Objectives  []ObjectiveInput  `json:"objectives"`
+	Constraints []ConstraintInput `json:"constraints"`
+	Algorithms  []BatchAlgorithm  `json:"algorithms"`
+	Runs        int               `json:"runs"`
+	// Seed of the first run, 0 picks a random one
+	Seed int64 `json:"seed"`
+	// Workers is the number of runs executed in parallel, 0 uses every CPU
+	Workers int `json:"workers"`
+}
+
+// BatchAlgorithm is an algorithm config of a batch. Label tells apart several
+// configs of the same algorithm and defaults to the algorithm name.
+type BatchAlgorithm struct {
+	Label string `json:"label"`
+	AlgorithmInput
+}
+
+var ErrUnsupportedBatchVersion = errors.New("unsupported batch version")
+
+const batchUsage = `Usage: optim-cons batch -batch <file.json> [-output <file.xlsx>] [-latex <file.tex>] [-workers <n>]
+
+Runs every algorithm config of a batch file several times with consecutive
+seeds and writes one workbook with the statistics, every run and every final
+archive:
+
+  {
+    "version":     1,
+    "problem":     { "problemName": "...", ... },
+    "objectives":  [ { "objectiveName": "...", "objectiveConfig": { ... } } ],
+    "constraints": [ { "constraintName": "...", "constraintConfig": { ... } } ],
+    "algorithms":  [ { "label": "...", "algorithmName": "...", "algorithmConfig": { ... } } ],
+    "runs":        30,
+    "seed":        1
+  }
+
+The seed of an algorithm config is replaced by the seed of the run. Relative
+file paths are resolved against the directory of the batch file. Pressing
+Ctrl+C stops the batch and exports the runs completed so far.
+
+With two or more algorithm configs the workbook and a LaTeX file also
+compare them with the Wilcoxon rank-sum and signed-rank tests, the Friedman
+test with Nemenyi post-hoc ranks and effect sizes: on the best objective
+values for single-objective algorithms and on the indicators of the final
+archives for multi-objective ones.
+
+Flags:
+`
+
+// batchCommand implements the "batch" subcommand.
+func batchCommand(args []string) error {
+	fs := flag.NewFlagSet("batch", flag.ContinueOnError)
+	batchPath := fs.String("batch", "", "path to the batch file")
+	outputPath := fs.String("output", "", "path of the exported workbook (default batch_<timestamp>.xlsx)")
+	latexPath := fs.String("latex", "", "path of the LaTeX comparison tables (default the output path with .tex)")
+	workers := fs.Int("workers", 0, "number of runs executed in parallel (default from the batch file, else every CPU)")
+	fs.Usage = func() {
+		fmt.Fprint(fs.Output(), batchUsage)
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *batchPath == "" {
+		fs.Usage()
+		return errors.New("missing -batch")
+	}
+
+	if *outputPath == "" {
+		*outputPath = fmt.Sprintf("batch_%s.xlsx", time.Now().Format("20060102150405"))
+	}
+
+	if *latexPath == "" {
+		*latexPath = strings.TrimSuffix(*outputPath, filepath.Ext(*outputPath)) + ".tex"
+	}
+
+	batch, err := loadBatch(*batchPath)
+	if err != nil {
+		return err
+	}
+
+	if *workers > 0 {
+		batch.Workers = *workers
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	total := batch.Runs * len(batch.Algorithms)
+	fmt.Printf("Running %d algorithm configs %d times each on %s\n", len(batch.Algorithms), batch.Runs, batch.Problem.ProblemName)
+
+	bar := progressbar.Default(int64(total))
+	start := time.Now()
+
+	options, err := runBatch(ctx, batch, func(run eprs.BatchRun) {
+		bar.Describe(fmt.Sprintf("%s #%d", run.Label, run.Run))
+		_ = bar.Add(1)
+	})
+
+	switch {
+	case errors.Is(err, context.Canceled):
+		_ = bar.Exit()
+		fmt.Printf("\nStopped after %d of %d runs in %s\n", len(options.Runs), total, time.Since(start).Round(time.Millisecond))
+	case err != nil:
+		_ = bar.Exit()
+		return err
+	default:
+		_ = bar.Finish()
+		fmt.Printf("Finished in %s\n", time.Since(start).Round(time.Millisecond))
+	}
+
+	options.FilePath = *outputPath
+	options.Comparisons = eprs.CompareBatch(options)
+	err = eprs.WriteXlsxBatch(options)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Results written to %s\n", *outputPath)
+
+	if len(options.Comparisons) > 0 {
+		err = eprs.WriteLatexComparison(options.Comparisons, *latexPath)
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("Comparison tables written to %s\n", *latexPath)
+	}
+
+	return nil
+}
+
+// loadBatch reads a batch file, resolves its relative paths and fills in the defaults.
+func loadBatch(filePath string) (Batch, error) {
+	batchBytes, err := os.ReadFile(filePath)
+	if err != nil {
+		return Batch{}, err
+	}
+
+	var batch Batch
+	err = sonic.Unmarshal(batchBytes, &batch)
+	if err != nil {
+		return Batch{}, fmt.Errorf("invalid batch file: %w", err)
+	}
+
+	if batch.Version < 1 || batch.Version > BatchVersion {
+		return Batch{}, fmt.Errorf("%w: %d", ErrUnsupportedBatchVersion, batch.Version)
+	}
+
+	if len(batch.Algorithms) == 0 {
+		return Batch{}, errors.New("batch has no algorithms")
+	}
+
+	if batch.Runs < 1 {
+		return Batch{}, errors.New("batch runs must be at least 1")
+	}
+
+	dir, err := filepath.Abs(filepath.Dir(filePath))
+	if err != nil {
+		return Batch{}, err
+	}
+
+	input := RunInput{
+		Problem:     batch.Problem,
+		Objectives:  batch.Objectives,
+		Constraints: batch.Constraints,
+	}
+	err = rewriteFilePaths(&input, func(path string) string {
+		if path == "" || filepath.IsAbs(path) {
+			return path
+		}
+		return filepath.Join(dir, path)
+	})
+	if err != nil {
+		return Batch{}, err
+	}
+
+	batch.Problem = input.Problem
+	batch.Objectives = input.Objectives
+	batch.Constraints = input.Constraints
+
+	if batch.Seed == 0 {
+		batch.Seed = util.NewSeed()
+	}
+
+	if batch.Workers <= 0 {
+		batch.Workers = runtime.NumCPU()
+	}
+
+	// labels identify the configs in the workbook, so they must be unique
+	seen := make(map[string]int)
+	for i := range batch.Algorithms {
+		label := batch.Algorithms[i].Label
+		if label == "" {
+			label = string(batch.Algorithms[i].AlgorithmName)
+		}
+
+		seen[label]++
+		if seen[label] > 1 {
+			label = fmt.Sprintf("%s (%d)", label, seen[label])
+		}
+		batch.Algorithms[i].Label = label
+	}
+
+	return batch, nil
+}
+
+// runBatch runs every algorithm config of batch batch.Runs times, up to
+// batch.Workers runs at a time. Every run builds its own problem so that runs
+// do not share state. onRun is called after every completed run.
+//
+// The first failing run stops the batch. When ctx is cancelled the completed
+// runs are returned together with the context error.
+func runBatch(ctx context.Context, batch Batch, onRun func(run eprs.BatchRun)) (eprs.BatchOptions, error) {
+	inputs := make([]RunInput, len(batch.Algorithms))
+	options := eprs.BatchOptions{
+		Algorithms: make([]eprs.BatchAlgorithm, len(batch.Algorithms)),
+	}
+
+	// set everything up once, to report invalid configs before running and
+	// to describe the setup in the workbook
+	for i, algorithm := range batch.Algorithms {
+		inputs[i] = RunInput{
+			Problem:     batch.Problem,
+			Objectives:  batch.Objectives,
+			Constraints: batch.Constraints,
+			Algorithm:   algorithm.AlgorithmInput,
+		}
+		// parallel runs cannot share a checkpoint file
+		inputs[i].Algorithm.Checkpoint = nil
+
+		input, err := withSeed(inputs[i], batch.Seed)
+		if err != nil {
+			return eprs.BatchOptions{}, fmt.Errorf("%s: %w", algorithm.Label, err)
+		}
+
+		app := NewApp()
+		err = app.setup(input)
+		if err != nil {
+			return eprs.BatchOptions{}, fmt.Errorf("%s: %w", algorithm.Label, err)
+		}
+
+		options.Algorithms[i] = eprs.BatchAlgorithm{
+			Label: algorithm.Label,
+			Name:  algorithm.AlgorithmName,
+			Info:  app.algorithmInfo,
+		}
+
+		if i == 0 {
+			options.Summary, err = app.summary()
+			if err != nil {
+				return eprs.BatchOptions{}, err
+			}
+		}
+	}
+
+	type job struct {
+		algorithm int
+		run       int
+	}
+
+	jobs := make(chan job)
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		runs     = make([]*eprs.BatchRun, len(batch.Algorithms)*batch.Runs)
+		firstErr error
+	)
+
+	wg.Add(batch.Workers)
+	for w := 0; w < batch.Workers; w++ {
+		go func() {
+			defer wg.Done()
+
+			for j := range jobs {
+				seed := batch.Seed + int64(j.run)
+				results, elapsed, err := runBatchJob(ctx, inputs[j.algorithm], seed)
+
+				mu.Lock()
+				switch {
+				case errors.Is(err, context.Canceled):
+					// stopped runs are left out
+				case err != nil:
+					if firstErr == nil {
+						firstErr = fmt.Errorf("%s run %d: %w", batch.Algorithms[j.algorithm].Label, j.run+1, err)
+					}
+					cancel()
+				default:
+					run := eprs.BatchRun{
+						Label:   batch.Algorithms[j.algorithm].Label,
+						Run:     j.run + 1,
+						Seed:    seed,
+						Time:    elapsed,
+						Results: results,
+					}
+					runs[j.algorithm*batch.Runs+j.run] = &run
+					onRun(run)
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+
+	// run k of every config before run k+1 so that a stopped batch has
+	// about as many runs for every config
+send:
+	for r := 0; r < batch.Runs; r++ {
+		for a := range batch.Algorithms {
+			select {
+			case jobs <- job{algorithm: a, run: r}:
+			case <-ctx.Done():
+				break send
+			}
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	for _, run := range runs {
+		if run != nil {
+			options.Runs = append(options.Runs, *run)
+		}
+	}
+
+	if firstErr != nil {
+		return options, firstErr
+	}
+
+	return options, ctx.Err()
+}
+
+// runBatchJob runs input with the given seed on a fresh App.
+func runBatchJob(ctx context.Context, input RunInput, seed int64) (algorithms.Result, time.Duration, error) {
+	input, err := withSeed(input, seed)
+	if err != nil {
+		return algorithms.Result{}, 0, err
+	}
+
+	app := NewApp()
+	err = app.setup(input)
+	if err != nil {
+		return algorithms.Result{}, 0, err
+	}
+
+	start := time.Now()
+	results, err := app.runAlgorithm(ctx, func(any) {})
+	return results, time.Since(start), err
+}
+
+// withSeed returns input with the seed of the algorithm config replaced.
+func withSeed(input RunInput, seed int64) (RunInput, error) {
+	config, err := toGenericConfig(input.Algorithm.AlgorithmConfig)
+	if err != nil {
+		return RunInput{}, err
+	}
+
+	configMap, ok := config.(map[string]any)
+	if !ok {
+		return RunInput{}, errors.New("algorithm config must be an object")
+	}
+	configMap["seed"] = seed
+
+	input.Algorithm.AlgorithmConfig = configMap
+	return input, nil
+}
@@ -0,0 +1,220 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"github.com/bytedance/sonic"
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+	"golang-moaha-construction/internal/algorithms"
+	"os"
+	"path/filepath"
+)
+
+// CheckpointVersion is the schema version written into every checkpoint file.
+const CheckpointVersion = 1
+
+// Checkpoint is the on-disk representation of a paused run. State is the
+// algorithm specific state encoded with algorithms.EncodeState.
+type Checkpoint struct {
+	Version     int                      `json:"version"`
+	Algorithm   algorithms.AlgorithmType `json:"algorithm"`
+	ProblemHash string                   `json:"problemHash"`
+	Iteration   int                      `json:"iteration"`
+	State       []byte                   `json:"state"`
+}
+
+// CheckpointInput enables periodic checkpoints of a run.
+type CheckpointInput struct {
+	FilePath string `json:"filePath"`
+	// Every is the number of iterations between two checkpoints
+	Every int `json:"every"`
+}
+
+var (
+	ErrUnsupportedCheckpointVersion = errors.New("unsupported checkpoint version")
+	ErrCheckpointMismatch           = errors.New("checkpoint does not match the current setup")
+)
+
+// ResumeAlgorithm asks for a checkpoint file and continues the run saved in it.
+// The problem, objectives, constraints and algorithm must be set up as they
+// were when the checkpoint was written.
+func (a *App) ResumeAlgorithm() error {
+	selection, err := runtime.OpenFileDialog(a.ctx, runtime.OpenDialogOptions{
+		Title: "Resume From Checkpoint",
+		Filters: []runtime.FileFilter{
+			{
+				DisplayName: "Checkpoint File (*.json)",
+				Pattern:     "*.json",
+			},
+		},
+		ShowHiddenFiles: false,
+	})
+	if err != nil {
+		return err
+	}
+
+	// If user cancelled the dialog
+	if selection == "" {
+		return nil
+	}
+
+	err = a.loadCheckpoint(selection)
+	if err != nil {
+		return err
+	}
+
+	return a.RunAlgorithm()
+}
+
+// loadCheckpoint checks that the checkpoint was written for the current setup
+// and makes the next run of the algorithm continue from it.
+func (a *App) loadCheckpoint(filePath string) error {
+	if a.algorithm == nil {
+		return errors.New("algorithm has not been created")
+	}
+
+	checkpointBytes, err := os.ReadFile(filePath)
+	if err != nil {
+		return err
+	}
+
+	var checkpoint Checkpoint
+	err = sonic.Unmarshal(checkpointBytes, &checkpoint)
+	if err != nil {
+		return fmt.Errorf("invalid checkpoint file: %w", err)
+	}
+
+	if checkpoint.Version < 1 || checkpoint.Version > CheckpointVersion {
+		return fmt.Errorf("%w: %d", ErrUnsupportedCheckpointVersion, checkpoint.Version)
+	}
+
+	if checkpoint.Algorithm != a.algorithmName {
+		return fmt.Errorf("%w: written by %s, current algorithm is %s", ErrCheckpointMismatch, checkpoint.Algorithm, a.algorithmName)
+	}
+
+	hash, err := a.problemHash()
+	if err != nil {
+		return err
+	}
+
+	if checkpoint.ProblemHash != hash {
+		return fmt.Errorf("%w: problem, objectives or constraints have changed", ErrCheckpointMismatch)
+	}
+
+	err = a.algorithm.LoadState(checkpoint.State)
+	if err != nil {
+		return fmt.Errorf("invalid checkpoint state: %w", err)
+	}
+
+	return nil
+}
+
+// checkpointSaver returns the callback handed to SetCheckpoint. It writes the
+// state of the current run to input.FilePath and reports write failures to
+// onError.
+func (a *App) checkpointSaver(input CheckpointInput, onError func(error)) (func(iteration int, state any), error) {
+	hash, err := a.problemHash()
+	if err != nil {
+		return nil, err
+	}
+
+	return func(iteration int, state any) {
+		stateBytes, err := algorithms.EncodeState(state)
+		if err == nil {
+			err = writeCheckpoint(input.FilePath, Checkpoint{
+				Version:     CheckpointVersion,
+				Algorithm:   a.algorithmName,
+				ProblemHash: hash,
+				Iteration:   iteration,
+				State:       stateBytes,
+			})
+		}
+		if err != nil {
+			onError(err)
+		}
+	}, nil
+}
+
+// writeCheckpoint replaces filePath atomically so that an interrupted write
+// never leaves a broken checkpoint behind.
+func writeCheckpoint(filePath string, checkpoint Checkpoint) error {
+	checkpointBytes, err := sonic.Marshal(checkpoint)
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(filePath), filepath.Base(filePath)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	_, err = tmp.Write(checkpointBytes)
+	if err != nil {
+		tmp.Close()
+		return err
+	}
+
+	err = tmp.Close()
+	if err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), filePath)
+}
+
+// problemHash identifies the problem, objectives and constraints of the
+// current setup. Input files are identified by their content instead of their
+// path so that a project can be moved together with its checkpoints.
+func (a *App) problemHash() (string, error) {
+	input, err := a.currentInput()
+	if err != nil {
+		return "", err
+	}
+
+	// the algorithm config may change between runs, e.g. to raise the
+	// number of iterations; LoadState refuses a changed population size
+	input.Algorithm = AlgorithmInput{}
+
+	// a file that cannot be read must not hash like a missing one
+	var digestErr error
+	err = rewriteFilePaths(&input, func(path string) string {
+		digest, err := fileDigest(path)
+		if err != nil && digestErr == nil {
+			digestErr = err
+		}
+		return digest
+	})
+	if err == nil {
+		err = digestErr
+	}
+	if err != nil {
+		return "", err
+	}
+
+	inputBytes, err := sonic.ConfigStd.Marshal(input)
+	if err != nil {
+		return "", err
+	}
+
+	sum := sha256.Sum256(inputBytes)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// fileDigest returns the sha256 of the file at path, or an empty string for
+// an empty path.
+func fileDigest(path string) (string, error) {
+	if path == "" {
+		return path, nil
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:]), nil
+}
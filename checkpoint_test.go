@@ -0,0 +1,212 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"golang-moaha-construction/internal/algorithms"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// resultValues returns the objective values and positions of the solutions in
+// result, in order.
+func resultValues(result algorithms.Result) [][]float64 {
+	values := make([][]float64, 0, 2*len(result.Result))
+	for _, r := range result.Result {
+		values = append(values, r.Value)
+
+		position := make([]float64, 0, 3*len(r.SliceLocations))
+		for _, loc := range r.SliceLocations {
+			position = append(position, loc.Coordinate.X, loc.Coordinate.Y, loc.Angle)
+		}
+		values = append(values, position)
+	}
+	return values
+}
+
+// withPenaltySchedule sets the penalty schedule of project.
+func withPenaltySchedule(project map[string]any, schedule string) map[string]any {
+	project["problem"].(map[string]any)["penaltySchedule"] = map[string]any{"type": schedule}
+	return project
+}
+
+func TestCheckpointResume(t *testing.T) {
+	const (
+		iterations = 6
+		checkpoint = 3
+	)
+
+	testTable := []struct {
+		name    string
+		project map[string]any
+	}{
+		{
+			// population with crowding distances
+			name: "NSGA-II",
+			project: testMultiObjectiveProject("NSGA-II", map[string]any{
+				"chromosome": 10, "generation": iterations, "crossoverRate": 0.9,
+				"mutationRate": 0.1, "mutationStrength": 0.1, "sigma": 0.5, "seed": 7,
+			}),
+		},
+		{
+			// agents, archive and visit table
+			name: "MOAHA",
+			project: testMultiObjectiveProject("MOAHA", map[string]any{
+				"iterations": iterations, "population": 10, "archiveSize": 20, "seed": 7,
+			}),
+		},
+		{
+			// penalty factors adapted to the population
+			name: "MOAHA self-adaptive",
+			project: withPenaltySchedule(testMultiObjectiveProject("MOAHA", map[string]any{
+				"iterations": iterations, "population": 10, "archiveSize": 20, "seed": 7,
+			}), "self-adaptive"),
+		},
+		{
+			// particles with velocities and personal bests, archive and grid
+			name: "MOPSO",
+			project: testMultiObjectiveProject("MOPSO", map[string]any{
+				"iterations": iterations, "population": 10, "archiveSize": 20, "numberOfGrids": 5,
+				"mutationRate": 0.5, "maxVelocity": 5, "c1": 2, "c2": 2, "w": 0.4, "seed": 7,
+			}),
+		},
+	}
+
+	for _, test := range testTable {
+		t.Run(test.name, func(t *testing.T) {
+			checkpointPath := filepath.Join(t.TempDir(), "run.ckpt")
+
+			// the uninterrupted run, saving its state halfway
+			app := newTestApp(t, test.project)
+			save, err := app.checkpointSaver(CheckpointInput{FilePath: checkpointPath, Every: checkpoint}, func(err error) {
+				t.Error(err)
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			app.algorithm.SetCheckpoint(checkpoint, func(iteration int, state any) {
+				if iteration == checkpoint {
+					save(iteration, state)
+				}
+			})
+			err = app.algorithm.Run(context.Background())
+			if err != nil {
+				t.Fatal(err)
+			}
+			full := app.algorithm.GetResults()
+			fullPenalty := app.problem.PenaltyReport(app.problem.GetConstraints())
+
+			// a new setup of the same project continuing from the checkpoint,
+			// with another seed so that only the saved state can reproduce the
+			// second half
+			test.project["algorithm"].(map[string]any)["algorithmConfig"].(map[string]any)["seed"] = 8
+			resumed := newTestApp(t, test.project)
+			err = resumed.loadCheckpoint(checkpointPath)
+			if err != nil {
+				t.Fatal(err)
+			}
+			err = resumed.algorithm.Run(context.Background())
+			if err != nil {
+				t.Fatal(err)
+			}
+			result := resumed.algorithm.GetResults()
+			resultPenalty := resumed.problem.PenaltyReport(resumed.problem.GetConstraints())
+
+			if len(full.Result) == 0 {
+				t.Fatal("expected a non-empty archive")
+			}
+			if !reflect.DeepEqual(resultValues(full), resultValues(result)) {
+				t.Errorf("expected the resumed run to reproduce the uninterrupted one\nfull:    %v\nresumed: %v", resultValues(full), resultValues(result))
+			}
+			if !reflect.DeepEqual(full.Indicators, result.Indicators) {
+				t.Errorf("expected the resumed run to reproduce the indicators\nfull:    %v\nresumed: %v", full.Indicators, result.Indicators)
+			}
+			if !reflect.DeepEqual(fullPenalty, resultPenalty) {
+				t.Errorf("expected the resumed run to reproduce the penalty factors\nfull:    %v\nresumed: %v", fullPenalty, resultPenalty)
+			}
+		})
+	}
+}
+
+func TestCheckpoint_UnreadableInput(t *testing.T) {
+	app := newTestApp(t, testProject())
+	checkpointPath := filepath.Join(t.TempDir(), "run.ckpt")
+
+	save, err := app.checkpointSaver(CheckpointInput{FilePath: checkpointPath, Every: 1}, func(err error) {
+		t.Error(err)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	app.algorithm.SetCheckpoint(1, save)
+	err = app.algorithm.Run(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// a missing input file does not hash like an empty path
+	err = os.Remove(*app.problemInput.FacilitiesFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = app.loadCheckpoint(checkpointPath)
+	if err == nil {
+		t.Error("expected the resume to be refused when an input file cannot be read")
+	}
+}
+
+func TestCheckpoint_ChangedPopulation(t *testing.T) {
+	testTable := []struct {
+		name   string
+		key    string
+		value  any
+		refuse bool
+	}{
+		{name: "more iterations", key: "iterations", value: 10},
+		{name: "fewer agents", key: "population", value: 5, refuse: true},
+		{name: "more agents", key: "population", value: 15, refuse: true},
+	}
+
+	for _, test := range testTable {
+		t.Run(test.name, func(t *testing.T) {
+			project := testMultiObjectiveProject("MOAHA", map[string]any{
+				"iterations": 2, "population": 10, "archiveSize": 20, "seed": 7,
+			})
+			checkpointPath := filepath.Join(t.TempDir(), "run.ckpt")
+
+			app := newTestApp(t, project)
+			save, err := app.checkpointSaver(CheckpointInput{FilePath: checkpointPath, Every: 1}, func(err error) {
+				t.Error(err)
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			app.algorithm.SetCheckpoint(1, save)
+			err = app.algorithm.Run(context.Background())
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			project["algorithm"].(map[string]any)["algorithmConfig"].(map[string]any)[test.key] = test.value
+			resumed := newTestApp(t, project)
+			err = resumed.loadCheckpoint(checkpointPath)
+			if test.refuse {
+				if !errors.Is(err, algorithms.ErrStateMismatch) {
+					t.Fatalf("expected ErrStateMismatch, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			err = resumed.algorithm.Run(context.Background())
+			if err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
@@ -0,0 +1,213 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"github.com/bytedance/sonic"
+	"github.com/schollz/progressbar/v3"
+	"os"
+	"os/signal"
+	"time"
+)
+
+// RunInput gathers everything the GUI collects across its pages so that a
+// project file or a headless run goes through exactly the same App methods.
+type RunInput struct {
+	Problem     ProblemInput      `json:"problem"`
+	Objectives  []ObjectiveInput  `json:"objectives"`
+	Constraints []ConstraintInput `json:"constraints"`
+	Algorithm   AlgorithmInput    `json:"algorithm"`
+}
+
+// progressMessage picks the common fields out of the anonymous structs the
+// algorithms send through RunWithChannel.
+type progressMessage struct {
+	Progress                float64  `json:"progress"`
+	BestFitness             *float64 `json:"bestFitness"`
+	NumberOfAgentsInArchive *int     `json:"numberOfAgentsInArchive"`
+	Type                    string   `json:"type"`
+}
+
+const runUsage = `Usage: optim-cons run (-project <file.json> | -config <file.json>) [-output <file.xlsx>]
+                       [-checkpoint <file.json> [-checkpoint-every <n>]] [-resume <file.json>]
+
+Runs an optimization without the graphical interface. The project file is the
+one written by "Save Project" in the application:
+
+  {
+    "version":     1,
+    "problem":     { "problemName": "...", ... },
+    "objectives":  [ { "objectiveName": "...", "objectiveConfig": { ... } } ],
+    "constraints": [ { "constraintName": "...", "constraintConfig": { ... } } ],
+    "algorithm":   { "algorithmName": "...", "algorithmConfig": { ... } }
+  }
+
+Relative file paths are resolved against the directory of the project file.
+
+-config takes the same inputs without "version", as written for earlier
+releases; relative file paths are resolved against the working directory.
+Pressing Ctrl+C stops the run after the current iteration and exports the
+solutions found so far.
+
+With -checkpoint the state of the run is written every -checkpoint-every
+iterations, or as set by "checkpoint" in the algorithm section of the project.
+-resume continues such a run; the project must describe the same problem,
+objectives and constraints, while the algorithm config may differ, e.g. to
+raise the number of iterations. The population and archive sizes must stay the
+same.
+
+Flags:
+`
+
+// runCommand implements the "run" subcommand.
+func runCommand(args []string) error {
+	fs := flag.NewFlagSet("run", flag.ContinueOnError)
+	projectPath := fs.String("project", "", "path to the project file")
+	configPath := fs.String("config", "", "path to a JSON run configuration without version")
+	outputPath := fs.String("output", "", "path of the exported result (default results_<timestamp>.xlsx)")
+	checkpointPath := fs.String("checkpoint", "", "path of the checkpoint file written during the run")
+	checkpointEvery := fs.Int("checkpoint-every", 10, "number of iterations between two checkpoints")
+	resumePath := fs.String("resume", "", "path of a checkpoint file to continue from")
+	fs.Usage = func() {
+		fmt.Fprint(fs.Output(), runUsage)
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *projectPath == "" && *configPath == "" {
+		fs.Usage()
+		return errors.New("missing -project or -config")
+	}
+
+	if *projectPath != "" && *configPath != "" {
+		return errors.New("-project and -config are mutually exclusive")
+	}
+
+	if *outputPath == "" {
+		*outputPath = fmt.Sprintf("results_%s.xlsx", time.Now().Format("20060102150405"))
+	}
+
+	app := NewApp()
+
+	var err error
+	if *projectPath != "" {
+		_, err = app.loadProject(*projectPath)
+	} else {
+		err = app.loadRunConfig(*configPath)
+	}
+	if err != nil {
+		return err
+	}
+
+	if *checkpointPath != "" {
+		app.algorithmInput.Checkpoint = &CheckpointInput{
+			FilePath: *checkpointPath,
+			Every:    *checkpointEvery,
+		}
+	}
+
+	if *resumePath != "" {
+		err = app.loadCheckpoint(*resumePath)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Resuming from %s\n", *resumePath)
+	}
+
+	fmt.Printf("Running %s on %s\n", app.algorithmName, app.problemName)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	bar := progressbar.Default(100)
+	start := time.Now()
+
+	_, err = app.runAlgorithm(ctx, func(progressData any) {
+		progressBytes, err := sonic.Marshal(progressData)
+		if err != nil {
+			return
+		}
+
+		var progress progressMessage
+		if err := sonic.Unmarshal(progressBytes, &progress); err != nil {
+			return
+		}
+
+		switch {
+		case progress.BestFitness != nil:
+			bar.Describe(fmt.Sprintf("Best fitness %g", *progress.BestFitness))
+		case progress.NumberOfAgentsInArchive != nil:
+			bar.Describe(fmt.Sprintf("Archive size %d", *progress.NumberOfAgentsInArchive))
+		}
+		_ = bar.Set(int(progress.Progress))
+	})
+
+	switch {
+	case errors.Is(err, context.Canceled):
+		_ = bar.Exit()
+		fmt.Printf("\nStopped after %s\n", time.Since(start).Round(time.Millisecond))
+	case err != nil:
+		_ = bar.Exit()
+		return err
+	default:
+		_ = bar.Finish()
+		fmt.Printf("Finished in %s\n", time.Since(start).Round(time.Millisecond))
+	}
+
+	err = app.exportResult(*outputPath)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Results written to %s\n", *outputPath)
+
+	return nil
+}
+
+// loadRunConfig reads a plain RunInput, as taken by -config, and runs it
+// through the same steps as the UI.
+func (a *App) loadRunConfig(filePath string) error {
+	inputBytes, err := os.ReadFile(filePath)
+	if err != nil {
+		return err
+	}
+
+	var input RunInput
+	err = sonic.Unmarshal(inputBytes, &input)
+	if err != nil {
+		return fmt.Errorf("invalid run configuration: %w", err)
+	}
+
+	return a.setup(input)
+}
+
+// setup builds the problem, objectives, constraints and algorithm in the
+// same order as the GUI pages do.
+func (a *App) setup(input RunInput) error {
+	err := a.CreateProblem(input.Problem)
+	if err != nil {
+		return fmt.Errorf("problem: %w", err)
+	}
+
+	err = a.CreateObjectives(input.Objectives)
+	if err != nil {
+		return fmt.Errorf("objectives: %w", err)
+	}
+
+	err = a.AddConstraints(input.Constraints)
+	if err != nil {
+		return fmt.Errorf("constraints: %w", err)
+	}
+
+	err = a.CreateAlgorithm(input.Algorithm)
+	if err != nil {
+		return fmt.Errorf("algorithm: %w", err)
+	}
+
+	return nil
+}
@@ -2,13 +2,15 @@ package main
 
 import (
 	"golang-moaha-construction/internal/algorithms"
-	"golang-moaha-construction/internal/algorithms/aha"
-	"golang-moaha-construction/internal/algorithms/ga"
-	"golang-moaha-construction/internal/algorithms/gwo"
-	"golang-moaha-construction/internal/algorithms/moaha"
-	"golang-moaha-construction/internal/algorithms/mopso"
-	"golang-moaha-construction/internal/algorithms/nsgaii"
-	"golang-moaha-construction/internal/algorithms/omoaha"
+	// the algorithm packages register themselves, see algorithms.Register
+	_ "golang-moaha-construction/internal/algorithms/aha"
+	_ "golang-moaha-construction/internal/algorithms/ga"
+	_ "golang-moaha-construction/internal/algorithms/gwo"
+	_ "golang-moaha-construction/internal/algorithms/moaha"
+	_ "golang-moaha-construction/internal/algorithms/mogwo"
+	_ "golang-moaha-construction/internal/algorithms/mopso"
+	_ "golang-moaha-construction/internal/algorithms/nsgaii"
+	_ "golang-moaha-construction/internal/algorithms/omoaha"
 	"golang-moaha-construction/internal/constraints"
 	"golang-moaha-construction/internal/data"
 	"golang-moaha-construction/internal/objectives/conslay_continuous"
@@ -35,94 +37,67 @@ var AllProblemsType = []struct {
 	},
 }
 
-var AllObjectivesType = []struct {
-	Value  data.ObjectiveType
+// AllObjectivesType is generated from the objective registry.
+var AllObjectivesType = objectiveTypes()
+
+// AllConstraintsType is generated from the constraint registry.
+var AllConstraintsType = constraintTypes()
+
+// AllAlgorithmType is generated from the algorithm registry.
+var AllAlgorithmType = algorithmTypes()
+
+func algorithmTypes() []struct {
+	Value  algorithms.AlgorithmType
 	TSName string // typescript enum name
-}{
-	{
-		Value:  objectives.SafetyObjectiveType,
-		TSName: "SafetyObjective",
-	},
-	{
-		Value:  objectives.HoistingObjectiveType,
-		TSName: "HoistingObjective",
-	},
-	{
-		Value:  objectives.RiskObjectiveType,
-		TSName: "RiskObjective",
-	},
-	{
-		Value:  objectives.TransportCostObjectiveType,
-		TSName: "TransportCostObjective",
-	},
-	{
-		Value:  objectives.SafetyHazardObjectiveType,
-		TSName: "SafetyHazardObjective",
-	},
-	{
-		Value:  objectives.ConstructionCostObjectiveType,
-		TSName: "ConstructionCostObjective",
-	},
+} {
+	registered := algorithms.Registered()
+	res := make([]struct {
+		Value  algorithms.AlgorithmType
+		TSName string // typescript enum name
+	}, len(registered))
+
+	for i, r := range registered {
+		res[i].Value = r.Name
+		res[i].TSName = r.Schema.TSName
+	}
+
+	return res
 }
 
-var AllConstraintsType = []struct {
-	Value  data.ConstraintType
+func objectiveTypes() []struct {
+	Value  data.ObjectiveType
 	TSName string // typescript enum name
-}{
-	{
-		Value:  constraints.ConstraintOverlap,
-		TSName: "Overlap",
-	},
-	{
-		Value:  constraints.ConstraintOutOfBound,
-		TSName: "OutOfBound",
-	},
-	{
-		Value:  constraints.ConstraintsCoverInCraneRadius,
-		TSName: "CoverInCraneRadius",
-	},
-	{
-		Value:  constraints.ConstraintInclusiveZone,
-		TSName: "InclusiveZone",
-	},
-	{
-		Value:  constraints.ConstraintSize,
-		TSName: "Size",
-	},
+} {
+	registered := objectives.Registered()
+	res := make([]struct {
+		Value  data.ObjectiveType
+		TSName string // typescript enum name
+	}, len(registered))
+
+	for i, r := range registered {
+		res[i].Value = r.Name
+		res[i].TSName = r.Schema.TSName
+	}
+
+	return res
 }
 
-var AllAlgorithmType = []struct {
-	Value  algorithms.AlgorithmType
+func constraintTypes() []struct {
+	Value  data.ConstraintType
 	TSName string // typescript enum name
-}{
-	{
-		Value:  ga.NameType,
-		TSName: "GeneticAlgorithm",
-	},
-	{
-		Value:  aha.NameType,
-		TSName: "AHA",
-	},
-	{
-		Value:  moaha.NameType,
-		TSName: "MOAHA",
-	},
-	{
-		Value:  gwo.NameType,
-		TSName: "GWO",
-	},
-	{
-		Value:  omoaha.NameType,
-		TSName: "oMOAHA",
-	},
-	{
-		Value:  mopso.NameType,
-		TSName: "MOPSO",
-	},
-	{
-		Value:  nsgaii.NameType,
-		TSName: "NSGAII",
-	},
+} {
+	registered := constraints.Registered()
+	res := make([]struct {
+		Value  data.ConstraintType
+		TSName string // typescript enum name
+	}, len(registered))
+
+	for i, r := range registered {
+		res[i].Value = r.Name
+		res[i].TSName = r.Schema.TSName
+	}
+
+	return res
 }
 
 type EventType string
@@ -130,6 +105,8 @@ type EventType string
 const (
 	ProgressEvent EventType = "ProgressEvent"
 	ResultEvent   EventType = "ResultEvent"
+	StoppedEvent  EventType = "StoppedEvent"
+	ErrorEvent    EventType = "ErrorEvent"
 )
 
 var AllEvent = []struct {
@@ -144,6 +121,14 @@ var AllEvent = []struct {
 		Value:  ResultEvent,
 		TSName: "ResultEvent",
 	},
+	{
+		Value:  StoppedEvent,
+		TSName: "StoppedEvent",
+	},
+	{
+		Value:  ErrorEvent,
+		TSName: "ErrorEvent",
+	},
 }
 
 type CommandType string
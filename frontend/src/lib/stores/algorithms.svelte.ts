@@ -10,6 +10,7 @@ import {
     moahaConfig, gwoConfig, ahaConfig, gaConfig, omoahaConfig, mopsoConfig, nsgaiiConfig
 } from "$lib/stores/algorithms";
 import {algorithms} from "$lib/wailsjs/go/models";
+import {AvailableAlgorithms} from "$lib/wailsjs/go/main/App";
 
 export type AlgorithmConfigMap = {
     [algorithms.AlgorithmType.GeneticAlgorithm]: IGAConfig;
@@ -19,70 +20,81 @@ export type AlgorithmConfigMap = {
     [algorithms.AlgorithmType.oMOAHA]: IOMOAHAConfig;
     [algorithms.AlgorithmType.MOPSO]: IMOPSOConfig;
     [algorithms.AlgorithmType.NSGAII]: INSGAIIConfig;
+    [algorithms.AlgorithmType.MOGWO]: ISchemaConfig;
+    [algorithms.AlgorithmType.MOPSOReimpl]: ISchemaConfig;
 }
 
+// config of an algorithm without a dedicated config store, keyed like its schema
+export type ISchemaConfig = Record<string, number | string>
+
 export interface AlgorithmWithLabel {
     label: string
     value: algorithms.AlgorithmType
 }
 
-const SingleList: AlgorithmWithLabel[] = [
-    {
-        label: 'Genetic Algorithm',
-        value: algorithms.AlgorithmType.GeneticAlgorithm,
-    },
-    {
-        label: "Artificial Hummingbird Algorithm",
-        value: algorithms.AlgorithmType.AHA,
-    },
-    {
-        label: 'Grey Wolf Algorithm',
-        value: algorithms.AlgorithmType.GWO,
-    },
-]
-
-const MultiList: AlgorithmWithLabel[] = [
-    {
-        label: 'Multi-Objective Artificial Hummingbird Algorithm',
-        value: algorithms.AlgorithmType.MOAHA,
-    },
-    {
-        label: 'OBL Multi-Objective Artificial Hummingbird Algorithm (oMOAHA)',
-        value: algorithms.AlgorithmType.oMOAHA,
-    },
-    {
-        label: 'Multi-Objective Particle Swarm Optimization (MOPSO)',
-        value: algorithms.AlgorithmType.MOPSO,
-    },
-    {
-        label: 'Non-dominated Sorting Genetic Algorithm II (NSGA-II)',
-        value: algorithms.AlgorithmType.NSGAII,
-    }
-]
-
 export interface IAlgorithmSwarmConfigBase {
     iterations: number
     population: number,
+    seed?: number,
+    constraintHandling?: string,
     type: 'Swarm'
 }
 
 export interface IAlgorithmBiologyConfigBase {
     generation: number
     chromosome: number,
+    seed?: number,
+    constraintHandling?: string,
     type: 'Biology'
 }
 
 class AlgorithmStore {
+    // every algorithm registered in the backend, see AvailableAlgorithms
+    registered = $state<algorithms.Registration[]>([])
+
+    // configs of the algorithms without a dedicated config store
+    schemaConfigs = $state<Record<string, ISchemaConfig>>({})
+
     validAlgorithmsList = $derived.by<AlgorithmWithLabel[]>(() => {
-        if (objectiveStore.objectives.selectedObjectives.length == 1) {
-            return SingleList
-        } else if (objectiveStore.objectives.selectedObjectives.length >= 1) {
-            return MultiList
-        } else {
+        const numberOfObjectives = objectiveStore.objectives.selectedObjectives.length
+        if (numberOfObjectives == 0) {
             return []
         }
+
+        return this.registered
+            .filter(r => r.schema.multiObjective === numberOfObjectives > 1)
+            .map(r => ({label: r.schema.label, value: r.name}))
     })
 
+    load = async () => {
+        if (this.registered.length > 0) {
+            return
+        }
+
+        this.registered = await AvailableAlgorithms()
+        for (const r of this.registered) {
+            this.schemaConfigs[r.name] = Object.fromEntries(
+                r.schema.parameters.map(p => [p.key, p.options?.length ? p.options[0] : p.default])
+            )
+        }
+    }
+
+    // the valid constraint handlings, shared by every algorithm
+    constraintHandlings = $derived.by<string[]>(() => {
+        const parameter = this.registered[0]?.schema.parameters.find(p => p.key === 'constraintHandling')
+        return parameter?.options ?? []
+    })
+
+    getRegistration = (algo: algorithms.AlgorithmType) => {
+        return this.registered.find(r => r.name === algo)
+    }
+
+    // schemaConfig returns the config of an algorithm without a dedicated
+    // config store, filled with the defaults of its schema by load
+    schemaConfig = (registration: algorithms.Registration): ISchemaConfig => {
+        return this.schemaConfigs[registration.name]
+    }
+
     selectedAlgorithm = $state<AlgorithmWithLabel>()
 
     resetSelection = () => {
@@ -105,6 +117,8 @@ class AlgorithmStore {
                 return mopsoConfig as AlgorithmConfigMap[T]
             case algorithms.AlgorithmType.NSGAII:
                 return nsgaiiConfig as AlgorithmConfigMap[T]
+            default:
+                return this.schemaConfig(this.getRegistration(algo)!) as AlgorithmConfigMap[T]
         }
     }
 
@@ -1,16 +1,20 @@
-import {data} from "$lib/wailsjs/go/models";
+import {constraints, data} from "$lib/wailsjs/go/models";
+import {AvailableConstraints} from "$lib/wailsjs/go/main/App";
 import {
   coverInCraneRadiusConfig,
   type ICoverInCraneRadiusConfig,
   type IInclusiveZoneConfig, inclusiveZoneConfig,
   type IOutOfBoundConfig,
-  type IOverlapConfig, outOfBoundConfig, overlapConfig
+  type IOverlapConfig, outOfBoundConfig, overlapConfig,
+  type ISiteBoundaryConfig, siteBoundaryConfig,
+  type IExclusiveZoneConfig, exclusiveZoneConfig,
+  type IDistanceConfig, distanceConfig
 } from "$lib/stores/constraints";
-import {objectiveStore} from "$lib/stores/objectives.svelte";
 import {type ISizeConfig, sizeConfig} from "$lib/stores/constraints/size.svelte";
 import {problemStore} from "$lib/stores/problem.svelte";
 
-type IConfigType = IOutOfBoundConfig | IOverlapConfig | ICoverInCraneRadiusConfig | IInclusiveZoneConfig | ISizeConfig
+type IConfigType = IOutOfBoundConfig | IOverlapConfig | ICoverInCraneRadiusConfig | IInclusiveZoneConfig | ISizeConfig | ISiteBoundaryConfig | IExclusiveZoneConfig
+  | IDistanceConfig
 
 interface IConstraint {
   selectedConstraints: {
@@ -28,9 +32,12 @@ export interface IConstraintOptions {
 export type ConstraintConfigMap = {
   [data.ConstraintType.CoverInCraneRadius]: ICoverInCraneRadiusConfig;
   [data.ConstraintType.InclusiveZone]: IInclusiveZoneConfig;
+  [data.ConstraintType.ExclusiveZone]: IExclusiveZoneConfig;
   [data.ConstraintType.Overlap]: IOverlapConfig;
   [data.ConstraintType.OutOfBound]: IOutOfBoundConfig;
   [data.ConstraintType.Size]: ISizeConfig;
+  [data.ConstraintType.SiteBoundary]: ISiteBoundaryConfig;
+  [data.ConstraintType.Distance]: IDistanceConfig;
 }
 
 class ConstraintsStore {
@@ -39,51 +46,36 @@ class ConstraintsStore {
   })
 
 
-  constraintList = $state<IConstraintOptions[]>([
-    {
-      label: 'Out of boundary',
-      value: data.ConstraintType.OutOfBound,
-      isChecked: false,
-    },
-    {
-      label: 'Overlap',
-      value: data.ConstraintType.Overlap,
-      isChecked: false,
-    },
-    {
-      label: 'Cover in crane radius',
-      value: data.ConstraintType.CoverInCraneRadius,
-      isChecked: false,
-    },
-    {
-      label: 'Inclusive zone',
-      value: data.ConstraintType.InclusiveZone,
-      isChecked: false,
-    },
-    {
-      label: 'Size',
-      value: data.ConstraintType.Size,
-      isChecked: false,
-    }
-  ])
+  // every constraint registered in the backend, see AvailableConstraints
+  registered = $state<constraints.Registration[]>([])
 
-  validConstraintList = $derived.by<IConstraintOptions[]>(() => {
-    if (problemStore.selectedProblem?.value === data.ProblemName.PredeterminedConstructionLayout) {
-      let constraints = []
-      constraints.push(this.constraintList.find(cons => cons.value === data.ConstraintType.Size)!)
+  constraintList = $state<IConstraintOptions[]>([])
 
-      return constraints
-    } else {
-      // filtering the "cover in crane radius"
-      const selectedObjectives = objectiveStore.objectives.selectedObjectives.map(selectedObjective => selectedObjective.objectiveType)
-      return this.constraintList
-          .filter(cons => cons.value !== data.ConstraintType.Size) // fitering the Size constraint ( for predetermined problem)
-          
+  // the constraints that apply to the selected problem
+  validConstraintList = $derived.by<IConstraintOptions[]>(() => {
+    const problemName = problemStore.selectedProblem?.value
+    if (!problemName) {
+      return []
     }
 
-
+    return this.constraintList.filter(cons =>
+      this.registered.find(r => r.name === cons.value)?.problems.includes(problemName)
+    )
   })
 
+  load = async () => {
+    if (this.registered.length > 0) {
+      return
+    }
+
+    this.registered = await AvailableConstraints()
+    this.constraintList = this.registered.map(r => ({
+      label: r.schema.label,
+      value: r.name,
+      isChecked: false,
+    }))
+  }
+
   clearConstraint = () => {
     this.constraints.selectedConstraints.length = 0
     this.validConstraintList.forEach(constraint => {
@@ -113,10 +105,16 @@ class ConstraintsStore {
         return overlapConfig as ConstraintConfigMap[T]
       case data.ConstraintType.InclusiveZone:
         return inclusiveZoneConfig as ConstraintConfigMap[T]
+      case data.ConstraintType.ExclusiveZone:
+        return exclusiveZoneConfig as ConstraintConfigMap[T]
       case data.ConstraintType.CoverInCraneRadius:
         return coverInCraneRadiusConfig as ConstraintConfigMap[T]
       case data.ConstraintType.Size:
         return sizeConfig as ConstraintConfigMap[T]
+      case data.ConstraintType.SiteBoundary:
+        return siteBoundaryConfig as ConstraintConfigMap[T]
+      case data.ConstraintType.Distance:
+        return distanceConfig as ConstraintConfigMap[T]
     }
   }
 
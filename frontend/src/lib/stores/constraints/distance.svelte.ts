@@ -0,0 +1,21 @@
+
+export const distanceMeasures = [
+  {label: "Centre to centre", value: "centre"},
+  {label: "Edge to edge", value: "edge"},
+]
+
+export interface IDistanceConfig {
+  AlphaDistancePenalty: number
+  PowerDifferencePenalty: number
+  RulesFilePath: string
+  // measure of the rules that do not name one
+  Measure: string
+}
+
+
+export const distanceConfig = $state<IDistanceConfig>({
+  AlphaDistancePenalty: 20000,
+  PowerDifferencePenalty: 1,
+  RulesFilePath: '',
+  Measure: 'centre',
+})
@@ -0,0 +1,21 @@
+export interface IExclusiveZone {
+  Name: string;
+  BuildingNames: string;
+  Size: number;
+  // phases counted from 1, every phase when empty
+  Phases: string;
+  Id: string;
+}
+
+export interface IExclusiveZoneConfig {
+  AlphaExclusiveZonePenalty: number,
+  PowerDifferencePenalty: number
+  Zones: IExclusiveZone[]
+}
+
+
+export const exclusiveZoneConfig = $state<IExclusiveZoneConfig>({
+  AlphaExclusiveZonePenalty: 20000,
+  PowerDifferencePenalty: 1,
+  Zones: []
+})
@@ -1,4 +1,7 @@
 export * from './out-of-bound.svelte'
 export * from './overlap.svelte'
 export * from './inclusive-zone.svelte'
-export * from './cover-in-crane-radius.svelte'
\ No newline at end of file
+export * from './cover-in-crane-radius.svelte'
+export * from './site-boundary.svelte'
+export * from './exclusive-zone.svelte'
+export * from './distance.svelte'
\ No newline at end of file
@@ -1,12 +1,14 @@
-
-
 export interface IOverlapConfig {
   AlphaOverLapPenalty: number
   PowerDifferencePenalty: number
+  Clearance: number
+  ClearanceFilePath: string
 }
 
 
 export const overlapConfig = $state<IOverlapConfig>({
   AlphaOverLapPenalty: 20000,
   PowerDifferencePenalty: 1,
-})
\ No newline at end of file
+  Clearance: 0,
+  ClearanceFilePath: '',
+})
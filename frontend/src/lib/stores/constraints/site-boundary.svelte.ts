@@ -0,0 +1,13 @@
+
+export interface ISiteBoundaryConfig {
+  AlphaSiteBoundaryPenalty: number
+  PowerDifferencePenalty: number
+  SiteFilePath: string
+}
+
+
+export const siteBoundaryConfig = $state<ISiteBoundaryConfig>({
+  AlphaSiteBoundaryPenalty: 20000,
+  PowerDifferencePenalty: 1,
+  SiteFilePath: '',
+})
@@ -1,15 +1,17 @@
 import {data} from "$lib/wailsjs/go/models";
+import {AvailableObjectives} from "$lib/wailsjs/go/main/App";
 import {
   hoistingConfig,
   type IHoistingConfig,
   type IRiskConfig,
   type ISafetyConfig, type ISafetyHazardConfig, type ITransportCostConfig, riskConfig,
-  safetyConfig, safetyHazardConfig, transportCostConfig
+  safetyConfig, safetyHazardConfig, transportCostConfig, type IRelocationCostConfig, relocationCostConfig
 } from "$lib/stores/objectives";
 import {constructionCostConfig, type IConstructionCostConfig} from "$lib/stores/objectives/construction-cost.svelte";
 
 type IConfigType = IHoistingConfig | IRiskConfig | ISafetyConfig
   | ITransportCostConfig | ISafetyHazardConfig | IConstructionCostConfig
+  | IRelocationCostConfig
 
 interface IObjectives {
   selectedObjectives: {
@@ -31,6 +33,7 @@ export type ObjectiveConfigMap = {
   [data.ObjectiveType.SafetyHazardObjective]: ISafetyHazardConfig;
   [data.ObjectiveType.TransportCostObjective]: ITransportCostConfig;
   [data.ObjectiveType.ConstructionCostObjective]: IConstructionCostConfig;
+  [data.ObjectiveType.RelocationCostObjective]: IRelocationCostConfig;
 }
 
 class ObjectiveStore {
@@ -39,38 +42,21 @@ class ObjectiveStore {
   })
 
 
-  objectiveList = $state<IOptions[]>([
-    {
-      label: 'Risk',
-      value: data.ObjectiveType.RiskObjective,
-      isChecked: false,
-    },
-    {
-      label: 'Hoisting',
-      value: data.ObjectiveType.HoistingObjective,
-      isChecked: false,
-    },
-    {
-      label: 'Safety',
-      value: data.ObjectiveType.SafetyObjective,
-      isChecked: false,
-    },
-    {
-      label: 'Safety Hazard',
-      value: data.ObjectiveType.SafetyHazardObjective,
-      isChecked: false,
-    },
-    {
-      label: 'Transportation Cost',
-      value: data.ObjectiveType.TransportCostObjective,
-      isChecked: false,
-    },
-    {
-      label: 'Construction Cost',
-      value: data.ObjectiveType.ConstructionCostObjective,
-      isChecked: false,
+  // every objective registered in the backend, see AvailableObjectives
+  objectiveList = $state<IOptions[]>([])
+
+  load = async () => {
+    if (this.objectiveList.length > 0) {
+      return
     }
-  ])
+
+    const registered = await AvailableObjectives()
+    this.objectiveList = registered.map(r => ({
+      label: r.schema.label,
+      value: r.name,
+      isChecked: false,
+    }))
+  }
 
   selectObjectiveOption = $state<IOptions>()
 
@@ -109,6 +95,8 @@ class ObjectiveStore {
         return transportCostConfig as ObjectiveConfigMap[T]
       case data.ObjectiveType.ConstructionCostObjective:
         return constructionCostConfig as ObjectiveConfigMap[T]
+      case data.ObjectiveType.RelocationCostObjective:
+        return relocationCostConfig as ObjectiveConfigMap[T]
     }
   }
 
@@ -0,0 +1,5 @@
+export const distanceMetrics = [
+  {label: "Euclidean", value: "euclidean"},
+  {label: "Manhattan", value: "manhattan"},
+  {label: "Road network", value: "network"},
+]
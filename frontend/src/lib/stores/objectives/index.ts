@@ -3,4 +3,6 @@ export * from './risk.svelte'
 export * from './safety.svelte'
 export * from './safety-hazard.svelte'
 export * from './transport-cost.svelte'
-export * from './construction-cost.svelte'
\ No newline at end of file
+export * from './construction-cost.svelte'
+export * from './relocation-cost.svelte'
+export * from './distance-metric.svelte'
@@ -0,0 +1,10 @@
+export interface IRelocationCostConfig {
+  MoveCostsFilePath: string;
+  AlphaRelocationPenalty: number,
+}
+
+
+export const relocationCostConfig = $state<IRelocationCostConfig>({
+  AlphaRelocationPenalty: 100,
+  MoveCostsFilePath: '',
+})
@@ -1,10 +1,15 @@
 export interface ISafetyConfig {
     SafetyProximityMatrixFilePath: string;
     AlphaSafetyPenalty:        number,
+    DistanceMetric: string,
+    // polylines of the site roads, for the network metric
+    RoadNetworkFilePath: string,
 }
 
 
 export const safetyConfig = $state<ISafetyConfig>({
     AlphaSafetyPenalty: 100,
     SafetyProximityMatrixFilePath: '',
+    DistanceMetric: 'euclidean',
+    RoadNetworkFilePath: '',
 })
\ No newline at end of file
@@ -1,10 +1,15 @@
 export interface ITransportCostConfig {
   InteractionMatrixFilePath: string;
   AlphaTCPenalty: number,
+  DistanceMetric: string,
+  // polylines of the site roads, for the network metric
+  RoadNetworkFilePath: string,
 }
 
 
 export const transportCostConfig = $state<ITransportCostConfig>({
   AlphaTCPenalty: 100,
   InteractionMatrixFilePath: '',
+  DistanceMetric: 'euclidean',
+  RoadNetworkFilePath: '',
 })
\ No newline at end of file
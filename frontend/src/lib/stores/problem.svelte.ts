@@ -4,7 +4,7 @@ import {
   type IGridConfig,
   type IPredeterminedConfig, predeterminedProblemConfig
 } from "$lib/stores/problems";
-import {data} from "$lib/wailsjs/go/models";
+import {data, objectives} from "$lib/wailsjs/go/models";
 import {objectiveStore} from "$lib/stores/objectives.svelte";
 
 export interface ProblemWithLabel {
@@ -33,9 +33,30 @@ export type ProblemConfigMap = {
   [data.ProblemName.PredeterminedConstructionLayout]: IPredeterminedConfig;
 }
 
+// penalty schedules of objectives.PenaltyScheduleType, the default first
+export const penaltySchedules = [
+  {label: "Static", value: "static"},
+  {label: "Death", value: "death"},
+  {label: "Dynamic", value: "dynamic"},
+  {label: "Annealing", value: "annealing"},
+  {label: "Self-adaptive", value: "self-adaptive"},
+]
+
 class ProblemStore {
   selectedProblem = $state<ProblemWithLabel>()
 
+  // zero parameters take the defaults of the backend
+  penaltySchedule = $state<objectives.PenaltyScheduleConfig>(new objectives.PenaltyScheduleConfig({
+    type: "static",
+    c: 0.5,
+    alpha: 2,
+    initialTemperature: 1,
+    coolingRate: 0.95,
+    targetFeasibleRatio: 0.5,
+    adaptationRate: 1.5,
+    tolerance: 1,
+  }))
+
   validProblemList = $derived.by<ProblemWithLabel[]>(() => {
     if (objectiveStore.objectives.selectedObjectives.find(
         o => o.objectiveType === data.ObjectiveType.ConstructionCostObjective)) {
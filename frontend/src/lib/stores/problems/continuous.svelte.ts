@@ -13,7 +13,10 @@ export interface IContinuousConfig {
   phasesFilePath: {
     label: ContinuousFile,
     value: string
-  }
+  };
+  repairLayout: boolean;
+  freeRotation: boolean;
+  dynamicLayout: boolean;
 }
 
 
@@ -28,4 +31,7 @@ export const continuousProblemConfig = $state<IContinuousConfig>({
     label: ContinuousFile.Phase,
     value: ''
   },
+  repairLayout: false,
+  freeRotation: false,
+  dynamicLayout: false,
 })
\ No newline at end of file
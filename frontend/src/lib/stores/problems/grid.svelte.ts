@@ -17,6 +17,8 @@ export interface IGridConfig {
     value: string
   };
   gridSize: number;
+  repairLayout: boolean;
+  dynamicLayout: boolean;
 }
 
 
@@ -32,4 +34,6 @@ export const gridProblemConfig = $state<IGridConfig>({
     value: ''
   },
   gridSize: 1,
+  repairLayout: false,
+  dynamicLayout: false,
 })
\ No newline at end of file
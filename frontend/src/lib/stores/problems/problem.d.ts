@@ -7,6 +7,7 @@ export interface Coordinate {
 export interface Facility {
     Coordinate: Coordinate
     Rotation:   boolean
+    Angle:      number
     Length:     number
     Width:      number
     IsFixed:    boolean
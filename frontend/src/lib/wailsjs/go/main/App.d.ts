@@ -1,12 +1,21 @@
 // Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
 // This file is automatically generated. DO NOT EDIT
 import {main} from '../models';
+import {algorithms} from '../models';
+import {constraints} from '../models';
+import {objectives} from '../models';
 
 export function AddConstraints(arg1:Array<main.ConstraintInput>):Promise<void>;
 
 export function AlgorithmInfo():Promise<any>;
 
-export function ConstraintsInfo():Promise<main.ConstraintsConfigResponse>;
+export function AvailableAlgorithms():Promise<Array<algorithms.Registration>>;
+
+export function AvailableConstraints():Promise<Array<constraints.Registration>>;
+
+export function AvailableObjectives():Promise<Array<objectives.Registration>>;
+
+export function ConstraintsInfo():Promise<Array<constraints.Info>>;
 
 export function CreateAlgorithm(arg1:main.AlgorithmInput):Promise<void>;
 
@@ -14,16 +23,24 @@ export function CreateObjectives(arg1:Array<main.ObjectiveInput>):Promise<void>;
 
 export function CreateProblem(arg1:main.ProblemInput):Promise<void>;
 
-export function ObjectivesInfo():Promise<main.ObjectiveConfigResponse>;
+export function LoadProject():Promise<string>;
+
+export function ObjectivesInfo():Promise<Array<objectives.Info>>;
 
 export function ProblemInfo():Promise<any>;
 
 export function Result():Promise<any>;
 
+export function ResumeAlgorithm():Promise<void>;
+
 export function RunAlgorithm():Promise<void>;
 
 export function SaveChartImage(arg1:string):Promise<string>;
 
 export function SaveFile(arg1:main.CommandType):Promise<void>;
 
+export function SaveProject():Promise<string>;
+
 export function SelectFile():Promise<string>;
+
+export function StopAlgorithm():Promise<any>;
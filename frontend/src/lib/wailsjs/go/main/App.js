@@ -10,6 +10,18 @@ export function AlgorithmInfo() {
   return window['go']['main']['App']['AlgorithmInfo']();
 }
 
+export function AvailableAlgorithms() {
+  return window['go']['main']['App']['AvailableAlgorithms']();
+}
+
+export function AvailableConstraints() {
+  return window['go']['main']['App']['AvailableConstraints']();
+}
+
+export function AvailableObjectives() {
+  return window['go']['main']['App']['AvailableObjectives']();
+}
+
 export function ConstraintsInfo() {
   return window['go']['main']['App']['ConstraintsInfo']();
 }
@@ -26,6 +38,10 @@ export function CreateProblem(arg1) {
   return window['go']['main']['App']['CreateProblem'](arg1);
 }
 
+export function LoadProject() {
+  return window['go']['main']['App']['LoadProject']();
+}
+
 export function ObjectivesInfo() {
   return window['go']['main']['App']['ObjectivesInfo']();
 }
@@ -38,6 +54,10 @@ export function Result() {
   return window['go']['main']['App']['Result']();
 }
 
+export function ResumeAlgorithm() {
+  return window['go']['main']['App']['ResumeAlgorithm']();
+}
+
 export function RunAlgorithm() {
   return window['go']['main']['App']['RunAlgorithm']();
 }
@@ -50,6 +70,14 @@ export function SaveFile(arg1) {
   return window['go']['main']['App']['SaveFile'](arg1);
 }
 
+export function SaveProject() {
+  return window['go']['main']['App']['SaveProject']();
+}
+
 export function SelectFile() {
   return window['go']['main']['App']['SelectFile']();
 }
+
+export function StopAlgorithm() {
+  return window['go']['main']['App']['StopAlgorithm']();
+}
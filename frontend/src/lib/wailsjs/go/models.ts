@@ -1,13 +1,103 @@
 export namespace algorithms {
 	
 	export enum AlgorithmType {
-	    GeneticAlgorithm = "GA",
 	    AHA = "AHA",
-	    MOAHA = "MOAHA",
+	    GeneticAlgorithm = "GA",
 	    GWO = "GWO",
-	    oMOAHA = "oMOAHA",
+	    MOAHA = "MOAHA",
+	    MOGWO = "MOGWO",
 	    MOPSO = "MOPSO",
+	    MOPSOReimpl = "MOPSO-Reimpl",
 	    NSGAII = "NSGA-II",
+	    oMOAHA = "oMOAHA",
+	}
+	export class Parameter {
+	    key: string;
+	    label: string;
+	    integer: boolean;
+	    default: number;
+	    options?: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new Parameter(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.key = source["key"];
+	        this.label = source["label"];
+	        this.integer = source["integer"];
+	        this.default = source["default"];
+	        this.options = source["options"];
+	    }
+	}
+	export class ConfigSchema {
+	    label: string;
+	    tsName: string;
+	    multiObjective: boolean;
+	    parameters: Parameter[];
+	
+	    static createFrom(source: any = {}) {
+	        return new ConfigSchema(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.label = source["label"];
+	        this.tsName = source["tsName"];
+	        this.multiObjective = source["multiObjective"];
+	        this.parameters = this.convertValues(source["parameters"], Parameter);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class Registration {
+	    name: AlgorithmType;
+	    schema: ConfigSchema;
+	
+	    static createFrom(source: any = {}) {
+	        return new Registration(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.schema = this.convertValues(source["schema"], ConfigSchema);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
 	}
 
 }
@@ -31,6 +121,133 @@ export namespace conslay_predetermined {
 
 }
 
+export namespace constraints {
+	
+	export class Field {
+	    key: string;
+	    label: string;
+	    type: string;
+	    default?: any;
+	    fields?: Field[];
+	
+	    static createFrom(source: any = {}) {
+	        return new Field(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.key = source["key"];
+	        this.label = source["label"];
+	        this.type = source["type"];
+	        this.default = source["default"];
+	        this.fields = this.convertValues(source["fields"], Field);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class ConfigSchema {
+	    label: string;
+	    tsName: string;
+	    fields: Field[];
+	
+	    static createFrom(source: any = {}) {
+	        return new ConfigSchema(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.label = source["label"];
+	        this.tsName = source["tsName"];
+	        this.fields = this.convertValues(source["fields"], Field);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class Info {
+	    name: data.ConstraintType;
+	    label: string;
+	    config: any;
+	
+	    static createFrom(source: any = {}) {
+	        return new Info(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.label = source["label"];
+	        this.config = source["config"];
+	    }
+	}
+	export class Registration {
+	    name: data.ConstraintType;
+	    schema: ConfigSchema;
+	    problems: data.ProblemName[];
+	
+	    static createFrom(source: any = {}) {
+	        return new Registration(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.schema = this.convertValues(source["schema"], ConfigSchema);
+	        this.problems = source["problems"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+
+}
+
 export namespace data {
 	
 	export enum ProblemName {
@@ -39,19 +256,23 @@ export namespace data {
 	    PredeterminedConstructionLayout = "Predetermined Construction Layout",
 	}
 	export enum ObjectiveType {
-	    SafetyObjective = "Safety Objective",
+	    ConstructionCostObjective = "Construction Cost Objective",
 	    HoistingObjective = "Hoisting Objective",
+	    RelocationCostObjective = "Relocation Cost Objective",
 	    RiskObjective = "Risk Objective",
-	    TransportCostObjective = "Transport Cost Objective",
 	    SafetyHazardObjective = "Safety Hazard Objective",
-	    ConstructionCostObjective = "Construction Cost Objective",
+	    SafetyObjective = "Safety Objective",
+	    TransportCostObjective = "Transport Cost Objective",
 	}
 	export enum ConstraintType {
+	    CoverInCraneRadius = "CoverInCraneRadius",
 	    Overlap = "Overlap",
 	    OutOfBound = "OutOfBound",
-	    CoverInCraneRadius = "CoverInCraneRadius",
 	    InclusiveZone = "InclusiveZone",
+	    ExclusiveZone = "ExclusiveZone",
+	    Distance = "Distance",
 	    Size = "Size",
+	    SiteBoundary = "SiteBoundary",
 	}
 
 }
@@ -65,10 +286,27 @@ export namespace main {
 	export enum EventType {
 	    ProgressEvent = "ProgressEvent",
 	    ResultEvent = "ResultEvent",
+	    StoppedEvent = "StoppedEvent",
+	    ErrorEvent = "ErrorEvent",
+	}
+	export class CheckpointInput {
+	    filePath: string;
+	    every: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new CheckpointInput(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.filePath = source["filePath"];
+	        this.every = source["every"];
+	    }
 	}
 	export class AlgorithmInput {
 	    algorithmName: algorithms.AlgorithmType;
 	    algorithmConfig: any;
+	    checkpoint?: CheckpointInput;
 	
 	    static createFrom(source: any = {}) {
 	        return new AlgorithmInput(source);
@@ -78,7 +316,26 @@ export namespace main {
 	        if ('string' === typeof source) source = JSON.parse(source);
 	        this.algorithmName = source["algorithmName"];
 	        this.algorithmConfig = source["algorithmConfig"];
+	        this.checkpoint = this.convertValues(source["checkpoint"], CheckpointInput);
 	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
 	}
 	export class ConstraintInput {
 	    constraintName: data.ConstraintType;
@@ -94,48 +351,6 @@ export namespace main {
 	        this.constraintConfig = source["constraintConfig"];
 	    }
 	}
-	export class ConstraintsConfigResponse {
-	    outOfBoundary?: any;
-	    overlap?: any;
-	    coverInCraneRadius?: any;
-	    inclusiveZone?: any;
-	    size?: any;
-	
-	    static createFrom(source: any = {}) {
-	        return new ConstraintsConfigResponse(source);
-	    }
-	
-	    constructor(source: any = {}) {
-	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.outOfBoundary = source["outOfBoundary"];
-	        this.overlap = source["overlap"];
-	        this.coverInCraneRadius = source["coverInCraneRadius"];
-	        this.inclusiveZone = source["inclusiveZone"];
-	        this.size = source["size"];
-	    }
-	}
-	export class ObjectiveConfigResponse {
-	    risk?: any;
-	    hoisting?: any;
-	    safety?: any;
-	    transportCost?: any;
-	    safetyHazard?: any;
-	    constructionCost?: any;
-	
-	    static createFrom(source: any = {}) {
-	        return new ObjectiveConfigResponse(source);
-	    }
-	
-	    constructor(source: any = {}) {
-	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.risk = source["risk"];
-	        this.hoisting = source["hoisting"];
-	        this.safety = source["safety"];
-	        this.transportCost = source["transportCost"];
-	        this.safetyHazard = source["safetyHazard"];
-	        this.constructionCost = source["constructionCost"];
-	    }
-	}
 	export class ObjectiveInput {
 	    objectiveName: data.ObjectiveType;
 	    objectiveConfig: any;
@@ -160,6 +375,10 @@ export namespace main {
 	    numberOfLocations?: number;
 	    numberOfFacilities?: number;
 	    fixedFacilities?: conslay_predetermined.LocFac[];
+	    penaltySchedule?: objectives.PenaltyScheduleConfig;
+	    repairLayout?: boolean;
+	    freeRotation?: boolean;
+	    dynamicLayout?: boolean;
 	
 	    static createFrom(source: any = {}) {
 	        return new ProblemInput(source);
@@ -176,6 +395,10 @@ export namespace main {
 	        this.numberOfLocations = source["numberOfLocations"];
 	        this.numberOfFacilities = source["numberOfFacilities"];
 	        this.fixedFacilities = this.convertValues(source["fixedFacilities"], conslay_predetermined.LocFac);
+	        this.penaltySchedule = this.convertValues(source["penaltySchedule"], objectives.PenaltyScheduleConfig);
+	        this.repairLayout = source["repairLayout"];
+	        this.freeRotation = source["freeRotation"];
+	        this.dynamicLayout = source["dynamicLayout"];
 	    }
 	
 		convertValues(a: any, classs: any, asMap: boolean = false): any {
@@ -199,3 +422,153 @@ export namespace main {
 
 }
 
+export namespace objectives {
+	
+	export class Field {
+	    key: string;
+	    label: string;
+	    type: string;
+	    default?: any;
+	    fields?: Field[];
+	
+	    static createFrom(source: any = {}) {
+	        return new Field(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.key = source["key"];
+	        this.label = source["label"];
+	        this.type = source["type"];
+	        this.default = source["default"];
+	        this.fields = this.convertValues(source["fields"], Field);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class ConfigSchema {
+	    label: string;
+	    tsName: string;
+	    fields: Field[];
+	
+	    static createFrom(source: any = {}) {
+	        return new ConfigSchema(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.label = source["label"];
+	        this.tsName = source["tsName"];
+	        this.fields = this.convertValues(source["fields"], Field);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class Info {
+	    name: data.ObjectiveType;
+	    label: string;
+	    config: any;
+	
+	    static createFrom(source: any = {}) {
+	        return new Info(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.label = source["label"];
+	        this.config = source["config"];
+	    }
+	}
+	export class Registration {
+	    name: data.ObjectiveType;
+	    schema: ConfigSchema;
+	
+	    static createFrom(source: any = {}) {
+	        return new Registration(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.schema = this.convertValues(source["schema"], ConfigSchema);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class PenaltyScheduleConfig {
+	    type: string;
+	    c: number;
+	    alpha: number;
+	    initialTemperature: number;
+	    coolingRate: number;
+	    targetFeasibleRatio: number;
+	    adaptationRate: number;
+	    tolerance: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new PenaltyScheduleConfig(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.type = source["type"];
+	        this.c = source["c"];
+	        this.alpha = source["alpha"];
+	        this.initialTemperature = source["initialTemperature"];
+	        this.coolingRate = source["coolingRate"];
+	        this.targetFeasibleRatio = source["targetFeasibleRatio"];
+	        this.adaptationRate = source["adaptationRate"];
+	        this.tolerance = source["tolerance"];
+	    }
+	}
+}
+
@@ -25,6 +25,8 @@ export interface ResultLocation {
   Penalty: Penalty
   Cranes: Crane[]
   Phases: string[][]
+  // the locations in every phase of a dynamic layout
+  PhaseLocations?: MapLocation[] | null
 }
 
 export interface ResultLocationWithId extends ResultLocation {
@@ -1,6 +1,7 @@
 package aha
 
 import (
+	"context"
 	"fmt"
 	"github.com/schollz/progressbar/v3"
 	"golang-moaha-construction/internal/algorithms"
@@ -8,7 +9,7 @@ import (
 	"golang-moaha-construction/internal/objectives"
 	"golang-moaha-construction/internal/util"
 	"math"
-	"math/rand"
+	"math/rand/v2"
 	"sync"
 )
 
@@ -19,17 +20,39 @@ const (
 )
 
 type AHAAlgorithm struct {
-	NumberOfAgents    int
-	NumberOfIter      int
-	Agents            []*objectives.Result
-	BestResult        *objectives.Result
-	Convergence       []float64
-	ObjectiveFunction objectives.Problem
+	NumberOfAgents     int
+	NumberOfIter       int
+	Agents             []*objectives.Result
+	BestResult         *objectives.Result
+	Convergence        []float64
+	ObjectiveFunction  objectives.Problem
+	Seed               int64
+	ConstraintHandling objectives.ConstraintHandling
+
+	algorithms.Checkpoints[state]
+
+	rng *rand.Rand
+	src *rand.PCG
+}
+
+// state is what a checkpoint holds to continue a run.
+type state struct {
+	Iteration   int
+	Agents      []*objectives.Result
+	BestResult  *objectives.Result
+	Convergence []float64
+	VisitTable  [][]float64
+	Penalty     objectives.PenaltyState
+	Rand        []byte
 }
 
 type Config struct {
-	NumberOfAgents int
-	NumberOfIter   int
+	NumberOfAgents int `json:"population"`
+	NumberOfIter   int `json:"iterations"`
+	// Seed makes the run reproducible, 0 picks a random seed
+	Seed int64 `json:"seed"`
+	// ConstraintHandling is how results with violated constraints compare
+	ConstraintHandling objectives.ConstraintHandling `json:"constraintHandling"`
 }
 
 func Create(
@@ -41,53 +64,69 @@ func Create(
 		return nil, objectives.ErrInvalidNumberOfObjectives
 	}
 
+	seed := configs.Seed
+	if seed == 0 {
+		seed = util.NewSeed()
+	}
+
 	return &AHAAlgorithm{
-		NumberOfAgents:    configs.NumberOfAgents,
-		NumberOfIter:      configs.NumberOfIter,
-		ObjectiveFunction: problem,
+		NumberOfAgents:     configs.NumberOfAgents,
+		NumberOfIter:       configs.NumberOfIter,
+		ObjectiveFunction:  problem,
+		Seed:               seed,
+		ConstraintHandling: configs.ConstraintHandling,
 	}, nil
 }
 
 func (a *AHAAlgorithm) reset() {
 	a.Agents = make([]*objectives.Result, a.NumberOfAgents)
 	a.Convergence = make([]float64, a.NumberOfIter)
+	a.rng, a.src = util.NewRandSource(a.Seed)
 }
 
 func (a *AHAAlgorithm) Type() data.TypeProblem {
 	return data.Single
 }
 
-func (a *AHAAlgorithm) Run() error {
+func (a *AHAAlgorithm) Run(ctx context.Context) error {
 	dimensions := a.ObjectiveFunction.GetDimension()
 
-	a.reset()
-
-	// initialization
-	a.initialization()
-	l := 0
+	l, visitTable, err := a.start()
+	if err != nil {
+		return err
+	}
 
 	bar := progressbar.Default(int64(a.NumberOfIter))
+	_ = bar.Set(l)
 	//var wg sync.WaitGroup
 
-	visitTable := initializeNMMatrix(a.NumberOfAgents, a.NumberOfAgents)
-
 	for l < a.NumberOfIter {
+		if err := algorithms.Interrupted(ctx, a.ObjectiveFunction); err != nil {
+			// keep the convergence of the completed iterations only
+			a.Convergence = a.Convergence[:l]
+			return err
+		}
+
+		if a.ObjectiveFunction.UpdatePenalty(l, a.NumberOfIter, a.Agents) {
+			objectives.Rescore(a.ObjectiveFunction, a.Agents, []*objectives.Result{a.BestResult})
+		}
+
 		// direct vector
 		directVector := initializeNMMatrix(a.NumberOfAgents, dimensions)
 
 		//wg.Add(a.NumberOfAgents)
 		for agentIdx := range a.Agents {
 
-			r := rand.Float64()
+			r := a.rng.Float64()
 
 			if r < 1.0/3.0 {
 				// diagonal flight
-				randDim := util.RandN(dimensions)
+				randDim := util.RandN(a.rng, dimensions)
 				randNum := 0
 				if dimensions > 3 {
-					randNum = rand.Intn(dimensions - 1)
+					randNum = a.rng.IntN(dimensions - 1)
 				} else {
-					randNum = rand.Intn(dimensions)
+					randNum = a.rng.IntN(dimensions)
 				}
 				for i := 0; i < randNum; i++ {
 					idx := randDim[i]
@@ -100,13 +139,13 @@ func (a *AHAAlgorithm) Run() error {
 				}
 			} else {
 				// axial flight
-				randNum := rand.Intn(dimensions)
+				randNum := a.rng.IntN(dimensions)
 				for i := 0; i < randNum; i++ {
 					directVector[agentIdx][i] = 1
 				}
 			}
 
-			r = rand.Float64()
+			r = a.rng.Float64()
 
 			if r < 0.5 {
 				// guided foraging
@@ -122,27 +161,28 @@ func (a *AHAAlgorithm) Run() error {
 
 		// migration foraging
 		if l%(a.NumberOfAgents*2) == 0 {
-			maxVal := -math.MaxFloat64
+			// the worst agent
 			maxIdx := 0
 			for i := range a.Agents {
-				if a.Agents[i].Value[0] > maxVal {
-					maxVal = a.Agents[i].Value[0]
+				if a.Agents[maxIdx].Better(a.Agents[i], a.ConstraintHandling) {
 					maxIdx = i
 				}
 			}
 
 			for i := range a.Agents[maxIdx].Position {
 				a.Agents[maxIdx].Position[i] =
-					a.ObjectiveFunction.GetLowerBound()[i] + rand.Float64()*
+					a.ObjectiveFunction.GetLowerBound()[i] + a.rng.Float64()*
 						(a.ObjectiveFunction.GetUpperBound()[i]-a.ObjectiveFunction.GetLowerBound()[i])
 			}
 
 			// evaluate
-			value, valuesWithKey, keys, penalty := a.ObjectiveFunction.Eval(a.Agents[maxIdx].Position)
+			value, valuesWithKey, keys, penalty, violations := a.ObjectiveFunction.Eval(a.ObjectiveFunction.Repair(a.Agents[maxIdx].Position))
 			a.Agents[maxIdx].Value = value
 			a.Agents[maxIdx].ValuesWithKey = valuesWithKey
 			a.Agents[maxIdx].Key = keys
 			a.Agents[maxIdx].Penalty = penalty
+			a.Agents[maxIdx].Violations = violations
+			a.Agents[maxIdx].Violation = objectives.TotalViolation(violations)
 
 			for i := range visitTable[maxIdx] {
 				visitTable[maxIdx][i] += 1
@@ -164,39 +204,49 @@ func (a *AHAAlgorithm) Run() error {
 		bar.Add(1)
 
 		l++
+		a.checkpoint(l, visitTable)
 	}
 
-	return nil
+	return a.ObjectiveFunction.Err()
 }
 
-func (a *AHAAlgorithm) RunWithChannel(doneChan chan<- struct{}, channel chan<- any) error {
+func (a *AHAAlgorithm) RunWithChannel(ctx context.Context, channel chan<- any) error {
 	dimensions := a.ObjectiveFunction.GetDimension()
 
-	a.reset()
+	l, visitTable, err := a.start()
+	if err != nil {
+		close(channel)
+		return err
+	}
 
-	// initialization
-	a.initialization()
-	l := 0
+	for l < a.NumberOfIter {
+		if err := algorithms.Interrupted(ctx, a.ObjectiveFunction); err != nil {
+			// keep the convergence of the completed iterations only
+			a.Convergence = a.Convergence[:l]
+			close(channel)
+			return err
+		}
 
-	visitTable := initializeNMMatrix(a.NumberOfAgents, a.NumberOfAgents)
+		if a.ObjectiveFunction.UpdatePenalty(l, a.NumberOfIter, a.Agents) {
+			objectives.Rescore(a.ObjectiveFunction, a.Agents, []*objectives.Result{a.BestResult})
+		}
 
-	for l < a.NumberOfIter {
 		// direct vector
 		directVector := initializeNMMatrix(a.NumberOfAgents, dimensions)
 
 		//wg.Add(a.NumberOfAgents)
 		for agentIdx := range a.Agents {
 
-			r := rand.Float64()
+			r := a.rng.Float64()
 
 			if r < 1.0/3.0 {
 				// diagonal flight
-				randDim := util.RandN(dimensions)
+				randDim := util.RandN(a.rng, dimensions)
 				randNum := 0
 				if dimensions > 3 {
-					randNum = rand.Intn(dimensions - 1)
+					randNum = a.rng.IntN(dimensions - 1)
 				} else {
-					randNum = rand.Intn(dimensions)
+					randNum = a.rng.IntN(dimensions)
 				}
 				for i := 0; i < randNum; i++ {
 					idx := randDim[i]
@@ -209,13 +259,13 @@ func (a *AHAAlgorithm) RunWithChannel(doneChan chan<- struct{}, channel chan<- a
 				}
 			} else {
 				// axial flight
-				randNum := rand.Intn(dimensions)
+				randNum := a.rng.IntN(dimensions)
 				for i := 0; i < randNum; i++ {
 					directVector[agentIdx][i] = 1
 				}
 			}
 
-			r = rand.Float64()
+			r = a.rng.Float64()
 
 			if r < 0.5 {
 				// guided foraging
@@ -231,26 +281,27 @@ func (a *AHAAlgorithm) RunWithChannel(doneChan chan<- struct{}, channel chan<- a
 
 		// migration foraging
 		if l%(a.NumberOfAgents*2) == 0 {
-			maxVal := -math.MaxFloat64
+			// the worst agent
 			maxIdx := 0
 			for i := range a.Agents {
-				if a.Agents[i].Value[0] > maxVal {
-					maxVal = a.Agents[i].Value[0]
+				if a.Agents[maxIdx].Better(a.Agents[i], a.ConstraintHandling) {
 					maxIdx = i
 				}
 			}
 
 			for i := range a.Agents[maxIdx].Position {
 				a.Agents[maxIdx].Position[i] =
-					a.ObjectiveFunction.GetLowerBound()[i] + rand.Float64()*
+					a.ObjectiveFunction.GetLowerBound()[i] + a.rng.Float64()*
 						(a.ObjectiveFunction.GetUpperBound()[i]-a.ObjectiveFunction.GetLowerBound()[i])
 			}
 
 			// evaluate
-			value, valuesWithKey, keys, penalty := a.ObjectiveFunction.Eval(a.Agents[maxIdx].Position)
+			value, valuesWithKey, keys, penalty, violations := a.ObjectiveFunction.Eval(a.ObjectiveFunction.Repair(a.Agents[maxIdx].Position))
 			a.Agents[maxIdx].Value = value
 			a.Agents[maxIdx].ValuesWithKey = valuesWithKey
 			a.Agents[maxIdx].Penalty = penalty
+			a.Agents[maxIdx].Violations = violations
+			a.Agents[maxIdx].Violation = objectives.TotalViolation(violations)
 			a.Agents[maxIdx].Key = keys
 
 			for i := range visitTable[maxIdx] {
@@ -281,11 +332,12 @@ func (a *AHAAlgorithm) RunWithChannel(doneChan chan<- struct{}, channel chan<- a
 		}
 
 		l++
+		a.checkpoint(l, visitTable)
 	}
 
 	close(channel)
 
-	return nil
+	return a.ObjectiveFunction.Err()
 }
 
 func (a *AHAAlgorithm) guidedForaging(visitTable [][]float64, directVector [][]float64, agentIdx int) {
@@ -308,11 +360,9 @@ func (a *AHAAlgorithm) guidedForaging(visitTable [][]float64, directVector [][]f
 	targetFoodIdx := 0
 	if len(maxValIdxs) >= 2 {
 		candidateIdx := maxValIdxs[0]
-		minFitness := a.Agents[candidateIdx].Value[0]
 
 		for i := 1; i < len(maxValIdxs); i++ {
-			if a.Agents[maxValIdxs[i]].Value[0] < minFitness {
-				minFitness = a.Agents[maxValIdxs[i]].Value[0]
+			if a.Agents[maxValIdxs[i]].Better(a.Agents[candidateIdx], a.ConstraintHandling) {
 				candidateIdx = maxValIdxs[i]
 			}
 		}
@@ -323,7 +373,7 @@ func (a *AHAAlgorithm) guidedForaging(visitTable [][]float64, directVector [][]f
 		panic("len(maxValIdxs) = 0")
 	}
 
-	r := rand.NormFloat64()
+	r := a.rng.NormFloat64()
 	newPos := make([]float64, a.ObjectiveFunction.GetDimension())
 	for i := 0; i < a.ObjectiveFunction.GetDimension(); i++ {
 		newPos[i] = a.Agents[targetFoodIdx].Position[i] + r*math.Round(directVector[agentIdx][i])*
@@ -333,14 +383,16 @@ func (a *AHAAlgorithm) guidedForaging(visitTable [][]float64, directVector [][]f
 	a.outOfBoundaries(newPos)
 
 	newAgent := a.Agents[agentIdx].CopyAgent()
-	value, valuesWithKey, keys, penalty := a.ObjectiveFunction.Eval(newPos)
+	value, valuesWithKey, keys, penalty, violations := a.ObjectiveFunction.Eval(a.ObjectiveFunction.Repair(newPos))
 	newAgent.Position = newPos
 	newAgent.Value = value
 	newAgent.Penalty = penalty
+	newAgent.Violations = violations
+	newAgent.Violation = objectives.TotalViolation(violations)
 	newAgent.Key = keys
 	newAgent.ValuesWithKey = valuesWithKey
 
-	if newAgent.Value[0] < a.Agents[agentIdx].Value[0] {
+	if newAgent.Better(a.Agents[agentIdx], a.ConstraintHandling) {
 		a.Agents[agentIdx] = newAgent.CopyAgent()
 
 		for i := range visitTable[agentIdx] {
@@ -369,7 +421,7 @@ func (a *AHAAlgorithm) guidedForaging(visitTable [][]float64, directVector [][]f
 }
 
 func (a *AHAAlgorithm) territoryForaging(visitTable [][]float64, directVector [][]float64, agentIdx int) {
-	r := rand.NormFloat64()
+	r := a.rng.NormFloat64()
 	newPos := make([]float64, a.ObjectiveFunction.GetDimension())
 	for i := 0; i < a.ObjectiveFunction.GetDimension(); i++ {
 		newPos[i] = a.Agents[agentIdx].Position[i] + r*math.Round(directVector[agentIdx][i])*a.Agents[agentIdx].Position[i]
@@ -378,14 +430,16 @@ func (a *AHAAlgorithm) territoryForaging(visitTable [][]float64, directVector []
 	a.outOfBoundaries(newPos)
 
 	newAgent := a.Agents[agentIdx].CopyAgent()
-	value, valuesWithKey, keys, penalty := a.ObjectiveFunction.Eval(newPos)
+	value, valuesWithKey, keys, penalty, violations := a.ObjectiveFunction.Eval(a.ObjectiveFunction.Repair(newPos))
 	newAgent.Position = newPos
 	newAgent.Value = value
 	newAgent.Penalty = penalty
+	newAgent.Violations = violations
+	newAgent.Violation = objectives.TotalViolation(violations)
 	newAgent.Key = keys
 	newAgent.ValuesWithKey = valuesWithKey
 
-	if newAgent.Value[0] < a.Agents[agentIdx].Value[0] {
+	if newAgent.Better(a.Agents[agentIdx], a.ConstraintHandling) {
 		a.Agents[agentIdx] = newAgent.CopyAgent()
 
 		for i := range visitTable[agentIdx] {
@@ -407,6 +461,60 @@ func (a *AHAAlgorithm) territoryForaging(visitTable [][]float64, directVector []
 	}
 }
 
+// LoadState refuses a state whose agents or visit table do not fit the
+// config, e.g. after the population size has changed.
+func (a *AHAAlgorithm) LoadState(encoded []byte) error {
+	return a.LoadStateChecked(encoded, func(s *state) error {
+		err := algorithms.CheckStateSize("agents", len(s.Agents), a.NumberOfAgents)
+		if err != nil {
+			return err
+		}
+
+		return algorithms.CheckVisitTable(s.VisitTable, a.NumberOfAgents)
+	})
+}
+
+// start resets the algorithm and either initializes a new population or
+// restores the state loaded by LoadState. It returns the iteration to continue
+// from and the visit table.
+func (a *AHAAlgorithm) start() (int, [][]float64, error) {
+	a.reset()
+
+	s := a.Resume()
+	if s == nil {
+		a.initialization()
+		return 0, initializeNMMatrix(a.NumberOfAgents, a.NumberOfAgents), nil
+	}
+
+	err := a.src.UnmarshalBinary(s.Rand)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	a.ObjectiveFunction.SetPenaltyState(s.Penalty)
+
+	a.Agents = s.Agents
+	a.BestResult = s.BestResult
+	copy(a.Convergence, s.Convergence)
+
+	return s.Iteration, s.VisitTable, nil
+}
+
+func (a *AHAAlgorithm) checkpoint(l int, visitTable [][]float64) {
+	a.Save(l, func() state {
+		rngState, _ := a.src.MarshalBinary()
+		return state{
+			Iteration:   l,
+			Agents:      a.Agents,
+			BestResult:  a.BestResult,
+			Convergence: a.Convergence[:l],
+			VisitTable:  visitTable,
+			Penalty:     a.ObjectiveFunction.PenaltyState(),
+			Rand:        rngState,
+		}
+	})
+}
+
 func (a *AHAAlgorithm) initialization() {
 
 	vals := make([]float64, a.ObjectiveFunction.NumberOfObjectives())
@@ -422,6 +530,9 @@ func (a *AHAAlgorithm) initialization() {
 		Value: vals,
 	}
 
+	// one stream per agent so the initial population does not depend on scheduling
+	rngs := util.SplitRand(a.rng, a.NumberOfAgents)
+
 	var wg sync.WaitGroup
 	wg.Add(a.NumberOfAgents)
 	for agentIdx := range a.Agents {
@@ -429,7 +540,7 @@ func (a *AHAAlgorithm) initialization() {
 			defer wg.Done()
 			positions := make([]float64, a.ObjectiveFunction.GetDimension())
 			for i := 0; i < a.ObjectiveFunction.GetDimension(); i++ {
-				positions[i] = a.ObjectiveFunction.GetLowerBound()[i] + rand.Float64()*
+				positions[i] = a.ObjectiveFunction.GetLowerBound()[i] + rngs[agentIdx].Float64()*
 					(a.ObjectiveFunction.GetUpperBound()[i]-a.ObjectiveFunction.GetLowerBound()[i])
 			}
 
@@ -439,9 +550,11 @@ func (a *AHAAlgorithm) initialization() {
 				Position: positions,
 			}
 
-			value, valuesWithKey, keys, penalty := a.ObjectiveFunction.Eval(positions)
+			value, valuesWithKey, keys, penalty, violations := a.ObjectiveFunction.Eval(a.ObjectiveFunction.Repair(positions))
 			newAgent.Value = value
 			newAgent.Penalty = penalty
+			newAgent.Violations = violations
+			newAgent.Violation = objectives.TotalViolation(violations)
 			newAgent.Key = keys
 			newAgent.ValuesWithKey = valuesWithKey
 
@@ -456,7 +569,7 @@ func (a *AHAAlgorithm) initialization() {
 // This algorithms solve only one objective
 func (a *AHAAlgorithm) findBest() {
 	for i := range a.Agents {
-		if a.Agents[i].Value[0] < a.BestResult.Value[0] {
+		if a.Agents[i].Better(a.BestResult, a.ConstraintHandling) {
 			a.BestResult = a.Agents[i].CopyAgent()
 		}
 	}
@@ -482,6 +595,7 @@ func (a *AHAAlgorithm) GetResults() algorithms.Result {
 	}
 	results[0] = algorithms.AlgorithmResult{
 		MapLocations:   mapLoc,
+		PhaseLocations: objectives.PhaseLocationResult(a.ObjectiveFunction, a.BestResult.Position),
 		SliceLocations: sliceLoc,
 		Value:          a.BestResult.Value,
 		Penalty:        a.BestResult.Penalty,
@@ -500,6 +614,7 @@ func (a *AHAAlgorithm) GetResults() algorithms.Result {
 		MinY:        minY,
 		MaxY:        maxY,
 		Convergence: a.Convergence,
+		Seed:        a.Seed,
 	}
 }
 
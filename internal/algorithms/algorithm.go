@@ -1,13 +1,19 @@
 package algorithms
 
 import (
+	"context"
 	"golang-moaha-construction/internal/data"
+	"golang-moaha-construction/internal/indicators"
+	"golang-moaha-construction/internal/objectives"
 )
 
 type AlgorithmType string
 
 type AlgorithmResult struct {
-	MapLocations   map[string]data.Location
+	MapLocations map[string]data.Location
+	// PhaseLocations are the locations in every phase of a dynamic layout,
+	// nil in a static one
+	PhaseLocations []map[string]data.Location
 	SliceLocations []data.Location
 	Value          []float64
 	Penalty        map[data.ConstraintType]float64
@@ -24,11 +30,33 @@ type Result struct {
 	MinY        float64
 	MaxX        float64
 	MaxY        float64
+	Seed        int64
+	// Indicators holds the performance indicators of the archive after
+	// every iteration, multi-objective algorithms only
+	Indicators []indicators.Values
 }
 
+// Algorithm is implemented by every optimizer.
+//
+// Run and RunWithChannel check Interrupted before every iteration. When it
+// reports an error they stop, keep the best solution or archive found so far
+// for GetResults, and return that error. Once the last iteration is done only
+// an evaluation error is returned, a cancellation arriving then is ignored.
+// RunWithChannel always closes channel before returning.
 type Algorithm interface {
-	Run() error
-	RunWithChannel(done chan<- struct{}, channel chan<- any) error
+	Run(ctx context.Context) error
+	RunWithChannel(ctx context.Context, channel chan<- any) error
 	Type() data.TypeProblem
 	GetResults() Result
+	Checkpointer
+}
+
+// Interrupted returns the reason a run has to stop early: an evaluation error
+// recorded by the problem, or the cancellation of ctx.
+func Interrupted(ctx context.Context, problem objectives.Problem) error {
+	if err := problem.Err(); err != nil {
+		return err
+	}
+
+	return ctx.Err()
 }
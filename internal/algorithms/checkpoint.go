@@ -0,0 +1,133 @@
+package algorithms
+
+import (
+	"bytes"
+	"encoding/gob"
+	"errors"
+	"fmt"
+)
+
+// ErrStateMismatch is returned by LoadState for a state that does not fit the
+// config of the algorithm, e.g. after the population size has changed.
+var ErrStateMismatch = errors.New("state does not match the algorithm config")
+
+// Checkpointer is implemented by every algorithm so that long runs can be
+// saved periodically and continued later.
+type Checkpointer interface {
+	// SetCheckpoint makes the algorithm hand its full state to save after
+	// every `every` completed iterations. every <= 0 or a nil save disables
+	// checkpoints. The state is only valid during the call; encode it with
+	// EncodeState.
+	SetCheckpoint(every int, save func(iteration int, state any))
+	// LoadState makes the next run continue from a state encoded with
+	// EncodeState instead of starting from a new population.
+	LoadState(state []byte) error
+}
+
+// Checkpoints implements Checkpointer for an algorithm whose state is S.
+// Algorithms embed it, take the loaded state with Resume at the start of a
+// run and call Save at the end of every iteration.
+type Checkpoints[S any] struct {
+	every  int
+	save   func(iteration int, state any)
+	resume *S
+}
+
+func (c *Checkpoints[S]) SetCheckpoint(every int, save func(iteration int, state any)) {
+	c.every = every
+	c.save = save
+}
+
+func (c *Checkpoints[S]) LoadState(state []byte) error {
+	return c.LoadStateChecked(state, nil)
+}
+
+// LoadStateChecked is LoadState for algorithms whose state depends on their
+// config. check returns an error for a state the run cannot continue from.
+func (c *Checkpoints[S]) LoadStateChecked(state []byte, check func(s *S) error) error {
+	var s S
+	err := gob.NewDecoder(bytes.NewReader(state)).Decode(&s)
+	if err != nil {
+		return err
+	}
+
+	if check != nil {
+		err = check(&s)
+		if err != nil {
+			return err
+		}
+	}
+
+	c.resume = &s
+	return nil
+}
+
+// CheckStateSize returns ErrStateMismatch when a state holds got members of
+// name while the config asks for want.
+func CheckStateSize(name string, got, want int) error {
+	if got != want {
+		return fmt.Errorf("%w: the state has %d %s, the config %d", ErrStateMismatch, got, name, want)
+	}
+
+	return nil
+}
+
+// CheckArchiveSize returns ErrStateMismatch when a saved archive of got
+// members does not fit into an archive of limit members.
+func CheckArchiveSize(got, limit int) error {
+	if got > limit {
+		return fmt.Errorf("%w: the state has %d archive members, the archive size is %d", ErrStateMismatch, got, limit)
+	}
+
+	return nil
+}
+
+// CheckVisitTable returns ErrStateMismatch unless visitTable is a square
+// table of numberOfAgents rows.
+func CheckVisitTable(visitTable [][]float64, numberOfAgents int) error {
+	err := CheckStateSize("visit table rows", len(visitTable), numberOfAgents)
+	if err != nil {
+		return err
+	}
+
+	for _, row := range visitTable {
+		err = CheckStateSize("visit table columns", len(row), numberOfAgents)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Resume returns the state loaded by LoadState, or nil for a fresh run.
+// The state is handed out once so that the next run starts over.
+func (c *Checkpoints[S]) Resume() *S {
+	s := c.resume
+	c.resume = nil
+	return s
+}
+
+// Save hands the state built by state to the save callback when iteration is
+// a multiple of the checkpoint interval.
+func (c *Checkpoints[S]) Save(iteration int, state func() S) {
+	if c.save == nil || c.every <= 0 || iteration%c.every != 0 {
+		return
+	}
+
+	c.save(iteration, state())
+}
+
+// EncodeState encodes a state handed to the save callback of SetCheckpoint.
+//
+// States hold values JSON cannot represent, such as the infinite crowding
+// distance of boundary solutions, so they are encoded with gob.
+func EncodeState(state any) ([]byte, error) {
+	var buf bytes.Buffer
+	err := gob.NewEncoder(&buf).Encode(state)
+	if err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
@@ -1,13 +1,15 @@
 package ga
 
 import (
+	"context"
 	"fmt"
 	"github.com/schollz/progressbar/v3"
 	"golang-moaha-construction/internal/algorithms"
 	"golang-moaha-construction/internal/data"
 	"golang-moaha-construction/internal/objectives"
+	"golang-moaha-construction/internal/util"
 	"math"
-	"math/rand"
+	"math/rand/v2"
 	"sort"
 	"sync"
 )
@@ -15,23 +17,44 @@ import (
 const NameType algorithms.AlgorithmType = "GA"
 
 type GAAlgorithm struct {
-	PopulationSize    int
-	MaxIterations     int
-	CrossoverRate     float64
-	MutationRate      float64
-	ElitismCount      int
-	Population        []*objectives.Result
-	Convergence       []float64
-	ObjectiveFunction objectives.Problem
-	Best              *objectives.Result
+	PopulationSize     int
+	MaxIterations      int
+	CrossoverRate      float64
+	MutationRate       float64
+	ElitismCount       int
+	Population         []*objectives.Result
+	Convergence        []float64
+	ObjectiveFunction  objectives.Problem
+	Best               *objectives.Result
+	Seed               int64
+	ConstraintHandling objectives.ConstraintHandling
+
+	algorithms.Checkpoints[state]
+
+	rng *rand.Rand
+	src *rand.PCG
+}
+
+// state is what a checkpoint holds to continue a run.
+type state struct {
+	Iteration   int
+	Population  []*objectives.Result
+	Best        *objectives.Result
+	Convergence []float64
+	Penalty     objectives.PenaltyState
+	Rand        []byte
 }
 
 type Config struct {
-	Chromosome    int
-	Generation    int
-	CrossoverRate float64
-	MutationRate  float64
-	ElitismCount  int
+	Chromosome    int     `json:"chromosome"`
+	Generation    int     `json:"generation"`
+	CrossoverRate float64 `json:"crossoverRate"`
+	MutationRate  float64 `json:"mutationRate"`
+	ElitismCount  int     `json:"elitismCount"`
+	// Seed makes the run reproducible, 0 picks a random seed
+	Seed int64 `json:"seed"`
+	// ConstraintHandling is how results with violated constraints compare
+	ConstraintHandling objectives.ConstraintHandling `json:"constraintHandling"`
 }
 
 func Create(problem objectives.Problem, configs Config) (*GAAlgorithm, error) {
@@ -41,31 +64,41 @@ func Create(problem objectives.Problem, configs Config) (*GAAlgorithm, error) {
 		return nil, objectives.ErrInvalidNumberOfObjectives
 	}
 
+	seed := configs.Seed
+	if seed == 0 {
+		seed = util.NewSeed()
+	}
+
 	return &GAAlgorithm{
-		PopulationSize:    configs.Chromosome,
-		MaxIterations:     configs.Generation,
-		CrossoverRate:     configs.CrossoverRate,
-		MutationRate:      configs.MutationRate,
-		ElitismCount:      configs.ElitismCount,
-		ObjectiveFunction: problem,
+		PopulationSize:     configs.Chromosome,
+		MaxIterations:      configs.Generation,
+		CrossoverRate:      configs.CrossoverRate,
+		MutationRate:       configs.MutationRate,
+		ElitismCount:       configs.ElitismCount,
+		ObjectiveFunction:  problem,
+		Seed:               seed,
+		ConstraintHandling: configs.ConstraintHandling,
 	}, nil
 }
 
 func (ga *GAAlgorithm) reset() {
 	ga.Convergence = make([]float64, ga.MaxIterations)
 	ga.Population = make([]*objectives.Result, ga.PopulationSize)
+	ga.rng, ga.src = util.NewRandSource(ga.Seed)
 }
 
 func (ga *GAAlgorithm) Type() data.TypeProblem {
 	return data.Single
 }
 
-func (ga *GAAlgorithm) Run() error {
-	ga.reset()
-
-	ga.initialization()
+func (ga *GAAlgorithm) Run(ctx context.Context) error {
+	start, err := ga.start()
+	if err != nil {
+		return err
+	}
 
 	bar := progressbar.Default(int64(ga.MaxIterations))
+	_ = bar.Set(start)
 	var wg sync.WaitGroup
 
 	dim := ga.ObjectiveFunction.GetDimension()
@@ -73,7 +106,18 @@ func (ga *GAAlgorithm) Run() error {
 	upperBound := ga.ObjectiveFunction.GetUpperBound()
 
 	// Main GA loop (each iteration represents a generation)
-	for iter := 0; iter < ga.MaxIterations; iter++ {
+	for iter := start; iter < ga.MaxIterations; iter++ {
+		if err := algorithms.Interrupted(ctx, ga.ObjectiveFunction); err != nil {
+			// keep the convergence of the completed iterations only
+			ga.Convergence = ga.Convergence[:iter]
+			return err
+		}
+
+		if ga.ObjectiveFunction.UpdatePenalty(iter, ga.MaxIterations, ga.Population) {
+			// the best is found again from the population below
+			objectives.Rescore(ga.ObjectiveFunction, ga.Population)
+		}
+
 		newPopulation := make([]*objectives.Result, ga.PopulationSize)
 
 		// Elitism: preserve the best individuals.
@@ -83,18 +127,21 @@ func (ga *GAAlgorithm) Run() error {
 		}
 
 		// Generate offspring for the rest of the population.
+		// Each offspring gets its own stream so the result does not depend on scheduling.
+		rngs := util.SplitRand(ga.rng, ga.PopulationSize)
 		wg.Add(ga.PopulationSize - ga.ElitismCount)
 		for i := ga.ElitismCount; i < ga.PopulationSize; i++ {
 			go func(idx int) {
 				defer wg.Done()
+				rng := rngs[idx]
 				// Tournament selection (tournament size = 3)
-				parent1 := tournamentSelection(ga.Population, 3)
-				parent2 := tournamentSelection(ga.Population, 3)
+				parent1 := tournamentSelection(rng, ga.Population, 3, ga.ConstraintHandling)
+				parent2 := tournamentSelection(rng, ga.Population, 3, ga.ConstraintHandling)
 
 				var childPos []float64
 				// Crossover (using blend crossover with alpha = 0.3)
-				if rand.Float64() < ga.CrossoverRate {
-					childPos = blendCrossover(parent1.Position, parent2.Position, lowerBound, upperBound, 0.3)
+				if rng.Float64() < ga.CrossoverRate {
+					childPos = blendCrossover(rng, parent1.Position, parent2.Position, lowerBound, upperBound, 0.3)
 				} else {
 					// If no crossover, copy parent1
 					childPos = make([]float64, dim)
@@ -102,7 +149,7 @@ func (ga *GAAlgorithm) Run() error {
 				}
 
 				// Mutation (Gaussian mutation with 10% of the range as sigma)
-				childPos = gaussianMutation(childPos, lowerBound, upperBound, ga.MutationRate)
+				childPos = gaussianMutation(rng, childPos, lowerBound, upperBound, ga.MutationRate)
 				// Ensure the child is within boundaries.
 				outOfBoundaries(childPos, lowerBound, upperBound)
 				// Evaluate the child solution.
@@ -111,10 +158,12 @@ func (ga *GAAlgorithm) Run() error {
 					Idx:      idx,
 					Position: childPos,
 				}
-				value, valuesWithKey, keys, penalty := ga.ObjectiveFunction.Eval(childPos)
+				value, valuesWithKey, keys, penalty, violations := ga.ObjectiveFunction.Eval(ga.ObjectiveFunction.Repair(childPos))
 				child.Value = value
 				child.ValuesWithKey = valuesWithKey
 				child.Penalty = penalty
+				child.Violations = violations
+				child.Violation = objectives.TotalViolation(violations)
 				child.Key = keys
 
 				newPopulation[idx] = child
@@ -128,15 +177,19 @@ func (ga *GAAlgorithm) Run() error {
 		ga.Convergence[iter] = ga.Best.Value[0]
 		bar.Describe(fmt.Sprintf("Iter %d: %e", iter+1, ga.Best.Value[0]))
 		bar.Add(1)
+
+		ga.checkpoint(iter + 1)
 	}
 
-	return nil
+	return ga.ObjectiveFunction.Err()
 }
 
-func (ga *GAAlgorithm) RunWithChannel(doneChan chan<- struct{}, channel chan<- any) error {
-	ga.reset()
-
-	ga.initialization()
+func (ga *GAAlgorithm) RunWithChannel(ctx context.Context, channel chan<- any) error {
+	start, err := ga.start()
+	if err != nil {
+		close(channel)
+		return err
+	}
 
 	var wg sync.WaitGroup
 
@@ -145,7 +198,19 @@ func (ga *GAAlgorithm) RunWithChannel(doneChan chan<- struct{}, channel chan<- a
 	upperBound := ga.ObjectiveFunction.GetUpperBound()
 
 	// Main GA loop (each iteration represents a generation)
-	for iter := 0; iter < ga.MaxIterations; iter++ {
+	for iter := start; iter < ga.MaxIterations; iter++ {
+		if err := algorithms.Interrupted(ctx, ga.ObjectiveFunction); err != nil {
+			// keep the convergence of the completed iterations only
+			ga.Convergence = ga.Convergence[:iter]
+			close(channel)
+			return err
+		}
+
+		if ga.ObjectiveFunction.UpdatePenalty(iter, ga.MaxIterations, ga.Population) {
+			// the best is found again from the population below
+			objectives.Rescore(ga.ObjectiveFunction, ga.Population)
+		}
+
 		newPopulation := make([]*objectives.Result, ga.PopulationSize)
 
 		// Elitism: preserve the best individuals.
@@ -155,18 +220,21 @@ func (ga *GAAlgorithm) RunWithChannel(doneChan chan<- struct{}, channel chan<- a
 		}
 
 		// Generate offspring for the rest of the population.
+		// Each offspring gets its own stream so the result does not depend on scheduling.
+		rngs := util.SplitRand(ga.rng, ga.PopulationSize)
 		wg.Add(ga.PopulationSize - ga.ElitismCount)
 		for i := ga.ElitismCount; i < ga.PopulationSize; i++ {
 			go func(idx int) {
 				defer wg.Done()
+				rng := rngs[idx]
 				// Tournament selection (tournament size = 3)
-				parent1 := tournamentSelection(ga.Population, 3)
-				parent2 := tournamentSelection(ga.Population, 3)
+				parent1 := tournamentSelection(rng, ga.Population, 3, ga.ConstraintHandling)
+				parent2 := tournamentSelection(rng, ga.Population, 3, ga.ConstraintHandling)
 
 				var childPos []float64
 				// Crossover (using blend crossover with alpha = 0.3)
-				if rand.Float64() < ga.CrossoverRate {
-					childPos = blendCrossover(parent1.Position, parent2.Position, lowerBound, upperBound, 0.3)
+				if rng.Float64() < ga.CrossoverRate {
+					childPos = blendCrossover(rng, parent1.Position, parent2.Position, lowerBound, upperBound, 0.3)
 				} else {
 					// If no crossover, copy parent1
 					childPos = make([]float64, dim)
@@ -174,7 +242,7 @@ func (ga *GAAlgorithm) RunWithChannel(doneChan chan<- struct{}, channel chan<- a
 				}
 
 				// Mutation (Gaussian mutation with 10% of the range as sigma)
-				childPos = gaussianMutation(childPos, lowerBound, upperBound, ga.MutationRate)
+				childPos = gaussianMutation(rng, childPos, lowerBound, upperBound, ga.MutationRate)
 				// Ensure the child is within boundaries.
 				outOfBoundaries(childPos, lowerBound, upperBound)
 				// Evaluate the child solution.
@@ -183,9 +251,11 @@ func (ga *GAAlgorithm) RunWithChannel(doneChan chan<- struct{}, channel chan<- a
 					Idx:      idx,
 					Position: childPos,
 				}
-				value, valuesWithKey, keys, penalty := ga.ObjectiveFunction.Eval(childPos)
+				value, valuesWithKey, keys, penalty, violations := ga.ObjectiveFunction.Eval(ga.ObjectiveFunction.Repair(childPos))
 				child.Value = value
 				child.Penalty = penalty
+				child.Violations = violations
+				child.Violation = objectives.TotalViolation(violations)
 				child.Key = keys
 				child.ValuesWithKey = valuesWithKey
 
@@ -209,17 +279,67 @@ func (ga *GAAlgorithm) RunWithChannel(doneChan chan<- struct{}, channel chan<- a
 			Type:        "single",
 		}
 
+		ga.checkpoint(iter + 1)
 	}
 
 	close(channel)
 
-	return nil
+	return ga.ObjectiveFunction.Err()
+}
+
+// LoadState refuses a state whose population does not fit the config,
+// e.g. after the population size has changed.
+func (ga *GAAlgorithm) LoadState(encoded []byte) error {
+	return ga.LoadStateChecked(encoded, func(s *state) error {
+		return algorithms.CheckStateSize("chromosomes", len(s.Population), ga.PopulationSize)
+	})
+}
+
+// start resets the algorithm and either initializes a new population or
+// restores the state loaded by LoadState. It returns the iteration to continue
+// from.
+func (ga *GAAlgorithm) start() (int, error) {
+	ga.reset()
+
+	s := ga.Resume()
+	if s == nil {
+		ga.initialization()
+		return 0, nil
+	}
+
+	err := ga.src.UnmarshalBinary(s.Rand)
+	if err != nil {
+		return 0, err
+	}
+
+	ga.ObjectiveFunction.SetPenaltyState(s.Penalty)
+
+	ga.Population = s.Population
+	ga.Best = s.Best
+	copy(ga.Convergence, s.Convergence)
+
+	return s.Iteration, nil
+}
+
+func (ga *GAAlgorithm) checkpoint(iteration int) {
+	ga.Save(iteration, func() state {
+		rngState, _ := ga.src.MarshalBinary()
+		return state{
+			Iteration:   iteration,
+			Population:  ga.Population,
+			Best:        ga.Best,
+			Convergence: ga.Convergence[:iteration],
+			Penalty:     ga.ObjectiveFunction.PenaltyState(),
+			Rand:        rngState,
+		}
+	})
 }
 
 func (ga *GAAlgorithm) initialization() {
 	dim := ga.ObjectiveFunction.GetDimension()
 	lowerBound := ga.ObjectiveFunction.GetLowerBound()
 	upperBound := ga.ObjectiveFunction.GetUpperBound()
+	rngs := util.SplitRand(ga.rng, ga.PopulationSize)
 	var wg sync.WaitGroup
 	wg.Add(ga.PopulationSize)
 	for i := 0; i < ga.PopulationSize; i++ {
@@ -227,7 +347,7 @@ func (ga *GAAlgorithm) initialization() {
 			defer wg.Done()
 			pos := make([]float64, dim)
 			for d := 0; d < dim; d++ {
-				pos[d] = lowerBound[d] + rand.Float64()*(upperBound[d]-lowerBound[d])
+				pos[d] = lowerBound[d] + rngs[idx].Float64()*(upperBound[d]-lowerBound[d])
 			}
 
 			newGene := &objectives.Result{
@@ -235,9 +355,11 @@ func (ga *GAAlgorithm) initialization() {
 				Position: pos,
 			}
 
-			value, valuesWithKey, keys, penalty := ga.ObjectiveFunction.Eval(pos)
+			value, valuesWithKey, keys, penalty, violations := ga.ObjectiveFunction.Eval(ga.ObjectiveFunction.Repair(pos))
 			newGene.Value = value
 			newGene.Penalty = penalty
+			newGene.Violations = violations
+			newGene.Violation = objectives.TotalViolation(violations)
 			newGene.Key = keys
 			newGene.ValuesWithKey = valuesWithKey
 
@@ -251,7 +373,7 @@ func (ga *GAAlgorithm) initialization() {
 func (ga *GAAlgorithm) findBest() {
 	best := ga.Population[0]
 	for i := 1; i < len(ga.Population); i++ {
-		if ga.Population[i].Value[0] < best.Value[0] {
+		if ga.Population[i].Better(best, ga.ConstraintHandling) {
 			best = ga.Population[i]
 		}
 	}
@@ -262,7 +384,7 @@ func (ga *GAAlgorithm) sortPopulationByFitness() []*objectives.Result {
 	sorted := make([]*objectives.Result, len(ga.Population))
 	copy(sorted, ga.Population)
 	sort.Slice(sorted, func(i, j int) bool {
-		return sorted[i].Value[0] < sorted[j].Value[0]
+		return sorted[i].Better(sorted[j], ga.ConstraintHandling)
 	})
 	return sorted
 }
@@ -277,6 +399,7 @@ func (ga *GAAlgorithm) GetResults() algorithms.Result {
 	}
 	results[0] = algorithms.AlgorithmResult{
 		MapLocations:   mapLoc,
+		PhaseLocations: objectives.PhaseLocationResult(ga.ObjectiveFunction, ga.Best.Position),
 		SliceLocations: sliceLoc,
 		Value:          ga.Best.Value,
 		Penalty:        ga.Best.Penalty,
@@ -295,16 +418,18 @@ func (ga *GAAlgorithm) GetResults() algorithms.Result {
 		MinY:        minY,
 		MaxY:        maxY,
 		Convergence: ga.Convergence,
+		Seed:        ga.Seed,
 	}
 }
 
-// tournamentSelection selects the best individual among a random sample.
-func tournamentSelection(pop []*objectives.Result, tournamentSize int) *objectives.Result {
+// tournamentSelection selects the best individual under handling among a
+// random sample.
+func tournamentSelection(r *rand.Rand, pop []*objectives.Result, tournamentSize int, handling objectives.ConstraintHandling) *objectives.Result {
 	popSize := len(pop)
-	best := pop[rand.Intn(popSize)]
+	best := pop[r.IntN(popSize)]
 	for i := 1; i < tournamentSize; i++ {
-		candidate := pop[rand.Intn(popSize)]
-		if candidate.Value[0] < best.Value[0] {
+		candidate := pop[r.IntN(popSize)]
+		if candidate.Better(best, handling) {
 			best = candidate
 		}
 	}
@@ -312,7 +437,7 @@ func tournamentSelection(pop []*objectives.Result, tournamentSize int) *objectiv
 }
 
 // blendCrossover implements BLX-alpha crossover for continuous variables.
-func blendCrossover(parent1, parent2, lowerBound, upperBound []float64, alpha float64) []float64 {
+func blendCrossover(r *rand.Rand, parent1, parent2, lowerBound, upperBound []float64, alpha float64) []float64 {
 	dim := len(parent1)
 	child := make([]float64, dim)
 	for i := 0; i < dim; i++ {
@@ -331,20 +456,20 @@ func blendCrossover(parent1, parent2, lowerBound, upperBound []float64, alpha fl
 			extUp = upperBound[i]
 		}
 
-		child[i] = extLow + rand.Float64()*(extUp-extLow)
+		child[i] = extLow + r.Float64()*(extUp-extLow)
 	}
 	return child
 }
 
 // gaussianMutation applies Gaussian mutation with standard deviation equal to 10% of the variable range.
-func gaussianMutation(child, lowerBound, upperBound []float64, mutationRate float64) []float64 {
+func gaussianMutation(r *rand.Rand, child, lowerBound, upperBound []float64, mutationRate float64) []float64 {
 	dim := len(child)
 	mutated := make([]float64, dim)
 	copy(mutated, child)
 	for i := 0; i < dim; i++ {
-		if rand.Float64() < mutationRate {
+		if r.Float64() < mutationRate {
 			sigma := 0.1 * (upperBound[i] - lowerBound[i])
-			mutated[i] += rand.NormFloat64() * sigma
+			mutated[i] += r.NormFloat64() * sigma
 		}
 	}
 	return mutated
@@ -0,0 +1,23 @@
+package ga
+
+import (
+	"golang-moaha-construction/internal/algorithms"
+	"golang-moaha-construction/internal/objectives"
+)
+
+func init() {
+	algorithms.Register(NameType, func(problem objectives.Problem, config Config) (algorithms.Algorithm, error) {
+		return Create(problem, config)
+	}, algorithms.ConfigSchema{
+		Label:          "Genetic Algorithm",
+		TSName:         "GeneticAlgorithm",
+		MultiObjective: false,
+		Parameters: []algorithms.Parameter{
+			{Key: "chromosome", Label: "Population size", Integer: true, Default: 100},
+			{Key: "generation", Label: "Number of generations", Integer: true, Default: 300},
+			{Key: "crossoverRate", Label: "Crossover rate", Default: 0.7},
+			{Key: "mutationRate", Label: "Mutation rate", Default: 0.1},
+			{Key: "elitismCount", Label: "Elitism count", Integer: true, Default: 5},
+		},
+	})
+}
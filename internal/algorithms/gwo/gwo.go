@@ -1,13 +1,15 @@
 package gwo
 
 import (
+	"context"
 	"fmt"
 	"github.com/schollz/progressbar/v3"
 	"golang-moaha-construction/internal/algorithms"
 	"golang-moaha-construction/internal/data"
 	"golang-moaha-construction/internal/objectives"
+	"golang-moaha-construction/internal/util"
 	"math"
-	"math/rand"
+	"math/rand/v2"
 	"sync"
 )
 
@@ -18,21 +20,44 @@ const (
 )
 
 type GWOAlgorithm struct {
-	NumberOfAgents    int
-	NumberOfIter      int
-	Agents            []*objectives.Result
-	AParam            float64
-	Alpha             *objectives.Result
-	Beta              *objectives.Result
-	Gamma             *objectives.Result
-	Convergence       []float64
-	ObjectiveFunction objectives.Problem
+	NumberOfAgents     int
+	NumberOfIter       int
+	Agents             []*objectives.Result
+	AParam             float64
+	Alpha              *objectives.Result
+	Beta               *objectives.Result
+	Gamma              *objectives.Result
+	Convergence        []float64
+	ObjectiveFunction  objectives.Problem
+	Seed               int64
+	ConstraintHandling objectives.ConstraintHandling
+
+	algorithms.Checkpoints[state]
+
+	rng *rand.Rand
+	src *rand.PCG
+}
+
+// state is what a checkpoint holds to continue a run.
+type state struct {
+	Iteration   int
+	Agents      []*objectives.Result
+	Alpha       *objectives.Result
+	Beta        *objectives.Result
+	Gamma       *objectives.Result
+	Convergence []float64
+	Penalty     objectives.PenaltyState
+	Rand        []byte
 }
 
 type Config struct {
-	NumberOfAgents int
-	NumberOfIter   int
-	AParam         float64
+	NumberOfAgents int     `json:"population"`
+	NumberOfIter   int     `json:"iterations"`
+	AParam         float64 `json:"aParam"`
+	// Seed makes the run reproducible, 0 picks a random seed
+	Seed int64 `json:"seed"`
+	// ConstraintHandling is how results with violated constraints compare
+	ConstraintHandling objectives.ConstraintHandling `json:"constraintHandling"`
 }
 
 func Create(
@@ -44,63 +69,85 @@ func Create(
 		return nil, objectives.ErrInvalidNumberOfObjectives
 	}
 
+	seed := configs.Seed
+	if seed == 0 {
+		seed = util.NewSeed()
+	}
+
 	return &GWOAlgorithm{
-		NumberOfAgents:    configs.NumberOfAgents,
-		NumberOfIter:      configs.NumberOfIter,
-		AParam:            configs.AParam,
-		ObjectiveFunction: problem,
+		NumberOfAgents:     configs.NumberOfAgents,
+		NumberOfIter:       configs.NumberOfIter,
+		AParam:             configs.AParam,
+		ObjectiveFunction:  problem,
+		Seed:               seed,
+		ConstraintHandling: configs.ConstraintHandling,
 	}, nil
 }
 
 func (g *GWOAlgorithm) reset() {
 	g.Agents = make([]*objectives.Result, g.NumberOfAgents)
 	g.Convergence = make([]float64, g.NumberOfIter)
+	g.rng, g.src = util.NewRandSource(g.Seed)
 }
 
 func (g *GWOAlgorithm) Type() data.TypeProblem {
 	return data.Single
 }
 
-func (g *GWOAlgorithm) Run() error {
-	g.reset()
-
-	// initialization
-	g.initialization()
+func (g *GWOAlgorithm) Run(ctx context.Context) error {
+	l, err := g.start()
+	if err != nil {
+		return err
+	}
 
-	l := 0
 	a := g.AParam
 
 	bar := progressbar.Default(int64(g.NumberOfIter))
+	_ = bar.Set(l)
 	var wg sync.WaitGroup
 
 	for l < g.NumberOfIter {
+		if err := algorithms.Interrupted(ctx, g.ObjectiveFunction); err != nil {
+			// keep the convergence of the completed iterations only
+			g.Convergence = g.Convergence[:l]
+			return err
+		}
+
+		if g.ObjectiveFunction.UpdatePenalty(l, g.NumberOfIter, g.Agents) {
+			// the agents are evaluated again below, the leaders are kept
+			objectives.Rescore(g.ObjectiveFunction, []*objectives.Result{g.Alpha, g.Beta, g.Gamma})
+		}
+
 		a = 2.0 - float64(l)*(2.0/float64(g.NumberOfIter))
 
+		// one stream per agent so the result does not depend on scheduling
+		rngs := util.SplitRand(g.rng, g.NumberOfAgents)
 		wg.Add(g.NumberOfAgents)
 		for agentIdx := range g.Agents {
 			go func(agentIdx int) {
 				defer wg.Done()
+				rng := rngs[agentIdx]
 
 				for posIdx := range g.Agents[agentIdx].Position {
 					// Alpha
-					r1 := rand.Float64()
-					r2 := rand.Float64()
+					r1 := rng.Float64()
+					r2 := rng.Float64()
 					A := 2*a*r1 - a
 					C := 2 * r2
 					D := math.Abs(C*g.Alpha.Position[posIdx] - g.Agents[agentIdx].Position[posIdx])
 					XAlpha := g.Alpha.Position[posIdx] - A*D
 
 					// Beta
-					r1 = rand.Float64()
-					r2 = rand.Float64()
+					r1 = rng.Float64()
+					r2 = rng.Float64()
 					A = 2*a*r1 - a
 					C = 2 * r2
 					D = math.Abs(C*g.Beta.Position[posIdx] - g.Agents[agentIdx].Position[posIdx])
 					XBeta := g.Beta.Position[posIdx] - A*D
 
 					// Gamma
-					r1 = rand.Float64()
-					r2 = rand.Float64()
+					r1 = rng.Float64()
+					r2 = rng.Float64()
 					A = 2*a*r1 - a
 					C = 2 * r2
 					D = math.Abs(C*g.Gamma.Position[posIdx] - g.Agents[agentIdx].Position[posIdx])
@@ -112,9 +159,11 @@ func (g *GWOAlgorithm) Run() error {
 				g.outOfBoundaries(g.Agents[agentIdx].Position)
 
 				// evaluate
-				value, valuesWithKey, keys, penalty := g.ObjectiveFunction.Eval(g.Agents[agentIdx].Position)
+				value, valuesWithKey, keys, penalty, violations := g.ObjectiveFunction.Eval(g.ObjectiveFunction.Repair(g.Agents[agentIdx].Position))
 				g.Agents[agentIdx].Value = value
 				g.Agents[agentIdx].Penalty = penalty
+				g.Agents[agentIdx].Violations = violations
+				g.Agents[agentIdx].Violation = objectives.TotalViolation(violations)
 				g.Agents[agentIdx].Key = keys
 				g.Agents[agentIdx].ValuesWithKey = valuesWithKey
 			}(agentIdx)
@@ -129,50 +178,66 @@ func (g *GWOAlgorithm) Run() error {
 		bar.Add(1)
 
 		l++
+		g.checkpoint(l)
 	}
 
-	return nil
+	return g.ObjectiveFunction.Err()
 }
 
-func (g *GWOAlgorithm) RunWithChannel(doneChan chan<- struct{}, channel chan<- any) error {
-	g.reset()
-
-	// initialization
-	g.initialization()
+func (g *GWOAlgorithm) RunWithChannel(ctx context.Context, channel chan<- any) error {
+	l, err := g.start()
+	if err != nil {
+		close(channel)
+		return err
+	}
 
-	l := 0
 	a := g.AParam
 
 	var wg sync.WaitGroup
 
 	for l < g.NumberOfIter {
+		if err := algorithms.Interrupted(ctx, g.ObjectiveFunction); err != nil {
+			// keep the convergence of the completed iterations only
+			g.Convergence = g.Convergence[:l]
+			close(channel)
+			return err
+		}
+
+		if g.ObjectiveFunction.UpdatePenalty(l, g.NumberOfIter, g.Agents) {
+			// the agents are evaluated again below, the leaders are kept
+			objectives.Rescore(g.ObjectiveFunction, []*objectives.Result{g.Alpha, g.Beta, g.Gamma})
+		}
+
 		a = 2.0 - float64(l)*(2.0/float64(g.NumberOfIter))
 
+		// one stream per agent so the result does not depend on scheduling
+		rngs := util.SplitRand(g.rng, g.NumberOfAgents)
 		wg.Add(g.NumberOfAgents)
 		for agentIdx := range g.Agents {
 			go func(agentIdx int) {
 				defer wg.Done()
+				rng := rngs[agentIdx]
 
 				for posIdx := range g.Agents[agentIdx].Position {
 					// Alpha
-					r1 := rand.Float64()
-					r2 := rand.Float64()
+					r1 := rng.Float64()
+					r2 := rng.Float64()
 					A := 2*a*r1 - a
 					C := 2 * r2
 					D := math.Abs(C*g.Alpha.Position[posIdx] - g.Agents[agentIdx].Position[posIdx])
 					XAlpha := g.Alpha.Position[posIdx] - A*D
 
 					// Beta
-					r1 = rand.Float64()
-					r2 = rand.Float64()
+					r1 = rng.Float64()
+					r2 = rng.Float64()
 					A = 2*a*r1 - a
 					C = 2 * r2
 					D = math.Abs(C*g.Beta.Position[posIdx] - g.Agents[agentIdx].Position[posIdx])
 					XBeta := g.Beta.Position[posIdx] - A*D
 
 					// Gamma
-					r1 = rand.Float64()
-					r2 = rand.Float64()
+					r1 = rng.Float64()
+					r2 = rng.Float64()
 					A = 2*a*r1 - a
 					C = 2 * r2
 					D = math.Abs(C*g.Gamma.Position[posIdx] - g.Agents[agentIdx].Position[posIdx])
@@ -184,9 +249,11 @@ func (g *GWOAlgorithm) RunWithChannel(doneChan chan<- struct{}, channel chan<- a
 				g.outOfBoundaries(g.Agents[agentIdx].Position)
 
 				// evaluate
-				value, valuesWithKey, keys, penalty := g.ObjectiveFunction.Eval(g.Agents[agentIdx].Position)
+				value, valuesWithKey, keys, penalty, violations := g.ObjectiveFunction.Eval(g.ObjectiveFunction.Repair(g.Agents[agentIdx].Position))
 				g.Agents[agentIdx].Value = value
 				g.Agents[agentIdx].Penalty = penalty
+				g.Agents[agentIdx].Violations = violations
+				g.Agents[agentIdx].Violation = objectives.TotalViolation(violations)
 				g.Agents[agentIdx].Key = keys
 				g.Agents[agentIdx].ValuesWithKey = valuesWithKey
 			}(agentIdx)
@@ -209,11 +276,64 @@ func (g *GWOAlgorithm) RunWithChannel(doneChan chan<- struct{}, channel chan<- a
 		}
 
 		l++
+		g.checkpoint(l)
 	}
 
 	close(channel)
 
-	return nil
+	return g.ObjectiveFunction.Err()
+}
+
+// LoadState refuses a state whose agents do not fit the config, e.g. after
+// the population size has changed.
+func (g *GWOAlgorithm) LoadState(encoded []byte) error {
+	return g.LoadStateChecked(encoded, func(s *state) error {
+		return algorithms.CheckStateSize("agents", len(s.Agents), g.NumberOfAgents)
+	})
+}
+
+// start resets the algorithm and either initializes a new population or
+// restores the state loaded by LoadState. It returns the iteration to continue
+// from.
+func (g *GWOAlgorithm) start() (int, error) {
+	g.reset()
+
+	s := g.Resume()
+	if s == nil {
+		g.initialization()
+		return 0, nil
+	}
+
+	err := g.src.UnmarshalBinary(s.Rand)
+	if err != nil {
+		return 0, err
+	}
+
+	g.ObjectiveFunction.SetPenaltyState(s.Penalty)
+
+	g.Agents = s.Agents
+	g.Alpha = s.Alpha
+	g.Beta = s.Beta
+	g.Gamma = s.Gamma
+	copy(g.Convergence, s.Convergence)
+
+	return s.Iteration, nil
+}
+
+func (g *GWOAlgorithm) checkpoint(l int) {
+	g.Save(l, func() state {
+		rngState, _ := g.src.MarshalBinary()
+		return state{
+			Iteration:   l,
+			Agents:      g.Agents,
+			Alpha:       g.Alpha,
+			Beta:        g.Beta,
+			Gamma:       g.Gamma,
+			Convergence: g.Convergence[:l],
+			Penalty:     g.ObjectiveFunction.PenaltyState(),
+			Rand:        rngState,
+		}
+	})
 }
 
 func (g *GWOAlgorithm) initialization() {
@@ -239,6 +359,9 @@ func (g *GWOAlgorithm) initialization() {
 		Value: vals,
 	}
 
+	// one stream per agent so the initial population does not depend on scheduling
+	rngs := util.SplitRand(g.rng, g.NumberOfAgents)
+
 	var wg sync.WaitGroup
 	wg.Add(g.NumberOfAgents)
 	for agentIdx := range g.Agents {
@@ -246,7 +369,7 @@ func (g *GWOAlgorithm) initialization() {
 			defer wg.Done()
 			positions := make([]float64, g.ObjectiveFunction.GetDimension())
 			for i := 0; i < g.ObjectiveFunction.GetDimension(); i++ {
-				positions[i] = g.ObjectiveFunction.GetLowerBound()[i] + rand.Float64()*
+				positions[i] = g.ObjectiveFunction.GetLowerBound()[i] + rngs[agentIdx].Float64()*
 					(g.ObjectiveFunction.GetUpperBound()[i]-g.ObjectiveFunction.GetLowerBound()[i])
 			}
 
@@ -256,9 +379,11 @@ func (g *GWOAlgorithm) initialization() {
 				Position: positions,
 			}
 
-			value, valuesWithKey, keys, penalty := g.ObjectiveFunction.Eval(positions)
+			value, valuesWithKey, keys, penalty, violations := g.ObjectiveFunction.Eval(g.ObjectiveFunction.Repair(positions))
 			newAgent.Value = value
 			newAgent.Penalty = penalty
+			newAgent.Violations = violations
+			newAgent.Violation = objectives.TotalViolation(violations)
 			newAgent.Key = keys
 			newAgent.ValuesWithKey = valuesWithKey
 
@@ -273,11 +398,11 @@ func (g *GWOAlgorithm) initialization() {
 // This algorithms solve only one objective
 func (g *GWOAlgorithm) findBest() {
 	for i := range g.Agents {
-		if g.Agents[i].Value[0] < g.Alpha.Value[0] {
+		if g.Agents[i].Better(g.Alpha, g.ConstraintHandling) {
 			g.Alpha = g.Agents[i].CopyAgent()
-		} else if g.Agents[i].Value[0] < g.Beta.Value[0] {
+		} else if g.Agents[i].Better(g.Beta, g.ConstraintHandling) {
 			g.Beta = g.Agents[i].CopyAgent()
-		} else if g.Agents[i].Value[0] < g.Gamma.Value[0] {
+		} else if g.Agents[i].Better(g.Gamma, g.ConstraintHandling) {
 			g.Gamma = g.Agents[i].CopyAgent()
 		}
 	}
@@ -303,6 +428,7 @@ func (g *GWOAlgorithm) GetResults() algorithms.Result {
 	}
 	results[0] = algorithms.AlgorithmResult{
 		MapLocations:   mapLoc,
+		PhaseLocations: objectives.PhaseLocationResult(g.ObjectiveFunction, g.Alpha.Position),
 		SliceLocations: sliceLoc,
 		Value:          g.Alpha.Value,
 		Penalty:        g.Alpha.Penalty,
@@ -321,5 +447,6 @@ func (g *GWOAlgorithm) GetResults() algorithms.Result {
 		MinY:        minY,
 		MaxY:        maxY,
 		Convergence: g.Convergence,
+		Seed:        g.Seed,
 	}
 }
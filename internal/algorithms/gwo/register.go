@@ -0,0 +1,21 @@
+package gwo
+
+import (
+	"golang-moaha-construction/internal/algorithms"
+	"golang-moaha-construction/internal/objectives"
+)
+
+func init() {
+	algorithms.Register(NameType, func(problem objectives.Problem, config Config) (algorithms.Algorithm, error) {
+		return Create(problem, config)
+	}, algorithms.ConfigSchema{
+		Label:          "Grey Wolf Algorithm",
+		TSName:         "GWO",
+		MultiObjective: false,
+		Parameters: []algorithms.Parameter{
+			{Key: "iterations", Label: "Number of iterations", Integer: true, Default: 300},
+			{Key: "population", Label: "Number of agents", Integer: true, Default: 100},
+			{Key: "aParam", Label: "A parameter", Default: 2},
+		},
+	})
+}
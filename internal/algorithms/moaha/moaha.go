@@ -1,13 +1,15 @@
 package moaha
 
 import (
+	"context"
 	"github.com/schollz/progressbar/v3"
 	"golang-moaha-construction/internal/algorithms"
 	"golang-moaha-construction/internal/data"
+	"golang-moaha-construction/internal/indicators"
 	"golang-moaha-construction/internal/objectives"
 	"golang-moaha-construction/internal/util"
 	"math"
-	"math/rand"
+	"math/rand/v2"
 	"slices"
 	"sync"
 )
@@ -15,19 +17,42 @@ import (
 const NameType algorithms.AlgorithmType = "MOAHA"
 
 type MOAHAAlgorithm struct {
-	NumberOfAgents    int
-	NumberOfIter      int
-	Agents            []*objectives.Result
-	Convergence       []float64
-	ObjectiveFunction objectives.Problem
-	ArchiveSize       int
-	Archive           []*objectives.Result
+	NumberOfAgents     int
+	NumberOfIter       int
+	Agents             []*objectives.Result
+	Convergence        []float64
+	ObjectiveFunction  objectives.Problem
+	ArchiveSize        int
+	Archive            []*objectives.Result
+	Seed               int64
+	ConstraintHandling objectives.ConstraintHandling
+
+	algorithms.Checkpoints[state]
+
+	history indicators.History
+	rng     *rand.Rand
+	src     *rand.PCG
+}
+
+// state is what a checkpoint holds to continue a run.
+type state struct {
+	Iteration  int
+	Agents     []*objectives.Result
+	Archive    []*objectives.Result
+	VisitTable [][]float64
+	Indicators indicators.History
+	Penalty    objectives.PenaltyState
+	Rand       []byte
 }
 
 type Configs struct {
-	NumAgents     int
-	NumIterations int
-	ArchiveSize   int
+	NumAgents     int `json:"population"`
+	NumIterations int `json:"iterations"`
+	ArchiveSize   int `json:"archiveSize"`
+	// Seed makes the run reproducible, 0 picks a random seed
+	Seed int64 `json:"seed"`
+	// ConstraintHandling is how results with violated constraints compare
+	ConstraintHandling objectives.ConstraintHandling `json:"constraintHandling"`
 }
 
 func Create(
@@ -35,44 +60,55 @@ func Create(
 	configs Configs,
 ) (*MOAHAAlgorithm, error) {
 
+	seed := configs.Seed
+	if seed == 0 {
+		seed = util.NewSeed()
+	}
+
 	return &MOAHAAlgorithm{
-		NumberOfAgents:    configs.NumAgents,
-		NumberOfIter:      configs.NumIterations,
-		ObjectiveFunction: problem,
-		ArchiveSize:       configs.ArchiveSize,
+		NumberOfAgents:     configs.NumAgents,
+		NumberOfIter:       configs.NumIterations,
+		ObjectiveFunction:  problem,
+		ArchiveSize:        configs.ArchiveSize,
+		Seed:               seed,
+		ConstraintHandling: configs.ConstraintHandling,
 	}, nil
 }
 
 func (a *MOAHAAlgorithm) reset() {
 	a.Agents = make([]*objectives.Result, a.NumberOfAgents)
 	a.Archive = make([]*objectives.Result, 0, a.ArchiveSize)
+	a.rng, a.src = util.NewRandSource(a.Seed)
+	a.history.Reset()
 }
 
 func (a *MOAHAAlgorithm) Type() data.TypeProblem {
 	return data.Multi
 }
 
-func (a *MOAHAAlgorithm) Run() error {
+func (a *MOAHAAlgorithm) Run(ctx context.Context) error {
 	dimensions := a.ObjectiveFunction.GetDimension()
 
-	a.reset()
-
-	// initialization
-	a.initialization()
-
-	a.Agents = objectives.DetermineDomination(a.Agents)
-	a.Archive = objectives.GetNonDominatedAgents(a.Agents)
-
-	l := 0
+	l, visitTable, err := a.start()
+	if err != nil {
+		return err
+	}
 
 	bar := progressbar.Default(int64(a.NumberOfIter))
+	_ = bar.Set(l)
 	//var wg sync.WaitGroup
 
-	visitTable := initializeNMMatrix(a.NumberOfAgents, a.NumberOfAgents)
-
 	for l < a.NumberOfIter {
+		if err := algorithms.Interrupted(ctx, a.ObjectiveFunction); err != nil {
+			return err
+		}
+
+		if a.ObjectiveFunction.UpdatePenalty(l, a.NumberOfIter, a.Agents) {
+			objectives.Rescore(a.ObjectiveFunction, a.Agents, a.Archive)
+		}
+
 		newPop := make([]*objectives.Result, 0)
-		agents, paretoFront := objectives.FastNonDominatedSorting_Vectorized(a.Agents)
+		agents, paretoFront := objectives.FastNonDominatedSorting_Vectorized(a.Agents, a.ConstraintHandling)
 
 		a.Agents = agents
 
@@ -82,17 +118,17 @@ func (a *MOAHAAlgorithm) Run() error {
 		//wg.Add(a.NumberOfAgents)
 		for agentIdx := range a.Agents {
 
-			r := rand.Float64()
+			r := a.rng.Float64()
 
 			//fmt.Println("")
 			if r < 1.0/3.0 {
 				// diagonal flight
-				randDim := util.RandN(dimensions)
+				randDim := util.RandN(a.rng, dimensions)
 				randNum := 0
 				if dimensions > 3 {
-					randNum = rand.Intn(dimensions - 1)
+					randNum = a.rng.IntN(dimensions - 1)
 				} else {
-					randNum = rand.Intn(dimensions)
+					randNum = a.rng.IntN(dimensions)
 				}
 
 				//test := []int{19, 28, 27, 7, 29, 20, 9, 4, 12, 15, 21, 6, 13, 25, 2, 23, 8, 26, 30, 1, 5, 14, 17, 24, 16, 10, 18, 22, 11, 3}
@@ -110,11 +146,11 @@ func (a *MOAHAAlgorithm) Run() error {
 				}
 			} else {
 				// axial flight
-				randNum := rand.Intn(dimensions)
+				randNum := a.rng.IntN(dimensions)
 				directVector[agentIdx][randNum] = 1
 			}
 
-			r = rand.Float64()
+			r = a.rng.Float64()
 			//fmt.Println()
 			if r < 0.5 {
 				// guided foraging
@@ -130,21 +166,23 @@ func (a *MOAHAAlgorithm) Run() error {
 
 		// migration foraging
 		if l%(a.NumberOfAgents*2) == 0 {
-			a.Agents, paretoFront = objectives.FastNonDominatedSorting_Vectorized(a.Agents)
+			a.Agents, paretoFront = objectives.FastNonDominatedSorting_Vectorized(a.Agents, a.ConstraintHandling)
 
 			for _, idx := range paretoFront[len(paretoFront)-1] {
 
 				for i := range a.Agents[idx].Position {
 					a.Agents[idx].Position[i] =
-						a.ObjectiveFunction.GetLowerBound()[i] + rand.Float64()*
+						a.ObjectiveFunction.GetLowerBound()[i] + a.rng.Float64()*
 							(a.ObjectiveFunction.GetUpperBound()[i]-a.ObjectiveFunction.GetLowerBound()[i])
 				}
 				// evaluate
-				value, valuesWithKey, keys, penalty := a.ObjectiveFunction.Eval(a.Agents[idx].Position)
+				value, valuesWithKey, keys, penalty, violations := a.ObjectiveFunction.Eval(a.ObjectiveFunction.Repair(a.Agents[idx].Position))
 
 				a.Agents[idx].Value = value
 				a.Agents[idx].ValuesWithKey = valuesWithKey
 				a.Agents[idx].Penalty = penalty
+				a.Agents[idx].Violations = violations
+				a.Agents[idx].Violation = objectives.TotalViolation(violations)
 				a.Agents[idx].Key = keys
 
 				for i := range visitTable[idx] {
@@ -163,12 +201,12 @@ func (a *MOAHAAlgorithm) Run() error {
 		}
 
 		// Determine Domination with a.Agents and newPop
-		newSolutions := objectives.DetermineDomination(objectives.MergeAgents(a.Agents, newPop))
+		newSolutions := objectives.DetermineDomination(objectives.MergeAgents(a.Agents, newPop), a.ConstraintHandling)
 		// Get Non-Dominated -> newNonDominatedPop
 		newNonDominatedPop := objectives.GetNonDominatedAgents(newSolutions)
 
 		// Determine Domination with newDominatedPop and a.Archive
-		newSolutions = objectives.DetermineDomination(objectives.MergeAgents(newNonDominatedPop, a.Archive))
+		newSolutions = objectives.DetermineDomination(objectives.MergeAgents(newNonDominatedPop, a.Archive), a.ConstraintHandling)
 		// Get Non-Dominated -> a.Archive
 		a.Archive = objectives.GetNonDominatedAgents(newSolutions)
 
@@ -180,29 +218,34 @@ func (a *MOAHAAlgorithm) Run() error {
 		bar.Add(1)
 
 		l++
+		a.history.Record(a.Archive)
+		a.checkpoint(l, visitTable)
 	}
 
-	return nil
+	return a.ObjectiveFunction.Err()
 }
 
-func (a *MOAHAAlgorithm) RunWithChannel(doneChan chan<- struct{}, channel chan<- any) error {
+func (a *MOAHAAlgorithm) RunWithChannel(ctx context.Context, channel chan<- any) error {
 	dimensions := a.ObjectiveFunction.GetDimension()
 
-	a.reset()
-
-	// initialization
-	a.initialization()
-
-	a.Agents = objectives.DetermineDomination(a.Agents)
-	a.Archive = objectives.GetNonDominatedAgents(a.Agents)
+	l, visitTable, err := a.start()
+	if err != nil {
+		close(channel)
+		return err
+	}
 
-	l := 0
+	for l < a.NumberOfIter {
+		if err := algorithms.Interrupted(ctx, a.ObjectiveFunction); err != nil {
+			close(channel)
+			return err
+		}
 
-	visitTable := initializeNMMatrix(a.NumberOfAgents, a.NumberOfAgents)
+		if a.ObjectiveFunction.UpdatePenalty(l, a.NumberOfIter, a.Agents) {
+			objectives.Rescore(a.ObjectiveFunction, a.Agents, a.Archive)
+		}
 
-	for l < a.NumberOfIter {
 		newPop := make([]*objectives.Result, 0)
-		agents, paretoFront := objectives.FastNonDominatedSorting_Vectorized(a.Agents)
+		agents, paretoFront := objectives.FastNonDominatedSorting_Vectorized(a.Agents, a.ConstraintHandling)
 
 		a.Agents = agents
 
@@ -212,17 +255,17 @@ func (a *MOAHAAlgorithm) RunWithChannel(doneChan chan<- struct{}, channel chan<-
 		//wg.Add(a.NumberOfAgents)
 		for agentIdx := range a.Agents {
 
-			r := rand.Float64()
+			r := a.rng.Float64()
 
 			//fmt.Println("")
 			if r < 1.0/3.0 {
 				// diagonal flight
-				randDim := util.RandN(dimensions)
+				randDim := util.RandN(a.rng, dimensions)
 				randNum := 0
 				if dimensions > 3 {
-					randNum = rand.Intn(dimensions - 1)
+					randNum = a.rng.IntN(dimensions - 1)
 				} else {
-					randNum = rand.Intn(dimensions)
+					randNum = a.rng.IntN(dimensions)
 				}
 
 				for i := 0; i < randNum; i++ {
@@ -237,11 +280,11 @@ func (a *MOAHAAlgorithm) RunWithChannel(doneChan chan<- struct{}, channel chan<-
 				}
 			} else {
 				// axial flight
-				randNum := rand.Intn(dimensions)
+				randNum := a.rng.IntN(dimensions)
 				directVector[agentIdx][randNum] = 1
 			}
 
-			r = rand.Float64()
+			r = a.rng.Float64()
 			if r < 0.5 {
 				// guided foraging
 				a.guidedForaging(visitTable, directVector, agentIdx, paretoFront, newPop)
@@ -254,21 +297,23 @@ func (a *MOAHAAlgorithm) RunWithChannel(doneChan chan<- struct{}, channel chan<-
 
 		// migration foraging
 		if l%(a.NumberOfAgents*2) == 0 {
-			a.Agents, paretoFront = objectives.FastNonDominatedSorting_Vectorized(a.Agents)
+			a.Agents, paretoFront = objectives.FastNonDominatedSorting_Vectorized(a.Agents, a.ConstraintHandling)
 
 			for _, idx := range paretoFront[len(paretoFront)-1] {
 
 				for i := range a.Agents[idx].Position {
 					a.Agents[idx].Position[i] =
-						a.ObjectiveFunction.GetLowerBound()[i] + rand.Float64()*
+						a.ObjectiveFunction.GetLowerBound()[i] + a.rng.Float64()*
 							(a.ObjectiveFunction.GetUpperBound()[i]-a.ObjectiveFunction.GetLowerBound()[i])
 				}
 				// evaluate
-				value, valuesWithKey, keys, penalty := a.ObjectiveFunction.Eval(a.Agents[idx].Position)
+				value, valuesWithKey, keys, penalty, violations := a.ObjectiveFunction.Eval(a.ObjectiveFunction.Repair(a.Agents[idx].Position))
 
 				a.Agents[idx].Value = value
 				a.Agents[idx].ValuesWithKey = valuesWithKey
 				a.Agents[idx].Penalty = penalty
+				a.Agents[idx].Violations = violations
+				a.Agents[idx].Violation = objectives.TotalViolation(violations)
 				a.Agents[idx].Key = keys
 
 				for i := range visitTable[idx] {
@@ -287,12 +332,12 @@ func (a *MOAHAAlgorithm) RunWithChannel(doneChan chan<- struct{}, channel chan<-
 		}
 
 		// Determine Domination with a.Agents and newPop
-		newSolutions := objectives.DetermineDomination(objectives.MergeAgents(a.Agents, newPop))
+		newSolutions := objectives.DetermineDomination(objectives.MergeAgents(a.Agents, newPop), a.ConstraintHandling)
 		// Get Non-Dominated -> newNonDominatedPop
 		newNonDominatedPop := objectives.GetNonDominatedAgents(newSolutions)
 
 		// Determine Domination with newDominatedPop and a.Archive
-		newSolutions = objectives.DetermineDomination(objectives.MergeAgents(newNonDominatedPop, a.Archive))
+		newSolutions = objectives.DetermineDomination(objectives.MergeAgents(newNonDominatedPop, a.Archive), a.ConstraintHandling)
 		// Get Non-Dominated -> a.Archive
 		a.Archive = objectives.GetNonDominatedAgents(newSolutions)
 
@@ -311,10 +356,12 @@ func (a *MOAHAAlgorithm) RunWithChannel(doneChan chan<- struct{}, channel chan<-
 		}
 
 		l++
+		a.history.Record(a.Archive)
+		a.checkpoint(l, visitTable)
 	}
 	close(channel)
 
-	return nil
+	return a.ObjectiveFunction.Err()
 }
 
 func (a *MOAHAAlgorithm) guidedForaging(visitTable [][]float64, directVector [][]float64, agentIdx int, paretoFront [][]int, tPop []*objectives.Result) {
@@ -344,14 +391,14 @@ func (a *MOAHAAlgorithm) guidedForaging(visitTable [][]float64, directVector [][
 			candidates = append(candidates, a.Agents[idx])
 		}
 
-		candidates = objectives.DetermineDomination(candidates)
+		candidates = objectives.DetermineDomination(candidates, a.ConstraintHandling)
 		for _, candidate := range candidates {
 			if !candidate.Dominated {
 				nonDominatedMUT = append(nonDominatedMUT, candidate)
 			}
 		}
 
-		candidateIdx := rand.Intn(len(nonDominatedMUT))
+		candidateIdx := a.rng.IntN(len(nonDominatedMUT))
 		//fmt.Println()
 		targetFoodIdx = candidateIdx
 	} else if len(maxValIdxs) == 1 {
@@ -360,7 +407,7 @@ func (a *MOAHAAlgorithm) guidedForaging(visitTable [][]float64, directVector [][
 		panic("len(maxValIdxs) = 0")
 	}
 
-	r := rand.NormFloat64()
+	r := a.rng.NormFloat64()
 	newPos := make([]float64, a.ObjectiveFunction.GetDimension())
 	for i := 0; i < a.ObjectiveFunction.GetDimension(); i++ {
 		newPos[i] = a.Agents[targetFoodIdx].Position[i] + r*math.Round(directVector[agentIdx][i])*
@@ -371,11 +418,13 @@ func (a *MOAHAAlgorithm) guidedForaging(visitTable [][]float64, directVector [][
 
 	newAgent := a.Agents[agentIdx].CopyAgent()
 
-	value, valuesWithKey, keys, penalty := a.ObjectiveFunction.Eval(newPos)
+	value, valuesWithKey, keys, penalty, violations := a.ObjectiveFunction.Eval(a.ObjectiveFunction.Repair(newPos))
 	newAgent.Position = newPos
 	newAgent.Value = value
 	newAgent.ValuesWithKey = valuesWithKey
 	newAgent.Penalty = penalty
+	newAgent.Violations = violations
+	newAgent.Violation = objectives.TotalViolation(violations)
 	newAgent.Key = keys
 
 	// Sanity check the index of current agent
@@ -390,16 +439,16 @@ func (a *MOAHAAlgorithm) guidedForaging(visitTable [][]float64, directVector [][
 	// compare the new value to other agents in the same front
 	dominatedFlag := 0
 	for _, v := range paretoFront[frontIdx] {
-		if newAgent.Dominates(a.Agents[v]) {
+		if newAgent.DominatesWith(a.Agents[v], a.ConstraintHandling) {
 			dominatedFlag = 1
 			break
-		} else if a.Agents[v].Dominates(newAgent) {
+		} else if a.Agents[v].DominatesWith(newAgent, a.ConstraintHandling) {
 			dominatedFlag = -1
 			break
 		}
 	}
 
-	newR := rand.Float64()
+	newR := a.rng.Float64()
 	if dominatedFlag == 1 || (dominatedFlag == 0 && newR > 0.5) {
 		tPop = append(tPop, a.Agents[agentIdx].CopyAgent())
 
@@ -433,8 +482,8 @@ func (a *MOAHAAlgorithm) guidedForaging(visitTable [][]float64, directVector [][
 }
 
 func (a *MOAHAAlgorithm) territoryForaging(visitTable [][]float64, directVector [][]float64, agentIdx int, paretoFront [][]int, tPop []*objectives.Result) {
-	r1 := rand.Float64()
-	r2 := rand.NormFloat64()
+	r1 := a.rng.Float64()
+	r2 := a.rng.NormFloat64()
 	newPos := make([]float64, a.ObjectiveFunction.GetDimension())
 	if r1 > 0.5 {
 		for i := 0; i < a.ObjectiveFunction.GetDimension(); i++ {
@@ -442,7 +491,7 @@ func (a *MOAHAAlgorithm) territoryForaging(visitTable [][]float64, directVector
 		}
 	} else {
 		// randomly selected from archive
-		selectedIdx := rand.Intn(len(a.Archive))
+		selectedIdx := a.rng.IntN(len(a.Archive))
 		agentInArchive := a.Archive[selectedIdx]
 		for i := 0; i < a.ObjectiveFunction.GetDimension(); i++ {
 			newPos[i] = agentInArchive.Position[i] + r2*math.Round(directVector[agentIdx][i])*agentInArchive.Position[i]
@@ -452,11 +501,13 @@ func (a *MOAHAAlgorithm) territoryForaging(visitTable [][]float64, directVector
 	a.outOfBoundaries(newPos)
 
 	newAgent := a.Agents[agentIdx].CopyAgent()
-	value, valuesWithKey, keys, penalty := a.ObjectiveFunction.Eval(newPos)
+	value, valuesWithKey, keys, penalty, violations := a.ObjectiveFunction.Eval(a.ObjectiveFunction.Repair(newPos))
 	newAgent.Position = newPos
 	newAgent.Value = value
 	newAgent.ValuesWithKey = valuesWithKey
 	newAgent.Penalty = penalty
+	newAgent.Violations = violations
+	newAgent.Violation = objectives.TotalViolation(violations)
 	newAgent.Key = keys
 
 	// Sanity check the index of current agent
@@ -471,16 +522,16 @@ func (a *MOAHAAlgorithm) territoryForaging(visitTable [][]float64, directVector
 	// compare the new value to other agents in the same front
 	dominatedFlag := 0
 	for _, v := range paretoFront[frontIdx] {
-		if newAgent.Dominates(a.Agents[v]) {
+		if newAgent.DominatesWith(a.Agents[v], a.ConstraintHandling) {
 			dominatedFlag = 1
 			break
-		} else if a.Agents[v].Dominates(newAgent) {
+		} else if a.Agents[v].DominatesWith(newAgent, a.ConstraintHandling) {
 			dominatedFlag = -1
 			break
 		}
 	}
 
-	newR := rand.Float64()
+	newR := a.rng.Float64()
 	if dominatedFlag == 1 || (dominatedFlag == 0 && newR > 0.5) {
 		tPop = append(tPop, a.Agents[agentIdx].CopyAgent())
 
@@ -507,6 +558,69 @@ func (a *MOAHAAlgorithm) territoryForaging(visitTable [][]float64, directVector
 	}
 }
 
+// LoadState refuses a state whose agents, archive or visit table do not fit
+// the config, e.g. after the population size has changed.
+func (a *MOAHAAlgorithm) LoadState(encoded []byte) error {
+	return a.LoadStateChecked(encoded, func(s *state) error {
+		err := algorithms.CheckStateSize("agents", len(s.Agents), a.NumberOfAgents)
+		if err != nil {
+			return err
+		}
+
+		err = algorithms.CheckArchiveSize(len(s.Archive), a.ArchiveSize)
+		if err != nil {
+			return err
+		}
+
+		return algorithms.CheckVisitTable(s.VisitTable, a.NumberOfAgents)
+	})
+}
+
+// start resets the algorithm and either initializes a new population and
+// archive or restores the state loaded by LoadState. It returns the iteration
+// to continue from and the visit table.
+func (a *MOAHAAlgorithm) start() (int, [][]float64, error) {
+	a.reset()
+
+	s := a.Resume()
+	if s == nil {
+		a.initialization()
+
+		a.Agents = objectives.DetermineDomination(a.Agents, a.ConstraintHandling)
+		a.Archive = objectives.GetNonDominatedAgents(a.Agents)
+
+		return 0, initializeNMMatrix(a.NumberOfAgents, a.NumberOfAgents), nil
+	}
+
+	err := a.src.UnmarshalBinary(s.Rand)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	a.ObjectiveFunction.SetPenaltyState(s.Penalty)
+
+	a.Agents = s.Agents
+	a.Archive = s.Archive
+	a.history = s.Indicators
+
+	return s.Iteration, s.VisitTable, nil
+}
+
+func (a *MOAHAAlgorithm) checkpoint(l int, visitTable [][]float64) {
+	a.Save(l, func() state {
+		rngState, _ := a.src.MarshalBinary()
+		return state{
+			Iteration:  l,
+			Agents:     a.Agents,
+			Archive:    a.Archive,
+			VisitTable: visitTable,
+			Indicators: a.history,
+			Penalty:    a.ObjectiveFunction.PenaltyState(),
+			Rand:       rngState,
+		}
+	})
+}
+
 func (a *MOAHAAlgorithm) initialization() {
 
 	vals := make([]float64, a.ObjectiveFunction.NumberOfObjectives())
@@ -518,6 +632,9 @@ func (a *MOAHAAlgorithm) initialization() {
 		}
 	}
 
+	// one stream per agent so the initial population does not depend on scheduling
+	rngs := util.SplitRand(a.rng, a.NumberOfAgents)
+
 	var wg sync.WaitGroup
 	wg.Add(a.NumberOfAgents)
 	for agentIdx := 0; agentIdx < a.NumberOfAgents; agentIdx++ {
@@ -526,7 +643,7 @@ func (a *MOAHAAlgorithm) initialization() {
 			positions := make([]float64, a.ObjectiveFunction.GetDimension())
 
 			for i := 0; i < a.ObjectiveFunction.GetDimension(); i++ {
-				positions[i] = a.ObjectiveFunction.GetLowerBound()[i] + rand.Float64()*
+				positions[i] = a.ObjectiveFunction.GetLowerBound()[i] + rngs[agentIdx].Float64()*
 					(a.ObjectiveFunction.GetUpperBound()[i]-a.ObjectiveFunction.GetLowerBound()[i])
 			}
 
@@ -536,10 +653,12 @@ func (a *MOAHAAlgorithm) initialization() {
 				Position: positions,
 			}
 
-			value, valuesWithKey, keys, penalty := a.ObjectiveFunction.Eval(positions)
+			value, valuesWithKey, keys, penalty, violations := a.ObjectiveFunction.Eval(a.ObjectiveFunction.Repair(positions))
 			newAgent.Value = value
 			newAgent.ValuesWithKey = valuesWithKey
 			newAgent.Penalty = penalty
+			newAgent.Violations = violations
+			newAgent.Violation = objectives.TotalViolation(violations)
 			newAgent.Key = keys
 
 			a.Agents[agentIdx] = newAgent
@@ -570,6 +689,7 @@ func (a *MOAHAAlgorithm) GetResults() algorithms.Result {
 
 		results[i] = algorithms.AlgorithmResult{
 			MapLocations:   mapLoc,
+			PhaseLocations: objectives.PhaseLocationResult(a.ObjectiveFunction, res.Position),
 			SliceLocations: sliceLoc,
 			Value:          res.Value,
 			Key:            res.Key,
@@ -583,11 +703,13 @@ func (a *MOAHAAlgorithm) GetResults() algorithms.Result {
 	minX, maxX, minY, maxY, _ := a.ObjectiveFunction.GetLayoutSize()
 
 	return algorithms.Result{
-		Result: results,
-		MinX:   minX,
-		MinY:   minY,
-		MaxX:   maxX,
-		MaxY:   maxY,
+		Result:     results,
+		MinX:       minX,
+		MinY:       minY,
+		MaxX:       maxX,
+		MaxY:       maxY,
+		Seed:       a.Seed,
+		Indicators: a.history.Values(),
 	}
 }
 
@@ -1,12 +1,15 @@
 package mogwo
 
 import (
+	"context"
 	"golang-moaha-construction/internal/algorithms"
 	"golang-moaha-construction/internal/data"
+	"golang-moaha-construction/internal/indicators"
 	"golang-moaha-construction/internal/objectives"
 	"golang-moaha-construction/internal/util"
+	"maps"
 	"math"
-	"math/rand"
+	"math/rand/v2"
 	"slices"
 	"sync"
 )
@@ -67,22 +70,48 @@ type MOGWOAlgorithm struct {
 	Archive           []*objectives.Result
 	ObjectiveFunction objectives.Problem
 	// for multi-objective hypercubes
-	NumberOfGrids int
-	Gamma         float64
-	Alpha         float64
-	Beta          float64
-	hypercube     Hypercube
+	NumberOfGrids      int
+	Gamma              float64
+	Alpha              float64
+	Beta               float64
+	hypercube          Hypercube
+	Seed               int64
+	ConstraintHandling objectives.ConstraintHandling
+
+	algorithms.Checkpoints[state]
+
+	history indicators.History
+	rng     *rand.Rand
+	src     *rand.PCG
+}
+
+// state is what a checkpoint holds to continue a run.
+type state struct {
+	Iteration  int
+	Agents     []*objectives.Result
+	Archive    []*objectives.Result
+	AlphaWolf  *objectives.Result
+	BetaWolf   *objectives.Result
+	GammaWolf  *objectives.Result
+	Hypercube  Hypercube
+	Indicators indicators.History
+	Penalty    objectives.PenaltyState
+	Rand       []byte
 }
 
 type Config struct {
-	NumberOfAgents int
-	NumberOfIter   int
-	AParam         float64
-	ArchiveSize    int
-	NumberOfGrids  int
-	Gamma          float64
-	Alpha          float64
-	Beta           float64
+	NumberOfAgents int     `json:"population"`
+	NumberOfIter   int     `json:"iterations"`
+	AParam         float64 `json:"aParam"`
+	ArchiveSize    int     `json:"archiveSize"`
+	NumberOfGrids  int     `json:"numberOfGrids"`
+	Gamma          float64 `json:"gamma"`
+	Alpha          float64 `json:"alpha"`
+	Beta           float64 `json:"beta"`
+	// Seed makes the run reproducible, 0 picks a random seed
+	Seed int64 `json:"seed"`
+	// ConstraintHandling is how results with violated constraints compare
+	ConstraintHandling objectives.ConstraintHandling `json:"constraintHandling"`
 }
 
 func Create(
@@ -90,6 +119,11 @@ func Create(
 	configs Config,
 ) (*MOGWOAlgorithm, error) {
 
+	seed := configs.Seed
+	if seed == 0 {
+		seed = util.NewSeed()
+	}
+
 	return &MOGWOAlgorithm{
 		NumberOfAgents:    configs.NumberOfAgents,
 		NumberOfIter:      configs.NumberOfIter,
@@ -104,52 +138,55 @@ func Create(
 			NumberOfGrids: configs.NumberOfGrids,
 			Alpha:         configs.Alpha,
 		},
+		Seed:               seed,
+		ConstraintHandling: configs.ConstraintHandling,
 	}, nil
 }
 
 func (g *MOGWOAlgorithm) reset() {
 	g.Agents = make([]*objectives.Result, g.NumberOfAgents)
 	g.Archive = make([]*objectives.Result, 0, g.ArchiveSize)
+	g.rng, g.src = util.NewRandSource(g.Seed)
+	g.history.Reset()
 }
 
 func (g *MOGWOAlgorithm) Type() data.TypeProblem {
-	return data.Single
+	return data.Multi
 }
 
-func (g *MOGWOAlgorithm) Run() error {
-	g.reset()
-
-	// initialization
-	g.initialization()
-
-	g.Agents = objectives.DetermineDomination(g.Agents)
-	g.Archive = objectives.GetNonDominatedAgents(g.Agents)
-	g.hypercube.UpdateHyperCube(getResultsFromArchive(g.Archive))
-
-	for _, val := range g.Archive {
-		g.hypercube.getGridIndex(val)
+func (g *MOGWOAlgorithm) Run(ctx context.Context) error {
+	l, err := g.start()
+	if err != nil {
+		return err
 	}
 
-	l := 0
 	a := g.AParam
 
 	for l < g.NumberOfIter {
+		if err := algorithms.Interrupted(ctx, g.ObjectiveFunction); err != nil {
+			return err
+		}
+
+		if g.ObjectiveFunction.UpdatePenalty(l, g.NumberOfIter, g.Agents) {
+			g.rescore()
+		}
+
 		a = 2.0 - float64(l)*(2.0/float64(g.NumberOfIter))
 
 		for agentIdx := range g.Agents {
 			// Choose the alpha, beta, and gamma grey wolves
 			if len(g.Archive) > 0 {
-				gammaLeader := selectLeader(g.Archive, g.Beta)
+				gammaLeader := selectLeader(g.rng, g.Archive, g.Beta)
 				if gammaLeader != nil {
 					g.GammaWolf = gammaLeader
 				}
 
-				betaLeader := selectLeader(g.Archive, g.Beta)
+				betaLeader := selectLeader(g.rng, g.Archive, g.Beta)
 				if betaLeader != nil {
 					g.BetaWolf = betaLeader
 				}
 
-				alphaLeader := selectLeader(g.Archive, g.Beta)
+				alphaLeader := selectLeader(g.rng, g.Archive, g.Beta)
 				if alphaLeader != nil {
 					g.AlphaWolf = alphaLeader
 				}
@@ -168,7 +205,7 @@ func (g *MOGWOAlgorithm) Run() error {
 					}
 
 					if len(rep2) > 0 {
-						betaLeader = selectLeader(rep2, g.Beta)
+						betaLeader = selectLeader(g.rng, rep2, g.Beta)
 						if betaLeader != nil {
 							g.BetaWolf = betaLeader
 						}
@@ -188,7 +225,7 @@ func (g *MOGWOAlgorithm) Run() error {
 					}
 
 					if len(rep3) > 0 {
-						alphaLeader = selectLeader(rep3, g.Beta)
+						alphaLeader = selectLeader(g.rng, rep3, g.Beta)
 						if alphaLeader != nil {
 							g.AlphaWolf = alphaLeader
 						}
@@ -198,24 +235,24 @@ func (g *MOGWOAlgorithm) Run() error {
 
 			for posIdx := range g.Agents[agentIdx].Position {
 				// Alpha
-				r1 := rand.Float64()
-				r2 := rand.Float64()
+				r1 := g.rng.Float64()
+				r2 := g.rng.Float64()
 				A := 2*a*r1 - a
 				C := 2 * r2
 				D := math.Abs(C*g.AlphaWolf.Position[posIdx] - g.Agents[agentIdx].Position[posIdx])
 				XAlpha := g.AlphaWolf.Position[posIdx] - A*D
 
 				// Beta
-				r1 = rand.Float64()
-				r2 = rand.Float64()
+				r1 = g.rng.Float64()
+				r2 = g.rng.Float64()
 				A = 2*a*r1 - a
 				C = 2 * r2
 				D = math.Abs(C*g.BetaWolf.Position[posIdx] - g.Agents[agentIdx].Position[posIdx])
 				XBeta := g.BetaWolf.Position[posIdx] - A*D
 
 				// Gamma
-				r1 = rand.Float64()
-				r2 = rand.Float64()
+				r1 = g.rng.Float64()
+				r2 = g.rng.Float64()
 				A = 2*a*r1 - a
 				C = 2 * r2
 				D = math.Abs(C*g.GammaWolf.Position[posIdx] - g.Agents[agentIdx].Position[posIdx])
@@ -227,17 +264,19 @@ func (g *MOGWOAlgorithm) Run() error {
 			g.outOfBoundaries(g.Agents[agentIdx].Position)
 
 			// evaluate
-			value, valuesWithKey, keys, penalty := g.ObjectiveFunction.Eval(g.Agents[agentIdx].Position)
+			value, valuesWithKey, keys, penalty, violations := g.ObjectiveFunction.Eval(g.ObjectiveFunction.Repair(g.Agents[agentIdx].Position))
 			g.Agents[agentIdx].Value = value
 			g.Agents[agentIdx].Penalty = penalty
+			g.Agents[agentIdx].Violations = violations
+			g.Agents[agentIdx].Violation = objectives.TotalViolation(violations)
 			g.Agents[agentIdx].Key = keys
 			g.Agents[agentIdx].ValuesWithKey = valuesWithKey
 		}
 
-		newSolutions := objectives.DetermineDomination(g.Agents)
+		newSolutions := objectives.DetermineDomination(g.Agents, g.ConstraintHandling)
 		newNonDominatedPop := objectives.GetNonDominatedAgents(newSolutions)
 
-		newSolutions = objectives.DetermineDomination(objectives.MergeAgents(newNonDominatedPop, g.Archive))
+		newSolutions = objectives.DetermineDomination(objectives.MergeAgents(newNonDominatedPop, g.Archive), g.ConstraintHandling)
 		g.Archive = objectives.GetNonDominatedAgents(newSolutions)
 		g.hypercube.UpdateHyperCube(getResultsFromArchive(g.Archive))
 
@@ -247,51 +286,54 @@ func (g *MOGWOAlgorithm) Run() error {
 
 		if len(g.Archive) > g.ArchiveSize {
 			exceeded := len(g.Archive) - g.ArchiveSize
-			g.Archive = removeExtraInArchive(g.Archive, exceeded, g.Gamma)
+			g.Archive = removeExtraInArchive(g.rng, g.Archive, exceeded, g.Gamma)
 			g.hypercube.UpdateHyperCube(getResultsFromArchive(g.Archive))
 		}
 
 		l++
+		g.history.Record(g.Archive)
+		g.checkpoint(l)
 	}
 
-	return nil
+	return g.ObjectiveFunction.Err()
 }
 
-func (g *MOGWOAlgorithm) RunWithChannel(doneChan chan<- struct{}, channel chan<- any) error {
-
-	g.reset()
-
-	// initialization
-	g.initialization()
+func (g *MOGWOAlgorithm) RunWithChannel(ctx context.Context, channel chan<- any) error {
 
-	g.Agents = objectives.DetermineDomination(g.Agents)
-	g.Archive = objectives.GetNonDominatedAgents(g.Agents)
-	g.hypercube.UpdateHyperCube(getResultsFromArchive(g.Archive))
-
-	for _, val := range g.Archive {
-		g.hypercube.getGridIndex(val)
+	l, err := g.start()
+	if err != nil {
+		close(channel)
+		return err
 	}
 
-	l := 0
 	a := g.AParam
 
 	for l < g.NumberOfIter {
+		if err := algorithms.Interrupted(ctx, g.ObjectiveFunction); err != nil {
+			close(channel)
+			return err
+		}
+
+		if g.ObjectiveFunction.UpdatePenalty(l, g.NumberOfIter, g.Agents) {
+			g.rescore()
+		}
+
 		a = 2.0 - float64(l)*(2.0/float64(g.NumberOfIter))
 
 		for agentIdx := range g.Agents {
 			// Choose the alpha, beta, and gamma grey wolves
 			if len(g.Archive) > 0 {
-				gammaLeader := selectLeader(g.Archive, g.Beta)
+				gammaLeader := selectLeader(g.rng, g.Archive, g.Beta)
 				if gammaLeader != nil {
 					g.GammaWolf = gammaLeader
 				}
 
-				betaLeader := selectLeader(g.Archive, g.Beta)
+				betaLeader := selectLeader(g.rng, g.Archive, g.Beta)
 				if betaLeader != nil {
 					g.BetaWolf = betaLeader
 				}
 
-				alphaLeader := selectLeader(g.Archive, g.Beta)
+				alphaLeader := selectLeader(g.rng, g.Archive, g.Beta)
 				if alphaLeader != nil {
 					g.AlphaWolf = alphaLeader
 				}
@@ -310,7 +352,7 @@ func (g *MOGWOAlgorithm) RunWithChannel(doneChan chan<- struct{}, channel chan<-
 					}
 
 					if len(rep2) > 0 {
-						betaLeader = selectLeader(rep2, g.Beta)
+						betaLeader = selectLeader(g.rng, rep2, g.Beta)
 						if betaLeader != nil {
 							g.BetaWolf = betaLeader
 						}
@@ -330,7 +372,7 @@ func (g *MOGWOAlgorithm) RunWithChannel(doneChan chan<- struct{}, channel chan<-
 					}
 
 					if len(rep3) > 0 {
-						alphaLeader = selectLeader(rep3, g.Beta)
+						alphaLeader = selectLeader(g.rng, rep3, g.Beta)
 						if alphaLeader != nil {
 							g.AlphaWolf = alphaLeader
 						}
@@ -340,24 +382,24 @@ func (g *MOGWOAlgorithm) RunWithChannel(doneChan chan<- struct{}, channel chan<-
 
 			for posIdx := range g.Agents[agentIdx].Position {
 				// Alpha
-				r1 := rand.Float64()
-				r2 := rand.Float64()
+				r1 := g.rng.Float64()
+				r2 := g.rng.Float64()
 				A := 2*a*r1 - a
 				C := 2 * r2
 				D := math.Abs(C*g.AlphaWolf.Position[posIdx] - g.Agents[agentIdx].Position[posIdx])
 				XAlpha := g.AlphaWolf.Position[posIdx] - A*D
 
 				// Beta
-				r1 = rand.Float64()
-				r2 = rand.Float64()
+				r1 = g.rng.Float64()
+				r2 = g.rng.Float64()
 				A = 2*a*r1 - a
 				C = 2 * r2
 				D = math.Abs(C*g.BetaWolf.Position[posIdx] - g.Agents[agentIdx].Position[posIdx])
 				XBeta := g.BetaWolf.Position[posIdx] - A*D
 
 				// Gamma
-				r1 = rand.Float64()
-				r2 = rand.Float64()
+				r1 = g.rng.Float64()
+				r2 = g.rng.Float64()
 				A = 2*a*r1 - a
 				C = 2 * r2
 				D = math.Abs(C*g.GammaWolf.Position[posIdx] - g.Agents[agentIdx].Position[posIdx])
@@ -369,17 +411,19 @@ func (g *MOGWOAlgorithm) RunWithChannel(doneChan chan<- struct{}, channel chan<-
 			g.outOfBoundaries(g.Agents[agentIdx].Position)
 
 			// evaluate
-			value, valuesWithKey, keys, penalty := g.ObjectiveFunction.Eval(g.Agents[agentIdx].Position)
+			value, valuesWithKey, keys, penalty, violations := g.ObjectiveFunction.Eval(g.ObjectiveFunction.Repair(g.Agents[agentIdx].Position))
 			g.Agents[agentIdx].Value = value
 			g.Agents[agentIdx].Penalty = penalty
+			g.Agents[agentIdx].Violations = violations
+			g.Agents[agentIdx].Violation = objectives.TotalViolation(violations)
 			g.Agents[agentIdx].Key = keys
 			g.Agents[agentIdx].ValuesWithKey = valuesWithKey
 		}
 
-		newSolutions := objectives.DetermineDomination(g.Agents)
+		newSolutions := objectives.DetermineDomination(g.Agents, g.ConstraintHandling)
 		newNonDominatedPop := objectives.GetNonDominatedAgents(newSolutions)
 
-		newSolutions = objectives.DetermineDomination(objectives.MergeAgents(newNonDominatedPop, g.Archive))
+		newSolutions = objectives.DetermineDomination(objectives.MergeAgents(newNonDominatedPop, g.Archive), g.ConstraintHandling)
 		g.Archive = objectives.GetNonDominatedAgents(newSolutions)
 		g.hypercube.UpdateHyperCube(getResultsFromArchive(g.Archive))
 
@@ -389,7 +433,7 @@ func (g *MOGWOAlgorithm) RunWithChannel(doneChan chan<- struct{}, channel chan<-
 
 		if len(g.Archive) > g.ArchiveSize {
 			exceeded := len(g.Archive) - g.ArchiveSize
-			g.Archive = removeExtraInArchive(g.Archive, exceeded, g.Gamma)
+			g.Archive = removeExtraInArchive(g.rng, g.Archive, exceeded, g.Gamma)
 			g.hypercube.UpdateHyperCube(getResultsFromArchive(g.Archive))
 		}
 
@@ -404,11 +448,96 @@ func (g *MOGWOAlgorithm) RunWithChannel(doneChan chan<- struct{}, channel chan<-
 		}
 
 		l++
+		g.history.Record(g.Archive)
+		g.checkpoint(l)
 	}
 
 	close(channel)
 
-	return nil
+	return g.ObjectiveFunction.Err()
+}
+
+// LoadState refuses a state whose agents or archive do not fit the config,
+// e.g. after the population size has changed.
+func (g *MOGWOAlgorithm) LoadState(encoded []byte) error {
+	return g.LoadStateChecked(encoded, func(s *state) error {
+		err := algorithms.CheckStateSize("agents", len(s.Agents), g.NumberOfAgents)
+		if err != nil {
+			return err
+		}
+
+		return algorithms.CheckArchiveSize(len(s.Archive), g.ArchiveSize)
+	})
+}
+
+// start resets the algorithm and either initializes a new population and
+// archive or restores the state loaded by LoadState. It returns the iteration
+// to continue from.
+func (g *MOGWOAlgorithm) start() (int, error) {
+	g.reset()
+
+	s := g.Resume()
+	if s == nil {
+		// initialization
+		g.initialization()
+
+		g.Agents = objectives.DetermineDomination(g.Agents, g.ConstraintHandling)
+		g.Archive = objectives.GetNonDominatedAgents(g.Agents)
+		g.hypercube.UpdateHyperCube(getResultsFromArchive(g.Archive))
+
+		for _, val := range g.Archive {
+			g.hypercube.getGridIndex(val)
+		}
+
+		return 0, nil
+	}
+
+	err := g.src.UnmarshalBinary(s.Rand)
+	if err != nil {
+		return 0, err
+	}
+
+	g.ObjectiveFunction.SetPenaltyState(s.Penalty)
+
+	g.Agents = s.Agents
+	g.Archive = s.Archive
+	g.AlphaWolf = s.AlphaWolf
+	g.BetaWolf = s.BetaWolf
+	g.GammaWolf = s.GammaWolf
+	g.hypercube = s.Hypercube
+	g.history = s.Indicators
+
+	return s.Iteration, nil
+}
+
+func (g *MOGWOAlgorithm) checkpoint(l int) {
+	g.Save(l, func() state {
+		rngState, _ := g.src.MarshalBinary()
+		return state{
+			Iteration:  l,
+			Agents:     g.Agents,
+			Archive:    g.Archive,
+			AlphaWolf:  g.AlphaWolf,
+			BetaWolf:   g.BetaWolf,
+			GammaWolf:  g.GammaWolf,
+			Hypercube:  g.hypercube,
+			Indicators: g.history,
+			Penalty:    g.ObjectiveFunction.PenaltyState(),
+			Rand:       rngState,
+		}
+	})
+}
+
+// rescore evaluates the archive and the leaders again after the penalty
+// factors changed, the agents are evaluated again in the iteration, and puts
+// the archive back into the hypercube.
+func (g *MOGWOAlgorithm) rescore() {
+	objectives.Rescore(g.ObjectiveFunction, g.Archive, []*objectives.Result{g.AlphaWolf, g.BetaWolf, g.GammaWolf})
+
+	g.hypercube.UpdateHyperCube(getResultsFromArchive(g.Archive))
+	for _, val := range g.Archive {
+		g.hypercube.getGridIndex(val)
+	}
 }
 
 func (g *MOGWOAlgorithm) initialization() {
@@ -422,6 +551,9 @@ func (g *MOGWOAlgorithm) initialization() {
 		}
 	}
 
+	// one stream per agent so the initial population does not depend on scheduling
+	rngs := util.SplitRand(g.rng, g.NumberOfAgents)
+
 	var wg sync.WaitGroup
 	wg.Add(g.NumberOfAgents)
 	for agentIdx := 0; agentIdx < g.NumberOfAgents; agentIdx++ {
@@ -430,7 +562,7 @@ func (g *MOGWOAlgorithm) initialization() {
 			positions := make([]float64, g.ObjectiveFunction.GetDimension())
 
 			for i := 0; i < g.ObjectiveFunction.GetDimension(); i++ {
-				positions[i] = g.ObjectiveFunction.GetLowerBound()[i] + rand.Float64()*
+				positions[i] = g.ObjectiveFunction.GetLowerBound()[i] + rngs[agentIdx].Float64()*
 					(g.ObjectiveFunction.GetUpperBound()[i]-g.ObjectiveFunction.GetLowerBound()[i])
 			}
 
@@ -440,10 +572,12 @@ func (g *MOGWOAlgorithm) initialization() {
 				Position: positions,
 			}
 
-			value, valuesWithKey, keys, penalty := g.ObjectiveFunction.Eval(positions)
+			value, valuesWithKey, keys, penalty, violations := g.ObjectiveFunction.Eval(g.ObjectiveFunction.Repair(positions))
 			newAgent.Value = value
 			newAgent.ValuesWithKey = valuesWithKey
 			newAgent.Penalty = penalty
+			newAgent.Violations = violations
+			newAgent.Violation = objectives.TotalViolation(violations)
 			newAgent.Key = keys
 
 			g.Agents[agentIdx] = newAgent
@@ -474,6 +608,7 @@ func (g *MOGWOAlgorithm) GetResults() algorithms.Result {
 
 		results[i] = algorithms.AlgorithmResult{
 			MapLocations:   mapLoc,
+			PhaseLocations: objectives.PhaseLocationResult(g.ObjectiveFunction, res.Position),
 			SliceLocations: sliceLoc,
 			Value:          res.Value,
 			Key:            res.Key,
@@ -487,11 +622,13 @@ func (g *MOGWOAlgorithm) GetResults() algorithms.Result {
 	minX, maxX, minY, maxY, _ := g.ObjectiveFunction.GetLayoutSize()
 
 	return algorithms.Result{
-		Result: results,
-		MinX:   minX,
-		MinY:   minY,
-		MaxX:   maxX,
-		MaxY:   maxY,
+		Result:     results,
+		MinX:       minX,
+		MinY:       minY,
+		MaxX:       maxX,
+		MaxY:       maxY,
+		Seed:       g.Seed,
+		Indicators: g.history.Values(),
 	}
 }
 
@@ -573,15 +710,16 @@ func getOccupiedCells(archive []*objectives.Result) ([]int, []int) {
 	occCellIndex := make([]int, 0, len(cellCountMap))
 	occCellMemberCount := make([]int, 0, len(cellCountMap))
 
-	for cellIdx, count := range cellCountMap {
+	// iterate in cell order so that roulette selection is reproducible
+	for _, cellIdx := range slices.Sorted(maps.Keys(cellCountMap)) {
 		occCellIndex = append(occCellIndex, cellIdx)
-		occCellMemberCount = append(occCellMemberCount, count)
+		occCellMemberCount = append(occCellMemberCount, cellCountMap[cellIdx])
 	}
 
 	return occCellIndex, occCellMemberCount
 }
 
-func removeExtraInArchive(archive []*objectives.Result, exceeded int, gamma float64) []*objectives.Result {
+func removeExtraInArchive(r *rand.Rand, archive []*objectives.Result, exceeded int, gamma float64) []*objectives.Result {
 	if gamma == 0 {
 		gamma = 1
 	}
@@ -604,7 +742,7 @@ func removeExtraInArchive(archive []*objectives.Result, exceeded int, gamma floa
 		}
 
 		// Select a cell using roulette wheel selection
-		selectedCellIndex := occCellIndex[util.RouletteWheelSelection(p)]
+		selectedCellIndex := occCellIndex[util.RouletteWheelSelection(r, p)]
 
 		// Find members in the selected cell
 		selectedCellMembers := make([]int, 0)
@@ -619,7 +757,7 @@ func removeExtraInArchive(archive []*objectives.Result, exceeded int, gamma floa
 		if n == 0 {
 			continue
 		}
-		selectedMemberIndex := selectedCellMembers[rand.Intn(n)]
+		selectedMemberIndex := selectedCellMembers[r.IntN(n)]
 
 		// Remove the selected member from the archive
 		archive = append(archive[:selectedMemberIndex], archive[selectedMemberIndex+1:]...)
@@ -631,7 +769,7 @@ func removeExtraInArchive(archive []*objectives.Result, exceeded int, gamma floa
 // SelectLeader selects a leader from the repository based on grid indices.
 // It takes a repository of solutions and a beta parameter (default 1).
 // The function returns the selected leader.
-func selectLeader(rep []*objectives.Result, beta float64) *objectives.Result {
+func selectLeader(r *rand.Rand, rep []*objectives.Result, beta float64) *objectives.Result {
 	// Set default value for beta if not provided
 	if beta == 0 {
 		beta = 1
@@ -654,7 +792,7 @@ func selectLeader(rep []*objectives.Result, beta float64) *objectives.Result {
 	}
 
 	// Select a cell using roulette wheel selection
-	selectedCellIndex := occCellIndex[util.RouletteWheelSelection(p)]
+	selectedCellIndex := occCellIndex[util.RouletteWheelSelection(r, p)]
 
 	// Find members in the selected cell
 	selectedCellMembers := make([]int, 0)
@@ -669,7 +807,7 @@ func selectLeader(rep []*objectives.Result, beta float64) *objectives.Result {
 	if n == 0 {
 		return nil
 	}
-	selectedMemberIndex := selectedCellMembers[rand.Intn(n)]
+	selectedMemberIndex := selectedCellMembers[r.IntN(n)]
 
 	// Return the selected member
 	return rep[selectedMemberIndex]
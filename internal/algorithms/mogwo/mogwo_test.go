@@ -267,7 +267,7 @@ func TestRemoveExtraInArchive(t *testing.T) {
 			}
 
 			// Call the function
-			result := removeExtraInArchive(testArchive, tt.exceeded, tt.gamma)
+			result := removeExtraInArchive(util.NewRand(1), testArchive, tt.exceeded, tt.gamma)
 
 			// Verify the results
 			expectedLen := len(testArchive) - tt.exceeded
@@ -0,0 +1,26 @@
+package mogwo
+
+import (
+	"golang-moaha-construction/internal/algorithms"
+	"golang-moaha-construction/internal/objectives"
+)
+
+func init() {
+	algorithms.Register(NameType, func(problem objectives.Problem, config Config) (algorithms.Algorithm, error) {
+		return Create(problem, config)
+	}, algorithms.ConfigSchema{
+		Label:          "Multi-Objective Grey Wolf Optimizer (MOGWO)",
+		TSName:         "MOGWO",
+		MultiObjective: true,
+		Parameters: []algorithms.Parameter{
+			{Key: "iterations", Label: "Number of iterations", Integer: true, Default: 300},
+			{Key: "population", Label: "Number of agents", Integer: true, Default: 100},
+			{Key: "archiveSize", Label: "Archive size", Integer: true, Default: 100},
+			{Key: "aParam", Label: "A parameter", Default: 2},
+			{Key: "numberOfGrids", Label: "Number of grids", Integer: true, Default: 10},
+			{Key: "alpha", Label: "Alpha (grid inflation)", Default: 0.1},
+			{Key: "beta", Label: "Beta (leader selection pressure)", Default: 4},
+			{Key: "gamma", Label: "Gamma (archive deletion pressure)", Default: 2},
+		},
+	})
+}
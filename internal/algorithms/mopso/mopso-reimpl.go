@@ -1,18 +1,24 @@
 package mopso
 
 import (
+	"context"
 	"fmt"
 	"github.com/schollz/progressbar/v3"
 	"golang-moaha-construction/internal/algorithms"
 	"golang-moaha-construction/internal/data"
+	"golang-moaha-construction/internal/indicators"
 	"golang-moaha-construction/internal/objectives"
 	"golang-moaha-construction/internal/util"
 	"math"
-	"math/rand"
+	"math/rand/v2"
 	"slices"
 	"sync"
 )
 
+// ReimplNameType is the name of the reimplementation of MOPSO, registered
+// next to MOPSO.
+const ReimplNameType algorithms.AlgorithmType = "MOPSO-Reimpl"
+
 type HypercubeReimpl struct {
 	Limits        [][]float64
 	Quality       []float64
@@ -91,19 +97,38 @@ func (h *HypercubeReimpl) updateGrid(archive []*objectives.Result, numberOfObjec
 }
 
 type MOPSOAlgorithmReimpl struct {
-	NumberOfAgents    int
-	NumberOfIter      int
-	Agents            []*ResultWithPersonalBest
-	C1                float64
-	C2                float64
-	W                 float64
-	ArchiveSize       int
-	Archive           []*objectives.Result
-	ObjectiveFunction objectives.Problem
-	MaxVelocity       []float64
-	NumberOfGrids     int
-	MutationRate      float64
-	hypercube         *HypercubeReimpl
+	NumberOfAgents     int
+	NumberOfIter       int
+	Agents             []*ResultWithPersonalBest
+	C1                 float64
+	C2                 float64
+	W                  float64
+	ArchiveSize        int
+	Archive            []*objectives.Result
+	ObjectiveFunction  objectives.Problem
+	MaxVelocity        []float64
+	NumberOfGrids      int
+	MutationRate       float64
+	hypercube          *HypercubeReimpl
+	Seed               int64
+	ConstraintHandling objectives.ConstraintHandling
+
+	algorithms.Checkpoints[reimplState]
+
+	history indicators.History
+	rng     *rand.Rand
+	src     *rand.PCG
+}
+
+// reimplState is what a checkpoint of MOPSOAlgorithmReimpl holds to continue a run.
+type reimplState struct {
+	Iteration  int
+	Agents     []*ResultWithPersonalBest
+	Archive    []*objectives.Result
+	Hypercube  *HypercubeReimpl
+	Indicators indicators.History
+	Penalty    objectives.PenaltyState
+	Rand       []byte
 }
 
 func CreateReimpl(
@@ -117,6 +142,11 @@ func CreateReimpl(
 		maxVelocity[i] = (problem.GetUpperBound()[i] - problem.GetLowerBound()[i]) * configs.MaxVelocity / 100
 	}
 
+	seed := configs.Seed
+	if seed == 0 {
+		seed = util.NewSeed()
+	}
+
 	return &MOPSOAlgorithmReimpl{
 		NumberOfAgents:    configs.NumberOfAgents,
 		NumberOfIter:      configs.NumberOfIter,
@@ -127,225 +157,170 @@ func CreateReimpl(
 		MutationRate:      configs.MutationRate,
 		C1:                configs.C1,
 		C2:                configs.C2,
+		W:                 configs.W,
 		hypercube: &HypercubeReimpl{
 			NumberOfGrids: configs.NumberOfGrids,
 			Limits:        make([][]float64, 0),
 			Quality:       make([]float64, configs.NumberOfGrids),
 		},
+		Seed:               seed,
+		ConstraintHandling: configs.ConstraintHandling,
 	}, nil
 }
 
 func (g *MOPSOAlgorithmReimpl) reset() {
 	g.Agents = make([]*ResultWithPersonalBest, g.NumberOfAgents)
 	g.Archive = make([]*objectives.Result, 0, g.ArchiveSize)
+	g.rng, g.src = util.NewRandSource(g.Seed)
+	g.history.Reset()
 }
 
 func (g *MOPSOAlgorithmReimpl) Type() data.TypeProblem {
 	return data.Multi
 }
 
-func (g *MOPSOAlgorithmReimpl) Run() error {
-	g.reset()
-
-	bar := progressbar.Default(int64(g.NumberOfIter))
-	g.initialization()
+// iterate moves the particles once and updates the archive and the personal
+// bests, the same way for Run and RunWithChannel.
+func (g *MOPSOAlgorithmReimpl) iterate(iter int) {
+	var wg sync.WaitGroup
 
-	// Initial archive: non-dominated solutions from initial population
-	onlyAgents := getResultsFromResultWithPersonalBest(g.Agents)
-	onlyAgents = objectives.DetermineDomination(onlyAgents)
-	g.Archive = objectives.GetNonDominatedAgents(onlyAgents)
-	g.Archive = g.hypercube.updateGrid(g.Archive, g.ObjectiveFunction.NumberOfObjectives())
+	if g.ObjectiveFunction.UpdatePenalty(iter, g.NumberOfIter, getResultsFromResultWithPersonalBest(g.Agents)) {
+		g.rescore()
+	}
 
-	var wg sync.WaitGroup
+	leader := selectLeaderFromArchive(g.rng, g.Archive)
+	// one stream per agent so the velocities do not depend on scheduling
+	rngs := util.SplitRand(g.rng, len(g.Agents))
+	wg.Add(len(g.Agents))
+	for i := range g.Agents {
+		go func(agentIdx int) {
+			defer wg.Done()
+			agent := g.Agents[agentIdx]
+			for d := 0; d < g.ObjectiveFunction.GetDimension(); d++ {
+				r1 := rngs[agentIdx].Float64()
+				r2 := rngs[agentIdx].Float64()
+				v := g.W*agent.Velocity[d] +
+					g.C1*r1*(agent.PersonalBest.Position[d]-agent.Result.Position[d]) +
+					g.C2*r2*(leader.Position[d]-agent.Result.Position[d])
+				agent.Velocity[d] = v
+				agent.Result.Position[d] += v
+			}
+		}(i)
+	}
 
-	for iter := 0; iter < g.NumberOfIter; iter++ {
-
-		leader := selectLeaderFromArchive(g.Archive)
-		wg.Add(len(g.Agents))
-		for i := range g.Agents {
-			go func(agentIdx int) {
-				defer wg.Done()
-				agent := g.Agents[agentIdx]
-				for d := 0; d < g.ObjectiveFunction.GetDimension(); d++ {
-					r1 := rand.Float64()
-					r2 := rand.Float64()
-					v := g.W*agent.Velocity[d] +
-						g.C1*r1*(agent.PersonalBest.Position[d]-agent.Result.Position[d]) +
-						g.C2*r2*(leader.Position[d]-agent.Result.Position[d])
-					agent.Velocity[d] = v
-					agent.Result.Position[d] += v
-				}
-			}(i)
-		}
+	wg.Wait()
+	g.Agents = g.applyMutation(iter)
 
-		wg.Wait()
-		g.Agents = g.applyMutation(iter)
-
-		// Checking boundary
-		g.checkingBoundaries()
-
-		// Evaluate and update personal bests using goroutines
-		wg.Add(len(g.Agents))
-		for i := range g.Agents {
-			go func(agentIdx int) {
-				defer wg.Done()
-				agent := g.Agents[agentIdx]
-				value, valuesWithKey, keys, penalty := g.ObjectiveFunction.Eval(agent.Result.Position)
-				agent.Result.Value = value
-				agent.Result.ValuesWithKey = valuesWithKey
-				agent.Result.Penalty = penalty
-				agent.Result.Key = keys
-			}(i)
-		}
-		wg.Wait()
+	// Checking boundary
+	g.checkingBoundaries()
 
-		agentResults := getResultsFromResultWithPersonalBest(g.Agents)
-		agentResults = objectives.DetermineDomination(agentResults)
-		nonDominatedAgents := objectives.GetNonDominatedAgents(agentResults)
+	// Evaluate and update personal bests using goroutines
+	wg.Add(len(g.Agents))
+	for i := range g.Agents {
+		go func(agentIdx int) {
+			defer wg.Done()
+			agent := g.Agents[agentIdx]
+			value, valuesWithKey, keys, penalty, violations := g.ObjectiveFunction.Eval(g.ObjectiveFunction.Repair(agent.Result.Position))
+			agent.Result.Value = value
+			agent.Result.ValuesWithKey = valuesWithKey
+			agent.Result.Penalty = penalty
+			agent.Result.Violations = violations
+			agent.Result.Violation = objectives.TotalViolation(violations)
+			agent.Result.Key = keys
+		}(i)
+	}
+	wg.Wait()
 
-		g.Archive = objectives.MergeAgents(g.Archive, nonDominatedAgents)
-		g.Archive = objectives.DetermineDomination(g.Archive)
-		g.Archive = objectives.GetNonDominatedAgents(g.Archive)
+	agentResults := getResultsFromResultWithPersonalBest(g.Agents)
+	agentResults = objectives.DetermineDomination(agentResults, g.ConstraintHandling)
+	nonDominatedAgents := objectives.GetNonDominatedAgents(agentResults)
 
-		g.Archive = g.hypercube.updateGrid(g.Archive, g.ObjectiveFunction.NumberOfObjectives())
+	g.Archive = objectives.MergeAgents(g.Archive, nonDominatedAgents)
+	g.Archive = objectives.DetermineDomination(g.Archive, g.ConstraintHandling)
+	g.Archive = objectives.GetNonDominatedAgents(g.Archive)
 
-		// Truncate archive if needed (crowding distance)
-		if len(g.Archive) > g.ArchiveSize {
-			excess := len(g.Archive) - g.ArchiveSize
-			g.Archive = g.removeExtraInArchive(excess)
-		}
+	g.Archive = g.hypercube.updateGrid(g.Archive, g.ObjectiveFunction.NumberOfObjectives())
 
-		// Update the best positions found so far for each particle
-		// This is the MATLAB code implementation:
-		// pos_best = dominates(POS_fit, PBEST_fit);
-		// best_pos = ~dominates(PBEST_fit, POS_fit);
-		// best_pos(rand(Np,1)>=0.5) = 0;
-
-		// Create arrays to track which particles need updates
-		posBest := make([]bool, len(g.Agents)) // Current position dominates personal best
-		bestPos := make([]bool, len(g.Agents)) // Personal best doesn't dominate current position
-
-		// Check domination relationships
-		for i, agent := range g.Agents {
-			// Check if current position dominates personal best
-			if agent.Result.Dominates(agent.PersonalBest) {
-				posBest[i] = true
-			}
+	// Truncate archive if needed (crowding distance)
+	if len(g.Archive) > g.ArchiveSize {
+		excess := len(g.Archive) - g.ArchiveSize
+		g.Archive = g.removeExtraInArchive(excess)
+	}
 
-			// Check if personal best doesn't dominate current position
-			if !agent.PersonalBest.Dominates(agent.Result) {
-				// Apply random selection with 50% probability
-				if rand.Float64() < 0.5 {
-					bestPos[i] = true
-				}
-			}
+	// Update the best positions found so far for each particle
+	// This is the MATLAB code implementation:
+	// pos_best = dominates(POS_fit, PBEST_fit);
+	// best_pos = ~dominates(PBEST_fit, POS_fit);
+	// best_pos(rand(Np,1)>=0.5) = 0;
+
+	// Create arrays to track which particles need updates
+	posBest := make([]bool, len(g.Agents)) // Current position dominates personal best
+	bestPos := make([]bool, len(g.Agents)) // Personal best doesn't dominate current position
+
+	// Check domination relationships
+	for i, agent := range g.Agents {
+		// Check if current position dominates personal best
+		if agent.Result.DominatesWith(agent.PersonalBest, g.ConstraintHandling) {
+			posBest[i] = true
 		}
 
-		// Update personal bests based on domination checks
-		for i, agent := range g.Agents {
-			if posBest[i] || bestPos[i] {
-				agent.PersonalBest = agent.Result.CopyAgent()
+		// Check if personal best doesn't dominate current position
+		if !agent.PersonalBest.DominatesWith(agent.Result, g.ConstraintHandling) {
+			// Apply random selection with 50% probability
+			if g.rng.Float64() < 0.5 {
+				bestPos[i] = true
 			}
 		}
-
-		bar.Describe(fmt.Sprintf("Iteration %d: %d", iter+1, len(g.Archive)))
-		bar.Add(1)
 	}
 
-	return nil
+	// Update personal bests based on domination checks
+	for i, agent := range g.Agents {
+		if posBest[i] || bestPos[i] {
+			agent.PersonalBest = agent.Result.CopyAgent()
+		}
+	}
 }
 
-func (g *MOPSOAlgorithmReimpl) RunWithChannel(doneChan chan<- struct{}, channel chan<- any) error {
-	g.reset()
-
-	g.initialization()
-
-	// Initial archive: non-dominated solutions from initial population
-	onlyAgents := getResultsFromResultWithPersonalBest(g.Agents)
-	onlyAgents = objectives.DetermineDomination(onlyAgents)
-	g.Archive = objectives.GetNonDominatedAgents(onlyAgents)
-	g.Archive = g.hypercube.updateGrid(g.Archive, g.ObjectiveFunction.NumberOfObjectives())
-
-	var wg sync.WaitGroup
-
-	for iter := 0; iter < g.NumberOfIter; iter++ {
+func (g *MOPSOAlgorithmReimpl) Run(ctx context.Context) error {
+	start, err := g.start()
+	if err != nil {
+		return err
+	}
 
-		leader := selectLeaderFromArchive(g.Archive)
-		for _, agent := range g.Agents {
-			for d := 0; d < g.ObjectiveFunction.GetDimension(); d++ {
-				r1 := rand.Float64()
-				r2 := rand.Float64()
-				v := g.W*agent.Velocity[d] +
-					g.C1*r1*(agent.PersonalBest.Position[d]-agent.Result.Position[d]) +
-					g.C2*r2*(leader.Position[d]-agent.Result.Position[d])
-				agent.Velocity[d] = v
-				agent.Result.Position[d] += v
-			}
-		}
+	bar := progressbar.Default(int64(g.NumberOfIter))
+	_ = bar.Set(start)
 
-		g.Agents = g.applyMutation(iter)
-
-		// Checking boundary
-		g.checkingBoundaries()
-
-		// Evaluate and update personal bests using goroutines
-		wg.Add(len(g.Agents))
-		for i := range g.Agents {
-			go func(agentIdx int) {
-				defer wg.Done()
-				agent := g.Agents[agentIdx]
-				value, valuesWithKey, keys, penalty := g.ObjectiveFunction.Eval(agent.Result.Position)
-				agent.Result.Value = value
-				agent.Result.ValuesWithKey = valuesWithKey
-				agent.Result.Penalty = penalty
-				agent.Result.Key = keys
-			}(i)
+	for iter := start; iter < g.NumberOfIter; iter++ {
+		if err := algorithms.Interrupted(ctx, g.ObjectiveFunction); err != nil {
+			return err
 		}
-		wg.Wait()
-
-		agentResults := getResultsFromResultWithPersonalBest(g.Agents)
-		agentResults = objectives.DetermineDomination(agentResults)
-		nonDominatedAgents := objectives.GetNonDominatedAgents(agentResults)
 
-		g.Archive = objectives.MergeAgents(g.Archive, nonDominatedAgents)
-		g.Archive = objectives.DetermineDomination(g.Archive)
-		g.Archive = objectives.GetNonDominatedAgents(g.Archive)
+		g.iterate(iter)
 
-		g.Archive = g.hypercube.updateGrid(g.Archive, g.ObjectiveFunction.NumberOfObjectives())
+		bar.Describe(fmt.Sprintf("Iteration %d: %d", iter+1, len(g.Archive)))
+		bar.Add(1)
 
-		// Truncate archive if needed (crowding distance)
-		if len(g.Archive) > g.ArchiveSize {
-			excess := len(g.Archive) - g.ArchiveSize
-			g.Archive = g.removeExtraInArchive(excess)
-		}
+		g.history.Record(g.Archive)
+		g.checkpoint(iter + 1)
+	}
 
-		// Update the best positions found so far for each particle
-		// Create arrays to track which particles need updates
-		posBest := make([]bool, len(g.Agents)) // Current position dominates personal best
-		bestPos := make([]bool, len(g.Agents)) // Personal best doesn't dominate current position
+	return g.ObjectiveFunction.Err()
+}
 
-		// Check domination relationships
-		for i, agent := range g.Agents {
-			// Check if current position dominates personal best
-			if agent.Result.Dominates(agent.PersonalBest) {
-				posBest[i] = true
-			}
+func (g *MOPSOAlgorithmReimpl) RunWithChannel(ctx context.Context, channel chan<- any) error {
+	start, err := g.start()
+	if err != nil {
+		close(channel)
+		return err
+	}
 
-			// Check if personal best doesn't dominate current position
-			if !agent.PersonalBest.Dominates(agent.Result) {
-				// Apply random selection with 50% probability
-				if rand.Float64() < 0.5 {
-					bestPos[i] = true
-				}
-			}
+	for iter := start; iter < g.NumberOfIter; iter++ {
+		if err := algorithms.Interrupted(ctx, g.ObjectiveFunction); err != nil {
+			close(channel)
+			return err
 		}
 
-		// Update personal bests based on domination checks
-		for i, agent := range g.Agents {
-			if posBest[i] || bestPos[i] {
-				agent.PersonalBest = agent.Result.CopyAgent()
-			}
-		}
+		g.iterate(iter)
 
 		// Send progress update through channel
 		channel <- struct {
@@ -357,16 +332,91 @@ func (g *MOPSOAlgorithmReimpl) RunWithChannel(doneChan chan<- struct{}, channel
 			NumberOfAgentsInArchive: len(g.Archive),
 			Type:                    "multi",
 		}
+
+		g.history.Record(g.Archive)
+		g.checkpoint(iter + 1)
 	}
 
 	// Signal completion
 	close(channel)
-	doneChan <- struct{}{}
 
-	return nil
+	return g.ObjectiveFunction.Err()
+}
+
+// LoadState refuses a state whose particles or archive do not fit the
+// config, e.g. after the population size has changed.
+func (g *MOPSOAlgorithmReimpl) LoadState(encoded []byte) error {
+	return g.LoadStateChecked(encoded, func(s *reimplState) error {
+		err := algorithms.CheckStateSize("particles", len(s.Agents), g.NumberOfAgents)
+		if err != nil {
+			return err
+		}
+
+		return algorithms.CheckArchiveSize(len(s.Archive), g.ArchiveSize)
+	})
+}
+
+// start resets the algorithm and either initializes a new swarm and archive or
+// restores the state loaded by LoadState. It returns the iteration to continue
+// from.
+func (g *MOPSOAlgorithmReimpl) start() (int, error) {
+	g.reset()
+
+	s := g.Resume()
+	if s == nil {
+		g.initialization()
+
+		// Initial archive: non-dominated solutions from initial population
+		onlyAgents := getResultsFromResultWithPersonalBest(g.Agents)
+		onlyAgents = objectives.DetermineDomination(onlyAgents, g.ConstraintHandling)
+		g.Archive = objectives.GetNonDominatedAgents(onlyAgents)
+		g.Archive = g.hypercube.updateGrid(g.Archive, g.ObjectiveFunction.NumberOfObjectives())
+
+		return 0, nil
+	}
+
+	err := g.src.UnmarshalBinary(s.Rand)
+	if err != nil {
+		return 0, err
+	}
+
+	g.ObjectiveFunction.SetPenaltyState(s.Penalty)
+
+	g.Agents = s.Agents
+	g.Archive = s.Archive
+	g.hypercube = s.Hypercube
+	g.history = s.Indicators
+
+	return s.Iteration, nil
+}
+
+func (g *MOPSOAlgorithmReimpl) checkpoint(iteration int) {
+	g.Save(iteration, func() reimplState {
+		rngState, _ := g.src.MarshalBinary()
+		return reimplState{
+			Iteration:  iteration,
+			Agents:     g.Agents,
+			Archive:    g.Archive,
+			Hypercube:  g.hypercube,
+			Indicators: g.history,
+			Penalty:    g.ObjectiveFunction.PenaltyState(),
+			Rand:       rngState,
+		}
+	})
+}
+
+// rescore evaluates the personal bests and the archive again after the
+// penalty factors changed, the particles are evaluated again in the
+// iteration, and updates the grid of the archive.
+func (g *MOPSOAlgorithmReimpl) rescore() {
+	objectives.Rescore(g.ObjectiveFunction, getPersonalBests(g.Agents), g.Archive)
+	g.Archive = g.hypercube.updateGrid(g.Archive, g.ObjectiveFunction.NumberOfObjectives())
 }
 
 func (g *MOPSOAlgorithmReimpl) initialization() {
+	// one stream per agent so the initial swarm does not depend on scheduling
+	rngs := util.SplitRand(g.rng, g.NumberOfAgents)
+
 	var wg sync.WaitGroup
 	wg.Add(g.NumberOfAgents)
 	for agentIdx := 0; agentIdx < g.NumberOfAgents; agentIdx++ {
@@ -375,7 +425,7 @@ func (g *MOPSOAlgorithmReimpl) initialization() {
 			positions := make([]float64, g.ObjectiveFunction.GetDimension())
 
 			for i := 0; i < g.ObjectiveFunction.GetDimension(); i++ {
-				positions[i] = g.ObjectiveFunction.GetLowerBound()[i] + rand.Float64()*
+				positions[i] = g.ObjectiveFunction.GetLowerBound()[i] + rngs[agentIdx].Float64()*
 					(g.ObjectiveFunction.GetUpperBound()[i]-g.ObjectiveFunction.GetLowerBound()[i])
 			}
 
@@ -385,10 +435,12 @@ func (g *MOPSOAlgorithmReimpl) initialization() {
 				Position: positions,
 			}
 
-			value, valuesWithKey, keys, penalty := g.ObjectiveFunction.Eval(positions)
+			value, valuesWithKey, keys, penalty, violations := g.ObjectiveFunction.Eval(g.ObjectiveFunction.Repair(positions))
 			result.Value = value
 			result.ValuesWithKey = valuesWithKey
 			result.Penalty = penalty
+			result.Violations = violations
+			result.Violation = objectives.TotalViolation(violations)
 			result.Key = keys
 
 			newAgent := &ResultWithPersonalBest{
@@ -415,6 +467,7 @@ func (g *MOPSOAlgorithmReimpl) GetResults() algorithms.Result {
 
 		results[i] = algorithms.AlgorithmResult{
 			MapLocations:   mapLoc,
+			PhaseLocations: objectives.PhaseLocationResult(g.ObjectiveFunction, res.Position),
 			SliceLocations: sliceLoc,
 			Value:          res.Value,
 			Key:            res.Key,
@@ -428,11 +481,13 @@ func (g *MOPSOAlgorithmReimpl) GetResults() algorithms.Result {
 	minX, maxX, minY, maxY, _ := g.ObjectiveFunction.GetLayoutSize()
 
 	return algorithms.Result{
-		Result: results,
-		MinX:   minX,
-		MinY:   minY,
-		MaxX:   maxX,
-		MaxY:   maxY,
+		Result:     results,
+		MinX:       minX,
+		MinY:       minY,
+		MaxX:       maxX,
+		MaxY:       maxY,
+		Seed:       g.Seed,
+		Indicators: g.history.Values(),
 	}
 }
 
@@ -444,7 +499,7 @@ func (g *MOPSOAlgorithmReimpl) selectLeader(archive []*objectives.Result) *objec
 		prob[i] = prob[i-1] + g.hypercube.Quality[i]
 	}
 
-	randVal := rand.Float64() * slices.Max(prob)
+	randVal := g.rng.Float64() * slices.Max(prob)
 	selectedIndex := util.FindLessOrEqual(prob, randVal)
 
 	agentsInGrid := make([]*objectives.Result, 0)
@@ -455,11 +510,11 @@ func (g *MOPSOAlgorithmReimpl) selectLeader(archive []*objectives.Result) *objec
 	}
 
 	if len(agentsInGrid) > 0 {
-		return agentsInGrid[rand.Intn(len(agentsInGrid))].CopyAgent()
+		return agentsInGrid[g.rng.IntN(len(agentsInGrid))].CopyAgent()
 	}
 
 	fmt.Println("No agents in grid")
-	return archive[rand.Intn(len(archive))].CopyAgent()
+	return archive[g.rng.IntN(len(archive))].CopyAgent()
 }
 
 func (g *MOPSOAlgorithmReimpl) applyMutation(curIter int) []*ResultWithPersonalBest {
@@ -488,11 +543,11 @@ func (g *MOPSOAlgorithmReimpl) applyMutation(curIter int) []*ResultWithPersonalB
 	// 2nd part: uniform mutation
 	nMut := int(math.Round(g.MutationRate * float64(subSizes[1])))
 	if nMut > 0 {
-		tempIndices := rand.Perm(subSizes[1])
+		tempIndices := g.rng.Perm(subSizes[1])
 		for i := 0; i < nMut; i++ {
 			idx := cumSum[0] + tempIndices[i]
 			for d := 0; d < g.ObjectiveFunction.GetDimension(); d++ {
-				g.Agents[idx].Result.Position[d] = g.ObjectiveFunction.GetLowerBound()[d] + rand.Float64()*(g.ObjectiveFunction.GetUpperBound()[d]-g.ObjectiveFunction.GetLowerBound()[d])
+				g.Agents[idx].Result.Position[d] = g.ObjectiveFunction.GetLowerBound()[d] + g.rng.Float64()*(g.ObjectiveFunction.GetUpperBound()[d]-g.ObjectiveFunction.GetLowerBound()[d])
 			}
 		}
 	}
@@ -502,11 +557,11 @@ func (g *MOPSOAlgorithmReimpl) applyMutation(curIter int) []*ResultWithPersonalB
 
 	nMut = int(math.Round(perMut * float64(subSizes[2])))
 	if nMut > 0 {
-		tempIndices := rand.Perm(subSizes[2])
+		tempIndices := g.rng.Perm(subSizes[2])
 		for i := 0; i < nMut; i++ {
 			idx := cumSum[1] + tempIndices[i]
 			for d := 0; d < g.ObjectiveFunction.GetDimension(); d++ {
-				g.Agents[idx].Result.Position[d] = g.ObjectiveFunction.GetLowerBound()[d] + rand.Float64()*(g.ObjectiveFunction.GetUpperBound()[d]-g.ObjectiveFunction.GetLowerBound()[d])
+				g.Agents[idx].Result.Position[d] = g.ObjectiveFunction.GetLowerBound()[d] + g.rng.Float64()*(g.ObjectiveFunction.GetUpperBound()[d]-g.ObjectiveFunction.GetLowerBound()[d])
 			}
 		}
 	}
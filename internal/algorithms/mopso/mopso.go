@@ -1,13 +1,16 @@
 package mopso
 
 import (
+	"context"
 	"fmt"
 	"golang-moaha-construction/internal/algorithms"
 	"golang-moaha-construction/internal/data"
+	"golang-moaha-construction/internal/indicators"
 	"golang-moaha-construction/internal/objectives"
 	"golang-moaha-construction/internal/util"
+	"maps"
 	"math"
-	"math/rand"
+	"math/rand/v2"
 	"slices"
 	"sync"
 
@@ -86,6 +89,17 @@ func getResultsFromResultWithPersonalBest(archive []*ResultWithPersonalBest) []*
 	return results
 }
 
+// getPersonalBests returns the personal best of every particle.
+func getPersonalBests(agents []*ResultWithPersonalBest) []*objectives.Result {
+	results := make([]*objectives.Result, len(agents))
+
+	for i, agent := range agents {
+		results[i] = agent.PersonalBest
+	}
+
+	return results
+}
+
 type MOPSOAlgorithm struct {
 	NumberOfAgents    int
 	NumberOfIter      int
@@ -99,22 +113,45 @@ type MOPSOAlgorithm struct {
 	MaxVelocity       []float64
 	MaxVelocityInfo   float64
 	// for multi-objective hypercubes
-	NumberOfGrids int
-	MutationRate  float64
+	NumberOfGrids      int
+	MutationRate       float64
+	Seed               int64
+	ConstraintHandling objectives.ConstraintHandling
+
+	algorithms.Checkpoints[state]
 
 	hypercube Hypercube
+	history   indicators.History
+	rng       *rand.Rand
+	src       *rand.PCG
+}
+
+// state is what a checkpoint holds to continue a run. The agents carry their
+// velocities and personal bests.
+type state struct {
+	Iteration  int
+	Agents     []*ResultWithPersonalBest
+	Archive    []*objectives.Result
+	Hypercube  Hypercube
+	Indicators indicators.History
+	Penalty    objectives.PenaltyState
+	Rand       []byte
 }
 
 type Config struct {
-	NumberOfAgents int
-	NumberOfIter   int
-	ArchiveSize    int
-	NumberOfGrids  int
-	MutationRate   float64
-	MaxVelocity    float64
-	C1             float64
-	C2             float64
-	W              float64
+	NumberOfAgents int     `json:"population"`
+	NumberOfIter   int     `json:"iterations"`
+	ArchiveSize    int     `json:"archiveSize"`
+	NumberOfGrids  int     `json:"numberOfGrids"`
+	MutationRate   float64 `json:"mutationRate"`
+	MaxVelocity    float64 `json:"maxVelocity"`
+	C1             float64 `json:"c1"`
+	C2             float64 `json:"c2"`
+	W              float64 `json:"w"`
+	// Seed makes the run reproducible, 0 picks a random seed
+	Seed int64 `json:"seed"`
+	// ConstraintHandling is how results with violated constraints compare
+	ConstraintHandling objectives.ConstraintHandling `json:"constraintHandling"`
 }
 
 func Create(
@@ -127,6 +164,11 @@ func Create(
 		maxVelocity[i] = (problem.GetUpperBound()[i] - problem.GetLowerBound()[i]) * configs.MaxVelocity / 100
 	}
 
+	seed := configs.Seed
+	if seed == 0 {
+		seed = util.NewSeed()
+	}
+
 	return &MOPSOAlgorithm{
 		NumberOfAgents:    configs.NumberOfAgents,
 		NumberOfIter:      configs.NumberOfIter,
@@ -134,7 +176,7 @@ func Create(
 		ObjectiveFunction: problem,
 		NumberOfGrids:     configs.NumberOfGrids,
 		MaxVelocity:       maxVelocity,
-		MaxVelocityInfo: configs.MaxVelocity,
+		MaxVelocityInfo:   configs.MaxVelocity,
 		MutationRate:      configs.MutationRate,
 		C1:                configs.C1,
 		C2:                configs.C2,
@@ -142,36 +184,46 @@ func Create(
 		hypercube: Hypercube{
 			NumberOfGrids: configs.NumberOfGrids,
 		},
+		Seed:               seed,
+		ConstraintHandling: configs.ConstraintHandling,
 	}, nil
 }
 
 func (g *MOPSOAlgorithm) reset() {
 	g.Agents = make([]*ResultWithPersonalBest, g.NumberOfAgents)
 	g.Archive = make([]*objectives.Result, 0, g.ArchiveSize)
+	g.rng, g.src = util.NewRandSource(g.Seed)
+	g.history.Reset()
 }
 
 func (g *MOPSOAlgorithm) Type() data.TypeProblem {
 	return data.Multi
 }
 
-func (g *MOPSOAlgorithm) Run() error {
-	g.reset()
+func (g *MOPSOAlgorithm) Run(ctx context.Context) error {
+	start, err := g.start()
+	if err != nil {
+		return err
+	}
 
 	bar := progressbar.Default(int64(g.NumberOfIter))
-	g.initialization()
+	_ = bar.Set(start)
 
-	// Initial archive: non-dominated solutions from initial population
-	onlyAgents := getResultsFromResultWithPersonalBest(g.Agents)
-	onlyAgents = objectives.DetermineDomination(onlyAgents)
-	g.Archive = objectives.GetNonDominatedAgents(onlyAgents)
+	for iter := start; iter < g.NumberOfIter; iter++ {
+		if err := algorithms.Interrupted(ctx, g.ObjectiveFunction); err != nil {
+			return err
+		}
+
+		if g.ObjectiveFunction.UpdatePenalty(iter, g.NumberOfIter, getResultsFromResultWithPersonalBest(g.Agents)) {
+			g.rescore()
+		}
 
-	for iter := 0; iter < g.NumberOfIter; iter++ {
 		// For each agent, select a leader from the archive and update velocity/position
-		leader := selectLeaderFromArchive(g.Archive)
+		leader := selectLeaderFromArchive(g.rng, g.Archive)
 		for _, agent := range g.Agents {
 			for d := 0; d < g.ObjectiveFunction.GetDimension(); d++ {
-				r1 := rand.Float64()
-				r2 := rand.Float64()
+				r1 := g.rng.Float64()
+				r2 := g.rng.Float64()
 				v := g.W*agent.Velocity[d] +
 					g.C1*r1*(agent.PersonalBest.Position[d]-agent.Result.Position[d]) +
 					g.C2*r2*(leader.Position[d]-agent.Result.Position[d])
@@ -189,18 +241,20 @@ func (g *MOPSOAlgorithm) Run() error {
 		}
 
 		// Apply mutation (as in MATLAB, to a subset of agents)
-		applyMutation(g.Agents, iter, g.NumberOfIter, g.ObjectiveFunction.GetLowerBound(), g.ObjectiveFunction.GetUpperBound(), g.MutationRate)
+		applyMutation(g.rng, g.Agents, iter, g.NumberOfIter, g.ObjectiveFunction.GetLowerBound(), g.ObjectiveFunction.GetUpperBound(), g.MutationRate)
 
 		// Evaluate and update personal bests
 		for _, agent := range g.Agents {
 			g.outOfBoundaries(agent.Result.Position)
-			value, valuesWithKey, keys, penalty := g.ObjectiveFunction.Eval(agent.Result.Position)
+			value, valuesWithKey, keys, penalty, violations := g.ObjectiveFunction.Eval(g.ObjectiveFunction.Repair(agent.Result.Position))
 			agent.Result.Value = value
 			agent.Result.ValuesWithKey = valuesWithKey
 			agent.Result.Penalty = penalty
+			agent.Result.Violations = violations
+			agent.Result.Violation = objectives.TotalViolation(violations)
 			agent.Result.Key = keys
 			// Update personal best if dominated or equal (random tie-break)
-			if agent.Result.Dominates(agent.PersonalBest) || (!agent.PersonalBest.Dominates(agent.Result) && rand.Float64() > 0.5) {
+			if agent.Result.DominatesWith(agent.PersonalBest, g.ConstraintHandling) || (!agent.PersonalBest.DominatesWith(agent.Result, g.ConstraintHandling) && g.rng.Float64() > 0.5) {
 				agent.PersonalBest = agent.Result.CopyAgent()
 			}
 		}
@@ -208,7 +262,7 @@ func (g *MOPSOAlgorithm) Run() error {
 		// Merge new agents into archive, keep only non-dominated
 		agentResults := getResultsFromResultWithPersonalBest(g.Agents)
 		g.Archive = objectives.MergeAgents(g.Archive, agentResults)
-		g.Archive = objectives.DetermineDomination(g.Archive)
+		g.Archive = objectives.DetermineDomination(g.Archive, g.ConstraintHandling)
 		g.Archive = objectives.GetNonDominatedAgents(g.Archive)
 
 		// Truncate archive if needed (crowding distance)
@@ -219,13 +273,16 @@ func (g *MOPSOAlgorithm) Run() error {
 
 		bar.Describe(fmt.Sprintf("Iteration %d: %d", iter+1, len(g.Archive)))
 		bar.Add(1)
+
+		g.history.Record(g.Archive)
+		g.checkpoint(iter + 1)
 	}
 
-	return nil
+	return g.ObjectiveFunction.Err()
 }
 
 // selectLeaderFromArchive selects a leader from the archive using roulette/crowding (MATLAB style)
-func selectLeaderFromArchive(archive []*objectives.Result) *objectives.Result {
+func selectLeaderFromArchive(r *rand.Rand, archive []*objectives.Result) *objectives.Result {
 	if len(archive) == 0 {
 		return nil
 	}
@@ -237,9 +294,9 @@ func selectLeaderFromArchive(archive []*objectives.Result) *objectives.Result {
 		total += crowd[i]
 	}
 	if total == 0 {
-		return archive[rand.Intn(len(archive))]
+		return archive[r.IntN(len(archive))]
 	}
-	pick := rand.Float64() * total
+	pick := r.Float64() * total
 	cumsum := 0.0
 	for i, a := range archive {
 		cumsum += crowd[i]
@@ -251,41 +308,43 @@ func selectLeaderFromArchive(archive []*objectives.Result) *objectives.Result {
 }
 
 // applyMutation mutates a subset of agents (as in MATLAB MOGWO/MOPSO)
-func applyMutation(agents []*ResultWithPersonalBest, iter, maxIter int, lower, upper []float64, mutationRate float64) {
+func applyMutation(r *rand.Rand, agents []*ResultWithPersonalBest, iter, maxIter int, lower, upper []float64, mutationRate float64) {
 	n := len(agents)
 	nMut := int(float64(n) * mutationRate)
 	for i := 0; i < nMut; i++ {
-		idx := rand.Intn(n)
+		idx := r.IntN(n)
 		for d := range agents[idx].Result.Position {
 			// Uniform mutation within bounds
-			agents[idx].Result.Position[d] = lower[d] + rand.Float64()*(upper[d]-lower[d])
+			agents[idx].Result.Position[d] = lower[d] + r.Float64()*(upper[d]-lower[d])
 		}
 	}
 }
 
-func (g *MOPSOAlgorithm) RunWithChannel(doneChan chan<- struct{}, channel chan<- any) error {
-	g.reset()
-
-	// initialization
-	g.initialization()
-
-	onlyAgents := getResultsFromResultWithPersonalBest(g.Agents)
+func (g *MOPSOAlgorithm) RunWithChannel(ctx context.Context, channel chan<- any) error {
+	l, err := g.start()
+	if err != nil {
+		close(channel)
+		return err
+	}
 
-	onlyAgents = objectives.DetermineDomination(onlyAgents)
-	g.Archive = objectives.GetNonDominatedAgents(onlyAgents)
-	g.hypercube.UpdateHyperCube(getResultsFromArchive(g.Archive))
+	for l < g.NumberOfIter {
+		if err := algorithms.Interrupted(ctx, g.ObjectiveFunction); err != nil {
+			close(channel)
+			return err
+		}
 
-	l := 0
+		if g.ObjectiveFunction.UpdatePenalty(l, g.NumberOfIter, getResultsFromResultWithPersonalBest(g.Agents)) {
+			g.rescore()
+		}
 
-	for l < g.NumberOfIter {
 		// selectLeader
-		leaderAgent := g.hypercube.SelectLeader(g.Archive)
+		leaderAgent := g.hypercube.SelectLeader(g.rng, g.Archive)
 
 		for agentIdx := range g.Agents {
 			for posIdx := range g.ObjectiveFunction.GetDimension() {
 				velocity := g.W*g.Agents[agentIdx].Velocity[posIdx] +
-					g.C1*rand.Float64()*(g.Agents[agentIdx].GetPersonalBest().Position[posIdx]-g.Agents[agentIdx].Result.Position[posIdx]) +
-					g.C2*rand.Float64()*(leaderAgent.Position[posIdx]-g.Agents[agentIdx].Result.Position[posIdx])
+					g.C1*g.rng.Float64()*(g.Agents[agentIdx].GetPersonalBest().Position[posIdx]-g.Agents[agentIdx].Result.Position[posIdx]) +
+					g.C2*g.rng.Float64()*(leaderAgent.Position[posIdx]-g.Agents[agentIdx].Result.Position[posIdx])
 
 				g.Agents[agentIdx].Velocity[posIdx] = velocity
 
@@ -294,7 +353,7 @@ func (g *MOPSOAlgorithm) RunWithChannel(doneChan chan<- struct{}, channel chan<-
 		}
 
 		// apply mutation
-		g.Agents = mutation(g.Agents, l,
+		g.Agents = mutation(g.rng, g.Agents, l,
 			g.NumberOfIter,
 			g.NumberOfAgents,
 			g.ObjectiveFunction.GetLowerBound(),
@@ -305,9 +364,11 @@ func (g *MOPSOAlgorithm) RunWithChannel(doneChan chan<- struct{}, channel chan<-
 			// check out of boundaries
 			g.outOfBoundaries(g.Agents[agentIdx].Result.Position)
 			// evaluate
-			value, valuesWithKey, keys, penalty := g.ObjectiveFunction.Eval(g.Agents[agentIdx].Result.Position)
+			value, valuesWithKey, keys, penalty, violations := g.ObjectiveFunction.Eval(g.ObjectiveFunction.Repair(g.Agents[agentIdx].Result.Position))
 			g.Agents[agentIdx].Result.Value = value
 			g.Agents[agentIdx].Result.Penalty = penalty
+			g.Agents[agentIdx].Result.Violations = violations
+			g.Agents[agentIdx].Result.Violation = objectives.TotalViolation(violations)
 			g.Agents[agentIdx].Result.Key = keys
 			g.Agents[agentIdx].Result.ValuesWithKey = valuesWithKey
 		}
@@ -324,12 +385,12 @@ func (g *MOPSOAlgorithm) RunWithChannel(doneChan chan<- struct{}, channel chan<-
 		pBestDominatesCur := make([]bool, len(g.Agents))
 
 		for i := 0; i < len(g.Agents); i++ {
-			if g.Agents[i].Result.Dominates(g.Agents[i].PersonalBest) {
+			if g.Agents[i].Result.DominatesWith(g.Agents[i].PersonalBest, g.ConstraintHandling) {
 				curDominatesPBest[i] = true
 			}
 
-			if !g.Agents[i].PersonalBest.Dominates(g.Agents[i].Result) {
-				if rand.Float64() > 0.5 {
+			if !g.Agents[i].PersonalBest.DominatesWith(g.Agents[i].Result, g.ConstraintHandling) {
+				if g.rng.Float64() > 0.5 {
 					pBestDominatesCur[i] = true
 				}
 			}
@@ -353,14 +414,91 @@ func (g *MOPSOAlgorithm) RunWithChannel(doneChan chan<- struct{}, channel chan<-
 		}
 
 		l++
+		g.history.Record(g.Archive)
+		g.checkpoint(l)
 	}
 
 	close(channel)
 
-	return nil
+	return g.ObjectiveFunction.Err()
+}
+
+// LoadState refuses a state whose particles or archive do not fit the
+// config, e.g. after the population size has changed.
+func (g *MOPSOAlgorithm) LoadState(encoded []byte) error {
+	return g.LoadStateChecked(encoded, func(s *state) error {
+		err := algorithms.CheckStateSize("particles", len(s.Agents), g.NumberOfAgents)
+		if err != nil {
+			return err
+		}
+
+		return algorithms.CheckArchiveSize(len(s.Archive), g.ArchiveSize)
+	})
+}
+
+// start resets the algorithm and either initializes a new swarm and archive or
+// restores the state loaded by LoadState. It returns the iteration to continue
+// from.
+func (g *MOPSOAlgorithm) start() (int, error) {
+	g.reset()
+
+	s := g.Resume()
+	if s == nil {
+		// initialization
+		g.initialization()
+
+		// Initial archive: non-dominated solutions from initial population
+		onlyAgents := getResultsFromResultWithPersonalBest(g.Agents)
+
+		onlyAgents = objectives.DetermineDomination(onlyAgents, g.ConstraintHandling)
+		g.Archive = objectives.GetNonDominatedAgents(onlyAgents)
+		g.hypercube.UpdateHyperCube(getResultsFromArchive(g.Archive))
+
+		return 0, nil
+	}
+
+	err := g.src.UnmarshalBinary(s.Rand)
+	if err != nil {
+		return 0, err
+	}
+
+	g.ObjectiveFunction.SetPenaltyState(s.Penalty)
+
+	g.Agents = s.Agents
+	g.Archive = s.Archive
+	g.hypercube = s.Hypercube
+	g.history = s.Indicators
+
+	return s.Iteration, nil
+}
+
+func (g *MOPSOAlgorithm) checkpoint(iteration int) {
+	g.Save(iteration, func() state {
+		rngState, _ := g.src.MarshalBinary()
+		return state{
+			Iteration:  iteration,
+			Agents:     g.Agents,
+			Archive:    g.Archive,
+			Hypercube:  g.hypercube,
+			Indicators: g.history,
+			Penalty:    g.ObjectiveFunction.PenaltyState(),
+			Rand:       rngState,
+		}
+	})
+}
+
+// rescore evaluates the personal bests and the archive again after the
+// penalty factors changed, the particles are evaluated again in the
+// iteration, and updates the grid of the archive.
+func (g *MOPSOAlgorithm) rescore() {
+	objectives.Rescore(g.ObjectiveFunction, getPersonalBests(g.Agents), g.Archive)
+	g.hypercube.UpdateHyperCube(getResultsFromArchive(g.Archive))
 }
 
 func (g *MOPSOAlgorithm) initialization() {
+	// one stream per agent so the initial swarm does not depend on scheduling
+	rngs := util.SplitRand(g.rng, g.NumberOfAgents)
+
 	var wg sync.WaitGroup
 	wg.Add(g.NumberOfAgents)
 	for agentIdx := 0; agentIdx < g.NumberOfAgents; agentIdx++ {
@@ -369,7 +507,7 @@ func (g *MOPSOAlgorithm) initialization() {
 			positions := make([]float64, g.ObjectiveFunction.GetDimension())
 
 			for i := 0; i < g.ObjectiveFunction.GetDimension(); i++ {
-				positions[i] = g.ObjectiveFunction.GetLowerBound()[i] + rand.Float64()*
+				positions[i] = g.ObjectiveFunction.GetLowerBound()[i] + rngs[agentIdx].Float64()*
 					(g.ObjectiveFunction.GetUpperBound()[i]-g.ObjectiveFunction.GetLowerBound()[i])
 			}
 
@@ -379,10 +517,12 @@ func (g *MOPSOAlgorithm) initialization() {
 				Position: positions,
 			}
 
-			value, valuesWithKey, keys, penalty := g.ObjectiveFunction.Eval(positions)
+			value, valuesWithKey, keys, penalty, violations := g.ObjectiveFunction.Eval(g.ObjectiveFunction.Repair(positions))
 			result.Value = value
 			result.ValuesWithKey = valuesWithKey
 			result.Penalty = penalty
+			result.Violations = violations
+			result.Violation = objectives.TotalViolation(violations)
 			result.Key = keys
 
 			newAgent := &ResultWithPersonalBest{
@@ -419,6 +559,7 @@ func (g *MOPSOAlgorithm) GetResults() algorithms.Result {
 
 		results[i] = algorithms.AlgorithmResult{
 			MapLocations:   mapLoc,
+			PhaseLocations: objectives.PhaseLocationResult(g.ObjectiveFunction, res.Position),
 			SliceLocations: sliceLoc,
 			Value:          res.Value,
 			Key:            res.Key,
@@ -432,11 +573,13 @@ func (g *MOPSOAlgorithm) GetResults() algorithms.Result {
 	minX, maxX, minY, maxY, _ := g.ObjectiveFunction.GetLayoutSize()
 
 	return algorithms.Result{
-		Result: results,
-		MinX:   minX,
-		MinY:   minY,
-		MaxX:   maxX,
-		MaxY:   maxY,
+		Result:     results,
+		MinX:       minX,
+		MinY:       minY,
+		MaxX:       maxX,
+		MaxY:       maxY,
+		Seed:       g.Seed,
+		Indicators: g.history.Values(),
 	}
 }
 
@@ -456,14 +599,14 @@ func (g *MOPSOAlgorithm) updateRepository(rep []*objectives.Result, agents []*Re
 	agentResults := getResultsFromResultWithPersonalBest(agents)
 
 	// Check domination between particles
-	agentResults = objectives.DetermineDomination(agentResults)
+	agentResults = objectives.DetermineDomination(agentResults, g.ConstraintHandling)
 	nonDominatedAgents := objectives.GetNonDominatedAgents(agentResults)
 
 	// Add non-dominated particles to repository
 	rep = objectives.MergeAgents(rep, nonDominatedAgents)
 
 	// Check domination between all particles in repository
-	rep = objectives.DetermineDomination(rep)
+	rep = objectives.DetermineDomination(rep, g.ConstraintHandling)
 	rep = objectives.GetNonDominatedAgents(rep)
 
 	// Update the grid
@@ -542,14 +685,15 @@ func (h *Hypercube) UpdateHyperCube(values [][]float64) {
 		Value float64
 	}, len(gridCounts))
 	i := 0
-	for idx, count := range gridCounts {
+	// iterate in cell order so that roulette selection is reproducible
+	for _, idx := range slices.Sorted(maps.Keys(gridCounts)) {
 		h.Quality[i].Index = idx
-		h.Quality[i].Value = 10.0 / float64(count)
+		h.Quality[i].Value = 10.0 / float64(gridCounts[idx])
 		i++
 	}
 }
 
-func (h *Hypercube) SelectLeader(rep []*objectives.Result) *objectives.Result {
+func (h *Hypercube) SelectLeader(r *rand.Rand, rep []*objectives.Result) *objectives.Result {
 	// Compute cumulative probabilities based on quality
 	prob := make([]float64, len(h.Quality))
 	prob[0] = h.Quality[0].Value
@@ -558,7 +702,7 @@ func (h *Hypercube) SelectLeader(rep []*objectives.Result) *objectives.Result {
 	}
 
 	// Perform roulette wheel selection
-	randVal := rand.Float64() * prob[len(prob)-1]
+	randVal := r.Float64() * prob[len(prob)-1]
 	selectedHypercube := -1
 	for i, p := range prob {
 		if randVal <= p {
@@ -579,7 +723,7 @@ func (h *Hypercube) SelectLeader(rep []*objectives.Result) *objectives.Result {
 	if len(selectedIndices) == 0 {
 		return nil
 	}
-	selectedIndex := selectedIndices[rand.Intn(len(selectedIndices))]
+	selectedIndex := selectedIndices[r.IntN(len(selectedIndices))]
 	return rep[selectedIndex]
 }
 
@@ -668,7 +812,7 @@ func (h *Hypercube) deleteFromRepository(rep []*objectives.Result, exceeded int)
 	return newRep
 }
 
-func mutation(pos []*ResultWithPersonalBest, curIter, maxIter, numberOfAgents int, upper, lower []float64, mutationRate float64) []*ResultWithPersonalBest {
+func mutation(r *rand.Rand, pos []*ResultWithPersonalBest, curIter, maxIter, numberOfAgents int, upper, lower []float64, mutationRate float64) []*ResultWithPersonalBest {
 	// Sub-divide the swarm in three parts
 	fract := float64(numberOfAgents)/3.0 - math.Floor(float64(numberOfAgents)/3.0)
 
@@ -702,9 +846,9 @@ func mutation(pos []*ResultWithPersonalBest, curIter, maxIter, numberOfAgents in
 	if nmut > 0 {
 		// Generate random indices for mutation
 		for i := 0; i < nmut; i++ {
-			idx := cumSizes[0] + rand.Intn(subSizes[1])
+			idx := cumSizes[0] + r.IntN(subSizes[1])
 			for j := range pos[idx].Result.Position {
-				pos[idx].Result.Position[j] = lower[j] + rand.Float64()*(upper[j]-lower[j])
+				pos[idx].Result.Position[j] = lower[j] + r.Float64()*(upper[j]-lower[j])
 			}
 		}
 	}
@@ -715,9 +859,9 @@ func mutation(pos []*ResultWithPersonalBest, curIter, maxIter, numberOfAgents in
 	if nmut > 0 {
 		// Generate random indices for mutation
 		for i := 0; i < nmut; i++ {
-			idx := cumSizes[1] + rand.Intn(subSizes[2])
+			idx := cumSizes[1] + r.IntN(subSizes[2])
 			for j := range pos[idx].Result.Position {
-				pos[idx].Result.Position[j] = lower[j] + rand.Float64()*(upper[j]-lower[j])
+				pos[idx].Result.Position[j] = lower[j] + r.Float64()*(upper[j]-lower[j])
 			}
 		}
 	}
@@ -0,0 +1,39 @@
+package mopso
+
+import (
+	"golang-moaha-construction/internal/algorithms"
+	"golang-moaha-construction/internal/objectives"
+)
+
+// parameters is shared by MOPSO and its reimplementation.
+var parameters = []algorithms.Parameter{
+	{Key: "iterations", Label: "Number of iterations", Integer: true, Default: 300},
+	{Key: "population", Label: "Number of agents", Integer: true, Default: 100},
+	{Key: "archiveSize", Label: "Archive size", Integer: true, Default: 100},
+	{Key: "numberOfGrids", Label: "Number of grids", Integer: true, Default: 20},
+	{Key: "mutationRate", Label: "Mutation rate", Default: 0.5},
+	{Key: "maxVelocity", Label: "Max velocity (% of range)", Default: 5},
+	{Key: "c1", Label: "C1", Default: 2},
+	{Key: "c2", Label: "C2", Default: 2},
+	{Key: "w", Label: "W", Default: 0.4},
+}
+
+func init() {
+	algorithms.Register(NameType, func(problem objectives.Problem, config Config) (algorithms.Algorithm, error) {
+		return Create(problem, config)
+	}, algorithms.ConfigSchema{
+		Label:          "Multi-Objective Particle Swarm Optimization (MOPSO)",
+		TSName:         "MOPSO",
+		MultiObjective: true,
+		Parameters:     parameters,
+	})
+
+	algorithms.Register(ReimplNameType, func(problem objectives.Problem, config Config) (algorithms.Algorithm, error) {
+		return CreateReimpl(problem, config)
+	}, algorithms.ConfigSchema{
+		Label:          "Multi-Objective Particle Swarm Optimization, reimplementation (MOPSO-Reimpl)",
+		TSName:         "MOPSOReimpl",
+		MultiObjective: true,
+		Parameters:     parameters,
+	})
+}
@@ -1,14 +1,16 @@
 package nsgaii
 
 import (
+	"context"
 	"fmt"
 	"github.com/schollz/progressbar/v3"
 	"golang-moaha-construction/internal/algorithms"
 	"golang-moaha-construction/internal/data"
+	"golang-moaha-construction/internal/indicators"
 	"golang-moaha-construction/internal/objectives"
 	"golang-moaha-construction/internal/util"
 	"math"
-	"math/rand"
+	"math/rand/v2"
 	"sort"
 	"sync"
 )
@@ -17,24 +19,46 @@ const NameType algorithms.AlgorithmType = "NSGA-II"
 
 // NSGAIIAlgorithm proposed by Deb et al., in 2002
 type NSGAIIAlgorithm struct {
-	PopulationSize    int
-	MaxIterations     int
-	CrossoverRate     float64
-	MutationRate      float64
-	MutationStrength  float64
-	Sigma             float64
-	Population        []*objectives.Result
-	Archive           []*objectives.Result
-	ObjectiveFunction objectives.Problem
+	PopulationSize     int
+	MaxIterations      int
+	CrossoverRate      float64
+	MutationRate       float64
+	MutationStrength   float64
+	Sigma              float64
+	Population         []*objectives.Result
+	Archive            []*objectives.Result
+	ObjectiveFunction  objectives.Problem
+	Seed               int64
+	ConstraintHandling objectives.ConstraintHandling
+
+	algorithms.Checkpoints[state]
+
+	history indicators.History
+	rng     *rand.Rand
+	src     *rand.PCG
+}
+
+// state is what a checkpoint holds to continue a run.
+type state struct {
+	Iteration  int
+	Population []*objectives.Result
+	Archive    []*objectives.Result
+	Indicators indicators.History
+	Penalty    objectives.PenaltyState
+	Rand       []byte
 }
 
 type Config struct {
-	PopulationSize   int
-	MaxIterations    int
-	CrossoverRate    float64
-	MutationRate     float64
-	MutationStrength float64
-	Sigma            float64
+	PopulationSize   int     `json:"chromosome"`
+	MaxIterations    int     `json:"generation"`
+	CrossoverRate    float64 `json:"crossoverRate"`
+	MutationRate     float64 `json:"mutationRate"`
+	MutationStrength float64 `json:"mutationStrength"`
+	Sigma            float64 `json:"sigma"`
+	// Seed makes the run reproducible, 0 picks a random seed
+	Seed int64 `json:"seed"`
+	// ConstraintHandling is how results with violated constraints compare
+	ConstraintHandling objectives.ConstraintHandling `json:"constraintHandling"`
 }
 
 func Create(problem objectives.Problem, configs Config) (*NSGAIIAlgorithm, error) {
@@ -43,33 +67,44 @@ func Create(problem objectives.Problem, configs Config) (*NSGAIIAlgorithm, error
 		return nil, objectives.ErrInvalidNumberOfObjectives
 	}
 
+	seed := configs.Seed
+	if seed == 0 {
+		seed = util.NewSeed()
+	}
+
 	return &NSGAIIAlgorithm{
-		PopulationSize:    configs.PopulationSize,
-		MaxIterations:     configs.MaxIterations,
-		CrossoverRate:     configs.CrossoverRate,
-		MutationRate:      configs.MutationRate,
-		MutationStrength:  configs.MutationStrength,
-		Sigma:             configs.Sigma,
-		Archive:           make([]*objectives.Result, 0, configs.PopulationSize),
-		ObjectiveFunction: problem,
+		PopulationSize:     configs.PopulationSize,
+		MaxIterations:      configs.MaxIterations,
+		CrossoverRate:      configs.CrossoverRate,
+		MutationRate:       configs.MutationRate,
+		MutationStrength:   configs.MutationStrength,
+		Sigma:              configs.Sigma,
+		Archive:            make([]*objectives.Result, 0, configs.PopulationSize),
+		ObjectiveFunction:  problem,
+		Seed:               seed,
+		ConstraintHandling: configs.ConstraintHandling,
 	}, nil
 }
 
 func (ga *NSGAIIAlgorithm) reset() {
 	ga.Population = make([]*objectives.Result, ga.PopulationSize)
 	ga.Archive = make([]*objectives.Result, 0, ga.PopulationSize)
+	ga.rng, ga.src = util.NewRandSource(ga.Seed)
+	ga.history.Reset()
 }
 
 func (ga *NSGAIIAlgorithm) Type() data.TypeProblem {
 	return data.Multi
 }
 
-func (ga *NSGAIIAlgorithm) Run() error {
-	ga.reset()
-
-	ga.initialization()
+func (ga *NSGAIIAlgorithm) Run(ctx context.Context) error {
+	start, err := ga.start()
+	if err != nil {
+		return err
+	}
 
 	bar := progressbar.Default(int64(ga.MaxIterations))
+	_ = bar.Set(start)
 
 	var wg sync.WaitGroup
 
@@ -80,15 +115,7 @@ func (ga *NSGAIIAlgorithm) Run() error {
 	nCrossover := int(2 * math.Round(pc*float64(ga.PopulationSize)/2))
 	nMutation := int(math.Round(pm * float64(ga.PopulationSize)))
 
-	// Non-Dominated Sorting
 	var paretoFront [][]int
-	ga.Population, paretoFront = objectives.FastNonDominatedSorting_Vectorized(ga.Population)
-
-	// Calculate Crowding Distance
-	ga.Population = ga.calculateCrowdingDistance(ga.Population, paretoFront)
-
-	// Sort Population
-	ga.Population, paretoFront = SortPopulation(ga.Population)
 
 	sigma := make([]float64, len(ga.ObjectiveFunction.GetLowerBound()))
 	for i := 0; i < len(sigma); i++ {
@@ -96,33 +123,49 @@ func (ga *NSGAIIAlgorithm) Run() error {
 	}
 
 	// Main NSGA-II loop
-	for iter := 0; iter < ga.MaxIterations; iter++ {
+	for iter := start; iter < ga.MaxIterations; iter++ {
+		if err := algorithms.Interrupted(ctx, ga.ObjectiveFunction); err != nil {
+			return err
+		}
+
+		if ga.ObjectiveFunction.UpdatePenalty(iter, ga.MaxIterations, ga.Population) {
+			// the archive is rebuilt from the population below
+			objectives.Rescore(ga.ObjectiveFunction, ga.Population)
+		}
+
 		// crossover
 		popC := make([]*objectives.Result, nCrossover)
+		// one stream per worker so the offspring do not depend on scheduling
+		crossoverRngs := util.SplitRand(ga.rng, nCrossover/2)
 		wg.Add(nCrossover / 2)
 		for i := 0; i < nCrossover/2; i++ {
 			go func(idx int) {
 				defer wg.Done()
-				i1 := rand.Intn(len(ga.Population))
-				i2 := rand.Intn(len(ga.Population))
+				rng := crossoverRngs[idx]
+				i1 := rng.IntN(len(ga.Population))
+				i2 := rng.IntN(len(ga.Population))
 
 				p1 := ga.Population[i1]
 				p2 := ga.Population[i2]
 
-				child1, child2 := crossOver(p1, p2)
+				child1, child2 := crossOver(rng, p1, p2)
 
 				popC[idx*2] = child1
-				value, valuesWithKey, keys, penalty := ga.ObjectiveFunction.Eval(popC[idx*2].Position)
+				value, valuesWithKey, keys, penalty, violations := ga.ObjectiveFunction.Eval(ga.ObjectiveFunction.Repair(popC[idx*2].Position))
 				popC[idx*2].Value = value
 				popC[idx*2].ValuesWithKey = valuesWithKey
 				popC[idx*2].Penalty = penalty
+				popC[idx*2].Violations = violations
+				popC[idx*2].Violation = objectives.TotalViolation(violations)
 				popC[idx*2].Key = keys
 
 				popC[(idx*2)+1] = child2
-				value, valuesWithKey, keys, penalty = ga.ObjectiveFunction.Eval(popC[(idx*2)+1].Position)
+				value, valuesWithKey, keys, penalty, violations = ga.ObjectiveFunction.Eval(ga.ObjectiveFunction.Repair(popC[(idx*2)+1].Position))
 				popC[(idx*2)+1].Value = value
 				popC[(idx*2)+1].ValuesWithKey = valuesWithKey
 				popC[(idx*2)+1].Penalty = penalty
+				popC[(idx*2)+1].Violations = violations
+				popC[(idx*2)+1].Violation = objectives.TotalViolation(violations)
 				popC[(idx*2)+1].Key = keys
 
 			}(i)
@@ -131,19 +174,23 @@ func (ga *NSGAIIAlgorithm) Run() error {
 
 		// mutation
 		popM := make([]*objectives.Result, nMutation)
+		mutationRngs := util.SplitRand(ga.rng, nMutation)
 		wg.Add(nMutation)
 		for i := 0; i < nMutation; i++ {
 			go func(idx int) {
 				defer wg.Done()
-				i := rand.Intn(len(ga.Population))
+				rng := mutationRngs[idx]
+				i := rng.IntN(len(ga.Population))
 				p := ga.Population[i]
 
-				c := mutation(p, ga.MutationStrength, sigma)
+				c := mutation(rng, p, ga.MutationStrength, sigma)
 				popM[idx] = c
-				value, valuesWithKey, keys, penalty := ga.ObjectiveFunction.Eval(popM[idx].Position)
+				value, valuesWithKey, keys, penalty, violations := ga.ObjectiveFunction.Eval(ga.ObjectiveFunction.Repair(popM[idx].Position))
 				popM[idx].Value = value
 				popM[idx].ValuesWithKey = valuesWithKey
 				popM[idx].Penalty = penalty
+				popM[idx].Violations = violations
+				popM[idx].Violation = objectives.TotalViolation(violations)
 				popM[idx].Key = keys
 			}(i)
 		}
@@ -151,7 +198,7 @@ func (ga *NSGAIIAlgorithm) Run() error {
 
 		// Merge parent and offspring
 		newPop := objectives.MergeAgents(objectives.MergeAgents(ga.Population, popC), popM)
-		newPop, paretoFront = objectives.FastNonDominatedSorting_Vectorized(newPop)
+		newPop, paretoFront = objectives.FastNonDominatedSorting_Vectorized(newPop, ga.ConstraintHandling)
 		newPop = ga.calculateCrowdingDistance(newPop, paretoFront)
 		newPop, paretoFront = SortPopulation(newPop)
 
@@ -162,7 +209,7 @@ func (ga *NSGAIIAlgorithm) Run() error {
 			trunkedPop[i].Idx = i
 		}
 
-		trunkedPop, paretoFront = objectives.FastNonDominatedSorting_Vectorized(trunkedPop)
+		trunkedPop, paretoFront = objectives.FastNonDominatedSorting_Vectorized(trunkedPop, ga.ConstraintHandling)
 		trunkedPop = ga.calculateCrowdingDistance(trunkedPop, paretoFront)
 		trunkedPop, paretoFront = SortPopulation(trunkedPop)
 
@@ -177,15 +224,20 @@ func (ga *NSGAIIAlgorithm) Run() error {
 		// Update progress bar
 		bar.Describe(fmt.Sprintf("Iter %d: Archive size %d", iter+1, len(ga.Archive)))
 		bar.Add(1)
+
+		ga.history.Record(ga.Archive)
+		ga.checkpoint(iter + 1)
 	}
 
-	return nil
+	return ga.ObjectiveFunction.Err()
 }
 
-func (ga *NSGAIIAlgorithm) RunWithChannel(doneChan chan<- struct{}, channel chan<- any) error {
-	ga.reset()
-
-	ga.initialization()
+func (ga *NSGAIIAlgorithm) RunWithChannel(ctx context.Context, channel chan<- any) error {
+	start, err := ga.start()
+	if err != nil {
+		close(channel)
+		return err
+	}
 
 	var wg sync.WaitGroup
 
@@ -196,15 +248,7 @@ func (ga *NSGAIIAlgorithm) RunWithChannel(doneChan chan<- struct{}, channel chan
 	nCrossover := int(2 * math.Round(pc*float64(ga.PopulationSize)/2))
 	nMutation := int(math.Round(pm * float64(ga.PopulationSize)))
 
-	// Non-Dominated Sorting
 	var paretoFront [][]int
-	ga.Population, paretoFront = objectives.FastNonDominatedSorting_Vectorized(ga.Population)
-
-	// Calculate Crowding Distance
-	ga.Population = ga.calculateCrowdingDistance(ga.Population, paretoFront)
-
-	// Sort Population
-	ga.Population, paretoFront = SortPopulation(ga.Population)
 
 	sigma := make([]float64, len(ga.ObjectiveFunction.GetLowerBound()))
 	for i := 0; i < len(sigma); i++ {
@@ -212,33 +256,50 @@ func (ga *NSGAIIAlgorithm) RunWithChannel(doneChan chan<- struct{}, channel chan
 	}
 
 	// Main NSGA-II loop
-	for iter := 0; iter < ga.MaxIterations; iter++ {
+	for iter := start; iter < ga.MaxIterations; iter++ {
+		if err := algorithms.Interrupted(ctx, ga.ObjectiveFunction); err != nil {
+			close(channel)
+			return err
+		}
+
+		if ga.ObjectiveFunction.UpdatePenalty(iter, ga.MaxIterations, ga.Population) {
+			// the archive is rebuilt from the population below
+			objectives.Rescore(ga.ObjectiveFunction, ga.Population)
+		}
+
 		// crossover
 		popC := make([]*objectives.Result, nCrossover)
+		// one stream per worker so the offspring do not depend on scheduling
+		crossoverRngs := util.SplitRand(ga.rng, nCrossover/2)
 		wg.Add(nCrossover / 2)
 		for i := 0; i < nCrossover/2; i++ {
 			go func(idx int) {
 				defer wg.Done()
-				i1 := rand.Intn(len(ga.Population))
-				i2 := rand.Intn(len(ga.Population))
+				rng := crossoverRngs[idx]
+				i1 := rng.IntN(len(ga.Population))
+				i2 := rng.IntN(len(ga.Population))
 
 				p1 := ga.Population[i1]
 				p2 := ga.Population[i2]
 
-				child1, child2 := crossOver(p1, p2)
+				child1, child2 := crossOver(rng, p1, p2)
 
 				popC[idx*2] = child1
-				value, valuesWithKey, keys, penalty := ga.ObjectiveFunction.Eval(popC[idx*2].Position)
+				value, valuesWithKey, keys, penalty, violations := ga.ObjectiveFunction.Eval(ga.ObjectiveFunction.Repair(popC[idx*2].Position))
 				popC[idx*2].Value = value
 				popC[idx*2].ValuesWithKey = valuesWithKey
 				popC[idx*2].Penalty = penalty
+				popC[idx*2].Violations = violations
+				popC[idx*2].Violation = objectives.TotalViolation(violations)
 				popC[idx*2].Key = keys
 
 				popC[(idx*2)+1] = child2
-				value, valuesWithKey, keys, penalty = ga.ObjectiveFunction.Eval(popC[(idx*2)+1].Position)
+				value, valuesWithKey, keys, penalty, violations = ga.ObjectiveFunction.Eval(ga.ObjectiveFunction.Repair(popC[(idx*2)+1].Position))
 				popC[(idx*2)+1].Value = value
 				popC[(idx*2)+1].ValuesWithKey = valuesWithKey
 				popC[(idx*2)+1].Penalty = penalty
+				popC[(idx*2)+1].Violations = violations
+				popC[(idx*2)+1].Violation = objectives.TotalViolation(violations)
 				popC[(idx*2)+1].Key = keys
 
 			}(i)
@@ -247,19 +308,23 @@ func (ga *NSGAIIAlgorithm) RunWithChannel(doneChan chan<- struct{}, channel chan
 
 		// mutation
 		popM := make([]*objectives.Result, nMutation)
+		mutationRngs := util.SplitRand(ga.rng, nMutation)
 		wg.Add(nMutation)
 		for i := 0; i < nMutation; i++ {
 			go func(idx int) {
 				defer wg.Done()
-				i := rand.Intn(len(ga.Population))
+				rng := mutationRngs[idx]
+				i := rng.IntN(len(ga.Population))
 				p := ga.Population[i]
 
-				c := mutation(p, ga.MutationStrength, sigma)
+				c := mutation(rng, p, ga.MutationStrength, sigma)
 				popM[idx] = c
-				value, valuesWithKey, keys, penalty := ga.ObjectiveFunction.Eval(popM[idx].Position)
+				value, valuesWithKey, keys, penalty, violations := ga.ObjectiveFunction.Eval(ga.ObjectiveFunction.Repair(popM[idx].Position))
 				popM[idx].Value = value
 				popM[idx].ValuesWithKey = valuesWithKey
 				popM[idx].Penalty = penalty
+				popM[idx].Violations = violations
+				popM[idx].Violation = objectives.TotalViolation(violations)
 				popM[idx].Key = keys
 			}(i)
 		}
@@ -267,7 +332,7 @@ func (ga *NSGAIIAlgorithm) RunWithChannel(doneChan chan<- struct{}, channel chan
 
 		// Merge parent and offspring
 		newPop := objectives.MergeAgents(objectives.MergeAgents(ga.Population, popC), popM)
-		newPop, paretoFront = objectives.FastNonDominatedSorting_Vectorized(newPop)
+		newPop, paretoFront = objectives.FastNonDominatedSorting_Vectorized(newPop, ga.ConstraintHandling)
 		newPop = ga.calculateCrowdingDistance(newPop, paretoFront)
 		newPop, paretoFront = SortPopulation(newPop)
 
@@ -278,7 +343,7 @@ func (ga *NSGAIIAlgorithm) RunWithChannel(doneChan chan<- struct{}, channel chan
 			trunkedPop[i].Idx = i
 		}
 
-		trunkedPop, paretoFront = objectives.FastNonDominatedSorting_Vectorized(trunkedPop)
+		trunkedPop, paretoFront = objectives.FastNonDominatedSorting_Vectorized(trunkedPop, ga.ConstraintHandling)
 		trunkedPop = ga.calculateCrowdingDistance(trunkedPop, paretoFront)
 		trunkedPop, paretoFront = SortPopulation(trunkedPop)
 
@@ -300,17 +365,80 @@ func (ga *NSGAIIAlgorithm) RunWithChannel(doneChan chan<- struct{}, channel chan
 			NumberOfAgentsInArchive: len(ga.Archive),
 			Type:                    "multi",
 		}
+
+		ga.history.Record(ga.Archive)
+		ga.checkpoint(iter + 1)
 	}
 
 	close(channel)
 
-	return nil
+	return ga.ObjectiveFunction.Err()
+}
+
+// LoadState refuses a state whose population does not fit the config,
+// e.g. after the population size has changed.
+func (ga *NSGAIIAlgorithm) LoadState(encoded []byte) error {
+	return ga.LoadStateChecked(encoded, func(s *state) error {
+		return algorithms.CheckStateSize("chromosomes", len(s.Population), ga.PopulationSize)
+	})
+}
+
+// start resets the algorithm and either initializes and sorts a new population
+// or restores the state loaded by LoadState. It returns the iteration to
+// continue from.
+func (ga *NSGAIIAlgorithm) start() (int, error) {
+	ga.reset()
+
+	s := ga.Resume()
+	if s == nil {
+		ga.initialization()
+
+		// Non-Dominated Sorting
+		var paretoFront [][]int
+		ga.Population, paretoFront = objectives.FastNonDominatedSorting_Vectorized(ga.Population, ga.ConstraintHandling)
+
+		// Calculate Crowding Distance
+		ga.Population = ga.calculateCrowdingDistance(ga.Population, paretoFront)
+
+		// Sort Population
+		ga.Population, _ = SortPopulation(ga.Population)
+
+		return 0, nil
+	}
+
+	err := ga.src.UnmarshalBinary(s.Rand)
+	if err != nil {
+		return 0, err
+	}
+
+	ga.ObjectiveFunction.SetPenaltyState(s.Penalty)
+
+	ga.Population = s.Population
+	ga.Archive = s.Archive
+	ga.history = s.Indicators
+
+	return s.Iteration, nil
+}
+
+func (ga *NSGAIIAlgorithm) checkpoint(iteration int) {
+	ga.Save(iteration, func() state {
+		rngState, _ := ga.src.MarshalBinary()
+		return state{
+			Iteration:  iteration,
+			Population: ga.Population,
+			Archive:    ga.Archive,
+			Indicators: ga.history,
+			Penalty:    ga.ObjectiveFunction.PenaltyState(),
+			Rand:       rngState,
+		}
+	})
 }
 
 func (ga *NSGAIIAlgorithm) initialization() {
 	dim := ga.ObjectiveFunction.GetDimension()
 	lowerBound := ga.ObjectiveFunction.GetLowerBound()
 	upperBound := ga.ObjectiveFunction.GetUpperBound()
+	rngs := util.SplitRand(ga.rng, ga.PopulationSize)
 	var wg sync.WaitGroup
 	wg.Add(ga.PopulationSize)
 	for i := 0; i < ga.PopulationSize; i++ {
@@ -318,7 +446,7 @@ func (ga *NSGAIIAlgorithm) initialization() {
 			defer wg.Done()
 			pos := make([]float64, dim)
 			for d := 0; d < dim; d++ {
-				pos[d] = lowerBound[d] + rand.Float64()*(upperBound[d]-lowerBound[d])
+				pos[d] = lowerBound[d] + rngs[idx].Float64()*(upperBound[d]-lowerBound[d])
 			}
 
 			newGene := &objectives.Result{
@@ -326,9 +454,11 @@ func (ga *NSGAIIAlgorithm) initialization() {
 				Position: pos,
 			}
 
-			value, valuesWithKey, keys, penalty := ga.ObjectiveFunction.Eval(pos)
+			value, valuesWithKey, keys, penalty, violations := ga.ObjectiveFunction.Eval(ga.ObjectiveFunction.Repair(pos))
 			newGene.Value = value
 			newGene.Penalty = penalty
+			newGene.Violations = violations
+			newGene.Violation = objectives.TotalViolation(violations)
 			newGene.Key = keys
 			newGene.ValuesWithKey = valuesWithKey
 
@@ -354,6 +484,7 @@ func (ga *NSGAIIAlgorithm) GetResults() algorithms.Result {
 
 		results[i] = algorithms.AlgorithmResult{
 			MapLocations:   mapLoc,
+			PhaseLocations: objectives.PhaseLocationResult(ga.ObjectiveFunction, solution.Position),
 			SliceLocations: sliceLoc,
 			Value:          solution.Value,
 			Penalty:        solution.Penalty,
@@ -367,11 +498,13 @@ func (ga *NSGAIIAlgorithm) GetResults() algorithms.Result {
 	minX, maxX, minY, maxY, _ := ga.ObjectiveFunction.GetLayoutSize()
 
 	return algorithms.Result{
-		Result: results,
-		MinX:   minX,
-		MaxX:   maxX,
-		MinY:   minY,
-		MaxY:   maxY,
+		Result:     results,
+		MinX:       minX,
+		MaxX:       maxX,
+		MinY:       minY,
+		MaxY:       maxY,
+		Seed:       ga.Seed,
+		Indicators: ga.history.Values(),
 	}
 }
 
@@ -501,7 +634,7 @@ func SortPopulation(pop []*objectives.Result) ([]*objectives.Result, [][]int) {
 	return sorted, F
 }
 
-func crossOver(p1, p2 *objectives.Result) (*objectives.Result, *objectives.Result) {
+func crossOver(r *rand.Rand, p1, p2 *objectives.Result) (*objectives.Result, *objectives.Result) {
 
 	child1 := &objectives.Result{
 		Position: make([]float64, len(p1.Position)),
@@ -511,7 +644,7 @@ func crossOver(p1, p2 *objectives.Result) (*objectives.Result, *objectives.Resul
 	}
 
 	for i := 0; i < len(p1.Position); i++ {
-		alpha := rand.Float64()
+		alpha := r.Float64()
 		child1.Position[i] = p1.Position[i]*alpha + (1-alpha)*(p2.Position[i])
 		child2.Position[i] = p2.Position[i]*alpha + (1-alpha)*(p1.Position[i])
 	}
@@ -519,7 +652,7 @@ func crossOver(p1, p2 *objectives.Result) (*objectives.Result, *objectives.Resul
 	return child1, child2
 }
 
-func mutation(p *objectives.Result, mu float64, sigma []float64) *objectives.Result {
+func mutation(r *rand.Rand, p *objectives.Result, mu float64, sigma []float64) *objectives.Result {
 	child := &objectives.Result{
 		Position: make([]float64, len(p.Position)),
 	}
@@ -532,10 +665,10 @@ func mutation(p *objectives.Result, mu float64, sigma []float64) *objectives.Res
 	if nMutations == 0 {
 		return child
 	}
-	indices := util.RandomSample(nVar, nMutations)
+	indices := util.RandomSample(r, nVar, nMutations)
 
 	for _, idx := range indices {
-		gaussianNoise := rand.NormFloat64()
+		gaussianNoise := r.NormFloat64()
 
 		child.Position[idx] = p.Position[idx] + sigma[idx]*gaussianNoise
 	}
@@ -12,7 +12,9 @@ type MockProblem struct {
 	GetLowerBoundFunc      func() []float64
 	GetUpperBoundFunc      func() []float64
 	GetDimensionFunc       func() int
-	EvalFunc               func(pos []float64) (values []float64, valuesWithKey map[data.ObjectiveType]float64, key []data.ObjectiveType, penalty map[data.ConstraintType]float64)
+	EvalFunc               func(pos []float64) (values []float64, valuesWithKey map[data.ObjectiveType]float64, key []data.ObjectiveType, penalty map[data.ConstraintType]float64, violations map[data.ConstraintType]float64)
+
+	objectives.AdaptivePenalty
 }
 
 func (m *MockProblem) GetUpperBound() []float64 {
@@ -99,11 +101,22 @@ func (m *MockProblem) GetLayoutSize() (minX float64, maxX float64, minY float64,
 	return 0, 0, 0, 0, nil
 }
 
+func (m *MockProblem) Repair(pos []float64) []float64 {
+	return pos
+}
+
+func (m *MockProblem) Err() error {
+	return nil
+}
+
+func (m *MockProblem) ResetErr() {}
+
 func (m *MockProblem) Eval(input []float64) (
 	values []float64,
 	valuesWithKey map[data.ObjectiveType]float64,
 	key []data.ObjectiveType,
-	penalty map[data.ConstraintType]float64) {
+	penalty map[data.ConstraintType]float64,
+	violations map[data.ConstraintType]float64) {
 	return m.EvalFunc(input)
 }
 
@@ -0,0 +1,24 @@
+package nsgaii
+
+import (
+	"golang-moaha-construction/internal/algorithms"
+	"golang-moaha-construction/internal/objectives"
+)
+
+func init() {
+	algorithms.Register(NameType, func(problem objectives.Problem, config Config) (algorithms.Algorithm, error) {
+		return Create(problem, config)
+	}, algorithms.ConfigSchema{
+		Label:          "Non-dominated Sorting Genetic Algorithm II (NSGA-II)",
+		TSName:         "NSGAII",
+		MultiObjective: true,
+		Parameters: []algorithms.Parameter{
+			{Key: "chromosome", Label: "Population size", Integer: true, Default: 100},
+			{Key: "generation", Label: "Number of generations", Integer: true, Default: 300},
+			{Key: "crossoverRate", Label: "Crossover rate", Default: 0.7},
+			{Key: "mutationRate", Label: "Mutation rate", Default: 0.4},
+			{Key: "mutationStrength", Label: "Mutation strength", Default: 0.01},
+			{Key: "sigma", Label: "Sigma", Default: 0.1},
+		},
+	})
+}
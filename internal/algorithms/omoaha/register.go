@@ -0,0 +1,21 @@
+package omoaha
+
+import (
+	"golang-moaha-construction/internal/algorithms"
+	"golang-moaha-construction/internal/objectives"
+)
+
+func init() {
+	algorithms.Register(NameType, func(problem objectives.Problem, config Configs) (algorithms.Algorithm, error) {
+		return Create(problem, config)
+	}, algorithms.ConfigSchema{
+		Label:          "OBL Multi-Objective Artificial Hummingbird Algorithm (oMOAHA)",
+		TSName:         "oMOAHA",
+		MultiObjective: true,
+		Parameters: []algorithms.Parameter{
+			{Key: "iterations", Label: "Number of iterations", Integer: true, Default: 300},
+			{Key: "population", Label: "Number of agents", Integer: true, Default: 100},
+			{Key: "archiveSize", Label: "Archive size", Integer: true, Default: 100},
+		},
+	})
+}
@@ -0,0 +1,254 @@
+package algorithms
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/bytedance/sonic"
+	"golang-moaha-construction/internal/objectives"
+	"golang-moaha-construction/internal/util"
+	"slices"
+	"sync"
+)
+
+// SeedKey is the config key of the seed. Every algorithm has it, Register adds
+// it to the schema.
+const SeedKey = "seed"
+
+// ConstraintHandlingKey is the config key of the constraint handling of the
+// run, see objectives.ConstraintHandling. Register adds it to every schema.
+const ConstraintHandlingKey = "constraintHandling"
+
+var (
+	ErrUnknownAlgorithm = errors.New("invalid algorithm name")
+	ErrInvalidOption    = errors.New("invalid option")
+)
+
+// Parameter describes one key of an algorithm config.
+type Parameter struct {
+	Key     string  `json:"key"`
+	Label   string  `json:"label"`
+	Integer bool    `json:"integer"`
+	Default float64 `json:"default"`
+	// Options are the valid values of a parameter that is a choice instead of
+	// a number, the first one being the default
+	Options []string `json:"options,omitempty"`
+}
+
+// ConfigSchema describes an algorithm and its config for the UI and the
+// exported summary.
+type ConfigSchema struct {
+	// Label is the full name shown to the user
+	Label string `json:"label"`
+	// TSName is the name of the algorithm in the frontend enum
+	TSName         string      `json:"tsName"`
+	MultiObjective bool        `json:"multiObjective"`
+	Parameters     []Parameter `json:"parameters"`
+}
+
+// Factory creates an algorithm for problem from its decoded config.
+type Factory[C any] func(problem objectives.Problem, config C) (Algorithm, error)
+
+// Registration is a registered algorithm.
+type Registration struct {
+	Name   AlgorithmType `json:"name"`
+	Schema ConfigSchema  `json:"schema"`
+}
+
+// ParameterValue is the value a created algorithm uses for a parameter.
+type ParameterValue struct {
+	Key   string `json:"key"`
+	Label string `json:"label"`
+	Value any    `json:"value"`
+}
+
+// Info describes a created algorithm with every parameter it uses, defaults
+// and the picked seed included.
+type Info struct {
+	Name       AlgorithmType    `json:"name"`
+	Label      string           `json:"label"`
+	Parameters []ParameterValue `json:"parameters"`
+}
+
+type registration struct {
+	Registration
+	create func(problem objectives.Problem, config map[string]any) (Algorithm, error)
+}
+
+var (
+	registryMu sync.RWMutex
+	registry   []registration
+)
+
+// Register makes an algorithm available under name. It is meant to be called
+// from the init function of the algorithm package and panics when name is
+// registered twice.
+//
+// The config of the algorithm is decoded into C with the json tags of C, after
+// the missing keys are filled with the defaults of schema.
+func Register[C any](name AlgorithmType, factory Factory[C], schema ConfigSchema) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	for _, r := range registry {
+		if r.Name == name {
+			panic(fmt.Sprintf("algorithms: %s registered twice", name))
+		}
+	}
+
+	handlings := make([]string, len(objectives.ConstraintHandlings))
+	for i, h := range objectives.ConstraintHandlings {
+		handlings[i] = string(h)
+	}
+
+	schema.Parameters = append(slices.Clone(schema.Parameters), Parameter{
+		Key:     ConstraintHandlingKey,
+		Label:   "Constraint handling",
+		Options: handlings,
+	}, Parameter{
+		Key:     SeedKey,
+		Label:   "Seed",
+		Integer: true,
+	})
+
+	registry = append(registry, registration{
+		Registration: Registration{
+			Name:   name,
+			Schema: schema,
+		},
+		create: func(problem objectives.Problem, config map[string]any) (Algorithm, error) {
+			configBytes, err := sonic.Marshal(config)
+			if err != nil {
+				return nil, err
+			}
+
+			var c C
+			err = sonic.Unmarshal(configBytes, &c)
+			if err != nil {
+				return nil, err
+			}
+
+			return factory(problem, c)
+		},
+	})
+}
+
+// Registered returns every registered algorithm in registration order.
+func Registered() []Registration {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	res := make([]Registration, len(registry))
+	for i, r := range registry {
+		res[i] = r.Registration
+	}
+
+	return res
+}
+
+// New creates the algorithm registered under name for problem. config is
+// anything that encodes to a JSON object, usually the config sent by the UI.
+// A missing or zero seed is replaced with a random one so that Info reports
+// the seed the run uses.
+func New(name AlgorithmType, problem objectives.Problem, config any) (Algorithm, Info, error) {
+	registryMu.RLock()
+	var r *registration
+	for i := range registry {
+		if registry[i].Name == name {
+			r = &registry[i]
+			break
+		}
+	}
+	registryMu.RUnlock()
+
+	if r == nil {
+		return nil, Info{}, fmt.Errorf("%w: %s", ErrUnknownAlgorithm, name)
+	}
+
+	values, err := resolveConfig(r.Schema, config)
+	if err != nil {
+		return nil, Info{}, err
+	}
+
+	algo, err := r.create(problem, values)
+	if err != nil {
+		return nil, Info{}, err
+	}
+
+	info := Info{
+		Name:       name,
+		Label:      r.Schema.Label,
+		Parameters: make([]ParameterValue, len(r.Schema.Parameters)),
+	}
+	for i, p := range r.Schema.Parameters {
+		info.Parameters[i] = ParameterValue{
+			Key:   p.Key,
+			Label: p.Label,
+			Value: values[p.Key],
+		}
+	}
+
+	return algo, info, nil
+}
+
+// resolveConfig decodes config into a map and fills in the defaults of
+// schema and the seed. Whole numbers become int64 so that seeds keep every
+// bit, which is why the map is decoded with encoding/json and UseNumber.
+func resolveConfig(schema ConfigSchema, config any) (map[string]any, error) {
+	values := make(map[string]any)
+
+	if config != nil {
+		configBytes, err := sonic.Marshal(config)
+		if err != nil {
+			return nil, err
+		}
+
+		decoder := json.NewDecoder(bytes.NewReader(configBytes))
+		decoder.UseNumber()
+		err = decoder.Decode(&values)
+		if err != nil {
+			return nil, fmt.Errorf("algorithm config must be an object: %w", err)
+		}
+	}
+
+	// a null config decodes into a nil map
+	if values == nil {
+		values = make(map[string]any)
+	}
+
+	for key, value := range values {
+		if number, ok := value.(json.Number); ok {
+			if i, err := number.Int64(); err == nil {
+				values[key] = i
+			} else if f, err := number.Float64(); err == nil {
+				values[key] = f
+			}
+		}
+	}
+
+	for _, p := range schema.Parameters {
+		value, ok := values[p.Key]
+		if ok && len(p.Options) > 0 && !slices.Contains(p.Options, fmt.Sprint(value)) {
+			return nil, fmt.Errorf("%w for %s: %v", ErrInvalidOption, p.Key, value)
+		}
+
+		if ok || p.Key == SeedKey {
+			continue
+		}
+
+		if len(p.Options) > 0 {
+			values[p.Key] = p.Options[0]
+		} else if p.Integer {
+			values[p.Key] = int64(p.Default)
+		} else {
+			values[p.Key] = p.Default
+		}
+	}
+
+	if seed := values[SeedKey]; seed == nil || seed == int64(0) || seed == 0.0 {
+		values[SeedKey] = util.NewSeed()
+	}
+
+	return values, nil
+}
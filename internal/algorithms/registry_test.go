@@ -0,0 +1,80 @@
+package algorithms
+
+import (
+	"errors"
+	"golang-moaha-construction/internal/objectives"
+	"testing"
+)
+
+type testConfig struct {
+	Iterations int     `json:"iterations"`
+	Rate       float64 `json:"rate"`
+	Seed       int64   `json:"seed"`
+
+	ConstraintHandling objectives.ConstraintHandling `json:"constraintHandling"`
+}
+
+type testAlgorithm struct {
+	Algorithm
+	config testConfig
+}
+
+func TestRegistry(t *testing.T) {
+	const name AlgorithmType = "registry-test"
+
+	Register(name, func(problem objectives.Problem, config testConfig) (Algorithm, error) {
+		return &testAlgorithm{config: config}, nil
+	}, ConfigSchema{
+		Label: "Test",
+		Parameters: []Parameter{
+			{Key: "iterations", Label: "Number of iterations", Integer: true, Default: 300},
+			{Key: "rate", Label: "Rate", Default: 0.5},
+		},
+	})
+
+	algo, info, err := New(name, nil, map[string]any{"rate": 0.25, "seed": int64(1) << 60})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	config := algo.(*testAlgorithm).config
+	if config.Iterations != 300 || config.Rate != 0.25 {
+		t.Errorf("expected the default iterations and the given rate, got %+v", config)
+	}
+	if config.Seed != 1<<60 {
+		t.Errorf("expected the seed to keep every bit, got %d", config.Seed)
+	}
+
+	if len(info.Parameters) != 4 || info.Parameters[3].Key != SeedKey {
+		t.Errorf("expected the seed to be added to the parameters, got %+v", info.Parameters)
+	}
+	if config.ConstraintHandling != objectives.PenaltyHandling || info.Parameters[2].Value != "penalty" {
+		t.Errorf("expected the penalty handling by default, got %q", config.ConstraintHandling)
+	}
+
+	algo, info, err = New(name, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if seed := algo.(*testAlgorithm).config.Seed; seed == 0 || info.Parameters[3].Value != seed {
+		t.Errorf("expected a random seed reported in the info, got %d and %v", seed, info.Parameters[3].Value)
+	}
+
+	algo, _, err = New(name, nil, map[string]any{ConstraintHandlingKey: "feasibility-first"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if handling := algo.(*testAlgorithm).config.ConstraintHandling; handling != objectives.FeasibilityFirst {
+		t.Errorf("expected the given constraint handling, got %q", handling)
+	}
+
+	_, _, err = New(name, nil, map[string]any{ConstraintHandlingKey: "death"})
+	if !errors.Is(err, ErrInvalidOption) {
+		t.Errorf("expected ErrInvalidOption, got %v", err)
+	}
+
+	_, _, err = New("unknown", nil, nil)
+	if !errors.Is(err, ErrUnknownAlgorithm) {
+		t.Errorf("expected ErrUnknownAlgorithm, got %v", err)
+	}
+}
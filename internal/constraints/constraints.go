@@ -1,8 +1,18 @@
 package constraints
 
 import (
+	"fmt"
 	"golang-moaha-construction/internal/data"
+	"golang-moaha-construction/internal/objectives/conslay_continuous"
+	"golang-moaha-construction/internal/objectives/conslay_grid"
+	"golang-moaha-construction/internal/util"
+	"maps"
 	"math"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
 )
 
 // list constraints
@@ -12,8 +22,15 @@ const (
 	ConstraintOutOfBound          data.ConstraintType = "OutOfBound"
 	ConstraintsCoverInCraneRadius data.ConstraintType = "CoverInCraneRadius"
 	ConstraintInclusiveZone       data.ConstraintType = "InclusiveZone"
+	ConstraintExclusiveZone       data.ConstraintType = "ExclusiveZone"
 )
 
+// layoutProblems are the problems placing facilities on the site
+var layoutProblems = []data.ProblemName{
+	conslay_continuous.ContinuousConsLayoutName,
+	conslay_grid.GridConsLayoutName,
+}
+
 // Cover Range of Crane
 
 type CoverRangeCraneConstraint struct {
@@ -38,6 +55,87 @@ func CreateCoverRangeCraneConstraint(
 	}
 }
 
+type coverInCraneRadiusConfig struct {
+	AlphaCoverInCraneRadiusPenalty float64 `json:"AlphaCoverInCraneRadiusPenalty"`
+	PowerDifferencePenalty         float64 `json:"PowerDifferencePenalty"`
+	CraneLocations                 []struct {
+		Name          string  `json:"Name"`
+		BuildingNames string  `json:"BuildingNames"`
+		Radius        float64 `json:"Radius"`
+	}
+}
+
+func init() {
+	Register(ConstraintsCoverInCraneRadius, Definition[coverInCraneRadiusConfig, *CoverRangeCraneConstraint]{
+		Schema: ConfigSchema{
+			Label:  "Cover in Crane's radius",
+			TSName: "CoverInCraneRadius",
+			Fields: []Field{
+				{Key: "AlphaCoverInCraneRadiusPenalty", Label: "Alpha (for penalty)", Type: FieldNumber, Default: 20000.0},
+				{Key: "PowerDifferencePenalty", Label: "Power difference (for penalty)", Type: FieldNumber, Default: 1.0},
+				{Key: "CraneLocations", Label: "Cranes", Type: FieldList, Fields: []Field{
+					{Key: "Name", Label: "Crane", Type: FieldString},
+					{Key: "BuildingNames", Label: "Facilities", Type: FieldNames},
+					{Key: "Radius", Label: "Radius", Type: FieldNumber},
+				}},
+			},
+		},
+		Problems: layoutProblems,
+		Create: func(problem Problem, config coverInCraneRadiusConfig) (*CoverRangeCraneConstraint, error) {
+			cranesLocation := make([]data.Crane, len(config.CraneLocations))
+
+			for i, craneLocation := range config.CraneLocations {
+				facilitiesName, err := formatBuildingNames(craneLocation.BuildingNames)
+				if err != nil {
+					return nil, err
+				}
+
+				cranesLocation[i] = data.Crane{
+					Location:     problem.GetLocations()[craneLocation.Name],
+					CraneSymbol:  craneLocation.Name,
+					BuildingName: facilitiesName,
+					Radius:       craneLocation.Radius,
+				}
+			}
+
+			err := problem.SetCranesLocations(cranesLocation)
+			if err != nil {
+				return nil, err
+			}
+
+			return CreateCoverRangeCraneConstraint(
+				cranesLocation,
+				problem.GetPhases(),
+				config.AlphaCoverInCraneRadiusPenalty,
+				config.PowerDifferencePenalty,
+			), nil
+		},
+		Info: func(con *CoverRangeCraneConstraint) any {
+			return struct {
+				AlphaCoverInCraneRadiusPenalty float64      `json:"alphaCoverInCraneRadiusPenalty"`
+				PowerCoverInCraneRadiusPenalty float64      `json:"powerCoverInCraneRadiusPenalty"`
+				Phases                         [][]string   `json:"phases"`
+				Cranes                         []data.Crane `json:"cranes"`
+			}{
+				AlphaCoverInCraneRadiusPenalty: con.AlphaCoverRangePenalty,
+				PowerCoverInCraneRadiusPenalty: con.PowerCoverRangePenalty,
+				Phases:                         con.Phases,
+				Cranes:                         con.Cranes,
+			}
+		},
+		Summary: func(con *CoverRangeCraneConstraint, w SummaryWriter) {
+			w.Value("Alpha (for penalty)", con.AlphaCoverRangePenalty)
+			w.Value("Power difference (for penalty)", con.PowerCoverRangePenalty)
+
+			for _, crane := range con.Cranes {
+				w.Group(crane.CraneSymbol)
+				w.Value("Facilities", strings.Join(crane.BuildingName, " "))
+				w.Value("Radius", crane.Radius)
+			}
+		},
+	})
+}
+
 func (c CoverRangeCraneConstraint) GetName() string {
 	return string(c.Name)
 }
@@ -50,13 +148,16 @@ func (c CoverRangeCraneConstraint) GetPowerPenalty() float64 {
 	return c.PowerCoverRangePenalty
 }
 
-func (c CoverRangeCraneConstraint) Eval(mapLocations map[string]data.Location) float64 {
+func (c CoverRangeCraneConstraint) Eval(mapLocations map[string]data.Location) (float64, error) {
 	amount := 0.0
 	for i := 0; i < len(c.Cranes); i++ {
-		buildings := make([]data.Location, len(c.Cranes[i].BuildingName))
+		buildings := make([]data.Location, 0, len(c.Cranes[i].BuildingName))
 
+		// a building idle in a phase of a dynamic layout is not in it
 		for j := 0; j < len(c.Cranes[i].BuildingName); j++ {
-			buildings[j] = mapLocations[c.Cranes[i].BuildingName[j]]
+			if building, ok := mapLocations[c.Cranes[i].BuildingName[j]]; ok {
+				buildings = append(buildings, building)
+			}
 		}
 		//fmt.Println(i + 1)
 		//fmt.Println("Buildings", buildings)
@@ -65,45 +166,17 @@ func (c CoverRangeCraneConstraint) Eval(mapLocations map[string]data.Location) f
 		amount += val
 	}
 
-	return amount
+	return amount, nil
 }
 
+// IsCoverRangeOfCrane reports whether every corner of the buildings is within
+// the radius of the crane, and the total distance of the corners outside it.
+// The corners of a freely rotated building are its rotated ones.
 func IsCoverRangeOfCrane(crane data.Crane, buildings []data.Location) (bool, float64) {
 	invalidAmountTotal := 0.0
 	for _, building := range buildings {
-		// Top Right
-		topRightCoordinate := data.Coordinate{
-			X: building.Coordinate.X + building.Length/2,
-			Y: building.Coordinate.Y + building.Width/2,
-		}
-
-		topRightAmount := data.Distance2D(topRightCoordinate, crane.Coordinate) - crane.Radius
-
-		topLeftCoordinate := data.Coordinate{
-			X: building.Coordinate.X - building.Length/2,
-			Y: building.Coordinate.Y + building.Width/2,
-		}
-
-		topLeftAmount := data.Distance2D(topLeftCoordinate, crane.Coordinate) - crane.Radius
-
-		bottomLeftCoordinate := data.Coordinate{
-			X: building.Coordinate.X - building.Length/2,
-			Y: building.Coordinate.Y - building.Width/2,
-		}
-		bottomLeftAmount := data.Distance2D(bottomLeftCoordinate, crane.Coordinate) - crane.Radius
-
-		bottomRightCoordinate := data.Coordinate{
-			X: building.Coordinate.X + building.Length/2,
-			Y: building.Coordinate.Y - building.Width/2,
-		}
-		bottomRightAmount := data.Distance2D(bottomRightCoordinate, crane.Coordinate) - crane.Radius
-
-		if topRightAmount > 0 || topLeftAmount > 0 || bottomLeftAmount > 0 || bottomRightAmount > 0 {
-			invalidAmountTotal += max(0, topLeftAmount) +
-				max(0, topRightAmount) +
-				max(0, bottomLeftAmount) +
-				max(0, bottomRightAmount)
-
+		for _, corner := range building.Corners() {
+			invalidAmountTotal += max(0, data.Distance2D(corner, crane.Coordinate)-crane.Radius)
 		}
 	}
 	if invalidAmountTotal > 0 {
@@ -121,6 +194,22 @@ type OverlapConstraint struct {
 	Name                data.ConstraintType
 	AlphaOverlapPenalty float64
 	PowerOverlapPenalty float64
+	// Clearance is the gap required between any two facilities
+	Clearance float64
+	// FacilityClearances is the gap required around a facility, raising
+	// Clearance for its neighbours
+	FacilityClearances map[string]float64
+	// PairClearances replace the gap required between two facilities
+	PairClearances    []PairClearance
+	ClearanceFilePath string
+	pairClearances    map[[2]string]float64
+}
+
+// PairClearance is the gap required between two facilities.
+type PairClearance struct {
+	First     string
+	Second    string
+	Clearance float64
 }
 
 func CreateOverlapConstraint(
@@ -136,6 +225,140 @@ func CreateOverlapConstraint(
 	}
 }
 
+// SetClearances sets the gaps required between the facilities.
+func (c *OverlapConstraint) SetClearances(clearance float64, facilityClearances map[string]float64, pairClearances []PairClearance) {
+	c.Clearance = clearance
+	c.FacilityClearances = facilityClearances
+	c.PairClearances = pairClearances
+
+	c.pairClearances = make(map[[2]string]float64, len(pairClearances))
+	for _, pair := range pairClearances {
+		c.pairClearances[[2]string{min(pair.First, pair.Second), max(pair.First, pair.Second)}] = pair.Clearance
+	}
+}
+
+// ClearanceBetween is the gap required between the facilities first and
+// second: their pair clearance if any, otherwise the largest of Clearance and
+// their facility clearances.
+func (c OverlapConstraint) ClearanceBetween(first, second string) float64 {
+	if clearance, ok := c.pairClearances[[2]string{min(first, second), max(first, second)}]; ok {
+		return clearance
+	}
+
+	return max(c.Clearance, c.FacilityClearances[first], c.FacilityClearances[second])
+}
+
+type overlapConfig struct {
+	AlphaOverlapPenalty    float64 `json:"AlphaOverLapPenalty"`
+	PowerDifferencePenalty float64 `json:"PowerDifferencePenalty"`
+	Clearance              float64 `json:"Clearance"`
+	ClearanceFilePath      string  `json:"ClearanceFilePath"`
+}
+
+func init() {
+	Register(ConstraintOverlap, Definition[overlapConfig, *OverlapConstraint]{
+		Schema: ConfigSchema{
+			Label:  "Overlap",
+			TSName: "Overlap",
+			Fields: []Field{
+				{Key: "AlphaOverLapPenalty", Label: "Alpha (for penalty)", Type: FieldNumber, Default: 20000.0},
+				{Key: "PowerDifferencePenalty", Label: "Power difference (for penalty)", Type: FieldNumber, Default: 1.0},
+				{Key: "Clearance", Label: "Clearance", Type: FieldNumber, Default: 0.0},
+				{Key: "ClearanceFilePath", Label: "Clearances file", Type: FieldString},
+			},
+		},
+		Problems: layoutProblems,
+		Create: func(problem Problem, config overlapConfig) (*OverlapConstraint, error) {
+			if config.Clearance < 0 {
+				return nil, fmt.Errorf("the clearance must not be negative")
+			}
+
+			con := CreateOverlapConstraint(
+				problem.GetPhases(),
+				config.AlphaOverlapPenalty,
+				config.PowerDifferencePenalty,
+			)
+
+			facilityClearances := make(map[string]float64)
+			var pairClearances []PairClearance
+			if config.ClearanceFilePath != "" {
+				var err error
+				facilityClearances, pairClearances, err = ReadClearancesFromFile(config.ClearanceFilePath)
+				if err != nil {
+					return nil, fmt.Errorf("clearances file: %w", err)
+				}
+
+				locations := problem.GetLocations()
+				for symbol := range facilityClearances {
+					if _, ok := locations[symbol]; !ok {
+						return nil, fmt.Errorf("clearances file: unknown facility %s", symbol)
+					}
+				}
+				for _, pair := range pairClearances {
+					for _, symbol := range []string{pair.First, pair.Second} {
+						if _, ok := locations[symbol]; !ok {
+							return nil, fmt.Errorf("clearances file: unknown facility %s", symbol)
+						}
+					}
+				}
+			}
+
+			con.SetClearances(config.Clearance, facilityClearances, pairClearances)
+			con.ClearanceFilePath = config.ClearanceFilePath
+
+			return con, nil
+		},
+		Info: func(con *OverlapConstraint) any {
+			return struct {
+				AlphaOverlapPenalty float64            `json:"alphaOverlapPenalty"`
+				PowerOverlapPenalty float64            `json:"powerOverlapPenalty"`
+				Phases              [][]string         `json:"phases"`
+				Clearance           float64            `json:"clearance"`
+				FacilityClearances  map[string]float64 `json:"facilityClearances"`
+				PairClearances      []PairClearance    `json:"pairClearances"`
+				ClearanceFilePath   string             `json:"clearanceFilePath"`
+			}{
+				AlphaOverlapPenalty: con.AlphaOverlapPenalty,
+				PowerOverlapPenalty: con.PowerOverlapPenalty,
+				Phases:              con.Phases,
+				Clearance:           con.Clearance,
+				FacilityClearances:  con.FacilityClearances,
+				PairClearances:      con.PairClearances,
+				ClearanceFilePath:   con.ClearanceFilePath,
+			}
+		},
+		Summary: func(con *OverlapConstraint, w SummaryWriter) {
+			w.Value("Alpha (for penalty)", con.AlphaOverlapPenalty)
+			w.Value("Power difference (for penalty)", con.PowerOverlapPenalty)
+			w.Value("Clearance", con.Clearance)
+
+			if con.ClearanceFilePath == "" {
+				return
+			}
+
+			w.Value("Clearances file", con.ClearanceFilePath)
+
+			if len(con.FacilityClearances) > 0 {
+				w.Group("Facility clearances")
+				symbols := slices.Collect(maps.Keys(con.FacilityClearances))
+				slices.SortFunc(symbols, func(a, b string) int {
+					return util.ExtractNumber(a) - util.ExtractNumber(b)
+				})
+				for _, symbol := range symbols {
+					w.Value(symbol, con.FacilityClearances[symbol])
+				}
+			}
+
+			if len(con.PairClearances) > 0 {
+				w.Group("Pair clearances")
+				for _, pair := range con.PairClearances {
+					w.Value(pair.First+" - "+pair.Second, pair.Clearance)
+				}
+			}
+		},
+	})
+}
+
 func (c OverlapConstraint) GetName() string {
 	return string(c.Name)
 }
@@ -148,26 +371,54 @@ func (c OverlapConstraint) GetPowerPenalty() float64 {
 	return c.PowerOverlapPenalty
 }
 
-func (c OverlapConstraint) Eval(mapLocations map[string]data.Location) float64 {
+func (c OverlapConstraint) Eval(mapLocations map[string]data.Location) (float64, error) {
+	return c.EvalPhases(data.SamePhaseLocations(mapLocations, len(c.Phases)))
+}
+
+// EvalPhases evaluates every phase with its own locations.
+func (c OverlapConstraint) EvalPhases(phaseLocations []map[string]data.Location) (float64, error) {
 	amount := 0.0
 
-	for _, phase := range c.Phases {
+	for phaseIdx, phase := range c.Phases {
+		mapLocations := phaseLocations[phaseIdx]
 		numberOfLocations := len(phase)
 		for i := 0; i < numberOfLocations-1; i++ {
 			for j := i + 1; j < numberOfLocations; j++ {
-				_, val := IsOverlapped(mapLocations[phase[i]], mapLocations[phase[j]])
+				clearance := c.ClearanceBetween(phase[i], phase[j])
+				_, val := IsOverlappedWithClearance(mapLocations[phase[i]], mapLocations[phase[j]], clearance)
 				amount += val
 			}
 		}
 	}
 
-	return amount
+	return amount, nil
 }
 
 func IsOverlapped(b1, b2 data.Location) (bool, float64) {
+	return IsOverlappedWithClearance(b1, b2, 0)
+}
+
+// IsOverlappedWithClearance reports whether b1 and b2 are closer than
+// clearance on both axes, and by how much. Rectangles exactly clearance apart
+// are valid. Freely rotated rectangles are checked on the axes of both with
+// the separating axis theorem, the amount being the sum of the two smallest
+// overlaps, which is the same as on the X and Y axes when neither is rotated.
+func IsOverlappedWithClearance(b1, b2 data.Location, clearance float64) (bool, float64) {
+	if b1.Angle != 0 || b2.Angle != 0 {
+		overlaps := b1.Corners().SeparatingAxisOverlaps(b2.Corners())
+		for i := range overlaps {
+			overlaps[i] += clearance
+			if overlaps[i] <= 0 {
+				return false, 0
+			}
+		}
+
+		slices.Sort(overlaps)
+		return true, overlaps[0] + overlaps[1]
+	}
 
-	l1 := -math.Abs(b1.Coordinate.X-b2.Coordinate.X) + b1.Length/2 + b2.Length/2
-	l2 := -math.Abs(b1.Coordinate.Y-b2.Coordinate.Y) + b1.Width/2 + b2.Width/2
+	l1 := -math.Abs(b1.Coordinate.X-b2.Coordinate.X) + b1.Length/2 + b2.Length/2 + clearance
+	l2 := -math.Abs(b1.Coordinate.Y-b2.Coordinate.Y) + b1.Width/2 + b2.Width/2 + clearance
 
 	if l1 <= 0 {
 		return false, 0
@@ -211,6 +462,68 @@ func CreateOutOfBoundsConstraint(minWidth, maxWidth, minLength, maxLength float6
 	}
 }
 
+type outOfBoundConfig struct {
+	AlphaOutOfBoundaryPenalty float64 `json:"AlphaOutOfBoundaryPenalty"`
+	PowerDifferencePenalty    float64 `json:"PowerDifferencePenalty"`
+}
+
+func init() {
+	Register(ConstraintOutOfBound, Definition[outOfBoundConfig, *OutOfBoundsConstraint]{
+		Schema: ConfigSchema{
+			Label:  "Out Of Boundary",
+			TSName: "OutOfBound",
+			Fields: []Field{
+				{Key: "AlphaOutOfBoundaryPenalty", Label: "Alpha (for penalty)", Type: FieldNumber, Default: 20000.0},
+				{Key: "PowerDifferencePenalty", Label: "Power difference (for penalty)", Type: FieldNumber, Default: 1.0},
+			},
+		},
+		Problems: layoutProblems,
+		Create: func(problem Problem, config outOfBoundConfig) (*OutOfBoundsConstraint, error) {
+			_, maxX, _, maxY, err := problem.GetLayoutSize()
+			if err != nil {
+				return nil, err
+			}
+
+			return CreateOutOfBoundsConstraint(
+				0,
+				maxY,
+				0,
+				maxX,
+				problem.GetPhases(),
+				config.AlphaOutOfBoundaryPenalty,
+				config.PowerDifferencePenalty,
+			), nil
+		},
+		Info: func(con *OutOfBoundsConstraint) any {
+			return struct {
+				MinWidth               float64    `json:"minWidth"`
+				MaxWidth               float64    `json:"maxWidth"`
+				MinLength              float64    `json:"minLength"`
+				MaxLength              float64    `json:"maxLength"`
+				AlphaOutOfBoundPenalty float64    `json:"alphaOutOfBoundPenalty"`
+				PowerOutOfBoundPenalty float64    `json:"powerOutOfBoundPenalty"`
+				Phases                 [][]string `json:"phases"`
+			}{
+				MinWidth:               con.MinWidth,
+				MaxWidth:               con.MaxWidth,
+				MinLength:              con.MinLength,
+				MaxLength:              con.MaxLength,
+				AlphaOutOfBoundPenalty: con.AlphaOutOfBoundsPenalty,
+				PowerOutOfBoundPenalty: con.PowerOutOfBoundsPenalty,
+				Phases:                 con.Phases,
+			}
+		},
+		Summary: func(con *OutOfBoundsConstraint, w SummaryWriter) {
+			w.Value("Min width", con.MinWidth)
+			w.Value("Max width", con.MaxWidth)
+			w.Value("Min length", con.MinLength)
+			w.Value("Max length", con.MaxLength)
+			w.Value("Alpha (for penalty)", con.AlphaOutOfBoundsPenalty)
+			w.Value("Power difference (for penalty)", con.PowerOutOfBoundsPenalty)
+		},
+	})
+}
+
 func (c OutOfBoundsConstraint) GetName() string {
 	return string(c.Name)
 }
@@ -223,7 +536,7 @@ func (c OutOfBoundsConstraint) GetPowerPenalty() float64 {
 	return c.PowerOutOfBoundsPenalty
 }
 
-func (c OutOfBoundsConstraint) Eval(mapLocations map[string]data.Location) float64 {
+func (c OutOfBoundsConstraint) Eval(mapLocations map[string]data.Location) (float64, error) {
 	amount := 0.0
 
 	for _, v := range mapLocations {
@@ -232,15 +545,19 @@ func (c OutOfBoundsConstraint) Eval(mapLocations map[string]data.Location) float
 			amount += val
 		}
 	}
-	return amount
+	return amount, nil
 }
 
+// IsOutOfBound reports whether b leaves the rectangle [minL, maxL] x
+// [minW, maxW], and by how much. A freely rotated b leaves it exactly when the
+// axis-aligned rectangle around it does.
 func IsOutOfBound(minL, maxL, minW, maxW float64, b data.Location) (bool, float64) {
+	halfX, halfY := b.HalfExtents()
 
-	l1 := minL + b.Length/2 - b.Coordinate.X
-	l2 := b.Coordinate.X + b.Length/2 - maxL
-	l3 := minW + b.Width/2 - b.Coordinate.Y
-	l4 := b.Coordinate.Y + b.Width/2 - maxW
+	l1 := minL + halfX - b.Coordinate.X
+	l2 := b.Coordinate.X + halfX - maxL
+	l3 := minW + halfY - b.Coordinate.Y
+	l4 := b.Coordinate.Y + halfY - maxW
 
 	if l1 <= 0 && l2 <= 0 && l3 <= 0 && l4 <= 0 {
 		return false, 0
@@ -280,6 +597,81 @@ func CreateInclusiveZoneConstraint(
 	}
 }
 
+type inclusiveZoneConfig struct {
+	AlphaInclusiveZonePenalty float64 `json:"AlphaInclusiveZonePenalty"`
+	PowerDifferencePenalty    float64 `json:"PowerDifferencePenalty"`
+	Zones                     []struct {
+		Name          string  `json:"Name"`
+		BuildingNames string  `json:"BuildingNames"`
+		Size          float64 `json:"Size"`
+	} `json:"Zones"`
+}
+
+func init() {
+	Register(ConstraintInclusiveZone, Definition[inclusiveZoneConfig, *InclusiveZoneConstraint]{
+		Schema: ConfigSchema{
+			Label:  "Inclusive Zone",
+			TSName: "InclusiveZone",
+			Fields: []Field{
+				{Key: "AlphaInclusiveZonePenalty", Label: "Alpha (for penalty)", Type: FieldNumber, Default: 20000.0},
+				{Key: "PowerDifferencePenalty", Label: "Power difference (for penalty)", Type: FieldNumber, Default: 1.0},
+				{Key: "Zones", Label: "Zones", Type: FieldList, Fields: []Field{
+					{Key: "Name", Label: "Facility", Type: FieldString},
+					{Key: "BuildingNames", Label: "Facilities", Type: FieldNames},
+					{Key: "Size", Label: "Size", Type: FieldNumber},
+				}},
+			},
+		},
+		Problems: layoutProblems,
+		Create: func(problem Problem, config inclusiveZoneConfig) (*InclusiveZoneConstraint, error) {
+			zones := make([]Zone, len(config.Zones))
+
+			for i, zone := range config.Zones {
+				facilitiesName, err := formatBuildingNames(zone.BuildingNames)
+				if err != nil {
+					return nil, err
+				}
+
+				zones[i] = Zone{
+					Location:      problem.GetLocations()[zone.Name],
+					BuildingNames: facilitiesName,
+					Size:          zone.Size,
+				}
+			}
+
+			return CreateInclusiveZoneConstraint(
+				zones,
+				problem.GetPhases(),
+				config.AlphaInclusiveZonePenalty,
+				config.PowerDifferencePenalty,
+			), nil
+		},
+		Info: func(con *InclusiveZoneConstraint) any {
+			return struct {
+				AlphaInclusivePenalty float64    `json:"alphaInclusivePenalty"`
+				PowerInclusivePenalty float64    `json:"powerInclusivePenalty"`
+				Phases                [][]string `json:"phases"`
+				Zones                 []Zone     `json:"zones"`
+			}{
+				AlphaInclusivePenalty: con.AlphaInclusiveZonePenalty,
+				PowerInclusivePenalty: con.PowerInclusiveZonePenalty,
+				Phases:                con.Phases,
+				Zones:                 con.Zones,
+			}
+		},
+		Summary: func(con *InclusiveZoneConstraint, w SummaryWriter) {
+			w.Value("Alpha (for penalty)", con.AlphaInclusiveZonePenalty)
+			w.Value("Power difference (for penalty)", con.PowerInclusiveZonePenalty)
+
+			for _, zone := range con.Zones {
+				w.Group(zone.Symbol)
+				w.Value("Facilities", strings.Join(zone.BuildingNames, " "))
+				w.Value("Size", zone.Size)
+			}
+		},
+	})
+}
+
 func (c InclusiveZoneConstraint) GetName() string {
 	return string(c.Name)
 }
@@ -292,7 +684,7 @@ func (c InclusiveZoneConstraint) GetPowerPenalty() float64 {
 	return c.PowerInclusiveZonePenalty
 }
 
-func (c InclusiveZoneConstraint) Eval(mapLocations map[string]data.Location) float64 {
+func (c InclusiveZoneConstraint) Eval(mapLocations map[string]data.Location) (float64, error) {
 	amount := 0.0
 	for _, zone := range c.Zones {
 		minL := zone.Coordinate.X - zone.Length/2 - zone.Size
@@ -301,9 +693,282 @@ func (c InclusiveZoneConstraint) Eval(mapLocations map[string]data.Location) flo
 		maxW := zone.Coordinate.Y + zone.Width/2 + zone.Size
 
 		for _, building := range zone.BuildingNames {
-			_, val := IsOutOfBound(minL, maxL, minW, maxW, mapLocations[building])
+			// a building idle in a phase of a dynamic layout is not in it
+			loc, ok := mapLocations[building]
+			if !ok {
+				continue
+			}
+
+			_, val := IsOutOfBound(minL, maxL, minW, maxW, loc)
 			amount += val
 		}
 	}
-	return amount
+	return amount, nil
+}
+
+// Exclusive Zone
+
+type ExclusiveZone struct {
+	// Symbol is the facility the zone surrounds, fixed or not
+	Symbol        string
+	BuildingNames []string
+	Size          float64
+	// Phases are the indexes of the phases the zone applies to, every phase
+	// when empty
+	Phases []int
+}
+
+type ExclusiveZoneConstraint struct {
+	Zones                     []ExclusiveZone
+	Phases                    [][]string
+	Name                      data.ConstraintType
+	AlphaExclusiveZonePenalty float64
+	PowerExclusiveZonePenalty float64
+}
+
+func CreateExclusiveZoneConstraint(
+	zones []ExclusiveZone,
+	phases [][]string,
+	alphaExclusiveZonePenalty float64,
+	powerExclusiveZonePenalty float64,
+) *ExclusiveZoneConstraint {
+	return &ExclusiveZoneConstraint{
+		Zones:                     zones,
+		Phases:                    phases,
+		Name:                      ConstraintExclusiveZone,
+		AlphaExclusiveZonePenalty: alphaExclusiveZonePenalty,
+		PowerExclusiveZonePenalty: powerExclusiveZonePenalty,
+	}
+}
+
+type exclusiveZoneConfig struct {
+	AlphaExclusiveZonePenalty float64 `json:"AlphaExclusiveZonePenalty"`
+	PowerDifferencePenalty    float64 `json:"PowerDifferencePenalty"`
+	Zones                     []struct {
+		Name          string  `json:"Name"`
+		BuildingNames string  `json:"BuildingNames"`
+		Size          float64 `json:"Size"`
+		Phases        string  `json:"Phases"`
+	} `json:"Zones"`
+}
+
+func init() {
+	Register(ConstraintExclusiveZone, Definition[exclusiveZoneConfig, *ExclusiveZoneConstraint]{
+		Schema: ConfigSchema{
+			Label:  "Exclusive Zone",
+			TSName: "ExclusiveZone",
+			Fields: []Field{
+				{Key: "AlphaExclusiveZonePenalty", Label: "Alpha (for penalty)", Type: FieldNumber, Default: 20000.0},
+				{Key: "PowerDifferencePenalty", Label: "Power difference (for penalty)", Type: FieldNumber, Default: 1.0},
+				{Key: "Zones", Label: "Zones", Type: FieldList, Fields: []Field{
+					{Key: "Name", Label: "Facility", Type: FieldString},
+					{Key: "BuildingNames", Label: "Facilities", Type: FieldNames},
+					{Key: "Size", Label: "Size", Type: FieldNumber},
+					{Key: "Phases", Label: "Phases", Type: FieldString},
+				}},
+			},
+		},
+		Problems: layoutProblems,
+		Create: func(problem Problem, config exclusiveZoneConfig) (*ExclusiveZoneConstraint, error) {
+			phases := problem.GetPhases()
+			zones := make([]ExclusiveZone, len(config.Zones))
+
+			for i, zone := range config.Zones {
+				symbol := strings.ToUpper(strings.TrimSpace(zone.Name))
+				if _, ok := problem.GetLocations()[symbol]; !ok {
+					return nil, fmt.Errorf("zone %d: unknown facility %q", i+1, zone.Name)
+				}
+
+				facilitiesName, err := formatBuildingNames(zone.BuildingNames)
+				if err != nil {
+					return nil, err
+				}
+
+				if zone.Size < 0 {
+					return nil, fmt.Errorf("zone %s: the size must not be negative", symbol)
+				}
+
+				zonePhases, err := formatPhases(zone.Phases, len(phases))
+				if err != nil {
+					return nil, fmt.Errorf("zone %s: %w", symbol, err)
+				}
+
+				zones[i] = ExclusiveZone{
+					Symbol:        symbol,
+					BuildingNames: facilitiesName,
+					Size:          zone.Size,
+					Phases:        zonePhases,
+				}
+			}
+
+			return CreateExclusiveZoneConstraint(
+				zones,
+				phases,
+				config.AlphaExclusiveZonePenalty,
+				config.PowerDifferencePenalty,
+			), nil
+		},
+		Info: func(con *ExclusiveZoneConstraint) any {
+			return struct {
+				AlphaExclusivePenalty float64         `json:"alphaExclusivePenalty"`
+				PowerExclusivePenalty float64         `json:"powerExclusivePenalty"`
+				Phases                [][]string      `json:"phases"`
+				Zones                 []ExclusiveZone `json:"zones"`
+			}{
+				AlphaExclusivePenalty: con.AlphaExclusiveZonePenalty,
+				PowerExclusivePenalty: con.PowerExclusiveZonePenalty,
+				Phases:                con.Phases,
+				Zones:                 con.Zones,
+			}
+		},
+		Summary: func(con *ExclusiveZoneConstraint, w SummaryWriter) {
+			w.Value("Alpha (for penalty)", con.AlphaExclusiveZonePenalty)
+			w.Value("Power difference (for penalty)", con.PowerExclusiveZonePenalty)
+
+			for _, zone := range con.Zones {
+				w.Group(zone.Symbol)
+				w.Value("Facilities", strings.Join(zone.BuildingNames, " "))
+				w.Value("Size", zone.Size)
+				w.Value("Phases", phasesLabel(zone.Phases))
+			}
+		},
+	})
+}
+
+func (c ExclusiveZoneConstraint) GetName() string {
+	return string(c.Name)
+}
+
+func (c ExclusiveZoneConstraint) GetAlphaPenalty() float64 {
+	return c.AlphaExclusiveZonePenalty
+}
+
+func (c ExclusiveZoneConstraint) GetPowerPenalty() float64 {
+	return c.PowerExclusiveZonePenalty
+}
+
+// Eval sums, over the phases a zone applies to, the penetration depth of the
+// facilities of the zone present in the phase into the zone.
+func (c ExclusiveZoneConstraint) Eval(mapLocations map[string]data.Location) (float64, error) {
+	return c.EvalPhases(data.SamePhaseLocations(mapLocations, len(c.Phases)))
+}
+
+// EvalPhases evaluates every phase with its own locations.
+func (c ExclusiveZoneConstraint) EvalPhases(phaseLocations []map[string]data.Location) (float64, error) {
+	amount := 0.0
+	for _, zone := range c.Zones {
+		for phaseIdx, phase := range c.Phases {
+			if len(zone.Phases) > 0 && !slices.Contains(zone.Phases, phaseIdx) {
+				continue
+			}
+
+			mapLocations := phaseLocations[phaseIdx]
+
+			if !slices.Contains(phase, zone.Symbol) {
+				continue
+			}
+
+			center := mapLocations[zone.Symbol]
+			for _, building := range zone.BuildingNames {
+				if !slices.Contains(phase, building) {
+					continue
+				}
+
+				_, val := IsInExclusiveZone(center, zone.Size, mapLocations[building])
+				amount += val
+			}
+		}
+	}
+	return amount, nil
+}
+
+// IsInExclusiveZone reports whether b enters the rectangle of center grown
+// by size on every side, and how deep: the shortest distance b has to move
+// to leave it. Freely rotated facilities count with the axis-aligned
+// rectangles around them.
+func IsInExclusiveZone(center data.Location, size float64, b data.Location) (bool, float64) {
+	centerHalfX, centerHalfY := center.HalfExtents()
+	halfX, halfY := b.HalfExtents()
+
+	l1 := -math.Abs(center.Coordinate.X-b.Coordinate.X) + centerHalfX + size + halfX
+	l2 := -math.Abs(center.Coordinate.Y-b.Coordinate.Y) + centerHalfY + size + halfY
+
+	if l1 <= 0 || l2 <= 0 {
+		return false, 0
+	}
+
+	return true, math.Min(l1, l2)
+}
+
+// formatBuildingNames formats and validates building names according to the required format
+// It accepts a string with building names separated by spaces, and returns a slice of valid building names and an error if any invalid names are found
+// Valid building names are in the format "TF1", "TF2", etc. or "tf1", "tf2", etc.
+func formatBuildingNames(buildingNamesStr string) ([]string, error) {
+	// Trim spaces and replace multiple spaces with a single space
+	re := regexp.MustCompile(`\s+`)
+	trimmed := re.ReplaceAllString(strings.TrimSpace(buildingNamesStr), " ")
+
+	// Split by space
+	parts := strings.Split(trimmed, " ")
+
+	// Filter out empty strings and validate format
+	var result []string
+	var invalidNames []string
+
+	for _, part := range parts {
+		if part == "" {
+			continue
+		}
+
+		// Validate format: must be "TF" or "tf" followed by numbers
+		if util.IsTFNumber(part) {
+			// Convert to uppercase for consistency
+			result = append(result, strings.ToUpper(part))
+		} else {
+			invalidNames = append(invalidNames, part)
+		}
+	}
+
+	// Return error if any invalid names were found
+	if len(invalidNames) > 0 {
+		return result, fmt.Errorf("invalid found: %s", strings.Join(invalidNames, ", "))
+	}
+
+	return result, nil
+}
+
+// formatPhases parses phases counted from 1 and separated by spaces or commas
+// into phase indexes. An empty string is every phase.
+func formatPhases(phasesStr string, numberOfPhases int) ([]int, error) {
+	fields := strings.FieldsFunc(phasesStr, func(r rune) bool {
+		return r == ',' || unicode.IsSpace(r)
+	})
+
+	phases := make([]int, 0, len(fields))
+	for _, field := range fields {
+		phase, err := strconv.Atoi(field)
+		if err != nil || phase < 1 || phase > numberOfPhases {
+			return nil, fmt.Errorf("invalid phase %q, expected 1 to %d", field, numberOfPhases)
+		}
+
+		if !slices.Contains(phases, phase-1) {
+			phases = append(phases, phase-1)
+		}
+	}
+
+	return phases, nil
+}
+
+// phasesLabel lists phase indexes counted from 1 for the summary.
+func phasesLabel(phases []int) string {
+	if len(phases) == 0 {
+		return "All"
+	}
+
+	names := make([]string, len(phases))
+	for i, phaseIdx := range phases {
+		names[i] = strconv.Itoa(phaseIdx + 1)
+	}
+
+	return strings.Join(names, ", ")
 }
@@ -870,7 +870,10 @@ func TestOutOfBoundsConstraint_Eval_Feasible(t *testing.T) {
 
 	locations := CreateInputLocation(true)
 
-	penalty := outOfBoundsConstraint.Eval(locations)
+	penalty, err := outOfBoundsConstraint.Eval(locations)
+	if err != nil {
+		t.Fatal(err)
+	}
 	if math.Round(penalty) != 0 {
 		t.Errorf("expected penalty to be 0, got %f", penalty)
 	}
@@ -886,7 +889,10 @@ func TestOverlapConstraint_Eval_Feasible(t *testing.T) {
 
 	locations := CreateInputLocation(true)
 
-	penalty := overlapConstraint.Eval(locations)
+	penalty, err := overlapConstraint.Eval(locations)
+	if err != nil {
+		t.Fatal(err)
+	}
 	if util.RoundTo(penalty, 2) != 26.68 {
 		t.Errorf("expected penalty to be 26.68, got %f", penalty)
 	}
@@ -907,7 +913,10 @@ func TestCoverRangeCraneConstraint_Eval_Feasible(t *testing.T) {
 		1,
 	)
 
-	penalty := coverRangeConstraint.Eval(locations)
+	penalty, err := coverRangeConstraint.Eval(locations)
+	if err != nil {
+		t.Fatal(err)
+	}
 	if math.Round(penalty) != 0 {
 		t.Errorf("expected penalty to be 0, got %f", penalty)
 	}
@@ -933,7 +942,10 @@ func TestInclusiveZoneConstraint_Eval_Feasible(t *testing.T) {
 		1,
 	)
 
-	penalty := zoneConstraint.Eval(locations)
+	penalty, err := zoneConstraint.Eval(locations)
+	if err != nil {
+		t.Fatal(err)
+	}
 	if math.Round(penalty) != 0 {
 		t.Errorf("expected penalty to be 0, got %f", penalty)
 	}
@@ -953,7 +965,10 @@ func TestOutOfBoundsConstraint_Eval_Infeasible(t *testing.T) {
 
 	locations := CreateInputLocation(false)
 
-	penalty := outOfBoundsConstraint.Eval(locations)
+	penalty, err := outOfBoundsConstraint.Eval(locations)
+	if err != nil {
+		t.Fatal(err)
+	}
 	if util.RoundTo(penalty, 2) != 0.48 {
 		t.Errorf("expected penalty to be 0.48, got %f", penalty)
 	}
@@ -969,7 +984,10 @@ func TestOverlapConstraint_Eval_Infeasible(t *testing.T) {
 
 	locations := CreateInputLocation(false)
 
-	penalty := overlapConstraint.Eval(locations)
+	penalty, err := overlapConstraint.Eval(locations)
+	if err != nil {
+		t.Fatal(err)
+	}
 	if util.RoundTo(penalty, 2) != 85.37 {
 		t.Errorf("expected penalty to be 85.37, got %f", penalty)
 	}
@@ -990,7 +1008,10 @@ func TestCoverRangeCraneConstraint_Eval_Infeasible(t *testing.T) {
 		1,
 	)
 
-	penalty := coverRangeConstraint.Eval(locations)
+	penalty, err := coverRangeConstraint.Eval(locations)
+	if err != nil {
+		t.Fatal(err)
+	}
 	if util.RoundTo(penalty, 2) != 208.81 {
 		t.Errorf("expected penalty to be 208.81, got %f", penalty)
 	}
@@ -1016,8 +1037,137 @@ func TestInclusiveZoneConstraint_Eval_Infeasible(t *testing.T) {
 		1,
 	)
 
-	penalty := zoneConstraint.Eval(locations)
+	penalty, err := zoneConstraint.Eval(locations)
+	if err != nil {
+		t.Fatal(err)
+	}
 	if util.RoundTo(penalty, 2) != 27.65 {
 		t.Errorf("expected penalty to be 27.65, got %f", penalty)
 	}
 }
+
+func TestIsOutOfSite(t *testing.T) {
+	site := data.Site{
+		Boundary: data.Polygon{{X: 0, Y: 0}, {X: 20, Y: 0}, {X: 20, Y: 10}, {X: 0, Y: 10}},
+		Obstacles: []data.Obstacle{
+			{Name: "Pit", Polygon: data.Polygon{{X: 10, Y: 0}, {X: 12, Y: 0}, {X: 12, Y: 2}, {X: 10, Y: 2}}, Phases: []int{1}},
+		},
+	}
+
+	// 2 x 4 facility sticking out of the right edge by 1, over the pit
+	b := data.Location{Coordinate: data.Coordinate{X: 11, Y: 2}, Length: 2, Width: 4}
+	if amount := IsOutOfSite(site, 0, b); amount != 0 {
+		t.Errorf("expected no violation without the pit, got %f", amount)
+	}
+	if amount := IsOutOfSite(site, 1, b); math.Abs(amount-4) > 1e-9 {
+		t.Errorf("expected the pit area 4, got %f", amount)
+	}
+
+	b.Coordinate = data.Coordinate{X: 20, Y: 5}
+	if amount := IsOutOfSite(site, 0, b); math.Abs(amount-4) > 1e-9 {
+		t.Errorf("expected half of the facility outside, got %f", amount)
+	}
+}
+
+func TestExclusiveZoneConstraint(t *testing.T) {
+	office := data.Location{Symbol: "TF1", Coordinate: data.Coordinate{X: 10, Y: 10}, Length: 4, Width: 4, IsFixed: true}
+	fuel := data.Location{Symbol: "TF2", Coordinate: data.Coordinate{X: 17, Y: 11}, Length: 2, Width: 2}
+
+	// the zone reaches x = 17 and the fuel storage starts at x = 16
+	inside, depth := IsInExclusiveZone(office, 5, fuel)
+	if !inside || depth != 1 {
+		t.Errorf("expected a penetration depth of 1, got %v %f", inside, depth)
+	}
+
+	con := CreateExclusiveZoneConstraint(
+		[]ExclusiveZone{{Symbol: "TF1", BuildingNames: []string{"TF2"}, Size: 5, Phases: []int{1}}},
+		[][]string{{"TF1", "TF2"}, {"TF1", "TF2"}, {"TF1"}},
+		1, 1,
+	)
+
+	amount, err := con.Eval(map[string]data.Location{"TF1": office, "TF2": fuel})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if amount != 1 {
+		t.Errorf("expected the violation of the second phase only, got %f", amount)
+	}
+
+	phases, err := formatPhases(" 2, 3 2", 3)
+	if err != nil || len(phases) != 2 || phases[0] != 1 || phases[1] != 2 {
+		t.Errorf("unexpected phases %v %v", phases, err)
+	}
+	if _, err = formatPhases("4", 3); err == nil {
+		t.Error("expected an error for a missing phase")
+	}
+}
+
+func TestOverlapConstraintClearance(t *testing.T) {
+	con := CreateOverlapConstraint([][]string{{"TF1", "TF2", "TF3"}}, 1, 1)
+	con.SetClearances(
+		1,
+		map[string]float64{"TF3": 6},
+		[]PairClearance{{First: "TF2", Second: "TF1", Clearance: 3}},
+	)
+
+	if clearance := con.ClearanceBetween("TF1", "TF2"); clearance != 3 {
+		t.Errorf("expected the pair clearance 3, got %f", clearance)
+	}
+	if clearance := con.ClearanceBetween("TF1", "TF3"); clearance != 6 {
+		t.Errorf("expected the facility clearance 6, got %f", clearance)
+	}
+
+	locations := map[string]data.Location{
+		// 2 apart on both axes, 1 short of the pair clearance
+		"TF1": {Coordinate: data.Coordinate{X: 0, Y: 0}, Length: 4, Width: 4},
+		"TF2": {Coordinate: data.Coordinate{X: 6, Y: 6}, Length: 4, Width: 4},
+		// 5 apart from TF1, 1 short of its facility clearance
+		"TF3": {Coordinate: data.Coordinate{X: -9, Y: 0}, Length: 4, Width: 4},
+	}
+
+	amount, err := con.Eval(locations)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// TF1 - TF2 by 1 and 1, TF1 - TF3 by 1 and 10
+	if math.Abs(amount-13) > 1e-9 {
+		t.Errorf("expected a total of 13, got %f", amount)
+	}
+
+	if overlapped, _ := IsOverlappedWithClearance(locations["TF1"], locations["TF2"], 2); overlapped {
+		t.Errorf("expected facilities exactly the clearance apart to be valid")
+	}
+}
+
+func TestOrientedConstraints(t *testing.T) {
+	// a 2 x 2 square turned by 45 degrees, |x| + |y| <= sqrt(2)
+	diamond := data.Location{Coordinate: data.Coordinate{X: 0, Y: 0}, Length: 2, Width: 2, Angle: 45}
+
+	// inside the bounding box of the diamond but apart on a diagonal axis
+	apart := data.Location{Coordinate: data.Coordinate{X: 2, Y: 1.5}, Length: 2, Width: 2}
+	if overlapped, _ := IsOverlapped(diamond, apart); overlapped {
+		t.Errorf("expected the diamond and %+v apart", apart.Coordinate)
+	}
+
+	// overlaps by 0.41 on X, 2 on Y and 1 on both diagonals
+	touching := data.Location{Coordinate: data.Coordinate{X: 2, Y: 0}, Length: 2, Width: 2}
+	overlapped, amount := IsOverlapped(diamond, touching)
+	if !overlapped || math.Abs(amount-math.Sqrt2) > 1e-9 {
+		t.Errorf("expected an overlap of %f, got %t %f", math.Sqrt2, overlapped, amount)
+	}
+
+	diamond.Coordinate = data.Coordinate{X: 1, Y: 1}
+	_, amount = IsOutOfBound(0, 10, 0, 10, diamond)
+	if math.Abs(amount-2*(math.Sqrt2-1)) > 1e-9 {
+		t.Errorf("expected the diamond out of bound by %f, got %f", 2*(math.Sqrt2-1), amount)
+	}
+
+	// a 4 x 1 facility turned upright, its corners at (+-0.5, +-2)
+	upright := data.Location{Coordinate: data.Coordinate{X: 0, Y: 0}, Length: 4, Width: 1, Angle: 90}
+	crane := data.Crane{Location: data.Location{Coordinate: data.Coordinate{X: 0, Y: 1.5}}, Radius: 1}
+	_, amount = IsCoverRangeOfCrane(crane, []data.Location{upright})
+	expected := 2 * (math.Hypot(0.5, 3.5) - 1)
+	if math.Abs(amount-expected) > 1e-9 {
+		t.Errorf("expected the lower corners %f outside the radius, got %f", expected, amount)
+	}
+}
@@ -0,0 +1,436 @@
+package constraints
+
+import (
+	"fmt"
+	"github.com/xuri/excelize/v2"
+	"golang-moaha-construction/internal/data"
+	"golang-moaha-construction/internal/util"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+const ConstraintDistance data.ConstraintType = "Distance"
+
+// DistanceMeasure is how the distance between two facilities is measured.
+type DistanceMeasure string
+
+const (
+	// CentreDistance is the distance between the centres of the facilities
+	CentreDistance DistanceMeasure = "centre"
+	// EdgeDistance is the shortest distance between the edges of the
+	// facilities, 0 when they touch or overlap
+	EdgeDistance DistanceMeasure = "edge"
+)
+
+func parseDistanceMeasure(measure string) (DistanceMeasure, error) {
+	switch strings.ToLower(strings.TrimSpace(measure)) {
+	case "centre", "center":
+		return CentreDistance, nil
+	case "edge":
+		return EdgeDistance, nil
+	default:
+		return "", fmt.Errorf("invalid distance measure %q, expected centre or edge", measure)
+	}
+}
+
+// Distance between facilities
+
+// DistanceRule keeps two facilities at least Min and at most Max apart. A
+// zero Min or Max is no limit.
+type DistanceRule struct {
+	First   string
+	Second  string
+	Min     float64
+	Max     float64
+	Measure DistanceMeasure
+}
+
+// Distance measures the distance between a and b with the measure of the
+// rule.
+func (r DistanceRule) Distance(a, b data.Location) float64 {
+	if r.Measure == EdgeDistance {
+		halfXA, halfYA := a.HalfExtents()
+		halfXB, halfYB := b.HalfExtents()
+		dx := math.Max(0, math.Abs(a.Coordinate.X-b.Coordinate.X)-(halfXA+halfXB))
+		dy := math.Max(0, math.Abs(a.Coordinate.Y-b.Coordinate.Y)-(halfYA+halfYB))
+		return math.Hypot(dx, dy)
+	}
+
+	return data.Distance2D(a.Coordinate, b.Coordinate)
+}
+
+// Violation is how far distance is below Min or above Max.
+func (r DistanceRule) Violation(distance float64) float64 {
+	amount := 0.0
+	if r.Min > 0 && distance < r.Min {
+		amount += r.Min - distance
+	}
+	if r.Max > 0 && distance > r.Max {
+		amount += distance - r.Max
+	}
+
+	return amount
+}
+
+func (r DistanceRule) String() string {
+	limits := make([]string, 0, 2)
+	if r.Min > 0 {
+		limits = append(limits, fmt.Sprintf(">= %g", r.Min))
+	}
+	if r.Max > 0 {
+		limits = append(limits, fmt.Sprintf("<= %g", r.Max))
+	}
+
+	return fmt.Sprintf("%s %s", r.Measure, strings.Join(limits, ", "))
+}
+
+// DistanceConstraint keeps pairs of facilities within a minimum and a maximum
+// distance in every phase both facilities are in.
+type DistanceConstraint struct {
+	Rules                []DistanceRule
+	RulesFilePath        string
+	Phases               [][]string
+	Name                 data.ConstraintType
+	AlphaDistancePenalty float64
+	PowerDistancePenalty float64
+}
+
+func CreateDistanceConstraint(
+	rules []DistanceRule,
+	phases [][]string,
+	alphaDistancePenalty float64,
+	powerDistancePenalty float64,
+) *DistanceConstraint {
+	return &DistanceConstraint{
+		Rules:                rules,
+		Phases:               phases,
+		Name:                 ConstraintDistance,
+		AlphaDistancePenalty: alphaDistancePenalty,
+		PowerDistancePenalty: powerDistancePenalty,
+	}
+}
+
+type distanceConfig struct {
+	AlphaDistancePenalty   float64 `json:"AlphaDistancePenalty"`
+	PowerDifferencePenalty float64 `json:"PowerDifferencePenalty"`
+	RulesFilePath          string  `json:"RulesFilePath"`
+	Measure                string  `json:"Measure"`
+}
+
+func init() {
+	Register(ConstraintDistance, Definition[distanceConfig, *DistanceConstraint]{
+		Schema: ConfigSchema{
+			Label:  "Distance",
+			TSName: "Distance",
+			Fields: []Field{
+				{Key: "AlphaDistancePenalty", Label: "Alpha (for penalty)", Type: FieldNumber, Default: 20000.0},
+				{Key: "PowerDifferencePenalty", Label: "Power difference (for penalty)", Type: FieldNumber, Default: 1.0},
+				{Key: "RulesFilePath", Label: "Distance rules file", Type: FieldString},
+				{Key: "Measure", Label: "Default measure", Type: FieldString, Default: string(CentreDistance)},
+			},
+		},
+		Problems: layoutProblems,
+		Create: func(problem Problem, config distanceConfig) (*DistanceConstraint, error) {
+			measure, err := parseDistanceMeasure(config.Measure)
+			if err != nil {
+				return nil, err
+			}
+
+			rules, err := ReadDistanceRulesFromFile(config.RulesFilePath, measure)
+			if err != nil {
+				return nil, fmt.Errorf("distance rules file: %w", err)
+			}
+
+			locations := problem.GetLocations()
+			for _, rule := range rules {
+				for _, symbol := range []string{rule.First, rule.Second} {
+					if _, ok := locations[symbol]; !ok {
+						return nil, fmt.Errorf("distance rules file: unknown facility %s", symbol)
+					}
+				}
+			}
+
+			con := CreateDistanceConstraint(
+				rules,
+				problem.GetPhases(),
+				config.AlphaDistancePenalty,
+				config.PowerDifferencePenalty,
+			)
+			con.RulesFilePath = config.RulesFilePath
+
+			return con, nil
+		},
+		Info: func(con *DistanceConstraint) any {
+			return struct {
+				AlphaDistancePenalty float64        `json:"alphaDistancePenalty"`
+				PowerDistancePenalty float64        `json:"powerDistancePenalty"`
+				RulesFilePath        string         `json:"rulesFilePath"`
+				Phases               [][]string     `json:"phases"`
+				Rules                []DistanceRule `json:"rules"`
+			}{
+				AlphaDistancePenalty: con.AlphaDistancePenalty,
+				PowerDistancePenalty: con.PowerDistancePenalty,
+				RulesFilePath:        con.RulesFilePath,
+				Phases:               con.Phases,
+				Rules:                con.Rules,
+			}
+		},
+		Summary: func(con *DistanceConstraint, w SummaryWriter) {
+			w.Value("Alpha (for penalty)", con.AlphaDistancePenalty)
+			w.Value("Power difference (for penalty)", con.PowerDistancePenalty)
+			w.Value("Distance rules file", con.RulesFilePath)
+
+			w.Group("Rules")
+			for _, rule := range con.Rules {
+				w.Value(rule.First+" - "+rule.Second, rule.String())
+			}
+		},
+	})
+}
+
+func (c DistanceConstraint) GetName() string {
+	return string(c.Name)
+}
+
+func (c DistanceConstraint) GetAlphaPenalty() float64 {
+	return c.AlphaDistancePenalty
+}
+
+func (c DistanceConstraint) GetPowerPenalty() float64 {
+	return c.PowerDistancePenalty
+}
+
+func (c DistanceConstraint) Eval(mapLocations map[string]data.Location) (float64, error) {
+	return c.EvalPhases(data.SamePhaseLocations(mapLocations, len(c.Phases)))
+}
+
+// EvalPhases evaluates every phase with its own locations.
+func (c DistanceConstraint) EvalPhases(phaseLocations []map[string]data.Location) (float64, error) {
+	amount := 0.0
+	for _, violation := range c.PhasePairViolations(phaseLocations) {
+		amount += violation.Amount
+	}
+
+	return amount, nil
+}
+
+// PairViolations returns the rules violated in every phase both facilities
+// of the rule are in.
+func (c DistanceConstraint) PairViolations(mapLocations map[string]data.Location) []data.PairViolation {
+	return c.PhasePairViolations(data.SamePhaseLocations(mapLocations, len(c.Phases)))
+}
+
+// PhasePairViolations is PairViolations with the locations of every phase.
+func (c DistanceConstraint) PhasePairViolations(phaseLocations []map[string]data.Location) []data.PairViolation {
+	violations := make([]data.PairViolation, 0)
+
+	for phaseIdx, phase := range c.Phases {
+		mapLocations := phaseLocations[phaseIdx]
+		for _, rule := range c.Rules {
+			if !slices.Contains(phase, rule.First) || !slices.Contains(phase, rule.Second) {
+				continue
+			}
+
+			distance := rule.Distance(mapLocations[rule.First], mapLocations[rule.Second])
+			amount := rule.Violation(distance)
+			if amount == 0 {
+				continue
+			}
+
+			violations = append(violations, data.PairViolation{
+				Phase:  phaseIdx,
+				First:  rule.First,
+				Second: rule.Second,
+				Rule:   rule.String(),
+				Value:  distance,
+				Amount: amount,
+			})
+		}
+	}
+
+	return violations
+}
+
+// ReadDistanceRulesFromFile reads distance rules from an Excel file in one of
+// two layouts.
+//
+// A list is the first sheet with the columns First, Second, Min, Max and
+// Measure after a header row, Measure being optional.
+//
+// A matrix is a sheet named "Min" and/or a sheet named "Max", each with the
+// facilities on the first row and the first column. The cell of two
+// facilities is the limit between them; empty or 0 cells are no limit.
+//
+// Rules without a measure take measure.
+func ReadDistanceRulesFromFile(filePath string, measure DistanceMeasure) ([]DistanceRule, error) {
+	file, err := excelize.OpenFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	sheets := file.GetSheetList()
+	if slices.Contains(sheets, "Min") || slices.Contains(sheets, "Max") {
+		return readDistanceMatrices(file, measure)
+	}
+
+	rows, err := file.GetRows(sheets[0])
+	if err != nil {
+		return nil, err
+	}
+
+	rules := make([]DistanceRule, 0, len(rows))
+	for rowIdx, row := range rows {
+		if rowIdx == 0 || len(row) == 0 || strings.TrimSpace(row[0]) == "" {
+			continue
+		}
+
+		if len(row) < 3 {
+			return nil, fmt.Errorf("row %d: expected two facilities and a limit", rowIdx+1)
+		}
+
+		rule := DistanceRule{Measure: measure}
+		for i, cell := range row {
+			cell = strings.TrimSpace(cell)
+
+			switch i {
+			case 0, 1:
+				if err := util.ValidateTFNumber(cell); err != nil {
+					return nil, fmt.Errorf("row %d: %w", rowIdx+1, err)
+				}
+				if i == 0 {
+					rule.First = strings.ToUpper(cell)
+				} else {
+					rule.Second = strings.ToUpper(cell)
+				}
+			case 2, 3:
+				if cell == "" {
+					continue
+				}
+				val, err := strconv.ParseFloat(cell, 64)
+				if err != nil {
+					return nil, fmt.Errorf("row %d: %w", rowIdx+1, err)
+				}
+				if i == 2 {
+					rule.Min = val
+				} else {
+					rule.Max = val
+				}
+			case 4:
+				if cell == "" {
+					continue
+				}
+				rule.Measure, err = parseDistanceMeasure(cell)
+				if err != nil {
+					return nil, fmt.Errorf("row %d: %w", rowIdx+1, err)
+				}
+			}
+		}
+
+		err = validateDistanceRule(rule)
+		if err != nil {
+			return nil, fmt.Errorf("row %d: %w", rowIdx+1, err)
+		}
+
+		rules = append(rules, rule)
+	}
+
+	return rules, nil
+}
+
+// readDistanceMatrices merges the limits of the Min and Max sheets into one
+// rule per pair of facilities.
+func readDistanceMatrices(file *excelize.File, measure DistanceMeasure) ([]DistanceRule, error) {
+	rules := make([]DistanceRule, 0)
+	pairIdx := make(map[[2]string]int)
+
+	for _, sheet := range []string{"Min", "Max"} {
+		idx, err := file.GetSheetIndex(sheet)
+		if err != nil {
+			return nil, err
+		}
+		if idx < 0 {
+			continue
+		}
+
+		rows, err := file.GetRows(sheet)
+		if err != nil {
+			return nil, err
+		}
+		if len(rows) == 0 {
+			continue
+		}
+
+		header := rows[0]
+		for rowIdx, row := range rows[1:] {
+			if len(row) == 0 || strings.TrimSpace(row[0]) == "" {
+				continue
+			}
+
+			first := strings.ToUpper(strings.TrimSpace(row[0]))
+			if err := util.ValidateTFNumber(first); err != nil {
+				return nil, fmt.Errorf("%s row %d: %w", sheet, rowIdx+2, err)
+			}
+
+			for colIdx := 1; colIdx < len(row) && colIdx < len(header); colIdx++ {
+				cell := strings.TrimSpace(row[colIdx])
+				if cell == "" {
+					continue
+				}
+
+				second := strings.ToUpper(strings.TrimSpace(header[colIdx]))
+				if err := util.ValidateTFNumber(second); err != nil {
+					return nil, fmt.Errorf("%s column %d: %w", sheet, colIdx+1, err)
+				}
+
+				val, err := strconv.ParseFloat(cell, 64)
+				if err != nil {
+					return nil, fmt.Errorf("%s row %d: %w", sheet, rowIdx+2, err)
+				}
+				if val == 0 || first == second {
+					continue
+				}
+
+				// a symmetric matrix gives the same pair twice
+				key := [2]string{min(first, second), max(first, second)}
+				i, ok := pairIdx[key]
+				if !ok {
+					i = len(rules)
+					pairIdx[key] = i
+					rules = append(rules, DistanceRule{First: key[0], Second: key[1], Measure: measure})
+				}
+
+				if sheet == "Min" {
+					rules[i].Min = math.Max(rules[i].Min, val)
+				} else if rules[i].Max == 0 || val < rules[i].Max {
+					rules[i].Max = val
+				}
+			}
+		}
+	}
+
+	for _, rule := range rules {
+		if err := validateDistanceRule(rule); err != nil {
+			return nil, err
+		}
+	}
+
+	return rules, nil
+}
+
+func validateDistanceRule(rule DistanceRule) error {
+	switch {
+	case rule.First == rule.Second:
+		return fmt.Errorf("%s: a rule needs two different facilities", rule.First)
+	case rule.Min < 0 || rule.Max < 0:
+		return fmt.Errorf("%s - %s: the limits must not be negative", rule.First, rule.Second)
+	case rule.Min == 0 && rule.Max == 0:
+		return fmt.Errorf("%s - %s: a rule needs a minimum or a maximum", rule.First, rule.Second)
+	case rule.Max > 0 && rule.Min > rule.Max:
+		return fmt.Errorf("%s - %s: the minimum is above the maximum", rule.First, rule.Second)
+	}
+
+	return nil
+}
@@ -0,0 +1,135 @@
+package constraints
+
+import (
+	"github.com/xuri/excelize/v2"
+	"golang-moaha-construction/internal/data"
+	"math"
+	"path/filepath"
+	"testing"
+)
+
+func writeRows(t *testing.T, f *excelize.File, sheet string, rows [][]any) {
+	t.Helper()
+
+	if _, err := f.NewSheet(sheet); err != nil {
+		t.Fatal(err)
+	}
+	for i, row := range rows {
+		cell, _ := excelize.CoordinatesToCellName(1, i+1)
+		if err := f.SetSheetRow(sheet, cell, &row); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestReadDistanceRulesFromFile(t *testing.T) {
+	dir := t.TempDir()
+
+	list := excelize.NewFile()
+	writeRows(t, list, "Sheet1", [][]any{
+		{"First", "Second", "Min", "Max", "Measure"},
+		{"tf1", "TF2", 50, nil, "edge"},
+		{"TF1", "TF3", nil, 30},
+	})
+	listPath := filepath.Join(dir, "list.xlsx")
+	if err := list.SaveAs(listPath); err != nil {
+		t.Fatal(err)
+	}
+
+	rules, err := ReadDistanceRulesFromFile(listPath, CentreDistance)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []DistanceRule{
+		{First: "TF1", Second: "TF2", Min: 50, Measure: EdgeDistance},
+		{First: "TF1", Second: "TF3", Max: 30, Measure: CentreDistance},
+	}
+	if len(rules) != len(expected) || rules[0] != expected[0] || rules[1] != expected[1] {
+		t.Errorf("expected %+v, got %+v", expected, rules)
+	}
+
+	matrix := excelize.NewFile()
+	writeRows(t, matrix, "Min", [][]any{
+		{"", "TF1", "TF2"},
+		{"TF1", 0, 10},
+		{"TF2", 10, 0},
+	})
+	writeRows(t, matrix, "Max", [][]any{
+		{"", "TF1", "TF2"},
+		{"TF1", nil, 40},
+	})
+	matrixPath := filepath.Join(dir, "matrix.xlsx")
+	if err := matrix.SaveAs(matrixPath); err != nil {
+		t.Fatal(err)
+	}
+
+	rules, err = ReadDistanceRulesFromFile(matrixPath, EdgeDistance)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rules) != 1 || rules[0] != (DistanceRule{First: "TF1", Second: "TF2", Min: 10, Max: 40, Measure: EdgeDistance}) {
+		t.Errorf("expected one merged rule, got %+v", rules)
+	}
+}
+
+func TestDistanceConstraint(t *testing.T) {
+	con := CreateDistanceConstraint(
+		[]DistanceRule{
+			{First: "TF1", Second: "TF2", Min: 10, Measure: EdgeDistance},
+			{First: "TF1", Second: "TF3", Max: 5, Measure: CentreDistance},
+		},
+		[][]string{{"TF1", "TF2"}, {"TF1", "TF2", "TF3"}},
+		1, 1,
+	)
+
+	locations := map[string]data.Location{
+		"TF1": {Coordinate: data.Coordinate{X: 0, Y: 0}, Length: 4, Width: 4},
+		// edges 6 apart
+		"TF2": {Coordinate: data.Coordinate{X: 10, Y: 0}, Length: 4, Width: 4},
+		// centres 13 apart
+		"TF3": {Coordinate: data.Coordinate{X: 5, Y: 12}, Length: 2, Width: 2},
+	}
+
+	violations := con.PairViolations(locations)
+	if len(violations) != 3 {
+		t.Fatalf("expected TF1 - TF2 in both phases and TF1 - TF3 in the second, got %+v", violations)
+	}
+	if violations[0].Phase != 0 || violations[0].Value != 6 || violations[0].Amount != 4 {
+		t.Errorf("unexpected violation %+v", violations[0])
+	}
+	if violations[2].Second != "TF3" || violations[2].Amount != 8 {
+		t.Errorf("unexpected violation %+v", violations[2])
+	}
+
+	amount, err := con.Eval(locations)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(amount-16) > 1e-9 {
+		t.Errorf("expected a total violation of 16, got %f", amount)
+	}
+}
+
+func TestReadClearancesFromFile(t *testing.T) {
+	file := excelize.NewFile()
+	writeRows(t, file, "Sheet1", [][]any{
+		{"First", "Second", "Clearance"},
+		{"tf3", nil, 6},
+		{"TF1", "tf2", 3},
+	})
+	filePath := filepath.Join(t.TempDir(), "clearances.xlsx")
+	if err := file.SaveAs(filePath); err != nil {
+		t.Fatal(err)
+	}
+
+	facilityClearances, pairClearances, err := ReadClearancesFromFile(filePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(facilityClearances) != 1 || facilityClearances["TF3"] != 6 {
+		t.Errorf("expected a clearance of 6 around TF3, got %+v", facilityClearances)
+	}
+	if len(pairClearances) != 1 || pairClearances[0] != (PairClearance{First: "TF1", Second: "TF2", Clearance: 3}) {
+		t.Errorf("expected a clearance of 3 between TF1 and TF2, got %+v", pairClearances)
+	}
+}
@@ -0,0 +1,73 @@
+package constraints
+
+import (
+	"fmt"
+	"github.com/xuri/excelize/v2"
+	"golang-moaha-construction/internal/util"
+	"strconv"
+	"strings"
+)
+
+// ReadClearancesFromFile reads the clearances of the overlap constraint from
+// the first sheet of an Excel file with the columns First, Second and
+// Clearance. A row without a second facility is the clearance around the
+// first one, otherwise the clearance between both.
+func ReadClearancesFromFile(filePath string) (map[string]float64, []PairClearance, error) {
+	file, err := excelize.OpenFile(filePath)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer file.Close()
+
+	rows, err := file.GetRows(file.GetSheetList()[0])
+	if err != nil {
+		return nil, nil, err
+	}
+
+	facilityClearances := make(map[string]float64)
+	pairClearances := make([]PairClearance, 0, len(rows))
+	for rowIdx, row := range rows {
+		if rowIdx == 0 || len(row) == 0 || strings.TrimSpace(row[0]) == "" {
+			continue
+		}
+
+		if len(row) < 3 {
+			return nil, nil, fmt.Errorf("row %d: expected a facility and a clearance", rowIdx+1)
+		}
+
+		first := strings.ToUpper(strings.TrimSpace(row[0]))
+		second := strings.ToUpper(strings.TrimSpace(row[1]))
+
+		for _, symbol := range []string{first, second} {
+			if symbol == "" {
+				continue
+			}
+			if err := util.ValidateTFNumber(symbol); err != nil {
+				return nil, nil, fmt.Errorf("row %d: %w", rowIdx+1, err)
+			}
+		}
+
+		clearance, err := strconv.ParseFloat(strings.TrimSpace(row[2]), 64)
+		if err != nil {
+			return nil, nil, fmt.Errorf("row %d: %w", rowIdx+1, err)
+		}
+		if clearance < 0 {
+			return nil, nil, fmt.Errorf("row %d: the clearance must not be negative", rowIdx+1)
+		}
+
+		switch second {
+		case "":
+			facilityClearances[first] = clearance
+		case first:
+			return nil, nil, fmt.Errorf("row %d: %s is paired with itself", rowIdx+1, first)
+		default:
+			pairClearances = append(pairClearances, PairClearance{
+				First:     first,
+				Second:    second,
+				Clearance: clearance,
+			})
+		}
+	}
+
+	return facilityClearances, pairClearances, nil
+}
@@ -2,7 +2,9 @@ package constraints
 
 import (
 	"golang-moaha-construction/internal/data"
+	"golang-moaha-construction/internal/objectives/conslay_predetermined"
 	"slices"
+	"strings"
 )
 
 const (
@@ -34,6 +36,56 @@ func CreateSizeConstraint(
 	}
 }
 
+type sizeConfig struct {
+	AlphaSizePenalty       float64  `json:"AlphaSizePenalty"`
+	PowerDifferencePenalty float64  `json:"PowerDifferencePenalty"`
+	SmallLocations         []string `json:"SmallLocations"`
+	LargeFacilities        []string `json:"LargeFacilities"`
+}
+
+func init() {
+	Register(ConstraintSize, Definition[sizeConfig, *SizeConstraint]{
+		Schema: ConfigSchema{
+			Label:  "Size",
+			TSName: "Size",
+			Fields: []Field{
+				{Key: "AlphaSizePenalty", Label: "Alpha (for penalty)", Type: FieldNumber, Default: 20000.0},
+				{Key: "PowerDifferencePenalty", Label: "Power difference (for penalty)", Type: FieldNumber, Default: 1.0},
+				{Key: "SmallLocations", Label: "Small locations", Type: FieldNames},
+				{Key: "LargeFacilities", Label: "Large facilities", Type: FieldNames},
+			},
+		},
+		Problems: []data.ProblemName{conslay_predetermined.PredeterminedConsLayoutName},
+		Create: func(problem Problem, config sizeConfig) (*SizeConstraint, error) {
+			return CreateSizeConstraint(
+				config.SmallLocations,
+				config.LargeFacilities,
+				config.AlphaSizePenalty,
+				config.PowerDifferencePenalty,
+			), nil
+		},
+		Info: func(con *SizeConstraint) any {
+			return struct {
+				AlphaSizePenalty       float64  `json:"alphaSizePenalty"`
+				PowerDifferencePenalty float64  `json:"powerDifferencePenalty"`
+				SmallLocations         []string `json:"smallLocations"`
+				LargeFacilities        []string `json:"largeFacilities"`
+			}{
+				AlphaSizePenalty:       con.AlphaSizePenalty,
+				PowerDifferencePenalty: con.PowerSizePenalty,
+				SmallLocations:         con.SmallLocations,
+				LargeFacilities:        con.LargeFacilities,
+			}
+		},
+		Summary: func(con *SizeConstraint, w SummaryWriter) {
+			w.Value("Alpha (for penalty)", con.AlphaSizePenalty)
+			w.Value("Power difference (for penalty)", con.PowerSizePenalty)
+			w.Value("Small locations", strings.Join(con.SmallLocations, " "))
+			w.Value("Large facilities", strings.Join(con.LargeFacilities, " "))
+		},
+	})
+}
+
 func (c SizeConstraint) GetName() string {
 	return string(c.Name)
 }
@@ -46,7 +98,7 @@ func (c SizeConstraint) GetPowerPenalty() float64 {
 	return c.PowerSizePenalty
 }
 
-func (c SizeConstraint) Eval(mapLocations map[string]data.Location) float64 {
+func (c SizeConstraint) Eval(mapLocations map[string]data.Location) (float64, error) {
 	// number of invalid locations
 	amount := 0.0
 
@@ -62,5 +114,5 @@ func (c SizeConstraint) Eval(mapLocations map[string]data.Location) float64 {
 		}
 	}
 
-	return amount
+	return amount, nil
 }
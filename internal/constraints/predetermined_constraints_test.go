@@ -114,7 +114,10 @@ func TestSizeConstraint_Eval(t *testing.T) {
 
 	for _, tt := range testTable {
 		t.Run(tt.name, func(t *testing.T) {
-			result := sizeConstraint.Eval(tt.locations)
+			result, err := sizeConstraint.Eval(tt.locations)
+			if err != nil {
+				t.Fatal(err)
+			}
 
 			if math.Abs(tt.expected-result) > 1e-9 {
 				t.Errorf("expected result to be %f, got %f", tt.expected, result)
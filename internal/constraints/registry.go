@@ -0,0 +1,279 @@
+package constraints
+
+import (
+	"errors"
+	"fmt"
+	"github.com/bytedance/sonic"
+	"golang-moaha-construction/internal/data"
+	"slices"
+	"strings"
+	"sync"
+)
+
+var (
+	ErrUnknownConstraint = errors.New("invalid constraint name")
+	ErrNotApplicable     = errors.New("constraint does not apply to the problem")
+)
+
+// FieldType is the kind of value of a config field.
+type FieldType string
+
+const (
+	FieldNumber FieldType = "number"
+	FieldString FieldType = "string"
+	// FieldNames is a list of facility names
+	FieldNames FieldType = "names"
+	// FieldList is a list of objects described by the Fields of the field
+	FieldList FieldType = "list"
+)
+
+// Field describes one key of a constraint config.
+type Field struct {
+	Key     string    `json:"key"`
+	Label   string    `json:"label"`
+	Type    FieldType `json:"type"`
+	Default any       `json:"default,omitempty"`
+	// Fields describes the items of a FieldList
+	Fields []Field `json:"fields,omitempty"`
+}
+
+// ConfigSchema describes a constraint and its config for the UI and the
+// exported summary.
+type ConfigSchema struct {
+	// Label is the name shown to the user
+	Label string `json:"label"`
+	// TSName is the name of the constraint in the frontend enum
+	TSName string  `json:"tsName"`
+	Fields []Field `json:"fields"`
+}
+
+// Problem is what the factories need to know about the problem the
+// constraint is added to.
+type Problem interface {
+	GetPhases() [][]string
+	GetLocations() map[string]data.Location
+	GetLayoutSize() (minX float64, maxX float64, minY float64, maxY float64, err error)
+	SetCranesLocations(locations []data.Crane) error
+}
+
+// SummaryWriter receives the rows a constraint writes to the exported
+// summary.
+type SummaryWriter interface {
+	// Value writes a labelled value on one row.
+	Value(label string, value any)
+	// Group writes a heading for the rows that follow.
+	Group(title string)
+}
+
+// Definition is everything a registered constraint provides. C is the config
+// decoded with the json tags of C, K the created constraint.
+type Definition[C any, K data.Constrainter] struct {
+	Schema ConfigSchema
+	// Problems are the problems the constraint applies to
+	Problems []data.ProblemName
+	Create   func(problem Problem, config C) (K, error)
+	// Info is the view of a created constraint sent to the UI.
+	Info func(con K) any
+	// Summary writes a created constraint to the exported summary.
+	Summary func(con K, w SummaryWriter)
+}
+
+// Registration is a registered constraint.
+type Registration struct {
+	Name     data.ConstraintType `json:"name"`
+	Schema   ConfigSchema        `json:"schema"`
+	Problems []data.ProblemName  `json:"problems"`
+}
+
+// AppliesTo reports whether the constraint is valid for problemName.
+func (r Registration) AppliesTo(problemName data.ProblemName) bool {
+	return slices.Contains(r.Problems, problemName)
+}
+
+// Info is the view of a created constraint.
+type Info struct {
+	Name   data.ConstraintType `json:"name"`
+	Label  string              `json:"label"`
+	Config any                 `json:"config"`
+}
+
+type registration struct {
+	Registration
+	create  func(problem Problem, config map[string]any) (data.Constrainter, error)
+	info    func(con data.Constrainter) (any, error)
+	summary func(con data.Constrainter, w SummaryWriter) error
+}
+
+var (
+	registryMu sync.RWMutex
+	registry   []registration
+)
+
+// Register makes a constraint available under name. It is meant to be called
+// from an init function and panics when name is registered twice.
+func Register[C any, K data.Constrainter](name data.ConstraintType, def Definition[C, K]) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	for _, r := range registry {
+		if r.Name == name {
+			panic(fmt.Sprintf("constraints: %s registered twice", name))
+		}
+	}
+
+	cast := func(con data.Constrainter) (K, error) {
+		k, ok := con.(K)
+		if !ok {
+			return k, fmt.Errorf("%s: unexpected constraint %T", name, con)
+		}
+
+		return k, nil
+	}
+
+	registry = append(registry, registration{
+		Registration: Registration{
+			Name:     name,
+			Schema:   def.Schema,
+			Problems: def.Problems,
+		},
+		create: func(problem Problem, config map[string]any) (data.Constrainter, error) {
+			configBytes, err := sonic.Marshal(config)
+			if err != nil {
+				return nil, err
+			}
+
+			var c C
+			err = sonic.Unmarshal(configBytes, &c)
+			if err != nil {
+				return nil, err
+			}
+
+			return def.Create(problem, c)
+		},
+		info: func(con data.Constrainter) (any, error) {
+			k, err := cast(con)
+			if err != nil {
+				return nil, err
+			}
+
+			return def.Info(k), nil
+		},
+		summary: func(con data.Constrainter, w SummaryWriter) error {
+			k, err := cast(con)
+			if err != nil {
+				return err
+			}
+
+			def.Summary(k, w)
+			return nil
+		},
+	})
+}
+
+// Registered returns every registered constraint in registration order.
+func Registered() []Registration {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	res := make([]Registration, len(registry))
+	for i, r := range registry {
+		res[i] = r.Registration
+	}
+
+	return res
+}
+
+func lookup(name data.ConstraintType) (*registration, error) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	for i := range registry {
+		if registry[i].Name == name {
+			return &registry[i], nil
+		}
+	}
+
+	return nil, fmt.Errorf("%w: %s", ErrUnknownConstraint, name)
+}
+
+// New creates the constraint registered under name for problem, which is a
+// problemName. config is anything that encodes to a JSON object, usually the
+// config sent by the UI. Missing keys take the defaults of the schema.
+func New(name data.ConstraintType, problemName data.ProblemName, problem Problem, config any) (data.Constrainter, error) {
+	r, err := lookup(name)
+	if err != nil {
+		return nil, err
+	}
+
+	if !r.AppliesTo(problemName) {
+		return nil, fmt.Errorf("%w: %s", ErrNotApplicable, problemName)
+	}
+
+	values := make(map[string]any)
+	if config != nil {
+		configBytes, err := sonic.Marshal(config)
+		if err != nil {
+			return nil, err
+		}
+
+		err = sonic.Unmarshal(configBytes, &values)
+		if err != nil {
+			return nil, fmt.Errorf("constraint config must be an object: %w", err)
+		}
+	}
+
+	// a null config decodes into a nil map
+	if values == nil {
+		values = make(map[string]any)
+	}
+
+	// keys are matched case-insensitively like the json decoder does
+	for _, field := range r.Schema.Fields {
+		if field.Default == nil || hasKey(values, field.Key) {
+			continue
+		}
+
+		values[field.Key] = field.Default
+	}
+
+	return r.create(problem, values)
+}
+
+// Describe returns the Info of con, created under name.
+func Describe(name data.ConstraintType, con data.Constrainter) (Info, error) {
+	r, err := lookup(name)
+	if err != nil {
+		return Info{}, err
+	}
+
+	config, err := r.info(con)
+	if err != nil {
+		return Info{}, err
+	}
+
+	return Info{
+		Name:   name,
+		Label:  r.Schema.Label,
+		Config: config,
+	}, nil
+}
+
+// WriteSummary writes con, created under name, to w.
+func WriteSummary(name data.ConstraintType, con data.Constrainter, w SummaryWriter) error {
+	r, err := lookup(name)
+	if err != nil {
+		return err
+	}
+
+	return r.summary(con, w)
+}
+
+func hasKey(values map[string]any, key string) bool {
+	for k := range values {
+		if strings.EqualFold(k, key) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,110 @@
+package constraints
+
+import (
+	"errors"
+	"golang-moaha-construction/internal/data"
+	"golang-moaha-construction/internal/objectives/conslay_continuous"
+	"golang-moaha-construction/internal/objectives/conslay_predetermined"
+	"testing"
+)
+
+type testProblem struct {
+	cranes []data.Crane
+}
+
+func (p *testProblem) GetPhases() [][]string {
+	return [][]string{{"TF1", "TF2"}}
+}
+
+func (p *testProblem) GetLocations() map[string]data.Location {
+	return map[string]data.Location{
+		"TF1": {Symbol: "TF1", Length: 10, Width: 10, IsFixed: true},
+	}
+}
+
+func (p *testProblem) GetLayoutSize() (float64, float64, float64, float64, error) {
+	return 0, 120, 0, 95, nil
+}
+
+func (p *testProblem) SetCranesLocations(locations []data.Crane) error {
+	p.cranes = locations
+	return nil
+}
+
+type recordingWriter []string
+
+func (w *recordingWriter) Value(label string, value any) {
+	*w = append(*w, label)
+}
+
+func (w *recordingWriter) Group(title string) {
+	*w = append(*w, "# "+title)
+}
+
+func TestRegistry(t *testing.T) {
+	problem := &testProblem{}
+
+	con, err := New(ConstraintOutOfBound, conslay_continuous.ContinuousConsLayoutName, problem, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	outOfBound := con.(*OutOfBoundsConstraint)
+	if outOfBound.MaxLength != 120 || outOfBound.MaxWidth != 95 {
+		t.Errorf("expected the layout size, got %+v", outOfBound)
+	}
+	if outOfBound.AlphaOutOfBoundsPenalty != 20000 || outOfBound.PowerOutOfBoundsPenalty != 1 {
+		t.Errorf("expected the default penalty, got %+v", outOfBound)
+	}
+
+	_, err = New(ConstraintSize, conslay_continuous.ContinuousConsLayoutName, problem, nil)
+	if !errors.Is(err, ErrNotApplicable) {
+		t.Errorf("expected ErrNotApplicable for Size on a continuous layout, got %v", err)
+	}
+
+	_, err = New(ConstraintOverlap, conslay_predetermined.PredeterminedConsLayoutName, problem, nil)
+	if !errors.Is(err, ErrNotApplicable) {
+		t.Errorf("expected ErrNotApplicable for Overlap on a predetermined layout, got %v", err)
+	}
+
+	_, err = New("unknown", conslay_continuous.ContinuousConsLayoutName, problem, nil)
+	if !errors.Is(err, ErrUnknownConstraint) {
+		t.Errorf("expected ErrUnknownConstraint, got %v", err)
+	}
+
+	con, err = New(ConstraintsCoverInCraneRadius, conslay_continuous.ContinuousConsLayoutName, problem, map[string]any{
+		"CraneLocations": []map[string]any{
+			{"Name": "TF1", "BuildingNames": " tf2  TF3 ", "Radius": 40},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(problem.cranes) != 1 || len(problem.cranes[0].BuildingName) != 2 || problem.cranes[0].BuildingName[0] != "TF2" {
+		t.Errorf("expected the cranes set on the problem, got %+v", problem.cranes)
+	}
+
+	info, err := Describe(ConstraintsCoverInCraneRadius, con)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Label != "Cover in Crane's radius" {
+		t.Errorf("unexpected info %+v", info)
+	}
+
+	var w recordingWriter
+	err = WriteSummary(ConstraintsCoverInCraneRadius, con, &w)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(w) != 5 || w[2] != "# TF1" {
+		t.Errorf("unexpected summary rows %v", w)
+	}
+
+	_, err = New(ConstraintInclusiveZone, conslay_continuous.ContinuousConsLayoutName, problem, map[string]any{
+		"Zones": []map[string]any{{"Name": "TF1", "BuildingNames": "TF2 X1", "Size": 5}},
+	})
+	if err == nil {
+		t.Error("expected an error for an invalid facility name")
+	}
+}
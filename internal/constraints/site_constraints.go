@@ -0,0 +1,180 @@
+package constraints
+
+import (
+	"fmt"
+	"golang-moaha-construction/internal/data"
+	"golang-moaha-construction/internal/objectives/conslay_continuous"
+	"slices"
+)
+
+const ConstraintSiteBoundary data.ConstraintType = "SiteBoundary"
+
+// Site Boundary and Obstacles
+
+// SiteBoundaryConstraint keeps the facilities of every phase inside a
+// polygonal site boundary and out of the obstacles of that phase.
+type SiteBoundaryConstraint struct {
+	Site                     data.Site
+	SiteFilePath             string
+	Phases                   [][]string
+	Name                     data.ConstraintType
+	AlphaSiteBoundaryPenalty float64
+	PowerSiteBoundaryPenalty float64
+}
+
+func CreateSiteBoundaryConstraint(
+	site data.Site,
+	phases [][]string,
+	alphaSiteBoundaryPenalty float64,
+	powerSiteBoundaryPenalty float64,
+) *SiteBoundaryConstraint {
+	return &SiteBoundaryConstraint{
+		Site:                     site,
+		Phases:                   phases,
+		Name:                     ConstraintSiteBoundary,
+		AlphaSiteBoundaryPenalty: alphaSiteBoundaryPenalty,
+		PowerSiteBoundaryPenalty: powerSiteBoundaryPenalty,
+	}
+}
+
+type siteBoundaryConfig struct {
+	AlphaSiteBoundaryPenalty float64 `json:"AlphaSiteBoundaryPenalty"`
+	PowerDifferencePenalty   float64 `json:"PowerDifferencePenalty"`
+	SiteFilePath             string  `json:"SiteFilePath"`
+}
+
+func init() {
+	Register(ConstraintSiteBoundary, Definition[siteBoundaryConfig, *SiteBoundaryConstraint]{
+		Schema: ConfigSchema{
+			Label:  "Site Boundary and Obstacles",
+			TSName: "SiteBoundary",
+			Fields: []Field{
+				{Key: "AlphaSiteBoundaryPenalty", Label: "Alpha (for penalty)", Type: FieldNumber, Default: 20000.0},
+				{Key: "PowerDifferencePenalty", Label: "Power difference (for penalty)", Type: FieldNumber, Default: 1.0},
+				{Key: "SiteFilePath", Label: "Site file", Type: FieldString},
+			},
+		},
+		Problems: []data.ProblemName{conslay_continuous.ContinuousConsLayoutName},
+		Create: func(problem Problem, config siteBoundaryConfig) (*SiteBoundaryConstraint, error) {
+			site, err := conslay_continuous.ReadSiteFromFile(config.SiteFilePath)
+			if err != nil {
+				return nil, fmt.Errorf("site file: %w", err)
+			}
+
+			phases := problem.GetPhases()
+			for _, obstacle := range site.Obstacles {
+				for _, phaseIdx := range obstacle.Phases {
+					if phaseIdx >= len(phases) {
+						return nil, fmt.Errorf("obstacle %s: phase %d does not exist", obstacle.Name, phaseIdx+1)
+					}
+				}
+			}
+
+			con := CreateSiteBoundaryConstraint(
+				site,
+				phases,
+				config.AlphaSiteBoundaryPenalty,
+				config.PowerDifferencePenalty,
+			)
+			con.SiteFilePath = config.SiteFilePath
+
+			return con, nil
+		},
+		Info: func(con *SiteBoundaryConstraint) any {
+			return struct {
+				AlphaSiteBoundaryPenalty float64    `json:"alphaSiteBoundaryPenalty"`
+				PowerSiteBoundaryPenalty float64    `json:"powerSiteBoundaryPenalty"`
+				SiteFilePath             string     `json:"siteFilePath"`
+				Phases                   [][]string `json:"phases"`
+				Site                     data.Site  `json:"site"`
+			}{
+				AlphaSiteBoundaryPenalty: con.AlphaSiteBoundaryPenalty,
+				PowerSiteBoundaryPenalty: con.PowerSiteBoundaryPenalty,
+				SiteFilePath:             con.SiteFilePath,
+				Phases:                   con.Phases,
+				Site:                     con.Site,
+			}
+		},
+		Summary: func(con *SiteBoundaryConstraint, w SummaryWriter) {
+			w.Value("Alpha (for penalty)", con.AlphaSiteBoundaryPenalty)
+			w.Value("Power difference (for penalty)", con.PowerSiteBoundaryPenalty)
+			w.Value("Site file", con.SiteFilePath)
+			w.Value("Boundary vertices", len(con.Site.Boundary))
+			w.Value("Boundary area", con.Site.Boundary.Area())
+
+			for _, obstacle := range con.Site.Obstacles {
+				w.Group(obstacle.Name)
+				w.Value("Vertices", len(obstacle.Polygon))
+				w.Value("Area", obstacle.Polygon.Area())
+				w.Value("Phases", phasesLabel(obstacle.Phases))
+			}
+		},
+	})
+}
+
+func (c SiteBoundaryConstraint) GetName() string {
+	return string(c.Name)
+}
+
+func (c SiteBoundaryConstraint) GetAlphaPenalty() float64 {
+	return c.AlphaSiteBoundaryPenalty
+}
+
+func (c SiteBoundaryConstraint) GetPowerPenalty() float64 {
+	return c.PowerSiteBoundaryPenalty
+}
+
+// Eval sums, over the non-fixed facilities of every phase, the area of the
+// facility outside the boundary and inside the obstacles of the phase.
+func (c SiteBoundaryConstraint) Eval(mapLocations map[string]data.Location) (float64, error) {
+	return c.EvalPhases(data.SamePhaseLocations(mapLocations, len(c.Phases)))
+}
+
+// EvalPhases evaluates every phase with its own locations.
+func (c SiteBoundaryConstraint) EvalPhases(phaseLocations []map[string]data.Location) (float64, error) {
+	amount := 0.0
+
+	for phaseIdx, phase := range c.Phases {
+		mapLocations := phaseLocations[phaseIdx]
+		for _, symbol := range phase {
+			loc, ok := mapLocations[symbol]
+			if !ok || loc.IsFixed {
+				continue
+			}
+
+			amount += IsOutOfSite(c.Site, phaseIdx, loc)
+		}
+	}
+
+	return amount, nil
+}
+
+// IsOutOfSite returns the area of b outside the boundary of site and inside
+// the obstacles existing in the phase phaseIdx.
+func IsOutOfSite(site data.Site, phaseIdx int, b data.Location) float64 {
+	covered := func(p data.Polygon) float64 {
+		if b.Angle != 0 {
+			return p.ClipConvex(b.Corners()).Area()
+		}
+
+		minX, maxX, minY, maxY := b.Bounds()
+		return p.RectangleArea(minX, maxX, minY, maxY)
+	}
+
+	amount := b.Length*b.Width - covered(site.Boundary)
+
+	for _, obstacle := range site.Obstacles {
+		if len(obstacle.Phases) > 0 && !slices.Contains(obstacle.Phases, phaseIdx) {
+			continue
+		}
+
+		amount += covered(obstacle.Polygon)
+	}
+
+	// rounding errors of the clipping
+	if amount < 1e-9 {
+		return 0
+	}
+
+	return amount
+}
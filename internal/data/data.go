@@ -19,8 +19,11 @@ type Coordinate struct {
 }
 
 type Location struct {
-	Coordinate  Coordinate
-	Rotation    bool
+	Coordinate Coordinate
+	Rotation   bool
+	// Angle is the counter-clockwise rotation in degrees of a freely rotated
+	// location, whose Length and Width are then not swapped
+	Angle       float64
 	Length      float64
 	Width       float64
 	IsFixed     bool
@@ -66,13 +69,67 @@ type ObjectiveType string
 type ConstraintType string
 
 type Objectiver interface {
-	Eval(mapLocations map[string]Location) float64
+	Eval(mapLocations map[string]Location) (float64, error)
 	GetAlphaPenalty() float64
 }
 
 type Constrainter interface {
-	Eval(map[string]Location) float64
+	Eval(map[string]Location) (float64, error)
 	GetName() string
 	GetAlphaPenalty() float64
 	GetPowerPenalty() float64
 }
+
+// PairViolation is how much two facilities violate a rule between them in a
+// phase.
+type PairViolation struct {
+	// Phase is the index of the phase
+	Phase  int
+	First  string
+	Second string
+	// Rule describes the rule that is violated
+	Rule string
+	// Value is the measured value, e.g. the distance between the facilities
+	Value  float64
+	Amount float64
+}
+
+// PairViolationReporter is implemented by the constraints that can report
+// their violations per pair of facilities.
+type PairViolationReporter interface {
+	PairViolations(mapLocations map[string]Location) []PairViolation
+	// PhasePairViolations is PairViolations with the locations of every
+	// phase, see PhaseEvaluator.
+	PhasePairViolations(phaseLocations []map[string]Location) []PairViolation
+}
+
+// ClearanceProvider is implemented by the constraints that require a gap
+// between two facilities, so that repairing a layout keeps it.
+type ClearanceProvider interface {
+	ClearanceBetween(first, second string) float64
+}
+
+// PhaseDurationProvider is implemented by the problems whose phases have a
+// duration. GetPhaseDurations returns nil when they have none.
+type PhaseDurationProvider interface {
+	GetPhaseDurations() []float64
+}
+
+// PhaseEvaluator is implemented by the objectives and constraints that add up
+// a contribution per phase, so that a dynamic layout, where facilities move
+// between phases, can evaluate every phase with its own locations.
+// phaseLocations[i] are the locations in the phase i.
+type PhaseEvaluator interface {
+	EvalPhases(phaseLocations []map[string]Location) (float64, error)
+}
+
+// SamePhaseLocations returns locations for each of numberOfPhases phases, the
+// phase locations of a layout whose facilities do not move.
+func SamePhaseLocations(locations map[string]Location, numberOfPhases int) []map[string]Location {
+	phaseLocations := make([]map[string]Location, numberOfPhases)
+	for i := range phaseLocations {
+		phaseLocations[i] = locations
+	}
+
+	return phaseLocations
+}
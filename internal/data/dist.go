@@ -10,3 +10,36 @@ func Distance2D(a, b Coordinate) float64 {
 
 	return math.Sqrt(x*x + y*y)
 }
+
+// DistanceMetric is how the distance travelled between two points of the site
+// is measured.
+type DistanceMetric string
+
+const (
+	// EuclideanMetric is the straight-line distance
+	EuclideanMetric DistanceMetric = "euclidean"
+	// ManhattanMetric is the distance along the x and y axes
+	ManhattanMetric DistanceMetric = "manhattan"
+	// NetworkMetric is the distance along the roads of the site
+	NetworkMetric DistanceMetric = "network"
+)
+
+// DistanceProvider measures the distance travelled between two points of the
+// site.
+type DistanceProvider interface {
+	Distance(a, b Coordinate) float64
+}
+
+// EuclideanDistance is the straight-line distance, Distance2D.
+type EuclideanDistance struct{}
+
+func (EuclideanDistance) Distance(a, b Coordinate) float64 {
+	return Distance2D(a, b)
+}
+
+// ManhattanDistance is the distance along the x and y axes.
+type ManhattanDistance struct{}
+
+func (ManhattanDistance) Distance(a, b Coordinate) float64 {
+	return math.Abs(a.X-b.X) + math.Abs(a.Y-b.Y)
+}
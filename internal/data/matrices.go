@@ -2,6 +2,7 @@ package data
 
 import (
 	"errors"
+	"fmt"
 )
 
 // TwoDimensionalMatrix is (n*n) matrix
@@ -108,6 +109,38 @@ func (m *TwoDimensionalMatrix) GetNumberOfItems() int {
 	return m.NumberOfItems
 }
 
+// PhaseMatrices are the matrices of the phases of a project, one per phase,
+// or a single one shared by every phase.
+type PhaseMatrices []TwoDimensionalMatrix
+
+// Index returns the index of the matrix of the phase phaseIdx.
+func (m PhaseMatrices) Index(phaseIdx int) int {
+	if len(m) == 1 {
+		return 0
+	}
+
+	return phaseIdx
+}
+
+// Phase returns the matrix of the phase phaseIdx.
+func (m PhaseMatrices) Phase(phaseIdx int) *TwoDimensionalMatrix {
+	return &m[m.Index(phaseIdx)]
+}
+
+// IsPerPhase reports whether every phase has its own matrix.
+func (m PhaseMatrices) IsPerPhase() bool {
+	return len(m) > 1
+}
+
+// Validate checks that there is a single matrix or one per phase.
+func (m PhaseMatrices) Validate(numberOfPhases int) error {
+	if len(m) == 1 || len(m) == numberOfPhases {
+		return nil
+	}
+
+	return fmt.Errorf("expected a single matrix or one per phase (%d), got %d", numberOfPhases, len(m))
+}
+
 // RectangleMatrix is (n*m) matrix
 type RectangleMatrix struct {
 	Matrix           [][]float64
@@ -0,0 +1,250 @@
+package data
+
+import (
+	"math"
+)
+
+// Polygon is a simple polygon given by its vertices in order, clockwise or
+// counter-clockwise. The last vertex connects back to the first.
+type Polygon []Coordinate
+
+// Area is the area enclosed by the polygon.
+func (p Polygon) Area() float64 {
+	if len(p) < 3 {
+		return 0
+	}
+
+	sum := 0.0
+	for i := range p {
+		a := p[i]
+		b := p[(i+1)%len(p)]
+		sum += a.X*b.Y - b.X*a.Y
+	}
+
+	return math.Abs(sum) / 2
+}
+
+// ClipRectangle returns the part of the polygon inside the axis-aligned
+// rectangle [minX, maxX] x [minY, maxY] with the Sutherland-Hodgman algorithm.
+// The polygon may be concave since the rectangle is convex. The result keeps
+// the area of the intersection but may contain degenerate edges.
+func (p Polygon) ClipRectangle(minX, maxX, minY, maxY float64) Polygon {
+	edges := []struct {
+		inside    func(c Coordinate) bool
+		intersect func(a, b Coordinate) Coordinate
+	}{
+		{
+			inside: func(c Coordinate) bool { return c.X >= minX },
+			intersect: func(a, b Coordinate) Coordinate {
+				return Coordinate{X: minX, Y: a.Y + (b.Y-a.Y)*(minX-a.X)/(b.X-a.X)}
+			},
+		},
+		{
+			inside: func(c Coordinate) bool { return c.X <= maxX },
+			intersect: func(a, b Coordinate) Coordinate {
+				return Coordinate{X: maxX, Y: a.Y + (b.Y-a.Y)*(maxX-a.X)/(b.X-a.X)}
+			},
+		},
+		{
+			inside: func(c Coordinate) bool { return c.Y >= minY },
+			intersect: func(a, b Coordinate) Coordinate {
+				return Coordinate{X: a.X + (b.X-a.X)*(minY-a.Y)/(b.Y-a.Y), Y: minY}
+			},
+		},
+		{
+			inside: func(c Coordinate) bool { return c.Y <= maxY },
+			intersect: func(a, b Coordinate) Coordinate {
+				return Coordinate{X: a.X + (b.X-a.X)*(maxY-a.Y)/(b.Y-a.Y), Y: maxY}
+			},
+		},
+	}
+
+	output := p
+	for _, edge := range edges {
+		if len(output) == 0 {
+			break
+		}
+
+		input := output
+		output = make(Polygon, 0, len(input)+2)
+
+		for i := range input {
+			current := input[i]
+			previous := input[(i+len(input)-1)%len(input)]
+
+			switch {
+			case edge.inside(current):
+				if !edge.inside(previous) {
+					output = append(output, edge.intersect(previous, current))
+				}
+				output = append(output, current)
+			case edge.inside(previous):
+				output = append(output, edge.intersect(previous, current))
+			}
+		}
+	}
+
+	return output
+}
+
+// RectangleArea is the area of the rectangle [minX, maxX] x [minY, maxY]
+// covered by the polygon.
+func (p Polygon) RectangleArea(minX, maxX, minY, maxY float64) float64 {
+	return p.ClipRectangle(minX, maxX, minY, maxY).Area()
+}
+
+// ClipConvex returns the part of the polygon inside the convex polygon clip,
+// given counter-clockwise, with the Sutherland-Hodgman algorithm.
+func (p Polygon) ClipConvex(clip Polygon) Polygon {
+	output := p
+	for i := range clip {
+		if len(output) == 0 {
+			break
+		}
+
+		a := clip[i]
+		b := clip[(i+1)%len(clip)]
+		side := func(c Coordinate) float64 {
+			return (b.X-a.X)*(c.Y-a.Y) - (b.Y-a.Y)*(c.X-a.X)
+		}
+		intersect := func(from, to Coordinate) Coordinate {
+			t := side(from) / (side(from) - side(to))
+			return Coordinate{X: from.X + (to.X-from.X)*t, Y: from.Y + (to.Y-from.Y)*t}
+		}
+
+		input := output
+		output = make(Polygon, 0, len(input)+2)
+
+		for j := range input {
+			current := input[j]
+			previous := input[(j+len(input)-1)%len(input)]
+
+			switch {
+			case side(current) >= 0:
+				if side(previous) < 0 {
+					output = append(output, intersect(previous, current))
+				}
+				output = append(output, current)
+			case side(previous) >= 0:
+				output = append(output, intersect(previous, current))
+			}
+		}
+	}
+
+	return output
+}
+
+// Project returns the interval the polygon covers along axis, a unit vector.
+func (p Polygon) Project(axis Coordinate) (lower, upper float64) {
+	lower, upper = math.Inf(1), math.Inf(-1)
+	for _, c := range p {
+		d := c.Dot(axis)
+		lower = math.Min(lower, d)
+		upper = math.Max(upper, d)
+	}
+
+	return lower, upper
+}
+
+// SeparatingAxisOverlaps returns how much the convex polygons p and q overlap
+// along every distinct normal of their edges, the axes of the separating axis
+// theorem. They intersect only when every overlap is positive.
+func (p Polygon) SeparatingAxisOverlaps(q Polygon) []float64 {
+	axes := make([]Coordinate, 0, len(p)+len(q))
+	for _, polygon := range []Polygon{p, q} {
+		for i := range polygon {
+			a := polygon[i]
+			b := polygon[(i+1)%len(polygon)]
+			length := math.Hypot(b.X-a.X, b.Y-a.Y)
+			if length == 0 {
+				continue
+			}
+
+			normal := Coordinate{X: -(b.Y - a.Y) / length, Y: (b.X - a.X) / length}
+			parallel := false
+			for _, axis := range axes {
+				if math.Abs(axis.X*normal.Y-axis.Y*normal.X) < 1e-9 {
+					parallel = true
+					break
+				}
+			}
+			if !parallel {
+				axes = append(axes, normal)
+			}
+		}
+	}
+
+	overlaps := make([]float64, len(axes))
+	for i, axis := range axes {
+		lowerP, upperP := p.Project(axis)
+		lowerQ, upperQ := q.Project(axis)
+		overlaps[i] = math.Min(upperP, upperQ) - math.Max(lowerP, lowerQ)
+	}
+
+	return overlaps
+}
+
+// Dot is the dot product of c and other.
+func (c Coordinate) Dot(other Coordinate) float64 {
+	return c.X*other.X + c.Y*other.Y
+}
+
+// Axes returns the unit vectors along the length and the width of a location.
+func (l Location) Axes() (length, width Coordinate) {
+	sin, cos := math.Sincos(l.Angle * math.Pi / 180)
+	return Coordinate{X: cos, Y: sin}, Coordinate{X: -sin, Y: cos}
+}
+
+// HalfExtents returns half of the size along X and Y of the axis-aligned
+// rectangle around a location.
+func (l Location) HalfExtents() (halfX, halfY float64) {
+	if l.Angle == 0 {
+		return l.Length / 2, l.Width / 2
+	}
+
+	sin, cos := math.Sincos(l.Angle * math.Pi / 180)
+	sin, cos = math.Abs(sin), math.Abs(cos)
+
+	return (cos*l.Length + sin*l.Width) / 2, (sin*l.Length + cos*l.Width) / 2
+}
+
+// Corners returns the corners of a location counter-clockwise.
+func (l Location) Corners() Polygon {
+	length, width := l.Axes()
+	corner := func(alongLength, alongWidth float64) Coordinate {
+		return Coordinate{
+			X: l.Coordinate.X + length.X*alongLength + width.X*alongWidth,
+			Y: l.Coordinate.Y + length.Y*alongLength + width.Y*alongWidth,
+		}
+	}
+
+	return Polygon{
+		corner(-l.Length/2, -l.Width/2),
+		corner(l.Length/2, -l.Width/2),
+		corner(l.Length/2, l.Width/2),
+		corner(-l.Length/2, l.Width/2),
+	}
+}
+
+// Bounds returns the axis-aligned rectangle around a location, its coordinate
+// being the centre.
+func (l Location) Bounds() (minX, maxX, minY, maxY float64) {
+	halfX, halfY := l.HalfExtents()
+	return l.Coordinate.X - halfX, l.Coordinate.X + halfX,
+		l.Coordinate.Y - halfY, l.Coordinate.Y + halfY
+}
+
+// Obstacle is a polygonal area of the site no facility may cover.
+type Obstacle struct {
+	Name    string
+	Polygon Polygon
+	// Phases are the indexes of the phases the obstacle exists in, every
+	// phase when empty
+	Phases []int
+}
+
+// Site is the boundary of a construction site and its obstacles.
+type Site struct {
+	Boundary  Polygon
+	Obstacles []Obstacle
+}
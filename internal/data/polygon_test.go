@@ -0,0 +1,71 @@
+package data
+
+import (
+	"math"
+	"testing"
+)
+
+func TestPolygonRectangleArea(t *testing.T) {
+	// an L-shaped site missing its top right quarter
+	lShape := Polygon{{0, 0}, {10, 0}, {10, 5}, {5, 5}, {5, 10}, {0, 10}}
+
+	tests := []struct {
+		name                   string
+		minX, maxX, minY, maxY float64
+		expected               float64
+	}{
+		{"inside", 1, 3, 1, 3, 4},
+		{"in the missing quarter", 6, 8, 6, 8, 0},
+		{"across the notch", 4, 6, 4, 6, 3},
+		{"half outside", -1, 1, 1, 3, 2},
+	}
+
+	if area := lShape.Area(); area != 75 {
+		t.Errorf("Area() = %f; want 75", area)
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := lShape.RectangleArea(tt.minX, tt.maxX, tt.minY, tt.maxY)
+			if math.Abs(result-tt.expected) > 1e-9 {
+				t.Errorf("RectangleArea() = %f; want %f", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestOrientedLocation(t *testing.T) {
+	diamond := Location{Coordinate: Coordinate{X: 0, Y: 0}, Length: 2, Width: 2, Angle: 45}
+
+	halfX, halfY := diamond.HalfExtents()
+	if math.Abs(halfX-math.Sqrt2) > 1e-9 || math.Abs(halfY-math.Sqrt2) > 1e-9 {
+		t.Errorf("HalfExtents() = %f, %f; want %f", halfX, halfY, math.Sqrt2)
+	}
+
+	if area := diamond.Corners().Area(); math.Abs(area-4) > 1e-9 {
+		t.Errorf("Corners().Area() = %f; want 4", area)
+	}
+
+	// the nearest corner (1, 0.5) of the square is inside the bounding box of
+	// the diamond but not the diamond itself
+	square := Location{Coordinate: Coordinate{X: 2, Y: 1.5}, Length: 2, Width: 2}
+	apart := false
+	for _, overlap := range diamond.Corners().SeparatingAxisOverlaps(square.Corners()) {
+		if overlap <= 0 {
+			apart = true
+		}
+	}
+	if !apart {
+		t.Errorf("expected a separating axis between the diamond and the square")
+	}
+
+	overlaps := diamond.Corners().SeparatingAxisOverlaps(Location{Length: 1, Width: 1}.Corners())
+	if len(overlaps) != 4 {
+		t.Errorf("expected 4 distinct axes, got %d", len(overlaps))
+	}
+
+	clipped := Polygon{{0, 0}, {10, 0}, {10, 10}, {0, 10}}.ClipConvex(diamond.Corners())
+	if area := clipped.Area(); math.Abs(area-1) > 1e-9 {
+		t.Errorf("ClipConvex().Area() = %f; want a quarter of the diamond", area)
+	}
+}
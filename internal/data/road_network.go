@@ -0,0 +1,129 @@
+package data
+
+import (
+	"math"
+)
+
+// RoadNetwork is the graph of the roads of a site, each road a polyline.
+// Roads are connected where they share a vertex.
+type RoadNetwork struct {
+	Nodes    []Coordinate
+	Segments [][2]int
+	// paths[i][j] is the length of the shortest path between the nodes i and
+	// j, +Inf when they are not connected
+	paths [][]float64
+}
+
+// NewRoadNetwork returns the network of roads, each road the vertices of a
+// polyline.
+func NewRoadNetwork(roads [][]Coordinate) *RoadNetwork {
+	network := &RoadNetwork{}
+
+	nodeIdx := make(map[[2]float64]int)
+	nodeOf := func(c Coordinate) int {
+		// vertices within a micrometre are the same node
+		key := [2]float64{math.Round(c.X * 1e6), math.Round(c.Y * 1e6)}
+		if idx, ok := nodeIdx[key]; ok {
+			return idx
+		}
+		nodeIdx[key] = len(network.Nodes)
+		network.Nodes = append(network.Nodes, c)
+		return nodeIdx[key]
+	}
+
+	for _, road := range roads {
+		for i := 1; i < len(road); i++ {
+			from, to := nodeOf(road[i-1]), nodeOf(road[i])
+			if from != to {
+				network.Segments = append(network.Segments, [2]int{from, to})
+			}
+		}
+	}
+
+	// a site has few road vertices, all the shortest paths are computed once
+	n := len(network.Nodes)
+	network.paths = make([][]float64, n)
+	for i := range network.paths {
+		network.paths[i] = make([]float64, n)
+		for j := range network.paths[i] {
+			if i != j {
+				network.paths[i][j] = math.Inf(1)
+			}
+		}
+	}
+	for _, segment := range network.Segments {
+		length := Distance2D(network.Nodes[segment[0]], network.Nodes[segment[1]])
+		from, to := segment[0], segment[1]
+		network.paths[from][to] = min(network.paths[from][to], length)
+		network.paths[to][from] = network.paths[from][to]
+	}
+	for k := 0; k < n; k++ {
+		for i := 0; i < n; i++ {
+			for j := 0; j < n; j++ {
+				network.paths[i][j] = min(network.paths[i][j], network.paths[i][k]+network.paths[k][j])
+			}
+		}
+	}
+
+	return network
+}
+
+// Distance returns the distance travelled from a to b: from a straight to its
+// nearest access point on the roads, along the shortest path on the roads,
+// then straight to b. It is the straight-line distance when there are no
+// roads or the access points are not connected.
+func (n *RoadNetwork) Distance(a, b Coordinate) float64 {
+	if len(n.Segments) == 0 {
+		return Distance2D(a, b)
+	}
+
+	segmentA, accessA := n.access(a)
+	segmentB, accessB := n.access(b)
+
+	// on the same segment, straight along it
+	along := math.Inf(1)
+	if segmentA == segmentB {
+		along = Distance2D(accessA, accessB)
+	}
+
+	for _, from := range n.Segments[segmentA] {
+		for _, to := range n.Segments[segmentB] {
+			along = min(along, Distance2D(accessA, n.Nodes[from])+n.paths[from][to]+Distance2D(n.Nodes[to], accessB))
+		}
+	}
+
+	if math.IsInf(along, 1) {
+		return Distance2D(a, b)
+	}
+
+	return Distance2D(a, accessA) + along + Distance2D(accessB, b)
+}
+
+// access returns the segment nearest to p and the point of it nearest to p.
+func (n *RoadNetwork) access(p Coordinate) (int, Coordinate) {
+	nearest, access := 0, Coordinate{}
+	best := math.Inf(1)
+
+	for i, segment := range n.Segments {
+		point := nearestOnSegment(p, n.Nodes[segment[0]], n.Nodes[segment[1]])
+		if d := Distance2D(p, point); d < best {
+			nearest, access, best = i, point, d
+		}
+	}
+
+	return nearest, access
+}
+
+// nearestOnSegment returns the point of the segment from a to b nearest to p.
+func nearestOnSegment(p, a, b Coordinate) Coordinate {
+	ab := Coordinate{X: b.X - a.X, Y: b.Y - a.Y}
+	lengthSquared := ab.Dot(ab)
+	if lengthSquared == 0 {
+		return a
+	}
+
+	t := (Coordinate{X: p.X - a.X, Y: p.Y - a.Y}).Dot(ab) / lengthSquared
+	t = max(0, min(1, t))
+
+	return Coordinate{X: a.X + t*ab.X, Y: a.Y + t*ab.Y}
+}
@@ -0,0 +1,51 @@
+package data
+
+import (
+	"math"
+	"testing"
+)
+
+func TestRoadNetworkDistance(t *testing.T) {
+	network := NewRoadNetwork([][]Coordinate{
+		// an L-shaped road
+		{{X: 0, Y: 0}, {X: 10, Y: 0}, {X: 10, Y: 10}},
+		// a branch joining it at a shared vertex
+		{{X: 10, Y: 10}, {X: 20, Y: 10}},
+		// a road on its own
+		{{X: 100, Y: 100}, {X: 110, Y: 100}},
+	})
+
+	if len(network.Nodes) != 6 || len(network.Segments) != 4 {
+		t.Fatalf("expected 6 nodes and 4 segments, got %d and %d", len(network.Nodes), len(network.Segments))
+	}
+
+	testTable := []struct {
+		name     string
+		a, b     Coordinate
+		expected float64
+	}{
+		// 1 to the road, 8 + 8 along it, 1 from it
+		{name: "around the corner", a: Coordinate{X: 2, Y: -1}, b: Coordinate{X: 11, Y: 8}, expected: 18},
+		// 1 to the road, 4 along it, 2 from it
+		{name: "same segment", a: Coordinate{X: 1, Y: 1}, b: Coordinate{X: 5, Y: -2}, expected: 7},
+		// 1 to the road, 10 + 10 + 5 along it, 1 from it
+		{name: "onto the branch", a: Coordinate{X: 0, Y: 1}, b: Coordinate{X: 15, Y: 11}, expected: 27},
+		{name: "not connected", a: Coordinate{X: 0, Y: 1}, b: Coordinate{X: 105, Y: 101}, expected: Distance2D(Coordinate{X: 0, Y: 1}, Coordinate{X: 105, Y: 101})},
+	}
+
+	for _, test := range testTable {
+		t.Run(test.name, func(t *testing.T) {
+			if d := network.Distance(test.a, test.b); math.Abs(d-test.expected) > 1e-9 {
+				t.Errorf("expected %f, got %f", test.expected, d)
+			}
+		})
+	}
+
+	if d := NewRoadNetwork(nil).Distance(Coordinate{X: 0, Y: 0}, Coordinate{X: 3, Y: 4}); d != 5 {
+		t.Errorf("expected the straight-line distance without roads, got %f", d)
+	}
+
+	if d := (ManhattanDistance{}).Distance(Coordinate{X: 0, Y: 0}, Coordinate{X: 3, Y: -4}); d != 7 {
+		t.Errorf("expected a Manhattan distance of 7, got %f", d)
+	}
+}
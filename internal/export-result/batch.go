@@ -0,0 +1,301 @@
+// Package export_result provides functionality for exporting optimization results to Excel files.
+package export_result
+
+import (
+	"fmt"
+	"github.com/xuri/excelize/v2"
+	"golang-moaha-construction/internal/algorithms"
+	"golang-moaha-construction/internal/data"
+	"golang-moaha-construction/internal/statistics"
+	"golang-moaha-construction/internal/util"
+	"math"
+	"time"
+)
+
+// BatchAlgorithm is one of the algorithm configs of a batch experiment.
+type BatchAlgorithm struct {
+	Label string
+	Name  algorithms.AlgorithmType
+	Info  algorithms.Info
+}
+
+// BatchRun is one completed run of a batch experiment.
+type BatchRun struct {
+	Label   string
+	Run     int
+	Seed    int64
+	Time    time.Duration
+	Results algorithms.Result
+}
+
+// BatchOptions holds all the parameters needed for exporting a batch experiment
+type BatchOptions struct {
+	Summary    Summary
+	Algorithms []BatchAlgorithm
+	Runs       []BatchRun
+	// Comparisons are written to their own sheet, see CompareBatch
+	Comparisons []statistics.Comparison
+	FilePath    string
+}
+
+var batchRunsHeader = []string{"Algorithm", "Run", "Seed", "Time (s)", "Solutions"}
+var batchStatisticsHeader = []string{"Algorithm", "Measure", "Best", "Worst", "Mean", "Median", "Std"}
+var batchArchivesHeader = []string{"Algorithm", "Run", "Solution"}
+
+// WriteXlsxBatch exports the runs of a batch experiment to a single Excel file:
+// the setup, statistics per algorithm, the statistical comparison, every run
+// and every final archive.
+func WriteXlsxBatch(option BatchOptions) error {
+
+	f := excelize.NewFile()
+	defer func() {
+		if err := f.Close(); err != nil {
+			fmt.Println(err)
+		}
+	}()
+
+	err := createStyles(f)
+	if err != nil {
+		return err
+	}
+
+	err = generateBatchSummary(f, option)
+	if err != nil {
+		return err
+	}
+
+	keys := batchObjectiveKeys(option.Runs)
+
+	err = generateBatchStatistics(f, option, keys)
+	if err != nil {
+		return err
+	}
+
+	err = generateBatchComparison(f, option.Comparisons)
+	if err != nil {
+		return err
+	}
+
+	err = generateBatchRuns(f, option.Runs, keys)
+	if err != nil {
+		return err
+	}
+
+	err = generateBatchArchives(f, option.Runs, keys)
+	if err != nil {
+		return err
+	}
+
+	err = f.SaveAs(option.FilePath)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// generateBatchSummary describes the problem and every algorithm config.
+func generateBatchSummary(f *excelize.File, option BatchOptions) error {
+	const SheetName = "Summary"
+
+	rowCount := 2
+	columnCount := 2
+	err := f.SetSheetName(f.GetSheetName(0), SheetName)
+	if err != nil {
+		return err
+	}
+
+	err = f.SetColWidth(SheetName, "B", "B", 40)
+	err = f.SetColWidth(SheetName, "C", "C", 80)
+	if err != nil {
+		return err
+	}
+
+	for _, algorithm := range option.Algorithms {
+		rowCount = sectionAlgorithm(f, algorithm.Info, algorithmLabel(algorithm), SheetName, rowCount, columnCount)
+	}
+	rowCount = sectionProblem(f, option.Summary.ProblemInfo, SheetName, rowCount, columnCount)
+	rowCount = sectionObjectives(f, option.Summary.Objectives, SheetName, rowCount, columnCount)
+	rowCount = sectionConstraints(f, option.Summary.Constraints, SheetName, rowCount, columnCount)
+
+	return nil
+}
+
+// generateBatchStatistics writes best, worst, mean, median and standard
+// deviation over the runs of every algorithm. Objectives use the best value
+// each run found for them.
+func generateBatchStatistics(f *excelize.File, option BatchOptions, keys []data.ObjectiveType) error {
+	const SheetName = "Statistics"
+
+	_, err := f.NewSheet(SheetName)
+	if err != nil {
+		return err
+	}
+
+	err = f.SetColWidth(SheetName, "A", "B", 30)
+	err = f.SetColWidth(SheetName, "C", "G", 20)
+	if err != nil {
+		return err
+	}
+
+	writeHeaderRow(f, SheetName, 1, batchStatisticsHeader)
+
+	rowCount := 2
+	for _, algorithm := range option.Algorithms {
+		runs := make([]BatchRun, 0)
+		for _, run := range option.Runs {
+			if run.Label == algorithm.Label {
+				runs = append(runs, run)
+			}
+		}
+
+		if len(runs) == 0 {
+			continue
+		}
+
+		measures := make([]string, 0, len(keys)+2)
+		values := make([][]float64, 0, len(keys)+2)
+		for i, key := range keys {
+			measures = append(measures, re.ReplaceAllString(string(key), ""))
+			values = append(values, make([]float64, len(runs)))
+			for j, run := range runs {
+				values[i][j] = bestObjectiveValue(run.Results, i)
+			}
+		}
+
+		measures = append(measures, "Time (s)", "Solutions")
+		times := make([]float64, len(runs))
+		solutions := make([]float64, len(runs))
+		for j, run := range runs {
+			times[j] = run.Time.Seconds()
+			solutions[j] = float64(len(run.Results.Result))
+		}
+		values = append(values, times, solutions)
+
+		for i, measure := range measures {
+			writeRow(f, SheetName, rowCount, []any{
+				algorithm.Label,
+				measure,
+				minOf(values[i]),
+				maxOf(values[i]),
+				util.Mean(values[i]),
+				util.Median(values[i]),
+				util.Std(values[i]),
+			})
+			rowCount++
+		}
+	}
+
+	return nil
+}
+
+// generateBatchRuns writes one row per run with the best value of every objective.
+func generateBatchRuns(f *excelize.File, runs []BatchRun, keys []data.ObjectiveType) error {
+	const SheetName = "Runs"
+
+	_, err := f.NewSheet(SheetName)
+	if err != nil {
+		return err
+	}
+
+	err = f.SetColWidth(SheetName, "A", "A", 30)
+	err = f.SetColWidth(SheetName, "B", "Z", 20)
+	if err != nil {
+		return err
+	}
+
+	header := append([]string(nil), batchRunsHeader...)
+	for _, key := range keys {
+		header = append(header, re.ReplaceAllString(string(key), ""))
+	}
+	writeHeaderRow(f, SheetName, 1, header)
+
+	for i, run := range runs {
+		row := []any{run.Label, run.Run, run.Seed, run.Time.Seconds(), len(run.Results.Result)}
+		for k := range keys {
+			row = append(row, bestObjectiveValue(run.Results, k))
+		}
+		writeRow(f, SheetName, i+2, row)
+	}
+
+	return nil
+}
+
+// generateBatchArchives writes the objective values of every solution in the
+// final archive of every run.
+func generateBatchArchives(f *excelize.File, runs []BatchRun, keys []data.ObjectiveType) error {
+	const SheetName = "Archives"
+
+	_, err := f.NewSheet(SheetName)
+	if err != nil {
+		return err
+	}
+
+	err = f.SetColWidth(SheetName, "A", "A", 30)
+	err = f.SetColWidth(SheetName, "B", "Z", 20)
+	if err != nil {
+		return err
+	}
+
+	header := append([]string(nil), batchArchivesHeader...)
+	for _, key := range keys {
+		header = append(header, re.ReplaceAllString(string(key), ""))
+	}
+	writeHeaderRow(f, SheetName, 1, header)
+
+	rowCount := 2
+	for _, run := range runs {
+		for i, result := range run.Results.Result {
+			row := []any{run.Label, run.Run, i + 1}
+			for _, v := range result.Value {
+				row = append(row, v)
+			}
+			writeRow(f, SheetName, rowCount, row)
+			rowCount++
+		}
+	}
+
+	return nil
+}
+
+func algorithmLabel(algorithm BatchAlgorithm) algorithms.AlgorithmType {
+	if algorithm.Label == string(algorithm.Name) {
+		return algorithm.Name
+	}
+
+	return algorithms.AlgorithmType(fmt.Sprintf("%s (%s)", algorithm.Label, algorithm.Name))
+}
+
+// batchObjectiveKeys returns the objective names of the first run with results.
+func batchObjectiveKeys(runs []BatchRun) []data.ObjectiveType {
+	for _, run := range runs {
+		if len(run.Results.Result) > 0 {
+			return run.Results.Result[0].Key
+		}
+	}
+
+	return nil
+}
+
+// bestObjectiveValue returns the lowest value of objective i over all
+// solutions of results.
+func bestObjectiveValue(results algorithms.Result, i int) float64 {
+	best := math.Inf(1)
+	for _, result := range results.Result {
+		if i < len(result.Value) {
+			best = min(best, result.Value[i])
+		}
+	}
+
+	return best
+}
+
+func minOf(values []float64) float64 {
+	res, _ := util.MinWithIdx(values)
+	return res
+}
+
+func maxOf(values []float64) float64 {
+	res, _ := util.MaxWithIdx(values)
+	return res
+}
@@ -5,6 +5,7 @@ import (
 	"github.com/xuri/excelize/v2"
 	"golang-moaha-construction/internal/algorithms"
 	"golang-moaha-construction/internal/data"
+	"golang-moaha-construction/internal/objectives"
 	"regexp"
 )
 
@@ -17,15 +18,19 @@ var contentBoldStyle int
 var re = regexp.MustCompile(`(?i)objective`) // (?i) = case-insensitive
 
 // Headers for result sheets
-var locationHeader = []string{"Name", "Symbol", "x", "y", "Rotated", "Length", "Width", "Fixed"}
+var locationHeader = []string{"Name", "Symbol", "x", "y", "Rotated", "Length", "Width", "Fixed", "Angle"}
 var locationHeaderPredetermined = []string{"Symbol", "Is Located At"}
+var phaseLocationHeader = []string{"Symbol", "x", "y", "Rotated", "Angle"}
+var pairViolationHeader = []string{"Phase", "Facility", "Facility", "Rule", "Value", "Violation"}
 
 // Summary holds information about the algorithm, constraints, problem, and objectives
 type Summary struct {
-	AlgorithmInfo   any
-	ConstraintsInfo any
-	ProblemInfo     any
-	ObjectivesInfo  any
+	AlgorithmInfo algorithms.Info
+	Constraints   map[data.ConstraintType]data.Constrainter
+	ProblemInfo   any
+	Objectives    map[data.ObjectiveType]data.Objectiver
+	// Penalty is the penalty schedule of the run and its final factors
+	Penalty objectives.PenaltyReport
 }
 
 // Options holds all the parameters needed for exporting results
@@ -46,4 +51,22 @@ func writeContentWithValue(f *excelize.File, colCount, rowCount int, sheetName s
 	cell, _ = excelize.CoordinatesToCellName(colCount+1, rowCount)
 	_ = f.SetCellValue(sheetName, cell, value)
 	_ = f.SetCellStyle(sheetName, cell, cell, contentStyle)
-}
\ No newline at end of file
+}
+
+// writeHeaderRow writes header into the given row of the sheet
+func writeHeaderRow(f *excelize.File, sheetName string, rowCount int, header []string) {
+	for i, h := range header {
+		cell, _ := excelize.CoordinatesToCellName(i+1, rowCount)
+		_ = f.SetCellValue(sheetName, cell, h)
+		_ = f.SetCellStyle(sheetName, cell, cell, headerStyle)
+	}
+}
+
+// writeRow writes the values of row into the given row of the sheet
+func writeRow(f *excelize.File, sheetName string, rowCount int, row []any) {
+	for i, value := range row {
+		cell, _ := excelize.CoordinatesToCellName(i+1, rowCount)
+		_ = f.SetCellValue(sheetName, cell, value)
+		_ = f.SetCellStyle(sheetName, cell, cell, contentStyle)
+	}
+}
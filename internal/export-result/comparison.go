@@ -0,0 +1,259 @@
+// Package export_result provides functionality for exporting optimization results to Excel files.
+package export_result
+
+import (
+	"fmt"
+	"github.com/xuri/excelize/v2"
+	"golang-moaha-construction/internal/indicators"
+	"golang-moaha-construction/internal/objectives"
+	"golang-moaha-construction/internal/statistics"
+	"math"
+	"slices"
+	"strings"
+)
+
+var comparisonSummaryHeader = []string{"Algorithm", "Runs", "Best", "Worst", "Mean", "Median", "Std", "Mean rank"}
+var comparisonPairsHeader = []string{"A", "B", "U", "p (rank-sum)", "V", "p (signed-rank)", "r", "A12", "Effect", "A vs B", "Nemenyi"}
+
+// indicatorMeasures are the indicators compared for multi-objective batches,
+// hypervolume being the only one where higher is better.
+var indicatorMeasures = []struct {
+	name     string
+	maximize bool
+	value    func(v indicators.Values) float64
+}{
+	{"HV", true, func(v indicators.Values) float64 { return v.Hypervolume }},
+	{"IGD+", false, func(v indicators.Values) float64 { return v.IGDPlus }},
+	{"IGD", false, func(v indicators.Values) float64 { return v.IGD }},
+	{"GD", false, func(v indicators.Values) float64 { return v.GD }},
+	{"Spread", false, func(v indicators.Values) float64 { return v.Spread }},
+}
+
+// CompareBatch compares the algorithms of a batch with statistics.Compare.
+//
+// Multi-objective runs are compared on the indicators of their final
+// archives, all evaluated against the non-dominated union of every final
+// archive of the batch. Single-objective runs are compared on the best value
+// of every objective. Only the run numbers completed by every algorithm are
+// used, so that runs with the same seed are paired.
+func CompareBatch(option BatchOptions) []statistics.Comparison {
+	labels := make([]string, 0, len(option.Algorithms))
+	byLabel := make(map[string][]BatchRun)
+	for _, algorithm := range option.Algorithms {
+		for _, run := range option.Runs {
+			if run.Label == algorithm.Label {
+				byLabel[algorithm.Label] = append(byLabel[algorithm.Label], run)
+			}
+		}
+
+		if len(byLabel[algorithm.Label]) > 0 {
+			labels = append(labels, algorithm.Label)
+		}
+	}
+
+	if len(labels) < 2 {
+		return nil
+	}
+
+	// keep the run numbers every algorithm has
+	counts := make(map[int]int)
+	for _, label := range labels {
+		for _, run := range byLabel[label] {
+			counts[run.Run]++
+		}
+	}
+
+	runs := make([][]BatchRun, len(labels))
+	multiObjective := true
+	for i, label := range labels {
+		for _, run := range byLabel[label] {
+			if counts[run.Run] == len(labels) {
+				runs[i] = append(runs[i], run)
+				multiObjective = multiObjective && len(run.Results.Indicators) > 0
+			}
+		}
+
+		slices.SortFunc(runs[i], func(a, b BatchRun) int {
+			return a.Run - b.Run
+		})
+	}
+
+	if len(runs[0]) == 0 {
+		return nil
+	}
+
+	if multiObjective {
+		return compareIndicators(labels, runs)
+	}
+
+	keys := batchObjectiveKeys(option.Runs)
+	comparisons := make([]statistics.Comparison, 0, len(keys))
+	for k, key := range keys {
+		samples := make([][]float64, len(labels))
+		for i := range labels {
+			samples[i] = make([]float64, len(runs[i]))
+			for j, run := range runs[i] {
+				samples[i][j] = bestObjectiveValue(run.Results, k)
+			}
+		}
+
+		comparisons = append(comparisons, statistics.Compare(strings.TrimSpace(re.ReplaceAllString(string(key), "")), false, labels, samples))
+	}
+
+	return comparisons
+}
+
+func compareIndicators(labels []string, runs [][]BatchRun) []statistics.Comparison {
+	fronts := make([][]*objectives.Result, 0)
+	for i := range labels {
+		for _, run := range runs[i] {
+			front := make([]*objectives.Result, len(run.Results.Result))
+			for j, result := range run.Results.Result {
+				front[j] = &objectives.Result{Value: result.Value}
+				// a penalized member is infeasible and left out of the indicators
+				for _, penalty := range result.Penalty {
+					front[j].Violation += penalty
+				}
+			}
+			fronts = append(fronts, front)
+		}
+	}
+
+	values := indicators.Evaluate(fronts)
+
+	comparisons := make([]statistics.Comparison, 0, len(indicatorMeasures))
+	for _, measure := range indicatorMeasures {
+		samples := make([][]float64, len(labels))
+		idx := 0
+		for i := range labels {
+			samples[i] = make([]float64, len(runs[i]))
+			for j := range runs[i] {
+				samples[i][j] = measure.value(values[idx])
+				idx++
+			}
+		}
+
+		comparisons = append(comparisons, statistics.Compare(measure.name, measure.maximize, labels, samples))
+	}
+
+	return comparisons
+}
+
+// generateBatchComparison writes one section per compared measure: the
+// summary of every algorithm with its Friedman mean rank, the Friedman test,
+// and the pairwise tests.
+func generateBatchComparison(f *excelize.File, comparisons []statistics.Comparison) error {
+	const SheetName = "Comparison"
+
+	if len(comparisons) == 0 {
+		return nil
+	}
+
+	_, err := f.NewSheet(SheetName)
+	if err != nil {
+		return err
+	}
+
+	err = f.SetColWidth(SheetName, "A", "B", 30)
+	err = f.SetColWidth(SheetName, "C", "K", 18)
+	if err != nil {
+		return err
+	}
+
+	rowCount := 1
+	for _, comparison := range comparisons {
+		cell, _ := excelize.CoordinatesToCellName(1, rowCount)
+		_ = f.SetCellValue(SheetName, cell, fmt.Sprintf("%s (%s is better)", comparison.Measure, direction(comparison.Maximize)))
+		_ = f.SetCellStyle(SheetName, cell, cell, subHeaderStyle)
+		rowCount++
+
+		writeHeaderRow(f, SheetName, rowCount, comparisonSummaryHeader)
+		rowCount++
+
+		for i, summary := range comparison.Summaries {
+			rank := math.NaN()
+			if i < len(comparison.Friedman.MeanRanks) {
+				rank = comparison.Friedman.MeanRanks[i]
+			}
+
+			writeRow(f, SheetName, rowCount, []any{
+				comparison.Algorithms[i],
+				summary.Runs,
+				number(summary.Best),
+				number(summary.Worst),
+				number(summary.Mean),
+				number(summary.Median),
+				number(summary.Std),
+				number(rank),
+			})
+			rowCount++
+		}
+
+		rowCount++
+		writeContentWithValue(f, 1, rowCount, SheetName, "Friedman chi-square", number(comparison.Friedman.Statistic))
+		writeContentWithValue(f, 1, rowCount+1, SheetName, "Friedman p-value", number(comparison.Friedman.PValue))
+		writeContentWithValue(f, 1, rowCount+2, SheetName, "Nemenyi critical difference (alpha 0.05)", number(comparison.Friedman.CriticalDifference))
+		rowCount += 4
+
+		writeHeaderRow(f, SheetName, rowCount, comparisonPairsHeader)
+		rowCount++
+
+		for _, pair := range comparison.Pairs {
+			writeRow(f, SheetName, rowCount, []any{
+				comparison.Algorithms[pair.A],
+				comparison.Algorithms[pair.B],
+				number(pair.RankSum.Statistic),
+				number(pair.RankSum.PValue),
+				number(pair.SignedRank.Statistic),
+				number(pair.SignedRank.PValue),
+				number(pair.RankSum.EffectSize),
+				number(pair.A12),
+				pair.Magnitude,
+				outcome(pair.Outcome),
+				significance(pair.Nemenyi),
+			})
+			rowCount++
+		}
+
+		rowCount += 2
+	}
+
+	return nil
+}
+
+func direction(maximize bool) string {
+	if maximize {
+		return "higher"
+	}
+
+	return "lower"
+}
+
+// outcome shows whether A is significantly better (+), worse (-) or neither (≈) than B.
+func outcome(outcome int) string {
+	switch outcome {
+	case 1:
+		return "+"
+	case -1:
+		return "-"
+	default:
+		return "≈"
+	}
+}
+
+func significance(significant bool) string {
+	if significant {
+		return "significant"
+	}
+
+	return "not significant"
+}
+
+// number leaves values that are not finite empty, Excel has no cell for them.
+func number(value float64) any {
+	if math.IsNaN(value) || math.IsInf(value, 0) {
+		return ""
+	}
+
+	return value
+}
@@ -0,0 +1,125 @@
+// Package export_result provides functionality for exporting optimization results to Excel files.
+package export_result
+
+import (
+	"fmt"
+	"golang-moaha-construction/internal/statistics"
+	"math"
+	"os"
+	"strings"
+)
+
+var latexEscaper = strings.NewReplacer(
+	`\`, `\textbackslash{}`,
+	`&`, `\&`,
+	`%`, `\%`,
+	`$`, `\$`,
+	`#`, `\#`,
+	`_`, `\_`,
+	`{`, `\{`,
+	`}`, `\}`,
+	`~`, `\textasciitilde{}`,
+	`^`, `\textasciicircum{}`,
+)
+
+// WriteLatexComparison writes two tables per compared measure to filePath:
+// the summary of every algorithm, the best mean in bold, and the pairwise
+// tests. The tables only need the standard LaTeX packages.
+func WriteLatexComparison(comparisons []statistics.Comparison, filePath string) error {
+	var b strings.Builder
+
+	for _, comparison := range comparisons {
+		writeLatexSummary(&b, comparison)
+		writeLatexPairs(&b, comparison)
+	}
+
+	return os.WriteFile(filePath, []byte(b.String()), 0644)
+}
+
+func writeLatexSummary(b *strings.Builder, comparison statistics.Comparison) {
+	best := -1
+	for i, summary := range comparison.Summaries {
+		if math.IsNaN(summary.Mean) {
+			continue
+		}
+		if best < 0 || (comparison.Maximize && summary.Mean > comparison.Summaries[best].Mean) ||
+			(!comparison.Maximize && summary.Mean < comparison.Summaries[best].Mean) {
+			best = i
+		}
+	}
+
+	measure := latexEscaper.Replace(comparison.Measure)
+
+	b.WriteString("\\begin{table}[htbp]\n\\centering\n")
+	fmt.Fprintf(b, "\\caption{%s (%s is better). Friedman $\\chi^2 = %s$, $p = %s$; Nemenyi critical difference $%s$ ($\\alpha = %g$).}\n",
+		measure, direction(comparison.Maximize),
+		latexNumber(comparison.Friedman.Statistic), latexNumber(comparison.Friedman.PValue),
+		latexNumber(comparison.Friedman.CriticalDifference), statistics.Alpha)
+	b.WriteString("\\begin{tabular}{lrrrrrr}\n\\hline\n")
+	b.WriteString("Algorithm & Runs & Best & Mean & Median & Std & Mean rank \\\\\n\\hline\n")
+
+	for i, summary := range comparison.Summaries {
+		mean := latexNumber(summary.Mean)
+		if i == best {
+			mean = "\\textbf{" + mean + "}"
+		}
+
+		rank := math.NaN()
+		if i < len(comparison.Friedman.MeanRanks) {
+			rank = comparison.Friedman.MeanRanks[i]
+		}
+
+		fmt.Fprintf(b, "%s & %d & %s & %s & %s & %s & %s \\\\\n",
+			latexEscaper.Replace(comparison.Algorithms[i]), summary.Runs,
+			latexNumber(summary.Best), mean, latexNumber(summary.Median), latexNumber(summary.Std),
+			latexNumber(rank))
+	}
+
+	b.WriteString("\\hline\n\\end{tabular}\n\\end{table}\n\n")
+}
+
+func writeLatexPairs(b *strings.Builder, comparison statistics.Comparison) {
+	if len(comparison.Pairs) == 0 {
+		return
+	}
+
+	b.WriteString("\\begin{table}[htbp]\n\\centering\n")
+	fmt.Fprintf(b, "\\caption{Pairwise comparison on %s. $+$, $-$ and $\\approx$: A significantly better, worse or neither by the Wilcoxon rank-sum test ($\\alpha = %g$).}\n",
+		latexEscaper.Replace(comparison.Measure), statistics.Alpha)
+	b.WriteString("\\begin{tabular}{llrrrlcc}\n\\hline\n")
+	b.WriteString("A & B & $p$ (rank-sum) & $p$ (signed-rank) & $\\hat{A}_{12}$ & Effect & A vs B & Nemenyi \\\\\n\\hline\n")
+
+	for _, pair := range comparison.Pairs {
+		nemenyi := ""
+		if pair.Nemenyi {
+			nemenyi = "$*$"
+		}
+
+		fmt.Fprintf(b, "%s & %s & %s & %s & %s & %s & %s & %s \\\\\n",
+			latexEscaper.Replace(comparison.Algorithms[pair.A]),
+			latexEscaper.Replace(comparison.Algorithms[pair.B]),
+			latexNumber(pair.RankSum.PValue), latexNumber(pair.SignedRank.PValue),
+			latexNumber(pair.A12), pair.Magnitude, latexOutcome(pair.Outcome), nemenyi)
+	}
+
+	b.WriteString("\\hline\n\\end{tabular}\n\\end{table}\n\n")
+}
+
+func latexNumber(value float64) string {
+	if math.IsNaN(value) || math.IsInf(value, 0) {
+		return "--"
+	}
+
+	return fmt.Sprintf("%.4g", value)
+}
+
+func latexOutcome(outcome int) string {
+	switch outcome {
+	case 1:
+		return "$+$"
+	case -1:
+		return "$-$"
+	default:
+		return "$\\approx$"
+	}
+}
@@ -17,6 +17,49 @@ func WriteXlsxResult(option Options) error {
 		}
 	}()
 
+	err := createStyles(f)
+	if err != nil {
+		return err
+	}
+
+	err = generateSheet1Info(f, option.Summary, option.AlgorithmName)
+	if err != nil {
+		return err
+	}
+
+	if option.ProblemName == conslay_predetermined.PredeterminedConsLayoutName {
+		err = generateSheet2ResultsPredetermined(f, option.Results)
+		if err != nil {
+			return err
+		}
+	} else {
+		err = generateSheet2Results(f, option.Results, option.Summary.Constraints)
+		if err != nil {
+			return err
+		}
+	}
+
+	// pareto
+	err = generateSheet3Graph(f, option.Results, option.NumberOfObjectives)
+	if err != nil {
+		return err
+	}
+
+	err = generateSheet4Indicators(f, option.Results)
+	if err != nil {
+		return err
+	}
+
+	err = f.SaveAs(option.FilePath)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// createStyles registers the styles shared by all sheets in f.
+func createStyles(f *excelize.File) error {
 	var err error
 
 	// Create header style
@@ -162,33 +205,5 @@ func WriteXlsxResult(option Options) error {
 		return err
 	}
 
-	err = generateSheet1Info(f, option.Summary, option.AlgorithmName)
-	if err != nil {
-		return err
-	}
-
-	if option.ProblemName == conslay_predetermined.PredeterminedConsLayoutName {
-		err = generateSheet2ResultsPredetermined(f, option.Results)
-		if err != nil {
-			return err
-		}
-	} else {
-		err = generateSheet2Results(f, option.Results)
-		if err != nil {
-			return err
-		}
-	}
-
-	// pareto
-	err = generateSheet3Graph(f, option.Results, option.NumberOfObjectives)
-	if err != nil {
-		return err
-	}
-
-	err = f.SaveAs(option.FilePath)
-	if err != nil {
-		return err
-	}
-
 	return nil
-}
\ No newline at end of file
+}
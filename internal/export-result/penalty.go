@@ -0,0 +1,65 @@
+package export_result
+
+import (
+	"github.com/xuri/excelize/v2"
+	"golang-moaha-construction/internal/constraints"
+	"golang-moaha-construction/internal/data"
+	"golang-moaha-construction/internal/objectives"
+	"math"
+)
+
+// sectionPenalty adds the penalty schedule to the summary sheet with the
+// coefficient every constraint ended the run with
+func sectionPenalty(f *excelize.File, penalty objectives.PenaltyReport, cons map[data.ConstraintType]data.Constrainter, sheetName string, rowCount int, colCount int) int {
+	if len(cons) == 0 {
+		return rowCount
+	}
+
+	// Add header
+	cell, _ := excelize.CoordinatesToCellName(colCount, rowCount)
+	endCell, _ := excelize.CoordinatesToCellName(colCount+1, rowCount)
+	_ = f.MergeCell(sheetName, cell, endCell)
+	_ = f.SetCellValue(sheetName, cell, "Penalty Schedule")
+	_ = f.SetCellStyle(sheetName, cell, cell, headerStyle)
+	rowCount++
+
+	w := &summaryWriter{f: f, sheetName: sheetName, rowCount: rowCount, colCount: colCount}
+	schedule := penalty.Schedule
+	w.Value("Schedule", string(schedule.Type))
+	switch schedule.Type {
+	case objectives.DynamicPenalty:
+		w.Value("C", schedule.C)
+		w.Value("Alpha", schedule.Alpha)
+	case objectives.AnnealingPenalty:
+		w.Value("Initial temperature", schedule.InitialTemperature)
+		w.Value("Cooling rate", schedule.CoolingRate)
+	case objectives.SelfAdaptivePenalty:
+		w.Value("Target feasible ratio", schedule.TargetFeasibleRatio)
+		w.Value("Adaptation rate", schedule.AdaptationRate)
+	}
+	if schedule.Tolerance > 0 {
+		w.Value("Tolerance", schedule.Tolerance)
+	}
+
+	w.Group("Final coefficients")
+	for _, r := range constraints.Registered() {
+		con, ok := cons[r.Name]
+		if !ok {
+			continue
+		}
+
+		factor, ok := penalty.Factors[r.Name]
+		if !ok {
+			factor = 1
+		}
+
+		coefficient := con.GetAlphaPenalty() * factor
+		if math.IsInf(coefficient, 1) {
+			w.Value(r.Schema.Label, "infeasible rejected")
+		} else {
+			w.Value(r.Schema.Label, coefficient)
+		}
+	}
+
+	return w.rowCount + 2
+}
@@ -5,13 +5,17 @@ import (
 	"fmt"
 	"github.com/xuri/excelize/v2"
 	"golang-moaha-construction/internal/algorithms"
+	"golang-moaha-construction/internal/constraints"
+	"golang-moaha-construction/internal/data"
+	"golang-moaha-construction/internal/util"
 	"reflect"
+	"slices"
 )
 
 // Sheet 2 - Result
 
 // generateSheet2Results generates the results sheet with optimization results
-func generateSheet2Results(f *excelize.File, results algorithms.Result) error {
+func generateSheet2Results(f *excelize.File, results algorithms.Result, cons map[data.ConstraintType]data.Constrainter) error {
 	const SheetName = "Results"
 
 	// Starting point
@@ -144,9 +148,16 @@ func generateSheet2Results(f *excelize.File, results algorithms.Result) error {
 					_ = f.SetCellValue(SheetName, cell, locValue.FieldByName("IsFixed").Bool())
 					_ = f.SetCellStyle(SheetName, cell, cell, contentStyle)
 
+					cell, _ = excelize.CoordinatesToCellName(columnCount+8, rowCount)
+					_ = f.SetCellValue(SheetName, cell, locValue.FieldByName("Angle").Float())
+					_ = f.SetCellStyle(SheetName, cell, cell, contentStyle)
+
 					rowCount++
 				}
 			}
+
+			rowCount = sectionPhaseLocations(f, results.Result[i], SheetName, rowCount+1, columnCount)
+			rowCount = sectionPairViolations(f, cons, results.Result[i], SheetName, rowCount+1, columnCount)
 			rowCount += 2
 		}
 	}
@@ -154,6 +165,54 @@ func generateSheet2Results(f *excelize.File, results algorithms.Result) error {
 	return nil
 }
 
+// sectionPairViolations lists the pairs of facilities violating the
+// constraints that report their violations per pair
+func sectionPairViolations(f *excelize.File, cons map[data.ConstraintType]data.Constrainter, result algorithms.AlgorithmResult, sheetName string, rowCount int, colCount int) int {
+	for _, r := range constraints.Registered() {
+		reporter, ok := cons[r.Name].(data.PairViolationReporter)
+		if !ok {
+			continue
+		}
+
+		cell, _ := excelize.CoordinatesToCellName(colCount, rowCount)
+		_ = f.SetCellValue(sheetName, cell, r.Schema.Label+" violations")
+		_ = f.SetCellStyle(sheetName, cell, cell, headerStyle)
+		rowCount++
+
+		violations := reporter.PairViolations(result.MapLocations)
+		if result.PhaseLocations != nil {
+			violations = reporter.PhasePairViolations(result.PhaseLocations)
+		}
+		if len(violations) == 0 {
+			cell, _ = excelize.CoordinatesToCellName(colCount, rowCount)
+			_ = f.SetCellValue(sheetName, cell, "None")
+			_ = f.SetCellStyle(sheetName, cell, cell, contentStyle)
+			rowCount += 2
+			continue
+		}
+
+		for headerIdx, header := range pairViolationHeader {
+			cell, _ = excelize.CoordinatesToCellName(colCount+headerIdx, rowCount)
+			_ = f.SetCellValue(sheetName, cell, header)
+			_ = f.SetCellStyle(sheetName, cell, cell, subHeaderStyle)
+		}
+		rowCount++
+
+		for _, violation := range violations {
+			row := []any{violation.Phase + 1, violation.First, violation.Second, violation.Rule, violation.Value, violation.Amount}
+			for colIdx, value := range row {
+				cell, _ = excelize.CoordinatesToCellName(colCount+colIdx, rowCount)
+				_ = f.SetCellValue(sheetName, cell, value)
+				_ = f.SetCellStyle(sheetName, cell, cell, contentStyle)
+			}
+			rowCount++
+		}
+		rowCount++
+	}
+
+	return rowCount
+}
+
 // generateSheet2ResultsPredetermined generates the results sheet for predetermined construction layout
 func generateSheet2ResultsPredetermined(f *excelize.File, results algorithms.Result) error {
 	const SheetName = "Results"
@@ -265,3 +324,42 @@ func generateSheet2ResultsPredetermined(f *excelize.File, results algorithms.Res
 
 	return nil
 }
+
+// sectionPhaseLocations lists the locations of the facilities in every phase
+// of a dynamic layout, nothing for a static one.
+func sectionPhaseLocations(f *excelize.File, result algorithms.AlgorithmResult, sheetName string, rowCount int, colCount int) int {
+	if result.PhaseLocations == nil {
+		return rowCount - 1
+	}
+
+	for phaseIdx, phase := range result.Phases {
+		cell, _ := excelize.CoordinatesToCellName(colCount, rowCount)
+		_ = f.SetCellValue(sheetName, cell, fmt.Sprintf("Phase %d", phaseIdx+1))
+		_ = f.SetCellStyle(sheetName, cell, cell, headerStyle)
+		rowCount++
+
+		for headerIdx, header := range phaseLocationHeader {
+			cell, _ = excelize.CoordinatesToCellName(colCount+headerIdx, rowCount)
+			_ = f.SetCellValue(sheetName, cell, header)
+			_ = f.SetCellStyle(sheetName, cell, cell, subHeaderStyle)
+		}
+		rowCount++
+
+		symbols := slices.SortedFunc(slices.Values(phase), func(a, b string) int {
+			return util.ExtractNumber(a) - util.ExtractNumber(b)
+		})
+		for _, symbol := range symbols {
+			loc := result.PhaseLocations[phaseIdx][symbol]
+			row := []any{loc.Symbol, loc.Coordinate.X, loc.Coordinate.Y, loc.Rotation, loc.Angle}
+			for colIdx, value := range row {
+				cell, _ = excelize.CoordinatesToCellName(colCount+colIdx, rowCount)
+				_ = f.SetCellValue(sheetName, cell, value)
+				_ = f.SetCellStyle(sheetName, cell, cell, contentStyle)
+			}
+			rowCount++
+		}
+		rowCount++
+	}
+
+	return rowCount
+}
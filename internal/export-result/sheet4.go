@@ -0,0 +1,49 @@
+// Package export_result provides functionality for exporting optimization results to Excel files.
+package export_result
+
+import (
+	"github.com/xuri/excelize/v2"
+	"golang-moaha-construction/internal/algorithms"
+)
+
+// Sheet 4 - Indicators
+
+var indicatorsHeader = []string{"Iteration", "Archive Size", "HV", "IGD", "IGD+", "GD", "Spacing", "Spread", "C(current, previous)"}
+
+// generateSheet4Indicators generates the sheet with the performance indicators
+// of every iteration. It is only added for multi-objective results.
+func generateSheet4Indicators(f *excelize.File, results algorithms.Result) error {
+	const SheetName = "Indicators"
+
+	if len(results.Indicators) == 0 {
+		return nil
+	}
+
+	_, err := f.NewSheet(SheetName)
+	if err != nil {
+		return err
+	}
+
+	err = f.SetColWidth(SheetName, "A", "I", 20)
+	if err != nil {
+		return err
+	}
+
+	writeHeaderRow(f, SheetName, 1, indicatorsHeader)
+
+	for i, values := range results.Indicators {
+		writeRow(f, SheetName, i+2, []any{
+			values.Iteration,
+			values.ArchiveSize,
+			values.Hypervolume,
+			values.IGD,
+			values.IGDPlus,
+			values.GD,
+			values.Spacing,
+			values.Spread,
+			values.Coverage,
+		})
+	}
+
+	return nil
+}
@@ -0,0 +1,182 @@
+package indicators
+
+import (
+	"golang-moaha-construction/internal/objectives"
+	"math"
+	"sync"
+)
+
+// historyReferencePoint is the hypervolume reference point of History in
+// normalized space, slightly beyond the nadir so that the extreme solutions
+// still count.
+const historyReferencePoint = 1.1
+
+// historySamples is the number of Monte Carlo samples History uses per
+// iteration for more than three objectives.
+const historySamples = 10000
+
+// Values holds the indicators of the archive after one iteration.
+type Values struct {
+	Iteration int
+	// ArchiveSize is the number of feasible archive members with finite
+	// values, the only ones the indicators count
+	ArchiveSize int
+	Hypervolume float64
+	IGD         float64
+	IGDPlus     float64
+	GD          float64
+	Spacing     float64
+	Spread      float64
+	// Coverage is C(archive, archive of the previous iteration)
+	Coverage float64
+}
+
+// History records the archive of a multi-objective algorithm after every
+// iteration and computes the indicators once the run is over.
+//
+// The true Pareto front is unknown, so the reference front is the
+// non-dominated union of all recorded archives and every archive is
+// normalized against it. The hypervolume reference point is 1.1 in every
+// objective of the normalized space. Only the feasible members with finite
+// values are recorded, see Counted.
+type History struct {
+	Fronts [][][]float64
+
+	// values caches Values until the next Record or Reset
+	values []Values
+}
+
+// Reset forgets the recorded archives.
+func (h *History) Reset() {
+	h.Fronts = make([][][]float64, 0)
+	h.values = nil
+}
+
+// Record stores the objective values of archive as the next iteration.
+func (h *History) Record(archive []*objectives.Result) {
+	counted := Counted(archive)
+	front := make([][]float64, len(counted))
+	for i, res := range counted {
+		front[i] = append([]float64(nil), res.Value...)
+	}
+
+	h.Fronts = append(h.Fronts, front)
+	h.values = nil
+}
+
+// Values computes the indicators of every recorded iteration. They are
+// computed once and cached until the next Record.
+func (h *History) Values() []Values {
+	if len(h.Fronts) == 0 {
+		return nil
+	}
+
+	if h.values != nil {
+		return h.values
+	}
+
+	fronts := make([][]*objectives.Result, len(h.Fronts))
+	for i, front := range h.Fronts {
+		fronts[i] = make([]*objectives.Result, len(front))
+		for j, value := range front {
+			fronts[i][j] = &objectives.Result{Value: value}
+		}
+		// the checkpoints of earlier versions hold every archive member
+		fronts[i] = Counted(fronts[i])
+	}
+
+	res := Evaluate(fronts)
+	for i := range res {
+		res[i].Iteration = i + 1
+	}
+
+	normalization := NormalizationFromFront(NonDominated(fronts...))
+	for i := 1; i < len(fronts); i++ {
+		if len(fronts[i]) > 0 {
+			res[i].Coverage = Coverage(normalization.Apply(fronts[i]), normalization.Apply(fronts[i-1]))
+		}
+	}
+
+	h.values = res
+	return res
+}
+
+// Counted returns the members of front the indicators count: the feasible
+// ones with finite values. The values of an infeasible member include its
+// penalty, and a failed evaluation or the death penalty make them infinite,
+// which would distort or break the normalization.
+func Counted(front []*objectives.Result) []*objectives.Result {
+	counted := make([]*objectives.Result, 0, len(front))
+	for _, res := range front {
+		if res.Violation > 0 || !finite(res.Value) {
+			continue
+		}
+		counted = append(counted, res)
+	}
+
+	return counted
+}
+
+func finite(value []float64) bool {
+	for _, v := range value {
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Evaluate computes the indicators of every front against the non-dominated
+// union of all fronts, normalized like History. Indicators of fronts
+// evaluated together are comparable with each other, so this also serves to
+// compare the final archives of several runs. Only the members counted by
+// Counted take part. Iteration and Coverage are left zero.
+func Evaluate(fronts [][]*objectives.Result) []Values {
+	counted := make([][]*objectives.Result, len(fronts))
+	for i, front := range fronts {
+		counted[i] = Counted(front)
+	}
+	fronts = counted
+
+	reference := NonDominated(fronts...)
+	normalization := NormalizationFromFront(reference)
+	reference = normalization.Apply(reference)
+
+	var referencePoint []float64
+	if len(reference) > 0 {
+		referencePoint = make([]float64, len(reference[0].Value))
+		for i := range referencePoint {
+			referencePoint[i] = historyReferencePoint
+		}
+	}
+
+	res := make([]Values, len(fronts))
+
+	var wg sync.WaitGroup
+	wg.Add(len(fronts))
+	for i := range fronts {
+		go func(i int) {
+			defer wg.Done()
+
+			front := normalization.Apply(fronts[i])
+			// the distance based indicators are undefined for an empty archive
+			if len(front) == 0 {
+				return
+			}
+
+			res[i] = Values{
+				ArchiveSize: len(front),
+				Hypervolume: hypervolume(front, referencePoint, historySamples),
+				IGD:         IGD(front, reference),
+				IGDPlus:     IGDPlus(front, reference),
+				GD:          GD(front, reference),
+				Spacing:     Spacing(front),
+				Spread:      Spread(front, reference),
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	return res
+}
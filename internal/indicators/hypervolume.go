@@ -0,0 +1,147 @@
+package indicators
+
+import (
+	"golang-moaha-construction/internal/objectives"
+	"golang-moaha-construction/internal/util"
+	"math/rand/v2"
+	"sort"
+)
+
+// MonteCarloSamples is the number of samples Hypervolume draws for fronts with
+// more than three objectives.
+const MonteCarloSamples = 100000
+
+// monteCarloSeed keeps the estimate reproducible for a given front.
+const monteCarloSeed = 1
+
+// Hypervolume returns the volume of the objective space dominated by front and
+// bounded by referencePoint. Solutions that do not dominate the reference
+// point contribute nothing.
+//
+// It is exact for two and three objectives and a Monte Carlo estimate with
+// MonteCarloSamples samples beyond.
+func Hypervolume(front []*objectives.Result, referencePoint []float64) float64 {
+	return hypervolume(front, referencePoint, MonteCarloSamples)
+}
+
+func hypervolume(front []*objectives.Result, referencePoint []float64, samples int) float64 {
+	points := make([][]float64, 0, len(front))
+	for _, p := range values(front) {
+		if strictlyBelow(p, referencePoint) {
+			points = append(points, p)
+		}
+	}
+
+	if len(points) == 0 {
+		return 0
+	}
+
+	switch len(referencePoint) {
+	case 1:
+		best := points[0][0]
+		for _, p := range points {
+			best = min(best, p[0])
+		}
+		return referencePoint[0] - best
+	case 2:
+		return hypervolume2D(points, referencePoint)
+	case 3:
+		return hypervolume3D(points, referencePoint)
+	default:
+		return hypervolumeMonteCarlo(util.NewRand(monteCarloSeed), points, referencePoint, samples)
+	}
+}
+
+// hypervolume2D sweeps the points by the first objective and adds the
+// rectangle each point adds to the ones before it.
+func hypervolume2D(points [][]float64, ref []float64) float64 {
+	sorted := make([][]float64, len(points))
+	copy(sorted, points)
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i][0] == sorted[j][0] {
+			return sorted[i][1] < sorted[j][1]
+		}
+		return sorted[i][0] < sorted[j][0]
+	})
+
+	volume := 0.0
+	bestY := ref[1]
+	for _, p := range sorted {
+		if p[1] < bestY {
+			volume += (ref[0] - p[0]) * (bestY - p[1])
+			bestY = p[1]
+		}
+	}
+
+	return volume
+}
+
+// hypervolume3D slices the space along the third objective and adds the area
+// dominated in every slice.
+func hypervolume3D(points [][]float64, ref []float64) float64 {
+	sorted := make([][]float64, len(points))
+	copy(sorted, points)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i][2] < sorted[j][2]
+	})
+
+	volume := 0.0
+	for i := range sorted {
+		top := ref[2]
+		if i+1 < len(sorted) {
+			top = sorted[i+1][2]
+		}
+
+		if top == sorted[i][2] {
+			continue
+		}
+
+		volume += hypervolume2D(sorted[:i+1], ref) * (top - sorted[i][2])
+	}
+
+	return volume
+}
+
+// hypervolumeMonteCarlo samples the box between the best value of every
+// objective and the reference point and counts the dominated samples.
+func hypervolumeMonteCarlo(r *rand.Rand, points [][]float64, ref []float64, samples int) float64 {
+	lower := make([]float64, len(ref))
+	copy(lower, points[0])
+	for _, p := range points[1:] {
+		for i := range lower {
+			lower[i] = min(lower[i], p[i])
+		}
+	}
+
+	box := 1.0
+	for i := range ref {
+		box *= ref[i] - lower[i]
+	}
+
+	sample := make([]float64, len(ref))
+	dominated := 0
+	for s := 0; s < samples; s++ {
+		for i := range sample {
+			sample[i] = lower[i] + r.Float64()*(ref[i]-lower[i])
+		}
+
+		for _, p := range points {
+			if weaklyDominates(p, sample) {
+				dominated++
+				break
+			}
+		}
+	}
+
+	return box * float64(dominated) / float64(samples)
+}
+
+func strictlyBelow(p, ref []float64) bool {
+	for i := range p {
+		if p[i] >= ref[i] {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,245 @@
+// Package indicators measures the quality of the archives found by the
+// multi-objective algorithms. All objectives are minimized.
+//
+// Indicators that compare against the true Pareto front take a reference
+// front; when it is unknown, the non-dominated union of all fronts being
+// compared is the usual stand-in. Normalize both fronts with the same
+// Normalization before comparing algorithms whose objectives have different
+// scales.
+package indicators
+
+import (
+	"golang-moaha-construction/internal/objectives"
+	"math"
+)
+
+// GD is the generational distance: the mean distance from every solution of
+// front to its nearest point of reference.
+func GD(front, reference []*objectives.Result) float64 {
+	return meanNearest(values(front), values(reference), euclidean)
+}
+
+// IGD is the inverted generational distance: the mean distance from every
+// point of reference to its nearest solution of front.
+func IGD(front, reference []*objectives.Result) float64 {
+	return meanNearest(values(reference), values(front), euclidean)
+}
+
+// IGDPlus is IGD with the modified distance of Ishibuchi et al., which only
+// counts the objectives in which a solution is worse than the reference point.
+// It is weakly Pareto compliant.
+func IGDPlus(front, reference []*objectives.Result) float64 {
+	return meanNearest(values(reference), values(front), func(ref, point []float64) float64 {
+		sum := 0.0
+		for i := range ref {
+			d := math.Max(point[i]-ref[i], 0)
+			sum += d * d
+		}
+		return math.Sqrt(sum)
+	})
+}
+
+// Spacing is Schott's spacing: the standard deviation of the Manhattan
+// distance from every solution to its nearest neighbour. 0 means the
+// solutions are evenly spaced.
+func Spacing(front []*objectives.Result) float64 {
+	points := values(front)
+	if len(points) < 2 {
+		return 0
+	}
+
+	nearest := nearestNeighbours(points, manhattan)
+	mean := 0.0
+	for _, d := range nearest {
+		mean += d
+	}
+	mean /= float64(len(nearest))
+
+	sum := 0.0
+	for _, d := range nearest {
+		sum += (mean - d) * (mean - d)
+	}
+
+	return math.Sqrt(sum / float64(len(nearest)-1))
+}
+
+// Spread is Deb's Δ generalized to any number of objectives: it combines the
+// distance from the extreme points of reference to front with the deviation
+// of the nearest neighbour distances inside front. 0 means an even spread
+// that reaches the extremes.
+func Spread(front, reference []*objectives.Result) float64 {
+	points := values(front)
+	refPoints := values(reference)
+	if len(points) == 0 || len(refPoints) == 0 {
+		return 0
+	}
+
+	// one extreme per objective: the reference point that is best in it
+	extremes := 0.0
+	for m := range refPoints[0] {
+		best := refPoints[0]
+		for _, p := range refPoints[1:] {
+			if p[m] < best[m] {
+				best = p
+			}
+		}
+		extremes += nearest(best, points, euclidean)
+	}
+
+	if len(points) < 2 {
+		if extremes == 0 {
+			return 0
+		}
+		return 1
+	}
+
+	neighbours := nearestNeighbours(points, euclidean)
+	mean := 0.0
+	for _, d := range neighbours {
+		mean += d
+	}
+	mean /= float64(len(neighbours))
+
+	deviation := 0.0
+	for _, d := range neighbours {
+		deviation += math.Abs(d - mean)
+	}
+
+	denominator := extremes + float64(len(neighbours))*mean
+	if denominator == 0 {
+		return 0
+	}
+
+	return (extremes + deviation) / denominator
+}
+
+// Coverage is Zitzler's C-metric C(a, b): the fraction of b that is weakly
+// dominated by at least one solution of a. C(a, b) = 1 means a covers b
+// completely. It is not symmetric, so compute both C(a, b) and C(b, a).
+func Coverage(a, b []*objectives.Result) float64 {
+	if len(b) == 0 {
+		return 0
+	}
+
+	aPoints := values(a)
+	covered := 0
+	for _, q := range values(b) {
+		for _, p := range aPoints {
+			if weaklyDominates(p, q) {
+				covered++
+				break
+			}
+		}
+	}
+
+	return float64(covered) / float64(len(b))
+}
+
+// NonDominated returns the solutions of fronts that are not dominated by any
+// other, without duplicates. It is the usual reference front when the true
+// Pareto front is unknown.
+func NonDominated(fronts ...[]*objectives.Result) []*objectives.Result {
+	all := make([]*objectives.Result, 0)
+	for _, front := range fronts {
+		all = append(all, front...)
+	}
+
+	res := make([]*objectives.Result, 0)
+	for i, candidate := range all {
+		dominated := false
+		for j, other := range all {
+			if i == j {
+				continue
+			}
+			// equal points: keep the first one only
+			if weaklyDominates(other.Value, candidate.Value) && (!weaklyDominates(candidate.Value, other.Value) || j < i) {
+				dominated = true
+				break
+			}
+		}
+
+		if !dominated {
+			res = append(res, &objectives.Result{Value: append([]float64(nil), candidate.Value...)})
+		}
+	}
+
+	return res
+}
+
+func values(results []*objectives.Result) [][]float64 {
+	points := make([][]float64, len(results))
+	for i, res := range results {
+		points[i] = res.Value
+	}
+
+	return points
+}
+
+// weaklyDominates reports whether p is nowhere worse than q.
+func weaklyDominates(p, q []float64) bool {
+	for i := range p {
+		if p[i] > q[i] {
+			return false
+		}
+	}
+
+	return true
+}
+
+func euclidean(p, q []float64) float64 {
+	sum := 0.0
+	for i := range p {
+		sum += (p[i] - q[i]) * (p[i] - q[i])
+	}
+
+	return math.Sqrt(sum)
+}
+
+func manhattan(p, q []float64) float64 {
+	sum := 0.0
+	for i := range p {
+		sum += math.Abs(p[i] - q[i])
+	}
+
+	return sum
+}
+
+// nearest returns the distance from p to the closest point of others.
+func nearest(p []float64, others [][]float64, distance func(p, q []float64) float64) float64 {
+	best := math.Inf(1)
+	for _, q := range others {
+		best = math.Min(best, distance(p, q))
+	}
+
+	return best
+}
+
+// meanNearest returns the mean distance from every point of from to its
+// nearest point of to. It is +Inf when to is empty and from is not.
+func meanNearest(from, to [][]float64, distance func(p, q []float64) float64) float64 {
+	if len(from) == 0 {
+		return 0
+	}
+
+	sum := 0.0
+	for _, p := range from {
+		sum += nearest(p, to, distance)
+	}
+
+	return sum / float64(len(from))
+}
+
+// nearestNeighbours returns for every point the distance to its nearest other point.
+func nearestNeighbours(points [][]float64, distance func(p, q []float64) float64) []float64 {
+	res := make([]float64, len(points))
+	for i, p := range points {
+		res[i] = math.Inf(1)
+		for j, q := range points {
+			if i != j {
+				res[i] = math.Min(res[i], distance(p, q))
+			}
+		}
+	}
+
+	return res
+}
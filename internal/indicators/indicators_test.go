@@ -0,0 +1,150 @@
+package indicators
+
+import (
+	"golang-moaha-construction/internal/objectives"
+	"math"
+	"testing"
+)
+
+func front(points ...[]float64) []*objectives.Result {
+	res := make([]*objectives.Result, len(points))
+	for i, p := range points {
+		res[i] = &objectives.Result{Value: p}
+	}
+	return res
+}
+
+func TestHypervolume(t *testing.T) {
+	tests := []struct {
+		name     string
+		front    []*objectives.Result
+		ref      []float64
+		expected float64
+		epsilon  float64
+	}{
+		{"2D", front([]float64{1, 2}, []float64{2, 1}), []float64{3, 3}, 3, 1e-12},
+		{"2D ignores dominated and outside points", front([]float64{1, 2}, []float64{2, 1}, []float64{2, 2}, []float64{4, 0}), []float64{3, 3}, 3, 1e-12},
+		{"3D", front([]float64{0, 0, 1}, []float64{1, 1, 0}), []float64{2, 2, 2}, 5, 1e-12},
+		{"4D Monte Carlo", front([]float64{0, 0, 0, 1}, []float64{1, 1, 1, 0}), []float64{2, 2, 2, 2}, 9, 0.2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hv := Hypervolume(tt.front, tt.ref)
+			if math.Abs(hv-tt.expected) > tt.epsilon {
+				t.Errorf("Hypervolume() = %v, want %v", hv, tt.expected)
+			}
+		})
+	}
+}
+
+func TestDistanceIndicators(t *testing.T) {
+	reference := front([]float64{0, 1}, []float64{1, 0})
+	approx := front([]float64{0, 2}, []float64{2, 0})
+
+	if igd := IGD(reference, reference); igd != 0 {
+		t.Errorf("IGD of the reference front = %v, want 0", igd)
+	}
+	if igd := IGD(approx, reference); igd != 1 {
+		t.Errorf("IGD() = %v, want 1", igd)
+	}
+	if gd := GD(approx, reference); gd != 1 {
+		t.Errorf("GD() = %v, want 1", gd)
+	}
+
+	// a point better than the reference is not penalized by IGD+
+	better := front([]float64{0, 0.5}, []float64{0.5, 0})
+	if igdPlus := IGDPlus(better, reference); igdPlus != 0 {
+		t.Errorf("IGDPlus() = %v, want 0", igdPlus)
+	}
+}
+
+func TestSpacingAndSpread(t *testing.T) {
+	even := front([]float64{0, 2}, []float64{1, 1}, []float64{2, 0})
+	if s := Spacing(even); s != 0 {
+		t.Errorf("Spacing() of an even front = %v, want 0", s)
+	}
+	if s := Spread(even, even); s != 0 {
+		t.Errorf("Spread() of an even front = %v, want 0", s)
+	}
+
+	uneven := front([]float64{0, 2}, []float64{0.2, 1.8}, []float64{2, 0})
+	if s := Spacing(uneven); s <= 0 {
+		t.Errorf("Spacing() of an uneven front = %v, want > 0", s)
+	}
+}
+
+func TestCoverage(t *testing.T) {
+	a := front([]float64{1, 1})
+	b := front([]float64{2, 2}, []float64{0, 3}, []float64{1, 1})
+
+	if c := Coverage(a, b); math.Abs(c-2.0/3.0) > 1e-12 {
+		t.Errorf("C(a, b) = %v, want 2/3", c)
+	}
+	if c := Coverage(b, a); c != 1 {
+		t.Errorf("C(b, a) = %v, want 1", c)
+	}
+}
+
+func TestNormalization(t *testing.T) {
+	n := NormalizationFromFront(front([]float64{10, 100}, []float64{20, 50}))
+	p := n.Point([]float64{15, 75})
+	if p[0] != 0.5 || p[1] != 0.5 {
+		t.Errorf("Point() = %v, want [0.5 0.5]", p)
+	}
+}
+
+func TestHistory(t *testing.T) {
+	var h History
+	h.Reset()
+	h.Record(front([]float64{2, 2}))
+	h.Record(front([]float64{1, 2}, []float64{2, 1}))
+
+	values := h.Values()
+	if len(values) != 2 {
+		t.Fatalf("expected 2 iterations, got %d", len(values))
+	}
+	if values[1].IGD != 0 {
+		t.Errorf("IGD of the last archive = %v, want 0", values[1].IGD)
+	}
+	if values[1].Hypervolume <= values[0].Hypervolume {
+		t.Errorf("expected hypervolume to grow, got %v then %v", values[0].Hypervolume, values[1].Hypervolume)
+	}
+	if values[1].Coverage != 1 {
+		t.Errorf("C(second, first) = %v, want 1", values[1].Coverage)
+	}
+}
+
+func TestHistory_Counted(t *testing.T) {
+	infeasible := &objectives.Result{Value: []float64{0, 0}, Violation: 1}
+	failed := &objectives.Result{Value: []float64{math.Inf(1), 1}}
+
+	var h History
+	h.Reset()
+	h.Record(append(front([]float64{2, 2}), infeasible, failed))
+	h.Record(front([]float64{1, 2}, []float64{2, 1}))
+
+	values := h.Values()
+	if values[0].ArchiveSize != 1 {
+		t.Errorf("expected only the feasible finite member to count, got %d", values[0].ArchiveSize)
+	}
+	for _, v := range values {
+		for _, x := range []float64{v.Hypervolume, v.IGD, v.IGDPlus, v.GD, v.Spacing, v.Spread, v.Coverage} {
+			if math.IsNaN(x) || math.IsInf(x, 0) {
+				t.Fatalf("expected finite indicators, got %+v", v)
+			}
+		}
+	}
+	if values[1].IGD != 0 {
+		t.Errorf("IGD of the last archive = %v, want 0", values[1].IGD)
+	}
+
+	// computed once until the next Record
+	if again := h.Values(); &again[0] != &values[0] {
+		t.Error("expected the cached values")
+	}
+	h.Record(front([]float64{1, 1}))
+	if again := h.Values(); len(again) != 3 {
+		t.Errorf("expected the values of 3 iterations after Record, got %d", len(again))
+	}
+}
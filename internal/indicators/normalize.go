@@ -0,0 +1,73 @@
+package indicators
+
+import (
+	"golang-moaha-construction/internal/objectives"
+)
+
+// Normalization maps every objective linearly so that Ideal becomes 0 and
+// Nadir becomes 1.
+type Normalization struct {
+	Ideal []float64
+	Nadir []float64
+}
+
+// NormalizationFromFront normalizes against the best and worst value of every
+// objective in reference.
+func NormalizationFromFront(reference []*objectives.Result) Normalization {
+	points := values(reference)
+	if len(points) == 0 {
+		return Normalization{}
+	}
+
+	ideal := append([]float64(nil), points[0]...)
+	nadir := append([]float64(nil), points[0]...)
+	for _, p := range points[1:] {
+		for i := range p {
+			ideal[i] = min(ideal[i], p[i])
+			nadir[i] = max(nadir[i], p[i])
+		}
+	}
+
+	return Normalization{
+		Ideal: ideal,
+		Nadir: nadir,
+	}
+}
+
+// NormalizationFromPoint normalizes so that ideal becomes the origin and
+// referencePoint becomes (1, ..., 1).
+func NormalizationFromPoint(ideal, referencePoint []float64) Normalization {
+	return Normalization{
+		Ideal: append([]float64(nil), ideal...),
+		Nadir: append([]float64(nil), referencePoint...),
+	}
+}
+
+// Apply returns copies of results holding only the normalized values. An
+// objective without range is only shifted.
+func (n Normalization) Apply(results []*objectives.Result) []*objectives.Result {
+	res := make([]*objectives.Result, len(results))
+	for i, result := range results {
+		res[i] = &objectives.Result{Value: n.Point(result.Value)}
+	}
+
+	return res
+}
+
+// Point normalizes a single objective vector.
+func (n Normalization) Point(value []float64) []float64 {
+	res := make([]float64, len(value))
+	for i, v := range value {
+		if i >= len(n.Ideal) {
+			res[i] = v
+			continue
+		}
+
+		res[i] = v - n.Ideal[i]
+		if width := n.Nadir[i] - n.Ideal[i]; width > 0 {
+			res[i] /= width
+		}
+	}
+
+	return res
+}
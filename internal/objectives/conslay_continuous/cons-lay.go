@@ -2,9 +2,12 @@ package conslay_continuous
 
 import (
 	"errors"
+	"fmt"
 	"github.com/xuri/excelize/v2"
 	"golang-moaha-construction/internal/data"
+	"golang-moaha-construction/internal/objectives"
 	"golang-moaha-construction/internal/util"
+	"maps"
 	"math"
 	"slices"
 	"sort"
@@ -15,6 +18,8 @@ import (
 const ContinuousConsLayoutName data.ProblemName = "Continuous Construction Layout"
 
 type ConsLay struct {
+	objectives.EvalErrors
+	objectives.AdaptivePenalty
 	Dimensions        int
 	LayoutLength      float64
 	LayoutWidth       float64
@@ -28,6 +33,18 @@ type ConsLay struct {
 	Phases            [][]string
 	CraneLocations    []data.Crane
 	Rounding          bool
+	// PhaseDurations are the durations of the phases, nil when they have none
+	PhaseDurations []float64
+	// RepairLayout moves overlapping facilities apart and inside the site
+	// before every evaluation
+	RepairLayout bool
+	// FreeRotation makes the third variable of every facility a rotation
+	// angle in degrees in [0, 180) instead of a 90 degrees rotation or not
+	FreeRotation bool
+	// DynamicLayout gives every non-fixed facility a position in each phase
+	// it is active in instead of one for the whole project
+	DynamicLayout bool
+	dynamic       *objectives.DynamicLayout
 }
 
 type ConsLayConfigs struct {
@@ -37,7 +54,11 @@ type ConsLayConfigs struct {
 	NonFixedLocations []data.Location
 	FixedLocations    []data.Location
 	Phases            [][]string
+	PhaseDurations    []float64
 	Rounding          bool
+	RepairLayout      bool
+	FreeRotation      bool
+	DynamicLayout     bool
 }
 
 func (s *ConsLay) Type() data.TypeProblem {
@@ -53,6 +74,10 @@ func CreateConsLayFromConfig(consLayConfigs ConsLayConfigs) (*ConsLay, error) {
 		FixedLocations:    consLayConfigs.FixedLocations,
 		NonFixedLocations: consLayConfigs.NonFixedLocations,
 		Phases:            consLayConfigs.Phases,
+		PhaseDurations:    consLayConfigs.PhaseDurations,
+		RepairLayout:      consLayConfigs.RepairLayout,
+		FreeRotation:      consLayConfigs.FreeRotation,
+		DynamicLayout:     consLayConfigs.DynamicLayout,
 		Objectives:        make(map[data.ObjectiveType]data.Objectiver),
 		Constraints:       make(map[data.ConstraintType]data.Constrainter),
 	}
@@ -93,6 +118,9 @@ func CreateConsLayFromConfig(consLayConfigs ConsLayConfigs) (*ConsLay, error) {
 		upperBound[idx] = math.Max(upperBoundX, upperBoundXRotated)
 		upperBound[idx+1] = math.Max(upperBoundY, upperBoundYRotated)
 		upperBound[idx+2] = 1.0
+		if consLay.FreeRotation {
+			upperBound[idx+2] = 180.0
+		}
 
 		//idx := i * 3
 		//upperBound[idx] = consLay.LayoutLength
@@ -104,6 +132,12 @@ func CreateConsLayFromConfig(consLayConfigs ConsLayConfigs) (*ConsLay, error) {
 		//lowerBound[idx+2] = 0
 	}
 
+	if consLay.DynamicLayout {
+		consLay.dynamic = objectives.NewDynamicLayout(consLay.NonFixedLocations, consLay.Phases)
+		dimensions = consLay.dynamic.Dimensions()
+		lowerBound, upperBound = consLay.dynamic.Bounds(lowerBound, upperBound)
+	}
+
 	consLay.Dimensions = dimensions
 	consLay.UpperBound = upperBound
 	consLay.LowerBound = lowerBound
@@ -115,7 +149,16 @@ func (s *ConsLay) Eval(input []float64) (
 	values []float64,
 	valuesWithKey map[data.ObjectiveType]float64,
 	key []data.ObjectiveType,
-	penalty map[data.ConstraintType]float64) {
+	penalty map[data.ConstraintType]float64,
+	violations map[data.ConstraintType]float64) {
+	// in a dynamic layout the phases are evaluated with their own locations
+	var phaseLocations, activeLocations []map[string]data.Location
+	if s.dynamic != nil {
+		phaseLocations = s.GetPhaseLocationResult(input)
+		activeLocations = objectives.ActivePhaseLocations(phaseLocations, s.Phases)
+		input = s.dynamic.FirstInput(input)
+	}
+
 	// add x, y, r to non-fixed locations
 	nonFixedLocations := make([]data.Location, len(s.NonFixedLocations))
 	mapLocations := make(map[string]data.Location, len(s.Locations))
@@ -130,8 +173,11 @@ func (s *ConsLay) Eval(input []float64) (
 		width := loc.Width
 		length := loc.Length
 		rotation := false
+		angle := 0.0
 
-		if math.Round(r) > 0 {
+		if s.FreeRotation {
+			angle = math.Mod(r, 180)
+		} else if math.Round(r) > 0 {
 			rotation = true
 			width = loc.Length
 			length = loc.Width
@@ -148,7 +194,8 @@ func (s *ConsLay) Eval(input []float64) (
 				Y: y,
 			}, // update x, y
 			Rotation: rotation, // update r
-			Length:   length,   // change length and width if rotation is true
+			Angle:    angle,
+			Length:   length, // change length and width if rotation is true
 			Width:    width,
 			IsFixed:  false,
 			Symbol:   loc.Symbol,
@@ -166,8 +213,15 @@ func (s *ConsLay) Eval(input []float64) (
 
 	// checking constraints
 	penalty = make(map[data.ConstraintType]float64)
+	violations = make(map[data.ConstraintType]float64)
 	for k, v := range s.Constraints {
-		penalty[k] = math.Pow(v.Eval(mapLocations), v.GetPowerPenalty()) * v.GetAlphaPenalty()
+		amount, err := objectives.EvaluateConstraint(v, mapLocations, phaseLocations, activeLocations)
+		if err != nil {
+			s.Record(fmt.Errorf("%s: %w", k, err))
+			amount = math.Inf(1)
+		}
+		violations[k] = amount
+		penalty[k] = s.Penalty(k, v, amount)
 	}
 
 	// calculate objectives and add penalty to them
@@ -192,11 +246,19 @@ func (s *ConsLay) Eval(input []float64) (
 		if !ok {
 			panic("objective not found")
 		}
-		val := v.Eval(mapLocations)
+		val, err := objectives.Evaluate(v, mapLocations, phaseLocations)
+		if err != nil {
+			s.Record(fmt.Errorf("%s: %w", k, err))
+			val = math.Inf(1)
+		}
 
 		// add penalty to objective value
-		for _, penaltyAlpha := range penalty {
-			val += penaltyAlpha * v.GetAlphaPenalty()
+		// in a fixed order so that the same position always gives the same value,
+		// an objective without a penalty coefficient ignores even infinite ones
+		if alpha := v.GetAlphaPenalty(); alpha != 0 {
+			for _, c := range slices.Sorted(maps.Keys(penalty)) {
+				val += penalty[c] * alpha
+			}
 		}
 
 		values[idx] = val
@@ -205,7 +267,7 @@ func (s *ConsLay) Eval(input []float64) (
 		valuesWithKey[k] = val
 	}
 
-	return values, valuesWithKey, valuesName, penalty
+	return values, valuesWithKey, valuesName, penalty, violations
 }
 
 func (s *ConsLay) GetUpperBound() []float64 {
@@ -258,7 +320,37 @@ func (s *ConsLay) GetPhases() [][]string {
 	return s.Phases
 }
 
+func (s *ConsLay) GetPhaseDurations() []float64 {
+	return s.PhaseDurations
+}
+
+// GetLocationResult returns the locations of input, in a dynamic layout every
+// facility in its first active phase. See GetPhaseLocationResult.
 func (s *ConsLay) GetLocationResult(input []float64) (map[string]data.Location, []data.Location, []data.Crane, error) {
+	if s.dynamic != nil {
+		input = s.dynamic.FirstInput(input)
+	}
+
+	return s.locationResult(input)
+}
+
+// GetPhaseLocationResult returns the locations of input in every phase of a
+// dynamic layout, nil in a static one.
+func (s *ConsLay) GetPhaseLocationResult(input []float64) []map[string]data.Location {
+	if s.dynamic == nil {
+		return nil
+	}
+
+	phaseLocations := make([]map[string]data.Location, len(s.Phases))
+	for phaseIdx := range s.Phases {
+		phaseLocations[phaseIdx], _, _, _ = s.locationResult(s.dynamic.PhaseInput(input, phaseIdx))
+	}
+
+	return phaseLocations
+}
+
+// locationResult returns the locations of input, a static decision vector.
+func (s *ConsLay) locationResult(input []float64) (map[string]data.Location, []data.Location, []data.Crane, error) {
 	// add x, y, r to non-fixed locations
 	nonFixedLocations := make([]data.Location, len(s.NonFixedLocations))
 	mapLocations := make(map[string]data.Location, len(s.Locations))
@@ -274,8 +366,11 @@ func (s *ConsLay) GetLocationResult(input []float64) (map[string]data.Location,
 		width := loc.Width
 		length := loc.Length
 		rotation := false
+		angle := 0.0
 
-		if math.Round(r) > 0 {
+		if s.FreeRotation {
+			angle = math.Mod(r, 180)
+		} else if math.Round(r) > 0 {
 			rotation = true
 			width = loc.Length
 			length = loc.Width
@@ -292,7 +387,8 @@ func (s *ConsLay) GetLocationResult(input []float64) (map[string]data.Location,
 				Y: y,
 			}, // update x, y
 			Rotation: rotation, // update r
-			Length:   length,   // change length and width if rotation is true
+			Angle:    angle,
+			Length:   length, // change length and width if rotation is true
 			Width:    width,
 			IsFixed:  false,
 			Symbol:   loc.Symbol,
@@ -317,6 +413,82 @@ func (s *ConsLay) GetLocationResult(input []float64) (map[string]data.Location,
 	return mapLocations, sliceLocations, s.CraneLocations, nil
 }
 
+// Repair resolves the overlaps within every phase and clamps the facilities
+// inside the site with objectives.RepairLocations when RepairLayout is set.
+// The clearances of a constraint implementing data.ClearanceProvider are kept.
+// Rotations are kept. With Rounding, the coordinates are rounded first and
+// moved by whole units.
+func (s *ConsLay) Repair(pos []float64) []float64 {
+	if !s.RepairLayout {
+		return pos
+	}
+
+	if s.dynamic == nil {
+		return s.repair(pos, s.Phases)
+	}
+
+	// in a dynamic layout every phase is repaired on its own
+	for phaseIdx, phase := range s.Phases {
+		phaseInput := s.dynamic.PhaseInput(pos, phaseIdx)
+		s.dynamic.SetPhaseInput(pos, phaseIdx, s.repair(phaseInput, [][]string{phase}))
+	}
+
+	return pos
+}
+
+// repair repairs pos, a static decision vector, in phases.
+func (s *ConsLay) repair(pos []float64, phases [][]string) []float64 {
+	mapLocations := make(map[string]data.Location, len(s.Locations))
+	for i := 0; i < len(s.FixedLocations); i++ {
+		mapLocations[s.FixedLocations[i].Symbol] = s.FixedLocations[i]
+	}
+
+	for i, loc := range s.NonFixedLocations {
+		idx := i * 3
+		x := pos[idx]
+		y := pos[idx+1]
+
+		width := loc.Width
+		length := loc.Length
+		if s.FreeRotation {
+			// separating the axis-aligned rectangles around the facilities
+			// separates the facilities
+			halfX, halfY := data.Location{Length: length, Width: width, Angle: pos[idx+2]}.HalfExtents()
+			length, width = 2*halfX, 2*halfY
+		} else if math.Round(pos[idx+2]) > 0 {
+			width = loc.Length
+			length = loc.Width
+		}
+
+		if s.Rounding {
+			x = math.Round(x)
+			y = math.Round(y)
+		}
+
+		mapLocations[loc.Symbol] = data.Location{
+			Coordinate: data.Coordinate{X: x, Y: y},
+			Length:     length,
+			Width:      width,
+			Symbol:     loc.Symbol,
+		}
+	}
+
+	step := 0.0
+	if s.Rounding {
+		step = 1
+	}
+
+	objectives.RepairLocations(mapLocations, phases, s.LayoutLength, s.LayoutWidth, step, objectives.ClearanceOf(s.Constraints))
+
+	for i, loc := range s.NonFixedLocations {
+		idx := i * 3
+		pos[idx] = mapLocations[loc.Symbol].Coordinate.X
+		pos[idx+1] = mapLocations[loc.Symbol].Coordinate.Y
+	}
+
+	return pos
+}
+
 func (s *ConsLay) GetLayoutSize() (minX float64, maxX float64, minY float64, maxY float64, err error) {
 	return 0, s.LayoutLength, 0, s.LayoutWidth, nil
 }
@@ -450,21 +622,28 @@ func ReadLocationsFromFile(filePath string) (locations map[string]data.Location,
 	return locations, fixedLocations, nonFixedLocations, nil
 }
 
-func ReadPhasesFromFile(filePath string) ([][]string, error) {
+// ReadPhasesFromFile reads the phases from Sheet1 of an Excel file, one phase
+// per row with its label and the comma-separated facilities active in it. An
+// optional third column is the duration of the phase, or the third and fourth
+// columns are its start and end dates. The durations are nil when no phase has
+// one, otherwise every phase must have one.
+func ReadPhasesFromFile(filePath string) ([][]string, []float64, error) {
 
 	// load data from file
 	file, err := excelize.OpenFile(filePath)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
-	rows, err := file.GetRows("Sheet1")
+	// raw so that a date is read as its serial number whatever its format
+	rows, err := file.GetRows("Sheet1", excelize.Options{RawCellValue: true})
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	phases := make([][]string, 0)
-	for _, row := range rows {
+	durations := make([]float64, 0)
+	for rowIdx, row := range rows {
 		for i, cell := range row {
 			switch i {
 			case 1:
@@ -473,16 +652,109 @@ func ReadPhasesFromFile(filePath string) ([][]string, error) {
 					vals[eachTF] = strings.TrimSpace(vals[eachTF])
 					// Validate that each phase entry follows the pattern "TF<number>" or "tf<number>"
 					if err := util.ValidateTFNumber(vals[eachTF]); err != nil {
-						return nil, err
+						return nil, nil, err
 					}
 					// Convert to uppercase for consistency
 					vals[eachTF] = strings.ToUpper(vals[eachTF])
 				}
 				phases = append(phases, vals)
+			case 2:
+				if strings.TrimSpace(strings.Join(row[2:], "")) == "" {
+					continue
+				}
+				duration, err := util.ParsePhaseDuration(row[2:])
+				if err != nil {
+					return nil, nil, fmt.Errorf("row %d: %w", rowIdx+1, err)
+				}
+				durations = append(durations, duration)
+			}
+		}
+	}
+
+	if len(durations) == 0 {
+		return phases, nil, nil
+	}
+	if len(durations) != len(phases) {
+		return nil, nil, errors.New("either every phase or no phase must have a duration")
+	}
+
+	return phases, durations, nil
+}
+
+// ReadSiteFromFile reads the boundary and the obstacles of a site from the
+// first sheet of an Excel file with the columns Name, X, Y and Phases after a
+// header row. Each row is a vertex; consecutive rows with the same name make
+// up one polygon. The polygon named "Boundary" is the site boundary and the
+// others are obstacles. Phases is an optional comma-separated list of the
+// phases, counted from 1, an obstacle exists in.
+func ReadSiteFromFile(filePath string) (data.Site, error) {
+	var site data.Site
+
+	file, err := excelize.OpenFile(filePath)
+	if err != nil {
+		return site, err
+	}
+
+	rows, err := file.GetRows(file.GetSheetName(0))
+	if err != nil {
+		return site, err
+	}
+
+	var obstacle *data.Obstacle
+	for rowIdx, row := range rows {
+		if rowIdx == 0 || len(row) == 0 || strings.TrimSpace(row[0]) == "" {
+			continue
+		}
+
+		if len(row) < 3 {
+			return site, fmt.Errorf("row %d: expected the name, x and y of a vertex", rowIdx+1)
+		}
+
+		name := strings.TrimSpace(row[0])
+		x, err := strconv.ParseFloat(strings.TrimSpace(row[1]), 64)
+		if err != nil {
+			return site, fmt.Errorf("row %d: %w", rowIdx+1, err)
+		}
+		y, err := strconv.ParseFloat(strings.TrimSpace(row[2]), 64)
+		if err != nil {
+			return site, fmt.Errorf("row %d: %w", rowIdx+1, err)
+		}
+		vertex := data.Coordinate{X: x, Y: y}
+
+		if strings.EqualFold(name, "Boundary") {
+			site.Boundary = append(site.Boundary, vertex)
+			obstacle = nil
+			continue
+		}
+
+		if obstacle == nil || obstacle.Name != name {
+			site.Obstacles = append(site.Obstacles, data.Obstacle{Name: name})
+			obstacle = &site.Obstacles[len(site.Obstacles)-1]
+		}
+		obstacle.Polygon = append(obstacle.Polygon, vertex)
+
+		if len(row) > 3 && strings.TrimSpace(row[3]) != "" {
+			for _, phase := range strings.Split(row[3], ",") {
+				phaseIdx, err := strconv.Atoi(strings.TrimSpace(phase))
+				if err != nil || phaseIdx < 1 {
+					return site, fmt.Errorf("row %d: invalid phase %q", rowIdx+1, phase)
+				}
+				if !slices.Contains(obstacle.Phases, phaseIdx-1) {
+					obstacle.Phases = append(obstacle.Phases, phaseIdx-1)
+				}
 			}
+		}
+	}
+
+	if len(site.Boundary) < 3 {
+		return site, errors.New("the site boundary needs at least 3 vertices")
+	}
 
+	for _, o := range site.Obstacles {
+		if len(o.Polygon) < 3 {
+			return site, fmt.Errorf("obstacle %s needs at least 3 vertices", o.Name)
 		}
 	}
 
-	return phases, nil
+	return site, nil
 }
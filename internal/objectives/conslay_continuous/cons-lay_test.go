@@ -1,6 +1,11 @@
 package conslay_continuous
 
-import "testing"
+import (
+	"github.com/xuri/excelize/v2"
+	"path/filepath"
+	"slices"
+	"testing"
+)
 
 func TestReadLocationsFromFile(t *testing.T) {
 	locs, fixedLocs, nonFixedLocs, err := ReadLocationsFromFile("../../../data/conslay/mini/locations.xlsx")
@@ -21,3 +26,45 @@ func TestReadLocationsFromFile(t *testing.T) {
 		t.Errorf("expected to read 3 non-fixed locations, got %d", len(nonFixedLocs))
 	}
 }
+
+func TestReadPhasesFromFile(t *testing.T) {
+	writePhases := func(rows [][]any) string {
+		file := excelize.NewFile()
+		for i, row := range rows {
+			cell, _ := excelize.CoordinatesToCellName(1, i+1)
+			_ = file.SetSheetRow("Sheet1", cell, &row)
+		}
+		filePath := filepath.Join(t.TempDir(), "phases.xlsx")
+		if err := file.SaveAs(filePath); err != nil {
+			t.Fatal(err)
+		}
+		return filePath
+	}
+
+	phases, durations, err := ReadPhasesFromFile("../../../data/conslay/continuous/phaseBuilding.xlsx")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(phases) != 3 || durations != nil {
+		t.Errorf("expected 3 phases without durations, got %d and %v", len(phases), durations)
+	}
+
+	phases, durations, err = ReadPhasesFromFile(writePhases([][]any{
+		{"Phase 1", "TF1, tf2", 14},
+		{"Phase 2", "TF2", "2025-01-01", "2025-03-01"},
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(phases[0], []string{"TF1", "TF2"}) || !slices.Equal(durations, []float64{14, 59}) {
+		t.Errorf("unexpected phases %v with durations %v", phases, durations)
+	}
+
+	_, _, err = ReadPhasesFromFile(writePhases([][]any{
+		{"Phase 1", "TF1", 14},
+		{"Phase 2", "TF2"},
+	}))
+	if err == nil {
+		t.Error("expected an error when only some phases have a duration")
+	}
+}
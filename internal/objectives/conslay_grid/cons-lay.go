@@ -2,9 +2,12 @@ package conslay_grid
 
 import (
 	"errors"
+	"fmt"
 	"github.com/xuri/excelize/v2"
 	"golang-moaha-construction/internal/data"
+	"golang-moaha-construction/internal/objectives"
 	"golang-moaha-construction/internal/util"
+	"maps"
 	"math"
 	"slices"
 	"sort"
@@ -17,6 +20,8 @@ const GridConsLayoutName data.ProblemName = "Grid Construction Layout"
 // ConsLay is a structure that represents a problem instance of a continuous construction layout problem.
 // x, y are the coordinates of the location at bottom-left corner.
 type ConsLay struct {
+	objectives.EvalErrors
+	objectives.AdaptivePenalty
 	Dimensions        int
 	LayoutLength      float64
 	LayoutWidth       float64
@@ -31,6 +36,15 @@ type ConsLay struct {
 	Rounding          bool
 	GridSize          int
 	CraneLocations    []data.Crane
+	// PhaseDurations are the durations of the phases, nil when they have none
+	PhaseDurations []float64
+	// RepairLayout moves overlapping facilities apart and inside the site
+	// before every evaluation
+	RepairLayout bool
+	// DynamicLayout gives every non-fixed facility a position in each phase
+	// it is active in instead of one for the whole project
+	DynamicLayout bool
+	dynamic       *objectives.DynamicLayout
 }
 
 type ConsLayConfigs struct {
@@ -40,8 +54,11 @@ type ConsLayConfigs struct {
 	NonFixedLocations []data.Location
 	FixedLocations    []data.Location
 	Phases            [][]string
+	PhaseDurations    []float64
 	Rounding          bool
 	GridSize          int
+	RepairLayout      bool
+	DynamicLayout     bool
 }
 
 func (s *ConsLay) Type() data.TypeProblem {
@@ -61,9 +78,12 @@ func CreateConsLayFromConfig(consLayConfigs ConsLayConfigs) (*ConsLay, error) {
 		FixedLocations:    consLayConfigs.FixedLocations,
 		NonFixedLocations: consLayConfigs.NonFixedLocations,
 		Phases:            consLayConfigs.Phases,
+		PhaseDurations:    consLayConfigs.PhaseDurations,
 		Objectives:        make(map[data.ObjectiveType]data.Objectiver),
 		Constraints:       make(map[data.ConstraintType]data.Constrainter),
 		GridSize:          consLayConfigs.GridSize,
+		RepairLayout:      consLayConfigs.RepairLayout,
+		DynamicLayout:     consLayConfigs.DynamicLayout,
 	}
 
 	// Find the x, y, r of Non-fixed Locations
@@ -104,6 +124,12 @@ func CreateConsLayFromConfig(consLayConfigs ConsLayConfigs) (*ConsLay, error) {
 		upperBound[idx+2] = 1.0
 	}
 
+	if consLay.DynamicLayout {
+		consLay.dynamic = objectives.NewDynamicLayout(consLay.NonFixedLocations, consLay.Phases)
+		dimensions = consLay.dynamic.Dimensions()
+		lowerBound, upperBound = consLay.dynamic.Bounds(lowerBound, upperBound)
+	}
+
 	consLay.Dimensions = dimensions
 	consLay.UpperBound = upperBound
 	consLay.LowerBound = lowerBound
@@ -115,7 +141,16 @@ func (s *ConsLay) Eval(input []float64) (
 	values []float64,
 	valuesWithKey map[data.ObjectiveType]float64,
 	key []data.ObjectiveType,
-	penalty map[data.ConstraintType]float64) {
+	penalty map[data.ConstraintType]float64,
+	violations map[data.ConstraintType]float64) {
+	// in a dynamic layout the phases are evaluated with their own locations
+	var phaseLocations, activeLocations []map[string]data.Location
+	if s.dynamic != nil {
+		phaseLocations = s.GetPhaseLocationResult(input)
+		activeLocations = objectives.ActivePhaseLocations(phaseLocations, s.Phases)
+		input = s.dynamic.FirstInput(input)
+	}
+
 	// add x, y, r to non-fixed locations
 	nonFixedLocations := make([]data.Location, len(s.NonFixedLocations))
 	mapLocations := make(map[string]data.Location, len(s.Locations))
@@ -168,8 +203,15 @@ func (s *ConsLay) Eval(input []float64) (
 
 	// checking constraints
 	penalty = make(map[data.ConstraintType]float64)
+	violations = make(map[data.ConstraintType]float64)
 	for k, v := range s.Constraints {
-		penalty[k] = math.Pow(v.Eval(mapLocations), v.GetPowerPenalty()) * v.GetAlphaPenalty()
+		amount, err := objectives.EvaluateConstraint(v, mapLocations, phaseLocations, activeLocations)
+		if err != nil {
+			s.Record(fmt.Errorf("%s: %w", k, err))
+			amount = math.Inf(1)
+		}
+		violations[k] = amount
+		penalty[k] = s.Penalty(k, v, amount)
 	}
 
 	// calculate objectives and add penalty to them
@@ -194,11 +236,19 @@ func (s *ConsLay) Eval(input []float64) (
 		if !ok {
 			panic("objective not found")
 		}
-		val := v.Eval(mapLocations)
+		val, err := objectives.Evaluate(v, mapLocations, phaseLocations)
+		if err != nil {
+			s.Record(fmt.Errorf("%s: %w", k, err))
+			val = math.Inf(1)
+		}
 
 		// add penalty to objective value
-		for _, penaltyAlpha := range penalty {
-			val += penaltyAlpha * v.GetAlphaPenalty()
+		// in a fixed order so that the same position always gives the same value,
+		// an objective without a penalty coefficient ignores even infinite ones
+		if alpha := v.GetAlphaPenalty(); alpha != 0 {
+			for _, c := range slices.Sorted(maps.Keys(penalty)) {
+				val += penalty[c] * alpha
+			}
 		}
 
 		values[idx] = val
@@ -207,7 +257,7 @@ func (s *ConsLay) Eval(input []float64) (
 		valuesWithKey[k] = val
 	}
 
-	return values, valuesWithKey, valuesName, penalty
+	return values, valuesWithKey, valuesName, penalty, violations
 }
 
 func (s *ConsLay) GetUpperBound() []float64 {
@@ -260,7 +310,37 @@ func (s *ConsLay) GetPhases() [][]string {
 	return s.Phases
 }
 
+func (s *ConsLay) GetPhaseDurations() []float64 {
+	return s.PhaseDurations
+}
+
+// GetLocationResult returns the locations of input, in a dynamic layout every
+// facility in its first active phase. See GetPhaseLocationResult.
 func (s *ConsLay) GetLocationResult(input []float64) (map[string]data.Location, []data.Location, []data.Crane, error) {
+	if s.dynamic != nil {
+		input = s.dynamic.FirstInput(input)
+	}
+
+	return s.locationResult(input)
+}
+
+// GetPhaseLocationResult returns the locations of input in every phase of a
+// dynamic layout, nil in a static one.
+func (s *ConsLay) GetPhaseLocationResult(input []float64) []map[string]data.Location {
+	if s.dynamic == nil {
+		return nil
+	}
+
+	phaseLocations := make([]map[string]data.Location, len(s.Phases))
+	for phaseIdx := range s.Phases {
+		phaseLocations[phaseIdx], _, _, _ = s.locationResult(s.dynamic.PhaseInput(input, phaseIdx))
+	}
+
+	return phaseLocations
+}
+
+// locationResult returns the locations of input, a static decision vector.
+func (s *ConsLay) locationResult(input []float64) (map[string]data.Location, []data.Location, []data.Crane, error) {
 	// add x, y, r to non-fixed locations
 	nonFixedLocations := make([]data.Location, len(s.NonFixedLocations))
 	mapLocations := make(map[string]data.Location, len(s.Locations))
@@ -322,6 +402,70 @@ func (s *ConsLay) GetLocationResult(input []float64) (map[string]data.Location,
 	return mapLocations, sliceLocations, s.CraneLocations, nil
 }
 
+// Repair resolves the overlaps within every phase and clamps the facilities
+// inside the site with objectives.RepairLocations when RepairLayout is set.
+// The clearances of a constraint implementing data.ClearanceProvider are kept.
+// The bottom-left corners are snapped to the grid first and moved by whole
+// cells, so the repaired position stays on the grid. Rotations are kept.
+func (s *ConsLay) Repair(pos []float64) []float64 {
+	if !s.RepairLayout {
+		return pos
+	}
+
+	if s.dynamic == nil {
+		return s.repair(pos, s.Phases)
+	}
+
+	// in a dynamic layout every phase is repaired on its own
+	for phaseIdx, phase := range s.Phases {
+		phaseInput := s.dynamic.PhaseInput(pos, phaseIdx)
+		s.dynamic.SetPhaseInput(pos, phaseIdx, s.repair(phaseInput, [][]string{phase}))
+	}
+
+	return pos
+}
+
+// repair repairs pos, a static decision vector, in phases.
+func (s *ConsLay) repair(pos []float64, phases [][]string) []float64 {
+	mapLocations := make(map[string]data.Location, len(s.Locations))
+	for i := 0; i < len(s.FixedLocations); i++ {
+		mapLocations[s.FixedLocations[i].Symbol] = s.FixedLocations[i]
+	}
+
+	for i, loc := range s.NonFixedLocations {
+		idx := i * 3
+
+		width := loc.Width
+		length := loc.Length
+		if math.Round(pos[idx+2]) > 0 {
+			width = loc.Length
+			length = loc.Width
+		}
+
+		// convert x,y from bottom-left to center
+		x := util.RoundToGrid(pos[idx], s.GridSize) + length/2
+		y := util.RoundToGrid(pos[idx+1], s.GridSize) + width/2
+
+		mapLocations[loc.Symbol] = data.Location{
+			Coordinate: data.Coordinate{X: x, Y: y},
+			Length:     length,
+			Width:      width,
+			Symbol:     loc.Symbol,
+		}
+	}
+
+	objectives.RepairLocations(mapLocations, phases, s.LayoutLength, s.LayoutWidth, float64(s.GridSize), objectives.ClearanceOf(s.Constraints))
+
+	for i, loc := range s.NonFixedLocations {
+		idx := i * 3
+		repaired := mapLocations[loc.Symbol]
+		pos[idx] = repaired.Coordinate.X - repaired.Length/2
+		pos[idx+1] = repaired.Coordinate.Y - repaired.Width/2
+	}
+
+	return pos
+}
+
 func (s *ConsLay) GetLayoutSize() (minX float64, maxX float64, minY float64, maxY float64, err error) {
 	return 0, s.LayoutLength, 0, s.LayoutWidth, nil
 }
@@ -461,21 +605,28 @@ func ReadLocationsFromFile(filePath string) (locations map[string]data.Location,
 	return locations, fixedLocations, nonFixedLocations, nil
 }
 
-func ReadPhasesFromFile(filePath string) ([][]string, error) {
+// ReadPhasesFromFile reads the phases from Sheet1 of an Excel file, one phase
+// per row with its label and the comma-separated facilities active in it. An
+// optional third column is the duration of the phase, or the third and fourth
+// columns are its start and end dates. The durations are nil when no phase has
+// one, otherwise every phase must have one.
+func ReadPhasesFromFile(filePath string) ([][]string, []float64, error) {
 
 	// load data from file
 	file, err := excelize.OpenFile(filePath)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
-	rows, err := file.GetRows("Sheet1")
+	// raw so that a date is read as its serial number whatever its format
+	rows, err := file.GetRows("Sheet1", excelize.Options{RawCellValue: true})
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	phases := make([][]string, 0)
-	for _, row := range rows {
+	durations := make([]float64, 0)
+	for rowIdx, row := range rows {
 		for i, cell := range row {
 			switch i {
 			case 1:
@@ -484,16 +635,31 @@ func ReadPhasesFromFile(filePath string) ([][]string, error) {
 					vals[eachTF] = strings.TrimSpace(vals[eachTF])
 					// Validate that each phase entry follows the pattern "TF<number>" or "tf<number>"
 					if err := util.ValidateTFNumber(vals[eachTF]); err != nil {
-						return nil, err
+						return nil, nil, err
 					}
 					// Convert to uppercase for consistency
 					vals[eachTF] = strings.ToUpper(vals[eachTF])
 				}
 				phases = append(phases, vals)
+			case 2:
+				if strings.TrimSpace(strings.Join(row[2:], "")) == "" {
+					continue
+				}
+				duration, err := util.ParsePhaseDuration(row[2:])
+				if err != nil {
+					return nil, nil, fmt.Errorf("row %d: %w", rowIdx+1, err)
+				}
+				durations = append(durations, duration)
 			}
-
 		}
 	}
 
-	return phases, nil
+	if len(durations) == 0 {
+		return phases, nil, nil
+	}
+	if len(durations) != len(phases) {
+		return nil, nil, errors.New("either every phase or no phase must have a duration")
+	}
+
+	return phases, durations, nil
 }
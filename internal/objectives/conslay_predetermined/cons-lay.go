@@ -4,7 +4,9 @@ import (
 	"errors"
 	"fmt"
 	"golang-moaha-construction/internal/data"
+	"golang-moaha-construction/internal/objectives"
 	"golang-moaha-construction/internal/util"
+	"maps"
 	"math"
 	"slices"
 	"sort"
@@ -13,6 +15,8 @@ import (
 const PredeterminedConsLayoutName data.ProblemName = "Predetermined Construction Layout"
 
 type ConsLay struct {
+	objectives.EvalErrors
+	objectives.AdaptivePenalty
 	Dimensions            int
 	UpperBound            []float64
 	LowerBound            []float64
@@ -121,14 +125,22 @@ func (s *ConsLay) Eval(input []float64) (
 	values []float64,
 	valuesWithKey map[data.ObjectiveType]float64,
 	key []data.ObjectiveType,
-	penalty map[data.ConstraintType]float64) {
+	penalty map[data.ConstraintType]float64,
+	violations map[data.ConstraintType]float64) {
 
 	mapLocations := s.MappingLocations(input)
 
 	// checking constraints
 	penalty = make(map[data.ConstraintType]float64)
+	violations = make(map[data.ConstraintType]float64)
 	for k, v := range s.Constraints {
-		penalty[k] = math.Pow(v.Eval(mapLocations), v.GetPowerPenalty()) * v.GetAlphaPenalty()
+		amount, err := objectives.SafeEval(v.Eval, mapLocations)
+		if err != nil {
+			s.Record(fmt.Errorf("%s: %w", k, err))
+			amount = math.Inf(1)
+		}
+		violations[k] = amount
+		penalty[k] = s.Penalty(k, v, amount)
 	}
 
 	// calculate objectives and add penalty to them
@@ -153,11 +165,19 @@ func (s *ConsLay) Eval(input []float64) (
 		if !ok {
 			panic("objective not found")
 		}
-		val := v.Eval(mapLocations)
+		val, err := objectives.SafeEval(v.Eval, mapLocations)
+		if err != nil {
+			s.Record(fmt.Errorf("%s: %w", k, err))
+			val = math.Inf(1)
+		}
 
 		// add penalty to objective value
-		for _, penaltyAlpha := range penalty {
-			val += penaltyAlpha * v.GetAlphaPenalty()
+		// in a fixed order so that the same position always gives the same value,
+		// an objective without a penalty coefficient ignores even infinite ones
+		if alpha := v.GetAlphaPenalty(); alpha != 0 {
+			for _, c := range slices.Sorted(maps.Keys(penalty)) {
+				val += penalty[c] * alpha
+			}
 		}
 
 		values[idx] = val
@@ -166,7 +186,7 @@ func (s *ConsLay) Eval(input []float64) (
 		valuesWithKey[k] = val
 	}
 
-	return values, valuesWithKey, valuesName, penalty
+	return values, valuesWithKey, valuesName, penalty, violations
 }
 
 func (s *ConsLay) GetUpperBound() []float64 {
@@ -219,6 +239,12 @@ func (s *ConsLay) GetPhases() [][]string {
 	return s.Phases
 }
 
+// Repair returns pos unchanged: the facilities of a predetermined layout
+// always sit on distinct locations.
+func (s *ConsLay) Repair(pos []float64) []float64 {
+	return pos
+}
+
 func (s *ConsLay) GetLocationResult(input []float64) (map[string]data.Location, []data.Location, []data.Crane, error) {
 
 	_, sortedIdx := util.SortWithIdx(input)
@@ -0,0 +1,240 @@
+package objectives
+
+import (
+	"fmt"
+	"golang-moaha-construction/internal/data"
+	"slices"
+)
+
+// VariablesPerFacility is the number of decision variables of a non-fixed
+// facility in a continuous or grid layout: x, y and the rotation.
+const VariablesPerFacility = 3
+
+// DynamicLayout maps the decision vector of a dynamic layout, where a
+// non-fixed facility has a position in every phase it is active in, to the
+// decision vector of a static layout of one phase.
+//
+// The variables of a facility are in slots of VariablesPerFacility, one slot
+// per active phase. In the phases it is not active in, a facility keeps the
+// slot of its previous active phase, or of its first one. A facility active
+// in no phase has a single slot.
+type DynamicLayout struct {
+	// Slots[i][p] is the slot of the non-fixed facility i in the phase p
+	Slots [][]int
+	// Active[i] are the phases the non-fixed facility i is active in
+	Active [][]int
+	// First[i] is the slot of the non-fixed facility i in its first active
+	// phase
+	First         []int
+	NumberOfSlots int
+}
+
+// NewDynamicLayout returns the dynamic layout of nonFixedLocations in phases.
+func NewDynamicLayout(nonFixedLocations []data.Location, phases [][]string) *DynamicLayout {
+	d := &DynamicLayout{
+		Slots:  make([][]int, len(nonFixedLocations)),
+		Active: make([][]int, len(nonFixedLocations)),
+		First:  make([]int, len(nonFixedLocations)),
+	}
+
+	for i, loc := range nonFixedLocations {
+		for phaseIdx, phase := range phases {
+			if slices.Contains(phase, loc.Symbol) {
+				d.Active[i] = append(d.Active[i], phaseIdx)
+			}
+		}
+
+		d.First[i] = d.NumberOfSlots
+		d.Slots[i] = make([]int, len(phases))
+		for phaseIdx := range phases {
+			d.Slots[i][phaseIdx] = d.First[i]
+		}
+
+		for k, phaseIdx := range d.Active[i] {
+			slot := d.First[i] + k
+			// until the next active phase
+			for p := phaseIdx; p < len(phases); p++ {
+				d.Slots[i][p] = slot
+			}
+		}
+
+		d.NumberOfSlots += max(1, len(d.Active[i]))
+	}
+
+	return d
+}
+
+// Dimensions is the length of the decision vector.
+func (d *DynamicLayout) Dimensions() int {
+	return d.NumberOfSlots * VariablesPerFacility
+}
+
+// Bounds returns the bounds of the decision vector from the bounds lower and
+// upper of a static layout, every slot taking the bounds of its facility.
+func (d *DynamicLayout) Bounds(lower, upper []float64) ([]float64, []float64) {
+	dynamicLower := make([]float64, d.Dimensions())
+	dynamicUpper := make([]float64, d.Dimensions())
+
+	for i := range d.Slots {
+		for k := 0; k < max(1, len(d.Active[i])); k++ {
+			slot := (d.First[i] + k) * VariablesPerFacility
+			copy(dynamicLower[slot:slot+VariablesPerFacility], lower[i*VariablesPerFacility:])
+			copy(dynamicUpper[slot:slot+VariablesPerFacility], upper[i*VariablesPerFacility:])
+		}
+	}
+
+	return dynamicLower, dynamicUpper
+}
+
+// PhaseInput returns the static decision vector of the phase phaseIdx.
+func (d *DynamicLayout) PhaseInput(input []float64, phaseIdx int) []float64 {
+	return d.staticInput(input, func(i int) int {
+		return d.Slots[i][phaseIdx]
+	})
+}
+
+// FirstInput returns the static decision vector of every facility in its
+// first active phase.
+func (d *DynamicLayout) FirstInput(input []float64) []float64 {
+	return d.staticInput(input, func(i int) int {
+		return d.First[i]
+	})
+}
+
+// SetPhaseInput writes the variables of the facilities active in the phase
+// phaseIdx from phaseInput, a static decision vector, into input.
+func (d *DynamicLayout) SetPhaseInput(input []float64, phaseIdx int, phaseInput []float64) {
+	for i := range d.Slots {
+		if !slices.Contains(d.Active[i], phaseIdx) {
+			continue
+		}
+
+		slot := d.Slots[i][phaseIdx] * VariablesPerFacility
+		copy(input[slot:slot+VariablesPerFacility], phaseInput[i*VariablesPerFacility:])
+	}
+}
+
+func (d *DynamicLayout) staticInput(input []float64, slotOf func(i int) int) []float64 {
+	static := make([]float64, len(d.Slots)*VariablesPerFacility)
+	for i := range d.Slots {
+		slot := slotOf(i) * VariablesPerFacility
+		copy(static[i*VariablesPerFacility:(i+1)*VariablesPerFacility], input[slot:])
+	}
+
+	return static
+}
+
+// Evaluator is an objective or a constraint.
+type Evaluator interface {
+	Eval(mapLocations map[string]data.Location) (float64, error)
+}
+
+// Evaluate evaluates e on locations, or on the locations of every phase with
+// EvalPhases when phaseLocations is not nil.
+func Evaluate(e Evaluator, locations map[string]data.Location, phaseLocations []map[string]data.Location) (float64, error) {
+	if phaseLocations == nil {
+		return SafeEval(e.Eval, locations)
+	}
+
+	return EvalPhases(e, phaseLocations)
+}
+
+// EvaluateConstraint is Evaluate for a constraint. A constraint that does not
+// evaluate the phases itself is evaluated on activeLocations instead, the
+// locations of every phase without the facilities idle in it, see
+// ActivePhaseLocations.
+func EvaluateConstraint(c data.Constrainter, locations map[string]data.Location, phaseLocations []map[string]data.Location, activeLocations []map[string]data.Location) (float64, error) {
+	if _, ok := c.(data.PhaseEvaluator); ok || activeLocations == nil {
+		return Evaluate(c, locations, phaseLocations)
+	}
+
+	return EvalPhases(c, activeLocations)
+}
+
+// ActivePhaseLocations returns the locations of every phase with only the
+// facilities of the phase. A facility in no phase is kept in the first phase,
+// so that every facility counts once for each phase it is active in.
+func ActivePhaseLocations(phaseLocations []map[string]data.Location, phases [][]string) []map[string]data.Location {
+	if phaseLocations == nil {
+		return nil
+	}
+
+	inPhase := make(map[string]bool)
+	for _, phase := range phases {
+		for _, symbol := range phase {
+			inPhase[symbol] = true
+		}
+	}
+
+	activeLocations := make([]map[string]data.Location, len(phaseLocations))
+	for phaseIdx, locations := range phaseLocations {
+		activeLocations[phaseIdx] = make(map[string]data.Location, len(locations))
+		for symbol, loc := range locations {
+			active := phaseIdx == 0 && !inPhase[symbol]
+			if phaseIdx < len(phases) && slices.Contains(phases[phaseIdx], symbol) {
+				active = true
+			}
+			if active {
+				activeLocations[phaseIdx][symbol] = loc
+			}
+		}
+	}
+
+	return activeLocations
+}
+
+// EvalPhases evaluates e on the locations of every phase. An e implementing
+// data.PhaseEvaluator evaluates the phases itself, any other is evaluated on
+// the locations of every phase. The amounts of a data.Constrainter are summed
+// so that a violation in any phase counts in full, as in the constraints
+// evaluating the phases themselves, the values of an objective are averaged,
+// keeping the scale it has in a static layout. A panic becomes an error
+// wrapping ErrEvalPanic.
+func EvalPhases(e Evaluator, phaseLocations []map[string]data.Location) (val float64, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%w: %v", ErrEvalPanic, r)
+		}
+	}()
+
+	if evaluator, ok := e.(data.PhaseEvaluator); ok {
+		return evaluator.EvalPhases(phaseLocations)
+	}
+
+	if len(phaseLocations) == 0 {
+		return 0, nil
+	}
+
+	for _, locations := range phaseLocations {
+		amount, err := e.Eval(locations)
+		if err != nil {
+			return 0, err
+		}
+		val += amount
+	}
+
+	if _, ok := e.(data.Constrainter); ok {
+		return val, nil
+	}
+
+	return val / float64(len(phaseLocations)), nil
+}
+
+// PhasedProblem is implemented by the problems whose facilities can move
+// between phases.
+type PhasedProblem interface {
+	// GetPhaseLocationResult returns the locations in every phase, nil when
+	// the facilities do not move.
+	GetPhaseLocationResult(input []float64) []map[string]data.Location
+}
+
+// PhaseLocationResult returns the locations in every phase of input when
+// problem is a PhasedProblem, nil otherwise.
+func PhaseLocationResult(problem Problem, input []float64) []map[string]data.Location {
+	phased, ok := problem.(PhasedProblem)
+	if !ok {
+		return nil
+	}
+
+	return phased.GetPhaseLocationResult(input)
+}
@@ -0,0 +1,135 @@
+package objectives
+
+import (
+	"golang-moaha-construction/internal/data"
+	"slices"
+	"testing"
+)
+
+func TestDynamicLayout(t *testing.T) {
+	nonFixed := []data.Location{{Symbol: "TF1"}, {Symbol: "TF2"}, {Symbol: "TF3"}}
+	// TF2 is idle in phase 1, TF3 is in no phase
+	phases := [][]string{{"TF1", "TF2"}, {"TF1"}, {"TF1", "TF2"}}
+
+	d := NewDynamicLayout(nonFixed, phases)
+
+	if d.NumberOfSlots != 6 || d.Dimensions() != 18 {
+		t.Fatalf("expected 6 slots and 18 dimensions, got %d and %d", d.NumberOfSlots, d.Dimensions())
+	}
+
+	expectedSlots := [][]int{{0, 1, 2}, {3, 3, 4}, {5, 5, 5}}
+	for i := range expectedSlots {
+		if !slices.Equal(d.Slots[i], expectedSlots[i]) {
+			t.Errorf("expected the slots of facility %d to be %v, got %v", i, expectedSlots[i], d.Slots[i])
+		}
+	}
+
+	lower, upper := d.Bounds([]float64{0, 0, 0, 1, 1, 1, 2, 2, 2}, []float64{10, 10, 1, 11, 11, 1, 12, 12, 1})
+	if lower[3*3] != 1 || upper[4*3] != 11 || lower[5*3] != 2 {
+		t.Errorf("expected every slot to take the bounds of its facility, got %v and %v", lower, upper)
+	}
+
+	input := make([]float64, d.Dimensions())
+	for i := range input {
+		input[i] = float64(i)
+	}
+
+	phaseInput := d.PhaseInput(input, 1)
+	if !slices.Equal(phaseInput, []float64{3, 4, 5, 9, 10, 11, 15, 16, 17}) {
+		t.Errorf("unexpected input of phase 1: %v", phaseInput)
+	}
+
+	if first := d.FirstInput(input); !slices.Equal(first, []float64{0, 1, 2, 9, 10, 11, 15, 16, 17}) {
+		t.Errorf("unexpected first input: %v", first)
+	}
+
+	// only TF1 is active in phase 1, TF2 keeps its slot of phase 0
+	d.SetPhaseInput(input, 1, []float64{-1, -1, -1, -2, -2, -2, -3, -3, -3})
+	if input[3] != -1 || input[9] != 9 || input[15] != 15 {
+		t.Errorf("expected only the active facilities to be written, got %v", input)
+	}
+}
+
+// phaseAmount is evaluated as the x coordinate of TF1.
+type phaseAmount struct{}
+
+func (phaseAmount) Eval(mapLocations map[string]data.Location) (float64, error) {
+	return mapLocations["TF1"].Coordinate.X, nil
+}
+
+// phaseConstraint is a constraint without EvalPhases violated by the x
+// coordinate of TF1.
+type phaseConstraint struct {
+	testConstraint
+}
+
+func (phaseConstraint) Eval(mapLocations map[string]data.Location) (float64, error) {
+	return phaseAmount{}.Eval(mapLocations)
+}
+
+func TestEvalPhases(t *testing.T) {
+	phaseLocations := make([]map[string]data.Location, 0, 3)
+	for _, x := range []float64{0, 3, 6} {
+		phaseLocations = append(phaseLocations, map[string]data.Location{"TF1": {Coordinate: data.Coordinate{X: x}}})
+	}
+
+	// an objective keeps its static scale
+	val, err := EvalPhases(phaseAmount{}, phaseLocations)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if val != 3 {
+		t.Errorf("expected the objective to be averaged to 3, got %v", val)
+	}
+
+	// a violation in a single phase is not diluted by the others
+	val, err = EvalPhases(phaseConstraint{}, phaseLocations)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if val != 9 {
+		t.Errorf("expected the constraint amounts to be summed to 9, got %v", val)
+	}
+}
+
+// countConstraint is violated by every facility it is evaluated on.
+type countConstraint struct {
+	testConstraint
+}
+
+func (countConstraint) Eval(mapLocations map[string]data.Location) (float64, error) {
+	return float64(len(mapLocations)), nil
+}
+
+func TestEvaluateConstraint(t *testing.T) {
+	// TF2 is idle in phase 1, TF3 is in no phase
+	phases := [][]string{{"TF1", "TF2"}, {"TF1"}, {"TF1", "TF2"}}
+	locations := map[string]data.Location{"TF1": {}, "TF2": {}, "TF3": {}}
+	phaseLocations := data.SamePhaseLocations(locations, len(phases))
+
+	activeLocations := ActivePhaseLocations(phaseLocations, phases)
+	if _, ok := activeLocations[1]["TF2"]; ok {
+		t.Error("expected TF2 to be left out of phase 1")
+	}
+	if _, ok := activeLocations[0]["TF3"]; !ok {
+		t.Error("expected TF3 to be kept in the first phase")
+	}
+
+	// 2 + 1 + 2 active facilities and TF3 once
+	val, err := EvaluateConstraint(countConstraint{}, locations, phaseLocations, activeLocations)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if val != 6 {
+		t.Errorf("expected every facility to count once per active phase, got %v", val)
+	}
+
+	// a static layout is evaluated once
+	val, err = EvaluateConstraint(countConstraint{}, locations, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if val != 3 {
+		t.Errorf("expected the static layout to count every facility once, got %v", val)
+	}
+}
@@ -0,0 +1,61 @@
+package objectives
+
+import (
+	"errors"
+	"fmt"
+	"golang-moaha-construction/internal/data"
+	"sync"
+)
+
+var ErrEvalPanic = errors.New("evaluation panicked")
+
+// EvalErrors keeps the first error raised while evaluating positions.
+//
+// Problem.Eval is called from many goroutines inside the algorithms, so
+// problems embed EvalErrors and record failures here instead of returning
+// them. Algorithms check Err between iterations and stop the run.
+type EvalErrors struct {
+	mu  sync.Mutex
+	err error
+}
+
+// Record keeps err if no error has been recorded yet.
+func (e *EvalErrors) Record(err error) {
+	if err == nil {
+		return
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.err == nil {
+		e.err = err
+	}
+}
+
+// Err returns the first recorded error.
+func (e *EvalErrors) Err() error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	return e.err
+}
+
+// ResetErr forgets the recorded error before a new run.
+func (e *EvalErrors) ResetErr() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.err = nil
+}
+
+// SafeEval calls eval and turns a panic into an error wrapping ErrEvalPanic.
+func SafeEval(eval func(map[string]data.Location) (float64, error), locations map[string]data.Location) (val float64, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%w: %v", ErrEvalPanic, r)
+		}
+	}()
+
+	return eval(locations)
+}
@@ -0,0 +1,36 @@
+package objectives
+
+import (
+	"errors"
+	"golang-moaha-construction/internal/data"
+	"testing"
+)
+
+func TestSafeEval_RecoversPanic(t *testing.T) {
+	_, err := SafeEval(func(map[string]data.Location) (float64, error) {
+		panic("index out of range")
+	}, nil)
+
+	if !errors.Is(err, ErrEvalPanic) {
+		t.Errorf("expected ErrEvalPanic, got %v", err)
+	}
+}
+
+func TestEvalErrors_KeepsFirstError(t *testing.T) {
+	first := errors.New("first")
+	second := errors.New("second")
+
+	var evalErrors EvalErrors
+	evalErrors.Record(nil)
+	evalErrors.Record(first)
+	evalErrors.Record(second)
+
+	if !errors.Is(evalErrors.Err(), first) {
+		t.Errorf("expected first error, got %v", evalErrors.Err())
+	}
+
+	evalErrors.ResetErr()
+	if evalErrors.Err() != nil {
+		t.Errorf("expected no error after reset, got %v", evalErrors.Err())
+	}
+}
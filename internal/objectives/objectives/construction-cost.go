@@ -3,7 +3,6 @@ package objectives
 import (
 	"github.com/xuri/excelize/v2"
 	"golang-moaha-construction/internal/data"
-	"log"
 	"maps"
 	"slices"
 	"strconv"
@@ -48,7 +47,69 @@ func CreateConstructionCostObjectiveFromConfig(ccConfigs ConstructionCostConfigs
 	return ccObj, nil
 }
 
-func (obj *ConstructionCostObjective) Eval(locations map[string]data.Location) float64 {
+type constructionCostConfig struct {
+	AlphaConstructionCostPenalty float64 `json:"AlphaCCPenalty"`
+	FrequencyMatrixFilePath      string  `json:"FrequencyMatrixFilePath"`
+	DistanceMatrixFilePath       string  `json:"DistanceMatrixFilePath"`
+	GeneralQAP                   bool    `json:"GeneralQAP"`
+}
+
+func init() {
+	Register(ConstructionCostObjectiveType, Definition[constructionCostConfig, *ConstructionCostObjective]{
+		Schema: ConfigSchema{
+			Label:  "Construction Cost",
+			TSName: "ConstructionCostObjective",
+			Fields: []Field{
+				{Key: "FrequencyMatrixFilePath", Label: "Frequency Matrix file path", Type: FieldFile},
+				{Key: "DistanceMatrixFilePath", Label: "Distance Matrix file path", Type: FieldFile},
+				{Key: "AlphaCCPenalty", Label: "Alpha (for penalty)", Type: FieldNumber, Default: 100.0},
+				{Key: "GeneralQAP", Label: "General QAP", Type: FieldBool, Default: false},
+			},
+		},
+		Create: func(problem Problem, config constructionCostConfig) (*ConstructionCostObjective, error) {
+			frequencyMatrix, err := ReadMatrixFromFile(config.FrequencyMatrixFilePath)
+			if err != nil {
+				return nil, err
+			}
+
+			distanceMatrix, err := ReadMatrixFromFile(config.DistanceMatrixFilePath)
+			if err != nil {
+				return nil, err
+			}
+
+			return CreateConstructionCostObjectiveFromConfig(ConstructionCostConfigs{
+				FrequencyMatrix:              frequencyMatrix,
+				DistanceMatrix:               distanceMatrix,
+				FullRun:                      config.GeneralQAP,
+				Delta:                        1,
+				AlphaConstructionCostPenalty: config.AlphaConstructionCostPenalty,
+				FrequencyFilePath:            config.FrequencyMatrixFilePath,
+				DistanceFilePath:             config.DistanceMatrixFilePath,
+			})
+		},
+		Info: func(obj *ConstructionCostObjective) any {
+			return struct {
+				AlphaCCPenalty          float64 `json:"alphaCCPenalty"`
+				FrequencyMatrixFilePath string  `json:"frequencyMatrixFilePath"`
+				DistanceMatrixFilePath  string  `json:"distanceMatrixFilePath"`
+				GeneralQAP              bool    `json:"generalQAP"`
+			}{
+				AlphaCCPenalty:          obj.AlphaConstructionCostPenalty,
+				FrequencyMatrixFilePath: obj.FrequencyFilePath,
+				DistanceMatrixFilePath:  obj.DistanceFilePath,
+				GeneralQAP:              obj.FullRun,
+			}
+		},
+		Summary: func(obj *ConstructionCostObjective, w SummaryWriter) {
+			w.Value("Alpha (for penalty)", obj.AlphaConstructionCostPenalty)
+			w.Value("Frequency Matrix file path", obj.FrequencyFilePath)
+			w.Value("Distance Matrix file path", obj.DistanceFilePath)
+			w.Value("General QAP", obj.FullRun)
+		},
+	})
+}
+
+func (obj *ConstructionCostObjective) Eval(locations map[string]data.Location) (float64, error) {
 	results := 0.0
 
 	// convert to slices
@@ -87,21 +148,19 @@ func (obj *ConstructionCostObjective) Eval(locations map[string]data.Location) f
 			jSymbol := locJ.Symbol
 			fref, err := obj.FrequencyMatrix.GetCellValueFromNames(iSymbol, jSymbol)
 			if err != nil {
-				log.Fatal(err)
-				return 0
+				return 0, err
 			}
 
 			distance, err := obj.DistanceMatrix.GetCellValueFromNames(iLocatedAt, jLocatedAt)
 			if err != nil {
-				log.Fatal(err)
-				return 0
+				return 0, err
 			}
 
 			results += fref * distance
 		}
 	}
 
-	return results
+	return results, nil
 }
 
 func (obj *ConstructionCostObjective) GetAlphaPenalty() float64 {
@@ -336,7 +336,10 @@ func TestConstructionCostObjective_Eval(t *testing.T) {
 				log.Fatal(err)
 			}
 
-			result := ccObj.Eval(tt.locations)
+			result, err := ccObj.Eval(tt.locations)
+			if err != nil {
+				t.Fatal(err)
+			}
 			if math.Abs(tt.expected-result) > 1e-9 {
 				t.Errorf("expected result to be %f, got %f", tt.expected, result)
 			}
@@ -379,7 +382,10 @@ func TestConstructionCostObjectiveFacLessLoc_Eval(t *testing.T) {
 				log.Fatal(err)
 			}
 
-			result := ccObj.Eval(tt.locations)
+			result, err := ccObj.Eval(tt.locations)
+			if err != nil {
+				t.Fatal(err)
+			}
 			if math.Abs(tt.expected-result) > 1e-9 {
 				t.Errorf("expected result to be %f, got %f", tt.expected, result)
 			}
@@ -0,0 +1,115 @@
+package objectives
+
+import (
+	"fmt"
+	"github.com/xuri/excelize/v2"
+	"golang-moaha-construction/internal/data"
+	"strconv"
+	"strings"
+)
+
+// distanceFields are the config fields of the objectives whose distance
+// metric is selectable.
+var distanceFields = []Field{
+	{Key: "DistanceMetric", Label: "Distance metric", Type: FieldString, Default: string(data.EuclideanMetric)},
+	{Key: "RoadNetworkFilePath", Label: "Road network file path", Type: FieldFile},
+}
+
+func parseDistanceMetric(metric string) (data.DistanceMetric, error) {
+	switch strings.ToLower(strings.TrimSpace(metric)) {
+	case "", "euclidean":
+		return data.EuclideanMetric, nil
+	case "manhattan":
+		return data.ManhattanMetric, nil
+	case "network":
+		return data.NetworkMetric, nil
+	default:
+		return "", fmt.Errorf("invalid distance metric %q, expected euclidean, manhattan or network", metric)
+	}
+}
+
+// newDistanceProvider returns the provider of metric, reading the road
+// network from roadNetworkFilePath for the network metric.
+func newDistanceProvider(metric string, roadNetworkFilePath string) (data.DistanceMetric, data.DistanceProvider, error) {
+	distanceMetric, err := parseDistanceMetric(metric)
+	if err != nil {
+		return "", nil, err
+	}
+
+	switch distanceMetric {
+	case data.ManhattanMetric:
+		return distanceMetric, data.ManhattanDistance{}, nil
+	case data.NetworkMetric:
+		network, err := ReadRoadNetworkFromFile(roadNetworkFilePath)
+		if err != nil {
+			return "", nil, err
+		}
+		return distanceMetric, network, nil
+	default:
+		return distanceMetric, data.EuclideanDistance{}, nil
+	}
+}
+
+// distanceSummary writes the distance metric of an objective to the exported
+// summary.
+func distanceSummary(w SummaryWriter, metric data.DistanceMetric, roadNetworkFilePath string) {
+	if metric == "" {
+		metric = data.EuclideanMetric
+	}
+
+	w.Value("Distance metric", string(metric))
+	if metric == data.NetworkMetric {
+		w.Value("Road network file path", roadNetworkFilePath)
+	}
+}
+
+// ReadRoadNetworkFromFile reads the roads of a site from the first sheet of an
+// Excel file with the columns Road, X and Y after a header row. Each row is a
+// vertex; consecutive rows with the same road name make up one polyline.
+// Roads are connected where they share a vertex.
+func ReadRoadNetworkFromFile(filePath string) (*data.RoadNetwork, error) {
+	dataFile, err := excelize.OpenFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+	defer dataFile.Close()
+
+	rows, err := dataFile.GetRows(dataFile.GetSheetList()[0])
+	if err != nil {
+		return nil, err
+	}
+
+	roads := make([][]data.Coordinate, 0)
+	var road string
+	for rowIdx, row := range rows {
+		if rowIdx == 0 || len(row) == 0 || strings.TrimSpace(row[0]) == "" {
+			continue
+		}
+
+		if len(row) < 3 {
+			return nil, fmt.Errorf("row %d: expected the road, x and y of a vertex", rowIdx+1)
+		}
+
+		x, err := strconv.ParseFloat(strings.TrimSpace(row[1]), 64)
+		if err != nil {
+			return nil, fmt.Errorf("row %d: %w", rowIdx+1, err)
+		}
+		y, err := strconv.ParseFloat(strings.TrimSpace(row[2]), 64)
+		if err != nil {
+			return nil, fmt.Errorf("row %d: %w", rowIdx+1, err)
+		}
+
+		if name := strings.TrimSpace(row[0]); len(roads) == 0 || name != road {
+			road = name
+			roads = append(roads, nil)
+		}
+		roads[len(roads)-1] = append(roads[len(roads)-1], data.Coordinate{X: x, Y: y})
+	}
+
+	network := data.NewRoadNetwork(roads)
+	if len(network.Segments) == 0 {
+		return nil, fmt.Errorf("%s: no road with at least two vertices", filePath)
+	}
+
+	return network, nil
+}
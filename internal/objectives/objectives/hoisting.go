@@ -1,10 +1,12 @@
 package objectives
 
 import (
+	"fmt"
 	"github.com/xuri/excelize/v2"
 	"golang-moaha-construction/internal/data"
-	"log"
+	"maps"
 	"math"
+	"slices"
 	"strconv"
 	"strings"
 )
@@ -77,7 +79,178 @@ func CreateHoistingObjectiveFromConfig(hoistingConfigs HoistingConfigs) (*Hoisti
 	return hoistingObj, nil
 }
 
-func (obj *HoistingObjective) Eval(locations map[string]data.Location) float64 {
+type hoistingConfig struct {
+	CraneLocations []struct {
+		Name                 string  `json:"Name"`
+		Radius               float64 `json:"Radius"`
+		HoistingTimeFilePath string  `json:"HoistingTimeFilePath"`
+		ForBuilding          string  `json:"ForBuilding"`
+	}
+	Buildings []struct {
+		Name           string  `json:"Name"`
+		NumberOfFloors int     `json:"NumberOfFloors"`
+		FloorHeight    float64 `json:"FloorHeight"`
+	}
+	ZM                   float64 `json:"ZM"`
+	Vuvg                 float64 `json:"Vuvg"`
+	Vlvg                 float64 `json:"Vlvg"`
+	Vag                  float64 `json:"Vag"`
+	Vwg                  float64 `json:"Vwg"`
+	AlphaHoistingPenalty float64 `json:"AlphaHoistingPenalty"`
+	AlphaHoisting        float64 `json:"AlphaHoisting"`
+	BetaHoisting         float64 `json:"BetaHoisting"`
+	// DistanceMetric is not a field of the schema. The hook slews in a
+	// straight line, so a project asking for another metric is refused
+	// instead of being evaluated with the straight line.
+	DistanceMetric string `json:"DistanceMetric"`
+}
+
+func init() {
+	Register(HoistingObjectiveType, Definition[hoistingConfig, *HoistingObjective]{
+		Schema: ConfigSchema{
+			Label:  "Hoisting",
+			TSName: "HoistingObjective",
+			Fields: []Field{
+				{Key: "CraneLocations", Label: "Cranes", Type: FieldList, Fields: []Field{
+					{Key: "Name", Label: "Name", Type: FieldString},
+					{Key: "Radius", Label: "Radius", Type: FieldNumber},
+					{Key: "HoistingTimeFilePath", Label: "Hoisting file path", Type: FieldFile},
+					{Key: "ForBuilding", Label: "For building", Type: FieldString},
+				}},
+				{Key: "Buildings", Label: "Buildings", Type: FieldList, Fields: []Field{
+					{Key: "Name", Label: "Name", Type: FieldString},
+					{Key: "NumberOfFloors", Label: "Number of floors", Type: FieldInteger},
+					{Key: "FloorHeight", Label: "Floor height", Type: FieldNumber},
+				}},
+				{Key: "ZM", Label: "ZM", Type: FieldNumber, Default: 2.0},
+				{Key: "Vuvg", Label: "Vuvg", Type: FieldNumber, Default: 37.5},
+				{Key: "Vlvg", Label: "Vlvg", Type: FieldNumber, Default: 37.5 / 2},
+				{Key: "Vag", Label: "Vag", Type: FieldNumber, Default: 50.0},
+				{Key: "Vwg", Label: "Vwg", Type: FieldNumber, Default: 0.5},
+				{Key: "AlphaHoistingPenalty", Label: "Alpha (for penalty)", Type: FieldNumber, Default: 1.0},
+				{Key: "AlphaHoisting", Label: "Alpha", Type: FieldNumber, Default: 0.25},
+				{Key: "BetaHoisting", Label: "Beta", Type: FieldNumber, Default: 1.0},
+			},
+		},
+		Create: createHoistingObjective,
+		Info: func(obj *HoistingObjective) any {
+			return struct {
+				Buildings            map[string]Building       `json:"buildings"`
+				ZM                   float64                   `json:"zm"`
+				Vuvg                 float64                   `json:"vuvg"`
+				Vlvg                 float64                   `json:"vlvg"`
+				Vag                  float64                   `json:"vag"`
+				Vwg                  float64                   `json:"vwg"`
+				AlphaHoisting        float64                   `json:"alphaHoisting"`
+				BetaHoisting         float64                   `json:"betaHoisting"`
+				Phases               [][]string                `json:"phases"`
+				AlphaHoistingPenalty float64                   `json:"alphaHoistingPenalty"`
+				HoistingTime         map[string][]HoistingTime `json:"hoistingTime"`
+				CraneLocations       []data.Crane              `json:"craneLocations"`
+				HoistingTimeWithInfo []HoistingTimeWithInfo    `json:"hoistingTimeWithInfo"`
+			}{
+				Buildings:            obj.Buildings,
+				HoistingTime:         obj.HoistingTime,
+				CraneLocations:       obj.CraneLocations,
+				ZM:                   obj.ZM,
+				Vuvg:                 obj.Vuvg,
+				Vlvg:                 obj.Vlvg,
+				Vag:                  obj.Vag,
+				Vwg:                  obj.Vwg,
+				AlphaHoistingPenalty: obj.AlphaHoistingPenalty,
+				AlphaHoisting:        obj.AlphaHoisting,
+				BetaHoisting:         obj.BetaHoisting,
+				Phases:               obj.Phases,
+				HoistingTimeWithInfo: obj.HoistingTimeWithInfo,
+			}
+		},
+		Summary: func(obj *HoistingObjective, w SummaryWriter) {
+			w.Group("Buildings")
+			for _, name := range slices.Sorted(maps.Keys(obj.Buildings)) {
+				building := obj.Buildings[name]
+				w.Value(name, fmt.Sprintf("NumberOfFloors: %d, FloorHeight: %g", building.NumberOfFloors, building.FloorHeight))
+			}
+
+			w.Value("ZM", obj.ZM)
+			w.Value("Vuvg", obj.Vuvg)
+			w.Value("Vlvg", obj.Vlvg)
+			w.Value("Vag", obj.Vag)
+			w.Value("Vwg", obj.Vwg)
+			w.Value("Alpha (for penalty)", obj.AlphaHoistingPenalty)
+			w.Value("Alpha", obj.AlphaHoisting)
+			w.Value("Beta", obj.BetaHoisting)
+
+			for _, info := range obj.HoistingTimeWithInfo {
+				w.Group(info.CraneSymbol)
+				w.Value("Hoisting file path", info.FilePath)
+			}
+		},
+	})
+}
+
+// createHoistingObjective reads the hoisting time of every crane. A crane is
+// named CraneName-ForBuildingName, upper-cased.
+func createHoistingObjective(problem Problem, config hoistingConfig) (*HoistingObjective, error) {
+	metric, err := parseDistanceMetric(config.DistanceMetric)
+	if err != nil {
+		return nil, err
+	}
+	if metric != data.EuclideanMetric {
+		return nil, fmt.Errorf("the hoisting objective measures straight lines, got the %s distance metric", metric)
+	}
+
+	hoistingTime := make(map[string][]HoistingTime, len(config.CraneLocations))
+	cranesLocation := make([]data.Crane, len(config.CraneLocations))
+	buildings := make(map[string]Building)
+	hoistingTimeWithInfo := make([]HoistingTimeWithInfo, len(config.CraneLocations))
+
+	for i, craneLocation := range config.CraneLocations {
+		hoistingTimeForCrane, err := ReadHoistingTimeDataFromFile(craneLocation.HoistingTimeFilePath)
+		if err != nil {
+			return nil, err
+		}
+
+		craneSymbol := fmt.Sprintf("%s-%s", strings.ToUpper(craneLocation.Name), strings.ToUpper(craneLocation.ForBuilding))
+
+		hoistingTime[craneSymbol] = hoistingTimeForCrane
+		cranesLocation[i] = data.Crane{
+			CraneSymbol: craneSymbol,
+			Radius:      craneLocation.Radius,
+		}
+		hoistingTimeWithInfo[i] = HoistingTimeWithInfo{
+			CraneSymbol: craneSymbol,
+			FilePath:    craneLocation.HoistingTimeFilePath,
+		}
+	}
+
+	for _, building := range config.Buildings {
+		upperName := strings.ToUpper(building.Name)
+		if _, ok := buildings[upperName]; !ok {
+			buildings[upperName] = Building{
+				NumberOfFloors: building.NumberOfFloors,
+				FloorHeight:    building.FloorHeight,
+			}
+		}
+	}
+
+	return CreateHoistingObjectiveFromConfig(HoistingConfigs{
+		Buildings:            buildings,
+		HoistingTime:         hoistingTime,
+		CraneLocations:       cranesLocation,
+		ZM:                   config.ZM,
+		Vuvg:                 config.Vuvg,
+		Vlvg:                 config.Vlvg,
+		Vag:                  config.Vag,
+		Vwg:                  config.Vwg,
+		AlphaHoisting:        config.AlphaHoisting,
+		BetaHoisting:         config.BetaHoisting,
+		Phases:               problem.GetPhases(),
+		AlphaHoistingPenalty: config.AlphaHoistingPenalty,
+		HoistingTimeWithInfo: hoistingTimeWithInfo,
+	})
+}
+
+func (obj *HoistingObjective) Eval(locations map[string]data.Location) (float64, error) {
 
 	result := 0.0
 
@@ -97,10 +270,11 @@ func (obj *HoistingObjective) Eval(locations map[string]data.Location) float64 {
 			cranes[i].Length = loc.Length
 			cranes[i].Width = loc.Width
 			cranes[i].Rotation = loc.Rotation
+			cranes[i].Angle = loc.Angle
 			cranes[i].Symbol = loc.Symbol
 			cranes[i].Name = loc.Name
 		} else {
-			log.Fatal("[HoistingTime - Eval()] Crane location not found in locations map")
+			return 0, fmt.Errorf("crane location %s not found in locations map", craneSymbol)
 		}
 	}
 
@@ -137,7 +311,7 @@ func (obj *HoistingObjective) Eval(locations map[string]data.Location) float64 {
 		result += TB
 	}
 
-	return result
+	return result, nil
 }
 
 func (obj *HoistingObjective) GetAlphaPenalty() float64 {
@@ -576,7 +576,10 @@ func TestHoistingObjective_Eval(t *testing.T) {
 		hoistingObj.CraneLocations = craneLocations
 		t.Run(test.name, func(t *testing.T) {
 
-			result := hoistingObj.Eval(test.locations)
+			result, err := hoistingObj.Eval(test.locations)
+			if err != nil {
+				t.Fatal(err)
+			}
 			if util.RoundTo(result, 2) != test.expected {
 				t.Errorf("expected result to be %f, got %f", test.expected, result)
 			}
@@ -676,7 +679,10 @@ func TestHoistingObjectiveWith2CranesAnd4Building_Eval(t *testing.T) {
 		hoistingObj.CraneLocations = craneLocations
 		t.Run(test.name, func(t *testing.T) {
 
-			result := hoistingObj.Eval(test.locations)
+			result, err := hoistingObj.Eval(test.locations)
+			if err != nil {
+				t.Fatal(err)
+			}
 			if util.RoundTo(result, 2) != test.expected {
 				t.Errorf("expected result to be %f, got %f", test.expected, result)
 			}
@@ -684,3 +690,20 @@ func TestHoistingObjectiveWith2CranesAnd4Building_Eval(t *testing.T) {
 	}
 
 }
+
+func TestHoistingObjective_DistanceMetric(t *testing.T) {
+	problem := testProblem{}
+
+	// the hook slews in a straight line
+	for _, metric := range []string{"manhattan", "network"} {
+		_, err := New(HoistingObjectiveType, problem, map[string]any{"DistanceMetric": metric})
+		if err == nil {
+			t.Errorf("expected the %s metric to be refused", metric)
+		}
+	}
+
+	_, err := New(HoistingObjectiveType, problem, map[string]any{"DistanceMetric": "euclidean"})
+	if err != nil {
+		t.Errorf("expected the euclidean metric to be accepted, got %v", err)
+	}
+}
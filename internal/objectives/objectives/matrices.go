@@ -0,0 +1,86 @@
+package objectives
+
+import (
+	"fmt"
+	"github.com/xuri/excelize/v2"
+	"golang-moaha-construction/internal/data"
+	"regexp"
+	"strconv"
+)
+
+// phaseSheet matches the name of the sheet holding the matrix of a phase,
+// e.g. "Phase 2" for the second phase.
+var phaseSheet = regexp.MustCompile(`(?i)^\s*phase\s*(\d+)\s*$`)
+
+// readPhaseMatrices reads the matrices of an Excel file with readSheet. The
+// matrix of the phase N is on the sheet named "Phase N", numbered from 1
+// without gaps; other sheets, e.g. notes, are ignored. A file without such
+// sheets holds a single matrix, shared by every phase, on its first sheet.
+func readPhaseMatrices(filePath string, readSheet func(rows [][]string) (data.TwoDimensionalMatrix, error)) (data.PhaseMatrices, error) {
+	dataFile, err := excelize.OpenFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+	defer dataFile.Close()
+
+	sheets := dataFile.GetSheetList()
+	phaseSheets := make(map[int]string)
+	for _, sheet := range sheets {
+		match := phaseSheet.FindStringSubmatch(sheet)
+		if match == nil {
+			continue
+		}
+
+		phase, err := strconv.Atoi(match[1])
+		if err != nil {
+			return nil, fmt.Errorf("sheet %s: %w", sheet, err)
+		}
+		if other, ok := phaseSheets[phase]; ok {
+			return nil, fmt.Errorf("sheets %s and %s are both the phase %d", other, sheet, phase)
+		}
+
+		phaseSheets[phase] = sheet
+	}
+
+	names := []string{sheets[0]}
+	if len(phaseSheets) > 0 {
+		names = make([]string, len(phaseSheets))
+		for i := range names {
+			sheet, ok := phaseSheets[i+1]
+			if !ok {
+				return nil, fmt.Errorf("the phase sheets must be numbered from 1 without gaps, Phase %d is missing", i+1)
+			}
+
+			names[i] = sheet
+		}
+	}
+
+	matrices := make(data.PhaseMatrices, 0, len(names))
+	for _, sheet := range names {
+		rows, err := dataFile.GetRows(sheet)
+		if err != nil {
+			return nil, err
+		}
+		if len(rows) == 0 {
+			return nil, fmt.Errorf("sheet %s: the matrix is empty", sheet)
+		}
+
+		matrix, err := readSheet(rows)
+		if err != nil {
+			return nil, fmt.Errorf("sheet %s: %w", sheet, err)
+		}
+
+		matrices = append(matrices, matrix)
+	}
+
+	return matrices, nil
+}
+
+// matricesSummary describes matrices in the exported summary.
+func matricesSummary(matrices data.PhaseMatrices) string {
+	if matrices.IsPerPhase() {
+		return fmt.Sprintf("One per phase (%d)", len(matrices))
+	}
+
+	return "Shared by every phase"
+}
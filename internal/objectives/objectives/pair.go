@@ -0,0 +1,74 @@
+package objectives
+
+import (
+	"cmp"
+	"golang-moaha-construction/internal/data"
+	"strings"
+)
+
+// pairArrangement is a pair of facilities at their coordinates in a phase.
+// The facilities of a dynamic layout may be arranged differently in each
+// phase, those of a static layout are arranged once. Matrix is the index of
+// the matrix of the phase, so that the same arrangement counts again under
+// the matrix of another phase.
+type pairArrangement struct {
+	Pair   string
+	First  data.Coordinate
+	Second data.Coordinate
+	Matrix int
+}
+
+// comparePairArrangements orders arrangements by pair, then by coordinates,
+// then by matrix.
+func comparePairArrangements(a, b pairArrangement) int {
+	return cmp.Or(
+		strings.Compare(a.Pair, b.Pair),
+		cmp.Compare(a.First.X, b.First.X),
+		cmp.Compare(a.First.Y, b.First.Y),
+		cmp.Compare(a.Second.X, b.Second.X),
+		cmp.Compare(a.Second.Y, b.Second.Y),
+		cmp.Compare(a.Matrix, b.Matrix),
+	)
+}
+
+// pairWeights weighs the contribution of the arrangements of pairs in each
+// phase. When the phases have no duration, an arrangement counts once, in the
+// first phase it appears in. When they have one, an arrangement counts in
+// every phase it appears in, weighted by the duration of the phase.
+type pairWeights struct {
+	durations []float64
+	counted   map[pairArrangement]struct{}
+}
+
+func newPairWeights(durations []float64) *pairWeights {
+	return &pairWeights{
+		durations: durations,
+		counted:   make(map[pairArrangement]struct{}),
+	}
+}
+
+// weight returns the weight of arrangement in the phase phaseIdx, 0 when it
+// does not count.
+func (w *pairWeights) weight(phaseIdx int, arrangement pairArrangement) float64 {
+	if w.durations != nil {
+		return w.durations[phaseIdx]
+	}
+
+	if _, ok := w.counted[arrangement]; ok {
+		return 0
+	}
+	w.counted[arrangement] = struct{}{}
+
+	return 1
+}
+
+// phaseDurationsOf returns the durations of the phases of problem, nil when
+// they have none.
+func phaseDurationsOf(problem Problem) []float64 {
+	provider, ok := problem.(data.PhaseDurationProvider)
+	if !ok {
+		return nil
+	}
+
+	return provider.GetPhaseDurations()
+}
@@ -0,0 +1,260 @@
+package objectives
+
+import (
+	"errors"
+	"fmt"
+	"github.com/bytedance/sonic"
+	"golang-moaha-construction/internal/data"
+	"strings"
+	"sync"
+)
+
+var ErrUnknownObjective = errors.New("invalid objective name")
+
+// FieldType is the kind of value of a config field.
+type FieldType string
+
+const (
+	FieldNumber  FieldType = "number"
+	FieldInteger FieldType = "integer"
+	FieldBool    FieldType = "bool"
+	FieldString  FieldType = "string"
+	FieldFile    FieldType = "file"
+	// FieldList is a list of objects described by the Fields of the field
+	FieldList FieldType = "list"
+)
+
+// Field describes one key of an objective config.
+type Field struct {
+	Key     string    `json:"key"`
+	Label   string    `json:"label"`
+	Type    FieldType `json:"type"`
+	Default any       `json:"default,omitempty"`
+	// Fields describes the items of a FieldList
+	Fields []Field `json:"fields,omitempty"`
+}
+
+// ConfigSchema describes an objective and its config for the UI and the
+// exported summary.
+type ConfigSchema struct {
+	// Label is the name shown to the user
+	Label string `json:"label"`
+	// TSName is the name of the objective in the frontend enum
+	TSName string  `json:"tsName"`
+	Fields []Field `json:"fields"`
+}
+
+// Problem is what the factories need to know about the problem the objective
+// is added to.
+type Problem interface {
+	GetPhases() [][]string
+}
+
+// SummaryWriter receives the rows an objective writes to the exported
+// summary.
+type SummaryWriter interface {
+	// Value writes a labelled value on one row.
+	Value(label string, value any)
+	// Group writes a heading for the rows that follow.
+	Group(title string)
+}
+
+// Definition is everything a registered objective provides. C is the config
+// decoded with the json tags of C, O the created objective.
+type Definition[C any, O data.Objectiver] struct {
+	Schema ConfigSchema
+	Create func(problem Problem, config C) (O, error)
+	// Info is the view of a created objective sent to the UI.
+	Info func(obj O) any
+	// Summary writes a created objective to the exported summary.
+	Summary func(obj O, w SummaryWriter)
+}
+
+// Registration is a registered objective.
+type Registration struct {
+	Name   data.ObjectiveType `json:"name"`
+	Schema ConfigSchema       `json:"schema"`
+}
+
+// Info is the view of a created objective.
+type Info struct {
+	Name   data.ObjectiveType `json:"name"`
+	Label  string             `json:"label"`
+	Config any                `json:"config"`
+}
+
+type registration struct {
+	Registration
+	create  func(problem Problem, config map[string]any) (data.Objectiver, error)
+	info    func(obj data.Objectiver) (any, error)
+	summary func(obj data.Objectiver, w SummaryWriter) error
+}
+
+var (
+	registryMu sync.RWMutex
+	registry   []registration
+)
+
+// Register makes an objective available under name. It is meant to be called
+// from an init function and panics when name is registered twice.
+func Register[C any, O data.Objectiver](name data.ObjectiveType, def Definition[C, O]) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	for _, r := range registry {
+		if r.Name == name {
+			panic(fmt.Sprintf("objectives: %s registered twice", name))
+		}
+	}
+
+	cast := func(obj data.Objectiver) (O, error) {
+		o, ok := obj.(O)
+		if !ok {
+			return o, fmt.Errorf("%s: unexpected objective %T", name, obj)
+		}
+
+		return o, nil
+	}
+
+	registry = append(registry, registration{
+		Registration: Registration{
+			Name:   name,
+			Schema: def.Schema,
+		},
+		create: func(problem Problem, config map[string]any) (data.Objectiver, error) {
+			configBytes, err := sonic.Marshal(config)
+			if err != nil {
+				return nil, err
+			}
+
+			var c C
+			err = sonic.Unmarshal(configBytes, &c)
+			if err != nil {
+				return nil, err
+			}
+
+			return def.Create(problem, c)
+		},
+		info: func(obj data.Objectiver) (any, error) {
+			o, err := cast(obj)
+			if err != nil {
+				return nil, err
+			}
+
+			return def.Info(o), nil
+		},
+		summary: func(obj data.Objectiver, w SummaryWriter) error {
+			o, err := cast(obj)
+			if err != nil {
+				return err
+			}
+
+			def.Summary(o, w)
+			return nil
+		},
+	})
+}
+
+// Registered returns every registered objective in registration order.
+func Registered() []Registration {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	res := make([]Registration, len(registry))
+	for i, r := range registry {
+		res[i] = r.Registration
+	}
+
+	return res
+}
+
+func lookup(name data.ObjectiveType) (*registration, error) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	for i := range registry {
+		if registry[i].Name == name {
+			return &registry[i], nil
+		}
+	}
+
+	return nil, fmt.Errorf("%w: %s", ErrUnknownObjective, name)
+}
+
+// New creates the objective registered under name for problem. config is
+// anything that encodes to a JSON object, usually the config sent by the UI.
+// Missing keys take the defaults of the schema.
+func New(name data.ObjectiveType, problem Problem, config any) (data.Objectiver, error) {
+	r, err := lookup(name)
+	if err != nil {
+		return nil, err
+	}
+
+	values := make(map[string]any)
+	if config != nil {
+		configBytes, err := sonic.Marshal(config)
+		if err != nil {
+			return nil, err
+		}
+
+		err = sonic.Unmarshal(configBytes, &values)
+		if err != nil {
+			return nil, fmt.Errorf("objective config must be an object: %w", err)
+		}
+	}
+
+	// a null config decodes into a nil map
+	if values == nil {
+		values = make(map[string]any)
+	}
+
+	// keys are matched case-insensitively like the json decoder does
+	for _, field := range r.Schema.Fields {
+		if field.Default == nil || hasKey(values, field.Key) {
+			continue
+		}
+
+		values[field.Key] = field.Default
+	}
+
+	return r.create(problem, values)
+}
+
+// Describe returns the Info of obj, created under name.
+func Describe(name data.ObjectiveType, obj data.Objectiver) (Info, error) {
+	r, err := lookup(name)
+	if err != nil {
+		return Info{}, err
+	}
+
+	config, err := r.info(obj)
+	if err != nil {
+		return Info{}, err
+	}
+
+	return Info{
+		Name:   name,
+		Label:  r.Schema.Label,
+		Config: config,
+	}, nil
+}
+
+// WriteSummary writes obj, created under name, to w.
+func WriteSummary(name data.ObjectiveType, obj data.Objectiver, w SummaryWriter) error {
+	r, err := lookup(name)
+	if err != nil {
+		return err
+	}
+
+	return r.summary(obj, w)
+}
+
+func hasKey(values map[string]any, key string) bool {
+	for k := range values {
+		if strings.EqualFold(k, key) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,114 @@
+package objectives
+
+import (
+	"errors"
+	"golang-moaha-construction/internal/data"
+	"testing"
+)
+
+type testProblem struct {
+	phases [][]string
+}
+
+func (p testProblem) GetPhases() [][]string {
+	return p.phases
+}
+
+type testObjective struct {
+	Alpha  float64
+	Phases [][]string
+}
+
+func (obj *testObjective) Eval(map[string]data.Location) (float64, error) {
+	return 0, nil
+}
+
+func (obj *testObjective) GetAlphaPenalty() float64 {
+	return obj.Alpha
+}
+
+type recordingWriter []string
+
+func (w *recordingWriter) Value(label string, value any) {
+	*w = append(*w, label)
+}
+
+func (w *recordingWriter) Group(title string) {
+	*w = append(*w, "# "+title)
+}
+
+func TestRegistry(t *testing.T) {
+	const name data.ObjectiveType = "Registry Test Objective"
+
+	Register(name, Definition[struct {
+		Alpha float64 `json:"AlphaPenalty"`
+	}, *testObjective]{
+		Schema: ConfigSchema{
+			Label: "Registry Test",
+			Fields: []Field{
+				{Key: "AlphaPenalty", Label: "Alpha (for penalty)", Type: FieldNumber, Default: 100.0},
+			},
+		},
+		Create: func(problem Problem, config struct {
+			Alpha float64 `json:"AlphaPenalty"`
+		}) (*testObjective, error) {
+			return &testObjective{Alpha: config.Alpha, Phases: problem.GetPhases()}, nil
+		},
+		Info: func(obj *testObjective) any {
+			return obj.Alpha
+		},
+		Summary: func(obj *testObjective, w SummaryWriter) {
+			w.Group("Penalty")
+			w.Value("Alpha (for penalty)", obj.Alpha)
+		},
+	})
+
+	problem := testProblem{phases: [][]string{{"TF1", "TF2"}}}
+
+	obj, err := New(name, problem, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if alpha := obj.GetAlphaPenalty(); alpha != 100 {
+		t.Errorf("expected the default alpha, got %v", alpha)
+	}
+	if phases := obj.(*testObjective).Phases; len(phases) != 1 {
+		t.Errorf("expected the phases of the problem, got %v", phases)
+	}
+
+	// keys match the schema case-insensitively
+	obj, err = New(name, problem, map[string]any{"alphapenalty": 5})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if alpha := obj.GetAlphaPenalty(); alpha != 5 {
+		t.Errorf("expected the given alpha, got %v", alpha)
+	}
+
+	info, err := Describe(name, obj)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Label != "Registry Test" || info.Config != 5.0 {
+		t.Errorf("unexpected info %+v", info)
+	}
+
+	var w recordingWriter
+	err = WriteSummary(name, obj, &w)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(w) != 2 || w[0] != "# Penalty" {
+		t.Errorf("unexpected summary rows %v", w)
+	}
+
+	_, err = New("unknown", problem, nil)
+	if !errors.Is(err, ErrUnknownObjective) {
+		t.Errorf("expected ErrUnknownObjective, got %v", err)
+	}
+
+	_, err = Describe(SafetyObjectiveType, obj)
+	if err == nil {
+		t.Error("expected an error describing an objective of another type")
+	}
+}
@@ -0,0 +1,197 @@
+package objectives
+
+import (
+	"fmt"
+	"github.com/xuri/excelize/v2"
+	"golang-moaha-construction/internal/data"
+	"golang-moaha-construction/internal/util"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+const RelocationCostObjectiveType data.ObjectiveType = "Relocation Cost Objective"
+
+// MoveCost is what moving a facility between two of its phases costs.
+type MoveCost struct {
+	// Fixed is charged for every move, e.g. dismantling and re-erecting
+	Fixed float64
+	// PerMeter is charged for every metre the facility moves
+	PerMeter float64
+}
+
+type RelocationCostConfigs struct {
+	MoveCosts              map[string]MoveCost
+	AlphaRelocationPenalty float64
+	Phases                 [][]string
+	FilePath               string
+}
+
+// RelocationCostObjective charges for moving the facilities of a dynamic
+// layout between their consecutive active phases. It is 0 in a static layout.
+type RelocationCostObjective struct {
+	MoveCosts              map[string]MoveCost
+	AlphaRelocationPenalty float64
+	Phases                 [][]string
+	FilePath               string
+	symbols                []string
+}
+
+func CreateRelocationCostObjectiveFromConfig(relocationCostConfigs RelocationCostConfigs) (*RelocationCostObjective, error) {
+	relocationObj := &RelocationCostObjective{
+		MoveCosts:              relocationCostConfigs.MoveCosts,
+		AlphaRelocationPenalty: relocationCostConfigs.AlphaRelocationPenalty,
+		Phases:                 relocationCostConfigs.Phases,
+		FilePath:               relocationCostConfigs.FilePath,
+		// in a fixed order so that the same layout always gives the same value
+		symbols: slices.Sorted(maps.Keys(relocationCostConfigs.MoveCosts)),
+	}
+	return relocationObj, nil
+}
+
+type relocationCostConfig struct {
+	MoveCostsFilePath      string  `json:"MoveCostsFilePath"`
+	AlphaRelocationPenalty float64 `json:"AlphaRelocationPenalty"`
+}
+
+func init() {
+	Register(RelocationCostObjectiveType, Definition[relocationCostConfig, *RelocationCostObjective]{
+		Schema: ConfigSchema{
+			Label:  "Relocation Cost",
+			TSName: "RelocationCostObjective",
+			Fields: []Field{
+				{Key: "MoveCostsFilePath", Label: "Move costs file path", Type: FieldFile},
+				{Key: "AlphaRelocationPenalty", Label: "Alpha (for penalty)", Type: FieldNumber, Default: 100.0},
+			},
+		},
+		Create: func(problem Problem, config relocationCostConfig) (*RelocationCostObjective, error) {
+			moveCosts, err := ReadMoveCostsFromFile(config.MoveCostsFilePath)
+			if err != nil {
+				return nil, err
+			}
+
+			return CreateRelocationCostObjectiveFromConfig(RelocationCostConfigs{
+				MoveCosts:              moveCosts,
+				AlphaRelocationPenalty: config.AlphaRelocationPenalty,
+				Phases:                 problem.GetPhases(),
+				FilePath:               config.MoveCostsFilePath,
+			})
+		},
+		Info: func(obj *RelocationCostObjective) any {
+			return struct {
+				MoveCosts              map[string]MoveCost `json:"moveCosts"`
+				AlphaRelocationPenalty float64             `json:"alphaRelocationPenalty"`
+				Phases                 [][]string          `json:"phases"`
+				FilePath               string              `json:"filePath"`
+			}{
+				MoveCosts:              obj.MoveCosts,
+				AlphaRelocationPenalty: obj.AlphaRelocationPenalty,
+				Phases:                 obj.Phases,
+				FilePath:               obj.FilePath,
+			}
+		},
+		Summary: func(obj *RelocationCostObjective, w SummaryWriter) {
+			w.Value("Alpha (for penalty)", obj.AlphaRelocationPenalty)
+			w.Value("Move costs file path", obj.FilePath)
+
+			w.Group("Move costs (fixed + per metre)")
+			symbols := slices.SortedFunc(slices.Values(obj.symbols), func(a, b string) int {
+				return util.ExtractNumber(a) - util.ExtractNumber(b)
+			})
+			for _, symbol := range symbols {
+				cost := obj.MoveCosts[symbol]
+				w.Value(symbol, fmt.Sprintf("%g + %g", cost.Fixed, cost.PerMeter))
+			}
+		},
+	})
+}
+
+// Eval is 0, the facilities of a static layout never move.
+func (obj *RelocationCostObjective) Eval(locations map[string]data.Location) (float64, error) {
+	return obj.EvalPhases(data.SamePhaseLocations(locations, len(obj.Phases)))
+}
+
+// EvalPhases charges the move cost of a facility every time its centre moves
+// between two of its consecutive active phases.
+func (obj *RelocationCostObjective) EvalPhases(phaseLocations []map[string]data.Location) (float64, error) {
+	result := 0.0
+
+	for _, symbol := range obj.symbols {
+		cost := obj.MoveCosts[symbol]
+
+		var previous *data.Location
+		for phaseIdx, phase := range obj.Phases {
+			if !slices.Contains(phase, symbol) {
+				continue
+			}
+
+			loc, ok := phaseLocations[phaseIdx][symbol]
+			if !ok {
+				continue
+			}
+
+			if previous != nil {
+				distance := data.Distance2D(previous.Coordinate, loc.Coordinate)
+				if distance > 0 {
+					result += cost.Fixed + cost.PerMeter*distance
+				}
+			}
+			previous = &loc
+		}
+	}
+
+	return result, nil
+}
+
+func (obj *RelocationCostObjective) GetAlphaPenalty() float64 {
+	return obj.AlphaRelocationPenalty
+}
+
+// ReadMoveCostsFromFile reads the move costs from the first sheet of an Excel
+// file with the columns Symbol, Fixed cost and Cost per metre after a header
+// row. The cost per metre is optional. Facilities missing from the file move
+// for free.
+func ReadMoveCostsFromFile(filePath string) (map[string]MoveCost, error) {
+	dataFile, err := excelize.OpenFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+	defer dataFile.Close()
+
+	rows, err := dataFile.GetRows(dataFile.GetSheetList()[0])
+	if err != nil {
+		return nil, err
+	}
+
+	moveCosts := make(map[string]MoveCost, len(rows))
+	for rowIdx, row := range rows {
+		if rowIdx == 0 || len(row) == 0 || strings.TrimSpace(row[0]) == "" {
+			continue
+		}
+
+		symbol := strings.ToUpper(strings.TrimSpace(row[0]))
+		if err := util.ValidateTFNumber(symbol); err != nil {
+			return nil, fmt.Errorf("row %d: %w", rowIdx+1, err)
+		}
+
+		var cost MoveCost
+		for i, target := range []*float64{&cost.Fixed, &cost.PerMeter} {
+			if len(row) <= i+1 || strings.TrimSpace(row[i+1]) == "" {
+				continue
+			}
+
+			*target, err = strconv.ParseFloat(strings.TrimSpace(row[i+1]), 64)
+			if err != nil {
+				return nil, fmt.Errorf("row %d: %w", rowIdx+1, err)
+			}
+			if *target < 0 {
+				return nil, fmt.Errorf("row %d: the cost must not be negative", rowIdx+1)
+			}
+		}
+
+		moveCosts[symbol] = cost
+	}
+
+	return moveCosts, nil
+}
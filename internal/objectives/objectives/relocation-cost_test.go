@@ -0,0 +1,71 @@
+package objectives
+
+import (
+	"golang-moaha-construction/internal/data"
+	"testing"
+)
+
+func TestRelocationCostObjective_EvalPhases(t *testing.T) {
+	relocationObj, err := CreateRelocationCostObjectiveFromConfig(RelocationCostConfigs{
+		MoveCosts: map[string]MoveCost{
+			"TF1": {Fixed: 10, PerMeter: 2},
+			"TF2": {Fixed: 5},
+		},
+		AlphaRelocationPenalty: 100,
+		// TF2 is idle in phase 2, its move is measured from phase 1 to phase 3
+		Phases: [][]string{{"TF1", "TF2"}, {"TF1"}, {"TF1", "TF2"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	at := func(x, y float64) data.Location {
+		return data.Location{Coordinate: data.Coordinate{X: x, Y: y}}
+	}
+
+	testTable := []struct {
+		name           string
+		phaseLocations []map[string]data.Location
+		expected       float64
+	}{
+		{
+			name: "no moves",
+			phaseLocations: []map[string]data.Location{
+				{"TF1": at(0, 0), "TF2": at(10, 10)},
+				{"TF1": at(0, 0)},
+				{"TF1": at(0, 0), "TF2": at(10, 10)},
+			},
+			expected: 0,
+		},
+		{
+			name: "moves",
+			phaseLocations: []map[string]data.Location{
+				{"TF1": at(0, 0), "TF2": at(10, 10)},
+				{"TF1": at(3, 4)},
+				{"TF1": at(3, 4), "TF2": at(20, 10)},
+			},
+			// TF1 moves 5 m once, TF2 moves once
+			expected: 10 + 2*5 + 5,
+		},
+	}
+
+	for _, test := range testTable {
+		t.Run(test.name, func(t *testing.T) {
+			result, err := relocationObj.EvalPhases(test.phaseLocations)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if result != test.expected {
+				t.Errorf("expected result to be %f, got %f", test.expected, result)
+			}
+		})
+	}
+
+	static, err := relocationObj.Eval(map[string]data.Location{"TF1": at(0, 0), "TF2": at(10, 10)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if static != 0 {
+		t.Errorf("expected a static layout to cost 0, got %f", static)
+	}
+}
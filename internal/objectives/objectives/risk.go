@@ -2,10 +2,10 @@ package objectives
 
 import (
 	"fmt"
-	"github.com/xuri/excelize/v2"
 	"golang-moaha-construction/internal/data"
 	"golang-moaha-construction/internal/util"
-	"log"
+	"maps"
+	"slices"
 	"strconv"
 	"strings"
 )
@@ -13,18 +13,20 @@ import (
 const RiskObjectiveType data.ObjectiveType = "Risk Objective"
 
 type RiskConfigs struct {
-	HazardInteractionMatrix data.TwoDimensionalMatrix
+	HazardInteractionMatrix data.PhaseMatrices
 	Delta                   float64
 	AlphaRiskPenalty        float64
 	Phases                  [][]string
+	PhaseDurations          []float64
 	FilePath                string
 }
 
 type RiskObjective struct {
-	HazardInteractionMatrix data.TwoDimensionalMatrix
+	HazardInteractionMatrix data.PhaseMatrices
 	Delta                   float64
 	AlphaRiskPenalty        float64
 	Phases                  [][]string
+	PhaseDurations          []float64
 	FilePath                string
 }
 
@@ -34,39 +36,110 @@ func CreateRiskObjectiveFromConfig(riskConfigs RiskConfigs) (*RiskObjective, err
 		Delta:                   riskConfigs.Delta,
 		AlphaRiskPenalty:        riskConfigs.AlphaRiskPenalty,
 		Phases:                  riskConfigs.Phases,
+		PhaseDurations:          riskConfigs.PhaseDurations,
 		FilePath:                riskConfigs.FilePath,
 	}
+	if err := riskObj.HazardInteractionMatrix.Validate(len(riskObj.Phases)); err != nil {
+		return nil, err
+	}
+
 	return riskObj, nil
 }
 
-func (obj *RiskObjective) Eval(locations map[string]data.Location) float64 {
-	mapFacility := make(map[string]struct {
+type riskConfig struct {
+	HazardInteractionMatrixFilePath string  `json:"hazardInteractionMatrixFilePath"`
+	Delta                           float64 `json:"Delta"`
+	AlphaRiskPenalty                float64 `json:"AlphaRiskPenalty"`
+}
+
+func init() {
+	Register(RiskObjectiveType, Definition[riskConfig, *RiskObjective]{
+		Schema: ConfigSchema{
+			Label:  "Risk",
+			TSName: "RiskObjective",
+			Fields: []Field{
+				{Key: "HazardInteractionMatrixFilePath", Label: "Hazard Interaction Matrix file path", Type: FieldFile},
+				{Key: "Delta", Label: "Delta", Type: FieldNumber, Default: 0.01},
+				{Key: "AlphaRiskPenalty", Label: "Alpha (for penalty)", Type: FieldNumber, Default: 100.0},
+			},
+		},
+		Create: func(problem Problem, config riskConfig) (*RiskObjective, error) {
+			hazardInteractionMatrix, err := ReadRiskHazardInteractionDataFromFile(config.HazardInteractionMatrixFilePath)
+			if err != nil {
+				return nil, err
+			}
+
+			return CreateRiskObjectiveFromConfig(RiskConfigs{
+				HazardInteractionMatrix: hazardInteractionMatrix,
+				Delta:                   config.Delta,
+				AlphaRiskPenalty:        config.AlphaRiskPenalty,
+				Phases:                  problem.GetPhases(),
+				PhaseDurations:          phaseDurationsOf(problem),
+				FilePath:                config.HazardInteractionMatrixFilePath,
+			})
+		},
+		Info: func(obj *RiskObjective) any {
+			return struct {
+				HazardInteractionMatrix data.PhaseMatrices `json:"hazardInteractionMatrix"`
+				Delta                   float64            `json:"delta"`
+				AlphaRiskPenalty        float64            `json:"alphaRiskPenalty"`
+				Phases                  [][]string         `json:"phases"`
+				PhaseDurations          []float64          `json:"phaseDurations"`
+				FilePath                string             `json:"filePath"`
+			}{
+				HazardInteractionMatrix: obj.HazardInteractionMatrix,
+				Delta:                   obj.Delta,
+				AlphaRiskPenalty:        obj.AlphaRiskPenalty,
+				Phases:                  obj.Phases,
+				PhaseDurations:          obj.PhaseDurations,
+				FilePath:                obj.FilePath,
+			}
+		},
+		Summary: func(obj *RiskObjective, w SummaryWriter) {
+			w.Value("Delta", obj.Delta)
+			w.Value("Alpha (for penalty)", obj.AlphaRiskPenalty)
+			w.Value("Hazard Interaction Matrix file path", obj.FilePath)
+			w.Value("Matrices", matricesSummary(obj.HazardInteractionMatrix))
+		},
+	})
+}
+
+func (obj *RiskObjective) Eval(locations map[string]data.Location) (float64, error) {
+	return obj.EvalPhases(data.SamePhaseLocations(locations, len(obj.Phases)))
+}
+
+// EvalPhases evaluates every phase with its own locations. Without phase
+// durations, the risk of a pair of facilities counts once for each of their
+// arrangements, so once in a static layout. With durations, the risk of every
+// phase counts, weighted by the duration of the phase.
+func (obj *RiskObjective) EvalPhases(phaseLocations []map[string]data.Location) (float64, error) {
+	mapFacility := make(map[pairArrangement]struct {
 		Count int
 		Value float64
 	})
 
 	results := 0.0
 
-	for _, phases := range obj.Phases {
-		hij := util.CopySliceOfSlice(obj.HazardInteractionMatrix.Matrix)
+	for phaseIdx, phases := range obj.Phases {
+		locations := phaseLocations[phaseIdx]
+		matrix := obj.HazardInteractionMatrix.Phase(phaseIdx)
+		hij := util.CopySliceOfSlice(matrix.Matrix)
 
 		for i := 0; i < len(phases); i++ {
 			facilityNameI := phases[i]
 			facilityI := locations[facilityNameI]
-			idxI, err := obj.HazardInteractionMatrix.GetIdxFromName(facilityNameI)
+			idxI, err := matrix.GetIdxFromName(facilityNameI)
 			if err != nil {
-				log.Fatal(err)
-				return 0
+				return 0, err
 			}
 
 			hio := hij[idxI][idxI]
 			for j := 0; j < len(phases); j++ {
 				facilityNameJ := phases[j]
 				facilityJ := locations[facilityNameJ]
-				idxJ, err := obj.HazardInteractionMatrix.GetIdxFromName(facilityNameJ)
+				idxJ, err := matrix.GetIdxFromName(facilityNameJ)
 				if err != nil {
-					log.Fatal(err)
-					return 0
+					return 0, err
 				}
 
 				computed := hio
@@ -76,7 +149,12 @@ func (obj *RiskObjective) Eval(locations map[string]data.Location) float64 {
 
 				hijComputed := max(0, computed)
 
-				k := fmt.Sprintf("%s-%s", facilityNameI, facilityNameJ)
+				k := pairArrangement{
+					Pair:   fmt.Sprintf("%s-%s", facilityNameI, facilityNameJ),
+					First:  facilityI.Coordinate,
+					Second: facilityJ.Coordinate,
+					Matrix: obj.HazardInteractionMatrix.Index(phaseIdx),
+				}
 
 				if v, ok := mapFacility[k]; ok {
 					v.Count++
@@ -95,51 +173,55 @@ func (obj *RiskObjective) Eval(locations map[string]data.Location) float64 {
 		phaseResult := 0.0
 		for i := 0; i < len(phases); i++ {
 			facilityNameI := phases[i]
-			idxI, err := obj.HazardInteractionMatrix.GetIdxFromName(facilityNameI)
+			idxI, err := matrix.GetIdxFromName(facilityNameI)
 			if err != nil {
-				log.Fatal(err)
-				return 0
+				return 0, err
 			}
 
 			for j := 0; j < len(phases); j++ {
 				facilityNameJ := phases[j]
-				idxJ, err := obj.HazardInteractionMatrix.GetIdxFromName(facilityNameJ)
+				idxJ, err := matrix.GetIdxFromName(facilityNameJ)
 				if err != nil {
-					log.Fatal(err)
-					return 0
+					return 0, err
 				}
 
 				phaseResult += hij[idxI][idxJ] * hij[idxI][idxJ]
 			}
 		}
 
+		if obj.PhaseDurations != nil {
+			phaseResult *= obj.PhaseDurations[phaseIdx]
+		}
 		results += phaseResult
 	}
 
-	for _, v := range mapFacility {
+	if obj.PhaseDurations != nil {
+		return results, nil
+	}
+
+	// in a fixed order so that the same layout always gives the same value
+	for _, k := range slices.SortedFunc(maps.Keys(mapFacility), comparePairArrangements) {
+		v := mapFacility[k]
 		if v.Count > 1 {
 			results -= (float64(v.Count) - 1) * v.Value
 		}
 	}
 
-	return results
+	return results, nil
 }
 
 func (obj *RiskObjective) GetAlphaPenalty() float64 {
 	return obj.AlphaRiskPenalty
 }
 
-func ReadRiskHazardInteractionDataFromFile(filePath string) (data.TwoDimensionalMatrix, error) {
-	dataFile, err := excelize.OpenFile(filePath)
-	if err != nil {
-		return data.TwoDimensionalMatrix{}, err
-	}
-
-	rows, err := dataFile.GetRows("Sheet1")
-	if err != nil {
-		return data.TwoDimensionalMatrix{}, err
-	}
+// ReadRiskHazardInteractionDataFromFile reads the hazard interaction matrix of
+// every phase from the sheets "Phase 1", "Phase 2", ..., or a single matrix
+// shared by every phase from the first sheet.
+func ReadRiskHazardInteractionDataFromFile(filePath string) (data.PhaseMatrices, error) {
+	return readPhaseMatrices(filePath, readRiskHazardInteractionSheet)
+}
 
+func readRiskHazardInteractionSheet(rows [][]string) (data.TwoDimensionalMatrix, error) {
 	facilitiesName := make([]string, len(rows)-1)
 
 	for idx, cell := range rows[0] {
@@ -207,7 +207,10 @@ func TestRiskObjective_Eval(t *testing.T) {
 	for _, test := range testTable {
 		t.Run(test.name, func(t *testing.T) {
 
-			result := hoistingObj.Eval(test.locations)
+			result, err := hoistingObj.Eval(test.locations)
+			if err != nil {
+				t.Fatal(err)
+			}
 			if util.RoundTo(result, 2) != test.expected {
 				t.Errorf("expected result to be %f, got %f", test.expected, result)
 			}
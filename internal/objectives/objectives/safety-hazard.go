@@ -13,6 +13,7 @@ type SafetyHazardConfigs struct {
 	SEMatrix       data.TwoDimensionalMatrix
 	AlphaSHPenalty float64
 	Phases         [][]string
+	PhaseDurations []float64
 	FilePath       string
 }
 
@@ -20,6 +21,7 @@ type SafetyHazardObjective struct {
 	SEMatrix       data.TwoDimensionalMatrix
 	AlphaSHPenalty float64
 	Phases         [][]string
+	PhaseDurations []float64
 	FilePath       string
 }
 
@@ -28,24 +30,85 @@ func CreateSafetyHazardObjectiveFromConfig(hsConfigs SafetyHazardConfigs) (*Safe
 		SEMatrix:       hsConfigs.SEMatrix,
 		AlphaSHPenalty: hsConfigs.AlphaSHPenalty,
 		Phases:         hsConfigs.Phases,
+		PhaseDurations: hsConfigs.PhaseDurations,
 		FilePath:       hsConfigs.FilePath,
 	}
 	return tcObj, nil
 }
 
-func (obj *SafetyHazardObjective) Eval(locations map[string]data.Location) float64 {
+type safetyHazardConfig struct {
+	SEMatrixFilePath         string  `json:"SEMatrixFilePath"`
+	AlphaSafetyHazardPenalty float64 `json:"AlphaSafetyHazardPenalty"`
+}
+
+func init() {
+	Register(SafetyHazardObjectiveType, Definition[safetyHazardConfig, *SafetyHazardObjective]{
+		Schema: ConfigSchema{
+			Label:  "Safety Hazard",
+			TSName: "SafetyHazardObjective",
+			Fields: []Field{
+				{Key: "SEMatrixFilePath", Label: "Safety and Environmental Concerns Matrix file path", Type: FieldFile},
+				{Key: "AlphaSafetyHazardPenalty", Label: "Alpha (for penalty)", Type: FieldNumber, Default: 100.0},
+			},
+		},
+		Create: func(problem Problem, config safetyHazardConfig) (*SafetyHazardObjective, error) {
+			seMatrix, err := ReadSafetyAndEnvDataFromFile(config.SEMatrixFilePath)
+			if err != nil {
+				return nil, err
+			}
+
+			return CreateSafetyHazardObjectiveFromConfig(SafetyHazardConfigs{
+				SEMatrix:       seMatrix,
+				AlphaSHPenalty: config.AlphaSafetyHazardPenalty,
+				Phases:         problem.GetPhases(),
+				PhaseDurations: phaseDurationsOf(problem),
+				FilePath:       config.SEMatrixFilePath,
+			})
+		},
+		Info: func(obj *SafetyHazardObjective) any {
+			return struct {
+				SEMatrix                 data.TwoDimensionalMatrix `json:"seMatrix"`
+				AlphaSafetyHazardPenalty float64                   `json:"alphaSafetyHazardPenalty"`
+				Phases                   [][]string                `json:"phases"`
+				PhaseDurations           []float64                 `json:"phaseDurations"`
+				FilePath                 string                    `json:"filePath"`
+			}{
+				SEMatrix:                 obj.SEMatrix,
+				AlphaSafetyHazardPenalty: obj.AlphaSHPenalty,
+				Phases:                   obj.Phases,
+				PhaseDurations:           obj.PhaseDurations,
+				FilePath:                 obj.FilePath,
+			}
+		},
+		Summary: func(obj *SafetyHazardObjective, w SummaryWriter) {
+			w.Value("Alpha (for penalty)", obj.AlphaSHPenalty)
+			w.Value("Safety and Environmental Concerns Matrix file path", obj.FilePath)
+		},
+	})
+}
+
+func (obj *SafetyHazardObjective) Eval(locations map[string]data.Location) (float64, error) {
+	return obj.EvalPhases(data.SamePhaseLocations(locations, len(obj.Phases)))
+}
+
+// EvalPhases evaluates every phase with its own locations. Without phase
+// durations, a pair of facilities counts once for each of their arrangements,
+// so once in a static layout. With durations, a pair counts in every phase it
+// is in, weighted by the duration of the phase.
+func (obj *SafetyHazardObjective) EvalPhases(phaseLocations []map[string]data.Location) (float64, error) {
 	result := 0.0
 
-	calculatedMap := make(map[string]struct{})
+	weights := newPairWeights(obj.PhaseDurations)
 
-	for _, phases := range obj.Phases {
+	for phaseIdx, phases := range obj.Phases {
+		locations := phaseLocations[phaseIdx]
 
 		for i := 0; i < len(phases); i++ {
 			facilityNameI := phases[i]
 			facilityI := locations[facilityNameI]
 			idxI, err := obj.SEMatrix.GetIdxFromName(facilityNameI)
 			if err != nil {
-				return 0
+				return 0, err
 			}
 
 			for j := 0; j < len(phases); j++ {
@@ -56,24 +119,29 @@ func (obj *SafetyHazardObjective) Eval(locations map[string]data.Location) float
 				facilityJ := locations[facilityNameJ]
 				idxJ, err := obj.SEMatrix.GetIdxFromName(facilityNameJ)
 				if err != nil {
-					return 0
+					return 0, err
 				}
 
-				if _, ok := calculatedMap[facilityNameI+facilityNameJ]; ok {
+				arrangement := pairArrangement{
+					Pair:   facilityNameI + facilityNameJ,
+					First:  facilityI.Coordinate,
+					Second: facilityJ.Coordinate,
+				}
+				if obj.SEMatrix.Matrix[idxI][idxJ] == 0 {
 					continue
 				}
 
-				if obj.SEMatrix.Matrix[idxI][idxJ] == 0 {
+				weight := weights.weight(phaseIdx, arrangement)
+				if weight == 0 {
 					continue
 				}
 
-				result += (-data.Distance2D(facilityI.Coordinate, facilityJ.Coordinate)) / obj.SEMatrix.Matrix[idxI][idxJ]
-				calculatedMap[facilityNameI+facilityNameJ] = struct{}{}
+				result += weight * (-data.Distance2D(facilityI.Coordinate, facilityJ.Coordinate)) / obj.SEMatrix.Matrix[idxI][idxJ]
 			}
 		}
 	}
 
-	return result
+	return result, nil
 }
 
 func (obj *SafetyHazardObjective) GetAlphaPenalty() float64 {
@@ -37,7 +37,10 @@ func TestSafetyHazardObjectiveMini_Eval(t *testing.T) {
 	for _, test := range testTable {
 		t.Run(test.name, func(t *testing.T) {
 
-			result := safetyHazardObj.Eval(test.locations)
+			result, err := safetyHazardObj.Eval(test.locations)
+			if err != nil {
+				t.Fatal(err)
+			}
 			if util.RoundTo(result, 2) != test.expected {
 				t.Errorf("expected result to be %f, got %f", test.expected, result)
 			}
@@ -1,9 +1,7 @@
 package objectives
 
 import (
-	"github.com/xuri/excelize/v2"
 	"golang-moaha-construction/internal/data"
-	"log"
 	"strconv"
 	"strings"
 )
@@ -11,82 +9,183 @@ import (
 const SafetyObjectiveType data.ObjectiveType = "Safety Objective"
 
 type SafetyConfigs struct {
-	SafetyProximity    data.TwoDimensionalMatrix
+	SafetyProximity    data.PhaseMatrices
 	AlphaSafetyPenalty float64
 	Phases             [][]string
+	PhaseDurations     []float64
 	FilePath           string
+	// Distance measures the distance between two facilities, Euclidean when
+	// nil
+	Distance            data.DistanceProvider
+	DistanceMetric      data.DistanceMetric
+	RoadNetworkFilePath string
 }
 
 type SafetyObjective struct {
-	SafetyProximity    data.TwoDimensionalMatrix
+	SafetyProximity    data.PhaseMatrices
 	AlphaSafetyPenalty float64
 	Phases             [][]string
+	PhaseDurations     []float64
 	FilePath           string
+	// Distance measures the distance between two facilities
+	Distance            data.DistanceProvider
+	DistanceMetric      data.DistanceMetric
+	RoadNetworkFilePath string
 }
 
 func CreateSafetyObjectiveFromConfig(safetyConfigs SafetyConfigs) (*SafetyObjective, error) {
 	safetyObj := &SafetyObjective{
-		SafetyProximity:    safetyConfigs.SafetyProximity,
-		AlphaSafetyPenalty: safetyConfigs.AlphaSafetyPenalty,
-		Phases:             safetyConfigs.Phases,
-		FilePath:           safetyConfigs.FilePath,
+		SafetyProximity:     safetyConfigs.SafetyProximity,
+		AlphaSafetyPenalty:  safetyConfigs.AlphaSafetyPenalty,
+		Phases:              safetyConfigs.Phases,
+		PhaseDurations:      safetyConfigs.PhaseDurations,
+		FilePath:            safetyConfigs.FilePath,
+		Distance:            safetyConfigs.Distance,
+		DistanceMetric:      safetyConfigs.DistanceMetric,
+		RoadNetworkFilePath: safetyConfigs.RoadNetworkFilePath,
 	}
+	if err := safetyObj.SafetyProximity.Validate(len(safetyObj.Phases)); err != nil {
+		return nil, err
+	}
+
+	if safetyObj.Distance == nil {
+		safetyObj.Distance = data.EuclideanDistance{}
+	}
+
 	return safetyObj, nil
 }
 
-func (obj *SafetyObjective) Eval(locations map[string]data.Location) float64 {
+type safetyConfig struct {
+	SafetyProximityMatrixFilePath string  `json:"safetyProximityMatrixFilePath"`
+	AlphaSafetyPenalty            float64 `json:"AlphaSafetyPenalty"`
+	DistanceMetric                string  `json:"DistanceMetric"`
+	RoadNetworkFilePath           string  `json:"RoadNetworkFilePath"`
+}
+
+func init() {
+	Register(SafetyObjectiveType, Definition[safetyConfig, *SafetyObjective]{
+		Schema: ConfigSchema{
+			Label:  "Safety",
+			TSName: "SafetyObjective",
+			Fields: append([]Field{
+				{Key: "SafetyProximityMatrixFilePath", Label: "Safety Proximity Matrix file path", Type: FieldFile},
+				{Key: "AlphaSafetyPenalty", Label: "Alpha (for penalty)", Type: FieldNumber, Default: 100.0},
+			}, distanceFields...),
+		},
+		Create: func(problem Problem, config safetyConfig) (*SafetyObjective, error) {
+			distanceMetric, distance, err := newDistanceProvider(config.DistanceMetric, config.RoadNetworkFilePath)
+			if err != nil {
+				return nil, err
+			}
+
+			safetyProximityMatrix, err := ReadSafetyProximityDataFromFile(config.SafetyProximityMatrixFilePath)
+			if err != nil {
+				return nil, err
+			}
+
+			return CreateSafetyObjectiveFromConfig(SafetyConfigs{
+				SafetyProximity:     safetyProximityMatrix,
+				AlphaSafetyPenalty:  config.AlphaSafetyPenalty,
+				Phases:              problem.GetPhases(),
+				PhaseDurations:      phaseDurationsOf(problem),
+				Distance:            distance,
+				DistanceMetric:      distanceMetric,
+				RoadNetworkFilePath: config.RoadNetworkFilePath,
+				FilePath:            config.SafetyProximityMatrixFilePath,
+			})
+		},
+		Info: func(obj *SafetyObjective) any {
+			return struct {
+				SafetyProximityMatrix data.PhaseMatrices  `json:"safetyProximityMatrix"`
+				AlphaSafetyPenalty    float64             `json:"alphaSafetyPenalty"`
+				Phases                [][]string          `json:"phases"`
+				PhaseDurations        []float64           `json:"phaseDurations"`
+				FilePath              string              `json:"filePath"`
+				DistanceMetric        data.DistanceMetric `json:"distanceMetric"`
+				RoadNetworkFilePath   string              `json:"roadNetworkFilePath"`
+			}{
+				SafetyProximityMatrix: obj.SafetyProximity,
+				AlphaSafetyPenalty:    obj.AlphaSafetyPenalty,
+				Phases:                obj.Phases,
+				PhaseDurations:        obj.PhaseDurations,
+				FilePath:              obj.FilePath,
+				DistanceMetric:        obj.DistanceMetric,
+				RoadNetworkFilePath:   obj.RoadNetworkFilePath,
+			}
+		},
+		Summary: func(obj *SafetyObjective, w SummaryWriter) {
+			w.Value("Alpha (for penalty)", obj.AlphaSafetyPenalty)
+			w.Value("Safety Proximity Matrix file path", obj.FilePath)
+			w.Value("Matrices", matricesSummary(obj.SafetyProximity))
+			distanceSummary(w, obj.DistanceMetric, obj.RoadNetworkFilePath)
+		},
+	})
+}
+
+func (obj *SafetyObjective) Eval(locations map[string]data.Location) (float64, error) {
+	return obj.EvalPhases(data.SamePhaseLocations(locations, len(obj.Phases)))
+}
+
+// EvalPhases evaluates every phase with its own locations. Without phase
+// durations, a pair of facilities counts once for each of their arrangements,
+// so once in a static layout. With durations, a pair counts in every phase it
+// is in, weighted by the duration of the phase.
+func (obj *SafetyObjective) EvalPhases(phaseLocations []map[string]data.Location) (float64, error) {
 	result := 0.0
 
-	calculatedMap := make(map[string]struct{})
+	weights := newPairWeights(obj.PhaseDurations)
 
-	for _, phases := range obj.Phases {
+	for phaseIdx, phases := range obj.Phases {
+		locations := phaseLocations[phaseIdx]
+		matrix := obj.SafetyProximity.Phase(phaseIdx)
 
 		for i := 0; i < len(phases)-1; i++ {
 			facilityNameI := phases[i]
 			facilityI := locations[facilityNameI]
-			idxI, err := obj.SafetyProximity.GetIdxFromName(facilityNameI)
+			idxI, err := matrix.GetIdxFromName(facilityNameI)
 			if err != nil {
-				log.Fatal(err)
-				return 0
+				return 0, err
 			}
 
 			for j := i + 1; j < len(phases); j++ {
 				facilityNameJ := phases[j]
 				facilityJ := locations[facilityNameJ]
-				idxJ, err := obj.SafetyProximity.GetIdxFromName(facilityNameJ)
+				idxJ, err := matrix.GetIdxFromName(facilityNameJ)
 				if err != nil {
-					log.Fatal(err)
-					return 0
+					return 0, err
 				}
 
-				if _, ok := calculatedMap[facilityNameI+facilityNameJ]; ok {
+				arrangement := pairArrangement{
+					Pair:   facilityNameI + facilityNameJ,
+					First:  facilityI.Coordinate,
+					Second: facilityJ.Coordinate,
+					Matrix: obj.SafetyProximity.Index(phaseIdx),
+				}
+				weight := weights.weight(phaseIdx, arrangement)
+				if weight == 0 {
 					continue
 				}
 
-				result += obj.SafetyProximity.Matrix[idxI][idxJ] * data.Distance2D(facilityI.Coordinate, facilityJ.Coordinate)
-				calculatedMap[facilityNameI+facilityNameJ] = struct{}{}
+				result += weight * matrix.Matrix[idxI][idxJ] * obj.Distance.Distance(facilityI.Coordinate, facilityJ.Coordinate)
 			}
 		}
 	}
 
-	return result
+	return result, nil
 }
 
 func (obj *SafetyObjective) GetAlphaPenalty() float64 {
 	return obj.AlphaSafetyPenalty
 }
 
-func ReadSafetyProximityDataFromFile(filePath string) (data.TwoDimensionalMatrix, error) {
-	dataFile, err := excelize.OpenFile(filePath)
-	if err != nil {
-		return data.TwoDimensionalMatrix{}, err
-	}
-
-	rows, err := dataFile.GetRows("Sheet1")
-	if err != nil {
-		return data.TwoDimensionalMatrix{}, err
-	}
+// ReadSafetyProximityDataFromFile reads the safety proximity matrix of every
+// phase from the sheets "Phase 1", "Phase 2", ..., or a single matrix shared
+// by every phase from the first sheet.
+func ReadSafetyProximityDataFromFile(filePath string) (data.PhaseMatrices, error) {
+	return readPhaseMatrices(filePath, readSafetyProximitySheet)
+}
 
+func readSafetyProximitySheet(rows [][]string) (data.TwoDimensionalMatrix, error) {
 	facilitiesName := make([]string, len(rows)-1)
 
 	for idx, cell := range rows[0] {
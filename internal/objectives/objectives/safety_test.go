@@ -48,7 +48,10 @@ func TestSafetyObjective_Eval(t *testing.T) {
 	for _, test := range testTable {
 		t.Run(test.name, func(t *testing.T) {
 
-			result := safetyObj.Eval(test.locations)
+			result, err := safetyObj.Eval(test.locations)
+			if err != nil {
+				t.Fatal(err)
+			}
 			if util.RoundTo(result, 2) != test.expected {
 				t.Errorf("expected result to be %f, got %f", test.expected, result)
 			}
@@ -139,10 +142,58 @@ func TestSafetyObjectiveMini_Eval(t *testing.T) {
 	for _, test := range testTable {
 		t.Run(test.name, func(t *testing.T) {
 
-			result := safetyObj.Eval(test.locations)
+			result, err := safetyObj.Eval(test.locations)
+			if err != nil {
+				t.Fatal(err)
+			}
 			if util.RoundTo(result, 2) != test.expected {
 				t.Errorf("expected result to be %f, got %f", test.expected, result)
 			}
 		})
 	}
 }
+
+func TestSafetyObjectiveMini_PhaseDurations(t *testing.T) {
+	safetyProximity, err := ReadSafetyProximityDataFromFile("../../../data/conslay/mini/safety_data.xlsx")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	phases := CreateInputPhasesMini()
+	durations := []float64{20, 5, 60}
+
+	safetyObj, err := CreateSafetyObjectiveFromConfig(SafetyConfigs{
+		SafetyProximity:    safetyProximity,
+		AlphaSafetyPenalty: 100,
+		Phases:             phases,
+		PhaseDurations:     durations,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// every phase counts on its own, weighted by its duration
+	expected := 0.0
+	for phaseIdx, phase := range phases {
+		phaseObj, err := CreateSafetyObjectiveFromConfig(SafetyConfigs{
+			SafetyProximity: safetyProximity,
+			Phases:          [][]string{phase},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		phaseResult, err := phaseObj.Eval(CreateInputMini())
+		if err != nil {
+			t.Fatal(err)
+		}
+		expected += durations[phaseIdx] * phaseResult
+	}
+
+	result, err := safetyObj.Eval(CreateInputMini())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if util.RoundTo(result, 6) != util.RoundTo(expected, 6) {
+		t.Errorf("expected result to be %f, got %f", expected, result)
+	}
+}
@@ -1,7 +1,6 @@
 package objectives
 
 import (
-	"github.com/xuri/excelize/v2"
 	"golang-moaha-construction/internal/data"
 	"strconv"
 	"strings"
@@ -10,42 +9,142 @@ import (
 const TransportCostObjectiveType data.ObjectiveType = "Transport Cost Objective"
 
 type TransportCostConfigs struct {
-	InteractionMatrix data.TwoDimensionalMatrix
+	InteractionMatrix data.PhaseMatrices
 	AlphaTCPenalty    float64
 	Phases            [][]string
+	PhaseDurations    []float64
 	FilePath          string
+	// Distance measures the distance between two facilities, Euclidean when
+	// nil
+	Distance            data.DistanceProvider
+	DistanceMetric      data.DistanceMetric
+	RoadNetworkFilePath string
 }
 
 type TransportCostObjective struct {
-	InteractionMatrix data.TwoDimensionalMatrix
+	InteractionMatrix data.PhaseMatrices
 	AlphaTCPenalty    float64
 	Phases            [][]string
+	PhaseDurations    []float64
 	FilePath          string
+	// Distance measures the distance between two facilities
+	Distance            data.DistanceProvider
+	DistanceMetric      data.DistanceMetric
+	RoadNetworkFilePath string
 }
 
 func CreateTransportCostObjectiveFromConfig(transportCostConfigs TransportCostConfigs) (*TransportCostObjective, error) {
 	tcObj := &TransportCostObjective{
-		InteractionMatrix: transportCostConfigs.InteractionMatrix,
-		AlphaTCPenalty:    transportCostConfigs.AlphaTCPenalty,
-		Phases:            transportCostConfigs.Phases,
-		FilePath:          transportCostConfigs.FilePath,
+		InteractionMatrix:   transportCostConfigs.InteractionMatrix,
+		AlphaTCPenalty:      transportCostConfigs.AlphaTCPenalty,
+		Phases:              transportCostConfigs.Phases,
+		PhaseDurations:      transportCostConfigs.PhaseDurations,
+		FilePath:            transportCostConfigs.FilePath,
+		Distance:            transportCostConfigs.Distance,
+		DistanceMetric:      transportCostConfigs.DistanceMetric,
+		RoadNetworkFilePath: transportCostConfigs.RoadNetworkFilePath,
 	}
+	if err := tcObj.InteractionMatrix.Validate(len(tcObj.Phases)); err != nil {
+		return nil, err
+	}
+
+	if tcObj.Distance == nil {
+		tcObj.Distance = data.EuclideanDistance{}
+	}
+
 	return tcObj, nil
 }
 
-func (obj *TransportCostObjective) Eval(locations map[string]data.Location) float64 {
+type transportCostConfig struct {
+	InteractionMatrixFilePath string  `json:"interactionMatrixFilePath"`
+	AlphaTransportCostPenalty float64 `json:"AlphaTCPenalty"`
+	DistanceMetric            string  `json:"DistanceMetric"`
+	RoadNetworkFilePath       string  `json:"RoadNetworkFilePath"`
+}
+
+func init() {
+	Register(TransportCostObjectiveType, Definition[transportCostConfig, *TransportCostObjective]{
+		Schema: ConfigSchema{
+			Label:  "Transport Cost",
+			TSName: "TransportCostObjective",
+			Fields: append([]Field{
+				{Key: "InteractionMatrixFilePath", Label: "Facilities Interaction Matrix file path", Type: FieldFile},
+				{Key: "AlphaTCPenalty", Label: "Alpha (for penalty)", Type: FieldNumber, Default: 100.0},
+			}, distanceFields...),
+		},
+		Create: func(problem Problem, config transportCostConfig) (*TransportCostObjective, error) {
+			distanceMetric, distance, err := newDistanceProvider(config.DistanceMetric, config.RoadNetworkFilePath)
+			if err != nil {
+				return nil, err
+			}
+
+			interactionMatrix, err := ReadInteractionTransportCostDataFromFile(config.InteractionMatrixFilePath)
+			if err != nil {
+				return nil, err
+			}
+
+			return CreateTransportCostObjectiveFromConfig(TransportCostConfigs{
+				InteractionMatrix:   interactionMatrix,
+				AlphaTCPenalty:      config.AlphaTransportCostPenalty,
+				Phases:              problem.GetPhases(),
+				PhaseDurations:      phaseDurationsOf(problem),
+				Distance:            distance,
+				DistanceMetric:      distanceMetric,
+				RoadNetworkFilePath: config.RoadNetworkFilePath,
+				FilePath:            config.InteractionMatrixFilePath,
+			})
+		},
+		Info: func(obj *TransportCostObjective) any {
+			return struct {
+				InteractionMatrix         data.PhaseMatrices  `json:"interactionMatrix"`
+				AlphaTransportCostPenalty float64             `json:"alphaTransportCostPenalty"`
+				Phases                    [][]string          `json:"phases"`
+				PhaseDurations            []float64           `json:"phaseDurations"`
+				FilePath                  string              `json:"filePath"`
+				DistanceMetric            data.DistanceMetric `json:"distanceMetric"`
+				RoadNetworkFilePath       string              `json:"roadNetworkFilePath"`
+			}{
+				InteractionMatrix:         obj.InteractionMatrix,
+				AlphaTransportCostPenalty: obj.AlphaTCPenalty,
+				Phases:                    obj.Phases,
+				PhaseDurations:            obj.PhaseDurations,
+				FilePath:                  obj.FilePath,
+				DistanceMetric:            obj.DistanceMetric,
+				RoadNetworkFilePath:       obj.RoadNetworkFilePath,
+			}
+		},
+		Summary: func(obj *TransportCostObjective, w SummaryWriter) {
+			w.Value("Alpha (for penalty)", obj.AlphaTCPenalty)
+			w.Value("Facilities Interaction Matrix file path", obj.FilePath)
+			w.Value("Matrices", matricesSummary(obj.InteractionMatrix))
+			distanceSummary(w, obj.DistanceMetric, obj.RoadNetworkFilePath)
+		},
+	})
+}
+
+func (obj *TransportCostObjective) Eval(locations map[string]data.Location) (float64, error) {
+	return obj.EvalPhases(data.SamePhaseLocations(locations, len(obj.Phases)))
+}
+
+// EvalPhases evaluates every phase with its own locations. Without phase
+// durations, a pair of facilities counts once for each of their arrangements,
+// so once in a static layout. With durations, a pair counts in every phase it
+// is in, weighted by the duration of the phase.
+func (obj *TransportCostObjective) EvalPhases(phaseLocations []map[string]data.Location) (float64, error) {
 	result := 0.0
 
-	calculatedMap := make(map[string]struct{})
+	weights := newPairWeights(obj.PhaseDurations)
 
-	for _, phases := range obj.Phases {
+	for phaseIdx, phases := range obj.Phases {
+		locations := phaseLocations[phaseIdx]
+		matrix := obj.InteractionMatrix.Phase(phaseIdx)
 
 		for i := 0; i < len(phases); i++ {
 			facilityNameI := phases[i]
 			facilityI := locations[facilityNameI]
-			idxI, err := obj.InteractionMatrix.GetIdxFromName(facilityNameI)
+			idxI, err := matrix.GetIdxFromName(facilityNameI)
 			if err != nil {
-				return 0
+				return 0, err
 			}
 
 			for j := 0; j < len(phases); j++ {
@@ -54,39 +153,42 @@ func (obj *TransportCostObjective) Eval(locations map[string]data.Location) floa
 				}
 				facilityNameJ := phases[j]
 				facilityJ := locations[facilityNameJ]
-				idxJ, err := obj.InteractionMatrix.GetIdxFromName(facilityNameJ)
+				idxJ, err := matrix.GetIdxFromName(facilityNameJ)
 				if err != nil {
-					return 0
+					return 0, err
 				}
 
-				if _, ok := calculatedMap[facilityNameI+facilityNameJ]; ok {
+				arrangement := pairArrangement{
+					Pair:   facilityNameI + facilityNameJ,
+					First:  facilityI.Coordinate,
+					Second: facilityJ.Coordinate,
+					Matrix: obj.InteractionMatrix.Index(phaseIdx),
+				}
+				weight := weights.weight(phaseIdx, arrangement)
+				if weight == 0 {
 					continue
 				}
 
-				result += obj.InteractionMatrix.Matrix[idxI][idxJ] * data.Distance2D(facilityI.Coordinate, facilityJ.Coordinate)
-				calculatedMap[facilityNameI+facilityNameJ] = struct{}{}
+				result += weight * matrix.Matrix[idxI][idxJ] * obj.Distance.Distance(facilityI.Coordinate, facilityJ.Coordinate)
 			}
 		}
 	}
 
-	return result
+	return result, nil
 }
 
 func (obj *TransportCostObjective) GetAlphaPenalty() float64 {
 	return obj.AlphaTCPenalty
 }
 
-func ReadInteractionTransportCostDataFromFile(filePath string) (data.TwoDimensionalMatrix, error) {
-	dataFile, err := excelize.OpenFile(filePath)
-	if err != nil {
-		return data.TwoDimensionalMatrix{}, err
-	}
-
-	rows, err := dataFile.GetRows("Sheet1")
-	if err != nil {
-		return data.TwoDimensionalMatrix{}, err
-	}
+// ReadInteractionTransportCostDataFromFile reads the interaction matrix of
+// every phase from the sheets "Phase 1", "Phase 2", ..., or a single matrix
+// shared by every phase from the first sheet.
+func ReadInteractionTransportCostDataFromFile(filePath string) (data.PhaseMatrices, error) {
+	return readPhaseMatrices(filePath, readInteractionTransportCostSheet)
+}
 
+func readInteractionTransportCostSheet(rows [][]string) (data.TwoDimensionalMatrix, error) {
 	facilitiesName := make([]string, len(rows)-1)
 
 	for idx, cell := range rows[0] {
@@ -1,9 +1,13 @@
 package objectives
 
 import (
+	"fmt"
+	"github.com/xuri/excelize/v2"
 	"golang-moaha-construction/internal/data"
 	"golang-moaha-construction/internal/util"
 	"log"
+	"path/filepath"
+	"strconv"
 	"testing"
 )
 
@@ -37,7 +41,10 @@ func TestTransportCostObjectiveMini_Eval(t *testing.T) {
 	for _, test := range testTable {
 		t.Run(test.name, func(t *testing.T) {
 
-			result := tcObj.Eval(test.locations)
+			result, err := tcObj.Eval(test.locations)
+			if err != nil {
+				t.Fatal(err)
+			}
 			if util.RoundTo(result, 2) != test.expected {
 				t.Errorf("expected result to be %f, got %f", test.expected, result)
 			}
@@ -45,3 +52,219 @@ func TestTransportCostObjectiveMini_Eval(t *testing.T) {
 		})
 	}
 }
+
+func TestTransportCostObjectiveMini_PhaseMatrices(t *testing.T) {
+	filePath := "../../../data/conslay/mini/transport_cost_data.xlsx"
+	interactionMatrix, err := ReadInteractionTransportCostDataFromFile(filePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the matrix of the phase k is the shared one scaled by k+1
+	source, err := excelize.OpenFile(filePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rows, err := source.GetRows("Sheet1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	phases := CreateInputPhasesMini()
+	file := excelize.NewFile()
+	for phaseIdx := range phases {
+		sheet := fmt.Sprintf("Phase %d", phaseIdx+1)
+		if phaseIdx == 0 {
+			_ = file.SetSheetName("Sheet1", sheet)
+		} else {
+			_, _ = file.NewSheet(sheet)
+		}
+
+		for rowIdx, row := range rows {
+			for colIdx, cell := range row {
+				value := any(cell)
+				if rowIdx > 0 && colIdx > 0 && cell != "" {
+					v, err := strconv.ParseFloat(cell, 64)
+					if err != nil {
+						t.Fatal(err)
+					}
+					value = v * float64(phaseIdx+1)
+				}
+				name, _ := excelize.CoordinatesToCellName(colIdx+1, rowIdx+1)
+				_ = file.SetCellValue(sheet, name, value)
+			}
+		}
+	}
+	// sheets not named after a phase are left out
+	_, _ = file.NewSheet("Notes")
+	_ = file.SetCellValue("Notes", "A1", "scaled by the phase number")
+	phaseFilePath := filepath.Join(t.TempDir(), "transport_cost_phases.xlsx")
+	if err := file.SaveAs(phaseFilePath); err != nil {
+		t.Fatal(err)
+	}
+
+	phaseMatrices, err := ReadInteractionTransportCostDataFromFile(phaseFilePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !phaseMatrices.IsPerPhase() {
+		t.Fatalf("expected a matrix per phase, got %d", len(phaseMatrices))
+	}
+
+	tcObj, err := CreateTransportCostObjectiveFromConfig(TransportCostConfigs{
+		InteractionMatrix: phaseMatrices,
+		AlphaTCPenalty:    100,
+		Phases:            phases,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// every phase counts on its own, under its own matrix
+	expected := 0.0
+	for phaseIdx, phase := range phases {
+		phaseObj, err := CreateTransportCostObjectiveFromConfig(TransportCostConfigs{
+			InteractionMatrix: interactionMatrix,
+			Phases:            [][]string{phase},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		phaseResult, err := phaseObj.Eval(CreateInputMini())
+		if err != nil {
+			t.Fatal(err)
+		}
+		expected += float64(phaseIdx+1) * phaseResult
+	}
+
+	result, err := tcObj.Eval(CreateInputMini())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if util.RoundTo(result, 6) != util.RoundTo(expected, 6) {
+		t.Errorf("expected result to be %f, got %f", expected, result)
+	}
+
+	_, err = CreateTransportCostObjectiveFromConfig(TransportCostConfigs{
+		InteractionMatrix: phaseMatrices[:2],
+		Phases:            phases,
+	})
+	if err == nil {
+		t.Error("expected an error when the matrices do not match the phases")
+	}
+}
+
+func TestReadPhaseMatrices_Sheets(t *testing.T) {
+	source, err := excelize.OpenFile("../../../data/conslay/mini/transport_cost_data.xlsx")
+	if err != nil {
+		t.Fatal(err)
+	}
+	rows, err := source.GetRows("Sheet1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testTable := []struct {
+		name     string
+		sheets   []string
+		expected int
+	}{
+		{name: "single sheet", sheets: []string{"Sheet1"}, expected: 1},
+		{name: "shared matrix with notes", sheets: []string{"Matrix", "Notes"}, expected: 1},
+		{name: "phases in any order", sheets: []string{"Phase 2", "phase 1", "Notes", "PHASE 3"}, expected: 3},
+		{name: "missing phase", sheets: []string{"Phase 1", "Phase 3"}, expected: 0},
+	}
+
+	for _, test := range testTable {
+		t.Run(test.name, func(t *testing.T) {
+			file := excelize.NewFile()
+			for idx, sheet := range test.sheets {
+				if idx == 0 {
+					_ = file.SetSheetName("Sheet1", sheet)
+				} else {
+					_, _ = file.NewSheet(sheet)
+				}
+				if sheet == "Notes" {
+					_ = file.SetCellValue(sheet, "A1", "not a matrix")
+					continue
+				}
+				for rowIdx, row := range rows {
+					_ = file.SetSheetRow(sheet, fmt.Sprintf("A%d", rowIdx+1), &row)
+				}
+			}
+			filePath := filepath.Join(t.TempDir(), "matrices.xlsx")
+			if err := file.SaveAs(filePath); err != nil {
+				t.Fatal(err)
+			}
+
+			matrices, err := ReadInteractionTransportCostDataFromFile(filePath)
+			if test.expected == 0 {
+				if err == nil {
+					t.Errorf("expected an error, got %d matrices", len(matrices))
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(matrices) != test.expected {
+				t.Errorf("expected %d matrices, got %d", test.expected, len(matrices))
+			}
+		})
+	}
+}
+
+func TestTransportCostObjectiveMini_DistanceMetric(t *testing.T) {
+	// a ring road around the site
+	file := excelize.NewFile()
+	_ = file.SetSheetRow("Sheet1", "A1", &[]any{"Road", "X", "Y"})
+	for idx, vertex := range [][2]float64{{0, 0}, {100, 0}, {100, 100}, {0, 100}, {0, 0}} {
+		_ = file.SetSheetRow("Sheet1", fmt.Sprintf("A%d", idx+2), &[]any{"Ring", vertex[0], vertex[1]})
+	}
+	roadsFilePath := filepath.Join(t.TempDir(), "roads.xlsx")
+	if err := file.SaveAs(roadsFilePath); err != nil {
+		t.Fatal(err)
+	}
+
+	network, err := ReadRoadNetworkFromFile(roadsFilePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(network.Segments) != 4 {
+		t.Fatalf("expected 4 road segments, got %d", len(network.Segments))
+	}
+
+	interactionMatrix, err := ReadInteractionTransportCostDataFromFile("../../../data/conslay/mini/transport_cost_data.xlsx")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	eval := func(distance data.DistanceProvider) float64 {
+		tcObj, err := CreateTransportCostObjectiveFromConfig(TransportCostConfigs{
+			InteractionMatrix: interactionMatrix,
+			Phases:            CreateInputPhasesMini(),
+			Distance:          distance,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		result, err := tcObj.Eval(CreateInputMini())
+		if err != nil {
+			t.Fatal(err)
+		}
+		return result
+	}
+
+	euclidean := eval(nil)
+	if result := eval(data.EuclideanDistance{}); result != euclidean {
+		t.Errorf("expected the default distance to be euclidean, got %f and %f", euclidean, result)
+	}
+
+	// neither a rectilinear nor a road path is shorter than a straight line
+	if result := eval(data.ManhattanDistance{}); result < euclidean {
+		t.Errorf("expected manhattan result of at least %f, got %f", euclidean, result)
+	}
+	if result := eval(network); result < euclidean {
+		t.Errorf("expected network result of at least %f, got %f", euclidean, result)
+	}
+}
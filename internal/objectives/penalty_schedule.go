@@ -0,0 +1,373 @@
+package objectives
+
+import (
+	"fmt"
+	"golang-moaha-construction/internal/data"
+	"maps"
+	"math"
+	"sync"
+)
+
+// PenaltyScheduleType is how the penalty coefficients of the constraints
+// change during a run.
+type PenaltyScheduleType string
+
+const (
+	// StaticPenalty keeps the coefficients of the constraints.
+	StaticPenalty PenaltyScheduleType = "static"
+	// DeathPenalty rejects infeasible positions with an infinite penalty.
+	DeathPenalty PenaltyScheduleType = "death"
+	// DynamicPenalty multiplies the coefficients by (C * t)^Alpha at
+	// iteration t, as proposed by Joines and Houck.
+	DynamicPenalty PenaltyScheduleType = "dynamic"
+	// AnnealingPenalty multiplies the coefficients by 1 / (2 * tau) where the
+	// temperature tau starts at InitialTemperature and cools by CoolingRate
+	// every iteration, as proposed by Michalewicz and Attia.
+	AnnealingPenalty PenaltyScheduleType = "annealing"
+	// SelfAdaptivePenalty raises the coefficient of a constraint by
+	// AdaptationRate while fewer than TargetFeasibleRatio of the agents satisfy
+	// it and lowers it by the same rate while more do.
+	SelfAdaptivePenalty PenaltyScheduleType = "self-adaptive"
+)
+
+// PenaltyScheduleTypes lists the valid schedules, the default first.
+var PenaltyScheduleTypes = []PenaltyScheduleType{
+	StaticPenalty,
+	DeathPenalty,
+	DynamicPenalty,
+	AnnealingPenalty,
+	SelfAdaptivePenalty,
+}
+
+// PenaltyScheduleConfig selects a schedule. The parameters of the other
+// schedules are ignored and zero parameters take their defaults.
+type PenaltyScheduleConfig struct {
+	Type PenaltyScheduleType `json:"type"`
+	// C and Alpha are the parameters of DynamicPenalty
+	C     float64 `json:"c"`
+	Alpha float64 `json:"alpha"`
+	// InitialTemperature and CoolingRate are the parameters of
+	// AnnealingPenalty
+	InitialTemperature float64 `json:"initialTemperature"`
+	CoolingRate        float64 `json:"coolingRate"`
+	// TargetFeasibleRatio and AdaptationRate are the parameters of
+	// SelfAdaptivePenalty
+	TargetFeasibleRatio float64 `json:"targetFeasibleRatio"`
+	AdaptationRate      float64 `json:"adaptationRate"`
+	// Tolerance is how far the factor of DynamicPenalty, AnnealingPenalty
+	// and SelfAdaptivePenalty may drift from the applied one. A new factor is
+	// applied once it reaches (1 + Tolerance) times or 1 / (1 + Tolerance) of
+	// the applied factor, 2 and 1/2 by default. Every applied change makes
+	// the algorithms evaluate the results they keep again, the population
+	// and archive, so a tolerance small enough to apply the factor every
+	// iteration roughly doubles the evaluations of a run.
+	Tolerance float64 `json:"tolerance"`
+}
+
+// withDefaults fills the zero parameters and validates config.
+func (config PenaltyScheduleConfig) withDefaults() (PenaltyScheduleConfig, error) {
+	if config.Type == "" {
+		config.Type = StaticPenalty
+	}
+
+	setDefault := func(value *float64, def float64) {
+		if *value == 0 {
+			*value = def
+		}
+	}
+
+	switch config.Type {
+	case StaticPenalty, DeathPenalty:
+	case DynamicPenalty:
+		setDefault(&config.C, 0.5)
+		setDefault(&config.Alpha, 2)
+		setDefault(&config.Tolerance, 1)
+		if config.C < 0 {
+			return config, fmt.Errorf("%w: C of the dynamic penalty must be positive", ErrInvalidConfig)
+		}
+	case AnnealingPenalty:
+		setDefault(&config.InitialTemperature, 1)
+		setDefault(&config.CoolingRate, 0.95)
+		setDefault(&config.Tolerance, 1)
+		if config.InitialTemperature < 0 || config.CoolingRate < 0 || config.CoolingRate >= 1 {
+			return config, fmt.Errorf("%w: the annealing penalty needs a positive temperature and a cooling rate below 1", ErrInvalidConfig)
+		}
+	case SelfAdaptivePenalty:
+		setDefault(&config.TargetFeasibleRatio, 0.5)
+		setDefault(&config.AdaptationRate, 1.5)
+		setDefault(&config.Tolerance, 1)
+		if config.TargetFeasibleRatio < 0 || config.TargetFeasibleRatio > 1 || config.AdaptationRate <= 1 {
+			return config, fmt.Errorf("%w: the self-adaptive penalty needs a target ratio between 0 and 1 and an adaptation rate above 1", ErrInvalidConfig)
+		}
+	default:
+		return config, fmt.Errorf("%w: unknown penalty schedule %q", ErrInvalidConfig, config.Type)
+	}
+
+	if config.Tolerance < 0 {
+		return config, fmt.Errorf("%w: the tolerance of the penalty schedule must be positive", ErrInvalidConfig)
+	}
+
+	return config, nil
+}
+
+// PenaltyReport is the schedule of a problem and the factor every constraint
+// coefficient is multiplied by, as reached by the last iteration.
+type PenaltyReport struct {
+	Schedule PenaltyScheduleConfig           `json:"schedule"`
+	Factors  map[data.ConstraintType]float64 `json:"factors"`
+}
+
+// AdaptivePenalty applies a penalty schedule to the constraints of a problem.
+//
+// Problems embed AdaptivePenalty and compute the penalty of every constraint
+// with Penalty. Algorithms call UpdatePenalty before every iteration, between
+// the concurrent evaluations, and Rescore the results they keep when it
+// reports a change. They save PenaltyState with their checkpoints.
+//
+// A rescore costs as many evaluations as there are kept results, so the
+// factors are only applied once they drift beyond the tolerance of the
+// schedule.
+type AdaptivePenalty struct {
+	mu       sync.RWMutex
+	schedule PenaltyScheduleConfig
+	// factor is the applied factor shared by the constraints, except for
+	// SelfAdaptivePenalty
+	factor float64
+	// factors are the applied factors of SelfAdaptivePenalty and adapted the
+	// factors it has adapted to so far
+	factors map[data.ConstraintType]float64
+	adapted map[data.ConstraintType]float64
+}
+
+// SetPenaltySchedule validates config and restarts the schedule with it.
+func (p *AdaptivePenalty) SetPenaltySchedule(config PenaltyScheduleConfig) error {
+	config, err := config.withDefaults()
+	if err != nil {
+		return err
+	}
+
+	p.mu.Lock()
+	p.schedule = config
+	p.mu.Unlock()
+
+	p.ResetPenalty()
+	return nil
+}
+
+// ResetPenalty restarts the schedule before a new run.
+func (p *AdaptivePenalty) ResetPenalty() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.factor = 1
+	p.factors = make(map[data.ConstraintType]float64)
+	p.adapted = make(map[data.ConstraintType]float64)
+}
+
+// PenaltyState is the progress of a schedule, saved with the state of an
+// algorithm so that a resumed run continues with the same factors.
+type PenaltyState struct {
+	Factor  float64
+	Factors map[data.ConstraintType]float64
+	Adapted map[data.ConstraintType]float64
+}
+
+// PenaltyState returns the progress of the schedule.
+func (p *AdaptivePenalty) PenaltyState() PenaltyState {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	return PenaltyState{
+		Factor:  p.factor,
+		Factors: maps.Clone(p.factors),
+		Adapted: maps.Clone(p.adapted),
+	}
+}
+
+// SetPenaltyState continues the schedule from state.
+func (p *AdaptivePenalty) SetPenaltyState(state PenaltyState) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.factor = state.Factor
+	p.factors = maps.Clone(state.Factors)
+	if p.factors == nil {
+		p.factors = make(map[data.ConstraintType]float64)
+	}
+	p.adapted = maps.Clone(state.Adapted)
+	if p.adapted == nil {
+		p.adapted = maps.Clone(p.factors)
+	}
+}
+
+// UpdatePenalty moves the schedule to iteration, counted from 0, of a run of
+// maxIterations iterations. agents is the population evaluated so far. It
+// reports whether an applied factor changed, the results evaluated before then
+// have to be scored again with Rescore to compare with the later ones.
+func (p *AdaptivePenalty) UpdatePenalty(iteration int, maxIterations int, agents []*Result) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	t := float64(iteration + 1)
+
+	switch p.schedule.Type {
+	case DynamicPenalty:
+		return p.apply(&p.factor, math.Pow(p.schedule.C*t, p.schedule.Alpha))
+	case AnnealingPenalty:
+		tau := p.schedule.InitialTemperature * math.Pow(p.schedule.CoolingRate, t)
+		return p.apply(&p.factor, 1/(2*tau))
+	case SelfAdaptivePenalty:
+		if len(agents) == 0 {
+			return false
+		}
+
+		if p.factors == nil {
+			p.factors = make(map[data.ConstraintType]float64)
+		}
+		if p.adapted == nil {
+			p.adapted = make(map[data.ConstraintType]float64)
+		}
+
+		satisfied := make(map[data.ConstraintType]int)
+		for _, agent := range agents {
+			for name, amount := range agent.Violations {
+				if _, ok := satisfied[name]; !ok {
+					satisfied[name] = 0
+				}
+				if amount == 0 {
+					satisfied[name]++
+				}
+			}
+		}
+
+		changed := false
+		for name, count := range satisfied {
+			adapted, ok := p.adapted[name]
+			if !ok {
+				adapted = 1
+			}
+
+			ratio := float64(count) / float64(len(agents))
+			if ratio < p.schedule.TargetFeasibleRatio {
+				adapted *= p.schedule.AdaptationRate
+			} else if ratio > p.schedule.TargetFeasibleRatio {
+				adapted /= p.schedule.AdaptationRate
+			}
+			p.adapted[name] = adapted
+
+			factor, ok := p.factors[name]
+			if !ok {
+				factor = 1
+			}
+			if p.apply(&factor, adapted) {
+				p.factors[name] = factor
+				changed = true
+			}
+		}
+		return changed
+	}
+
+	return false
+}
+
+// apply sets the applied factor to scheduled when it has drifted beyond the
+// tolerance of the schedule and reports whether it did.
+func (p *AdaptivePenalty) apply(applied *float64, scheduled float64) bool {
+	bound := 1 + p.schedule.Tolerance
+	if scheduled == *applied || (scheduled < *applied*bound && scheduled*bound > *applied) {
+		return false
+	}
+
+	*applied = scheduled
+	return true
+}
+
+// Rescore evaluates the positions of results again with the current penalty
+// factors of problem. A result found in several slices is evaluated once.
+func Rescore(problem Problem, results ...[]*Result) {
+	seen := make(map[*Result]bool)
+	var wg sync.WaitGroup
+	for _, slice := range results {
+		for _, result := range slice {
+			if result == nil || seen[result] {
+				continue
+			}
+			seen[result] = true
+
+			wg.Add(1)
+			go func(result *Result) {
+				defer wg.Done()
+				value, valuesWithKey, keys, penalty, violations := problem.Eval(result.Position)
+				result.Value = value
+				result.ValuesWithKey = valuesWithKey
+				result.Key = keys
+				result.Penalty = penalty
+				result.Violations = violations
+				result.Violation = TotalViolation(violations)
+			}(result)
+		}
+	}
+	wg.Wait()
+}
+
+// PenaltyFactor returns the factor the coefficient of the constraint name is
+// multiplied by, +Inf for DeathPenalty.
+func (p *AdaptivePenalty) PenaltyFactor(name data.ConstraintType) float64 {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	return p.penaltyFactor(name)
+}
+
+func (p *AdaptivePenalty) penaltyFactor(name data.ConstraintType) float64 {
+	switch p.schedule.Type {
+	case DeathPenalty:
+		return math.Inf(1)
+	case SelfAdaptivePenalty:
+		if factor, ok := p.factors[name]; ok {
+			return factor
+		}
+		return 1
+	case DynamicPenalty, AnnealingPenalty:
+		return p.factor
+	default:
+		return 1
+	}
+}
+
+// Penalty is the penalty of the constraint name violated by amount:
+// amount^power * alpha of the constraint, times the factor of the schedule.
+// A constraint whose alpha is 0 has no penalty, even with an infinite amount
+// or factor.
+func (p *AdaptivePenalty) Penalty(name data.ConstraintType, constraint data.Constrainter, amount float64) float64 {
+	if amount == 0 || constraint.GetAlphaPenalty() == 0 {
+		return 0
+	}
+
+	p.mu.RLock()
+	factor := p.penaltyFactor(name)
+	p.mu.RUnlock()
+
+	return math.Pow(amount, constraint.GetPowerPenalty()) * constraint.GetAlphaPenalty() * factor
+}
+
+// PenaltyReport returns the schedule and the factors of constraints.
+func (p *AdaptivePenalty) PenaltyReport(constraints map[data.ConstraintType]data.Constrainter) PenaltyReport {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	schedule := p.schedule
+	if schedule.Type == "" {
+		schedule.Type = StaticPenalty
+	}
+
+	report := PenaltyReport{
+		Schedule: schedule,
+		Factors:  make(map[data.ConstraintType]float64, len(constraints)),
+	}
+	for name := range constraints {
+		report.Factors[name] = p.penaltyFactor(name)
+	}
+
+	return report
+}
@@ -0,0 +1,212 @@
+package objectives
+
+import (
+	"errors"
+	"golang-moaha-construction/internal/data"
+	"math"
+	"sync/atomic"
+	"testing"
+)
+
+type testConstraint struct {
+	alpha float64
+	power float64
+}
+
+func (c testConstraint) Eval(map[string]data.Location) (float64, error) {
+	return 0, nil
+}
+
+func (c testConstraint) GetName() string {
+	return "test"
+}
+
+func (c testConstraint) GetAlphaPenalty() float64 {
+	return c.alpha
+}
+
+func (c testConstraint) GetPowerPenalty() float64 {
+	return c.power
+}
+
+func TestAdaptivePenalty(t *testing.T) {
+	const name data.ConstraintType = "Test"
+	con := testConstraint{alpha: 10, power: 2}
+
+	var p AdaptivePenalty
+	if penalty := p.Penalty(name, con, 3); penalty != 90 {
+		t.Errorf("expected the static penalty without a schedule, got %v", penalty)
+	}
+
+	err := p.SetPenaltySchedule(PenaltyScheduleConfig{Type: DynamicPenalty})
+	if err != nil {
+		t.Fatal(err)
+	}
+	p.UpdatePenalty(3, 10, nil)
+	// (0.5 * 4)^2
+	if factor := p.PenaltyFactor(name); factor != 4 {
+		t.Errorf("expected the dynamic factor 4, got %v", factor)
+	}
+
+	err = p.SetPenaltySchedule(PenaltyScheduleConfig{Type: DeathPenalty})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if penalty := p.Penalty(name, con, 0); penalty != 0 {
+		t.Errorf("expected no penalty for a satisfied constraint, got %v", penalty)
+	}
+	if penalty := p.Penalty(name, con, 0.1); !math.IsInf(penalty, 1) {
+		t.Errorf("expected an infinite penalty, got %v", penalty)
+	}
+	// a zero coefficient turns the constraint off instead of giving NaN
+	for _, amount := range []float64{0.1, math.Inf(1)} {
+		if penalty := p.Penalty(name, testConstraint{alpha: 0, power: 2}, amount); penalty != 0 {
+			t.Errorf("expected no penalty with a zero alpha for the amount %v, got %v", amount, penalty)
+		}
+	}
+
+	err = p.SetPenaltySchedule(PenaltyScheduleConfig{Type: SelfAdaptivePenalty, AdaptationRate: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	infeasible := []*Result{
+		{Violations: map[data.ConstraintType]float64{name: 1}},
+		{Violations: map[data.ConstraintType]float64{name: 1}},
+		{Violations: map[data.ConstraintType]float64{name: 0}},
+	}
+	p.UpdatePenalty(0, 10, infeasible)
+	p.UpdatePenalty(1, 10, infeasible)
+	if factor := p.PenaltyFactor(name); factor != 4 {
+		t.Errorf("expected the factor to double twice, got %v", factor)
+	}
+
+	report := p.PenaltyReport(map[data.ConstraintType]data.Constrainter{name: con})
+	if report.Schedule.TargetFeasibleRatio != 0.5 || report.Factors[name] != 4 {
+		t.Errorf("unexpected report %+v", report)
+	}
+
+	p.ResetPenalty()
+	if factor := p.PenaltyFactor(name); factor != 1 {
+		t.Errorf("expected the reset factor 1, got %v", factor)
+	}
+
+	err = p.SetPenaltySchedule(PenaltyScheduleConfig{Type: "unknown"})
+	if !errors.Is(err, ErrInvalidConfig) {
+		t.Errorf("expected ErrInvalidConfig, got %v", err)
+	}
+}
+
+func TestAdaptivePenalty_Tolerance(t *testing.T) {
+	const name data.ConstraintType = "Test"
+
+	var p AdaptivePenalty
+	err := p.SetPenaltySchedule(PenaltyScheduleConfig{Type: DynamicPenalty})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// (0.5 * t)^2 is applied once it doubles or halves
+	testTable := []struct {
+		changed bool
+		factor  float64
+	}{
+		{changed: true, factor: 0.25},
+		{changed: true, factor: 1},
+		{changed: true, factor: 2.25},
+		{changed: false, factor: 2.25},
+		{changed: true, factor: 6.25},
+	}
+	for iteration, test := range testTable {
+		if changed := p.UpdatePenalty(iteration, 10, nil); changed != test.changed {
+			t.Errorf("iteration %d: expected a change %v, got %v", iteration, test.changed, changed)
+		}
+		if factor := p.PenaltyFactor(name); factor != test.factor {
+			t.Errorf("iteration %d: expected the factor %v, got %v", iteration, test.factor, factor)
+		}
+	}
+
+	// the adapted factors are kept until they drift far enough
+	err = p.SetPenaltySchedule(PenaltyScheduleConfig{Type: SelfAdaptivePenalty})
+	if err != nil {
+		t.Fatal(err)
+	}
+	infeasible := []*Result{
+		{Violations: map[data.ConstraintType]float64{name: 1}},
+	}
+	if p.UpdatePenalty(0, 10, infeasible) {
+		t.Error("expected a factor of 1.5 to be kept back")
+	}
+	if !p.UpdatePenalty(1, 10, infeasible) {
+		t.Error("expected a factor of 2.25 to be applied")
+	}
+	if factor := p.PenaltyFactor(name); factor != 2.25 {
+		t.Errorf("expected the factor 2.25, got %v", factor)
+	}
+
+	err = p.SetPenaltySchedule(PenaltyScheduleConfig{Type: AnnealingPenalty, Tolerance: -1})
+	if !errors.Is(err, ErrInvalidConfig) {
+		t.Errorf("expected ErrInvalidConfig for a negative tolerance, got %v", err)
+	}
+}
+
+// rescoreProblem violates its constraint by the first coordinate and adds the
+// penalty to the second one.
+type rescoreProblem struct {
+	Problem
+	schedule    AdaptivePenalty
+	evaluations atomic.Int32
+}
+
+func (p *rescoreProblem) Eval(pos []float64) (
+	values []float64,
+	valuesWithKey map[data.ObjectiveType]float64,
+	key []data.ObjectiveType,
+	penalty map[data.ConstraintType]float64,
+	violations map[data.ConstraintType]float64) {
+	const name data.ConstraintType = "Test"
+
+	p.evaluations.Add(1)
+	violations = map[data.ConstraintType]float64{name: pos[0]}
+	penalty = map[data.ConstraintType]float64{name: p.schedule.Penalty(name, testConstraint{alpha: 1, power: 1}, pos[0])}
+	return []float64{pos[1] + penalty[name]}, nil, nil, penalty, violations
+}
+
+func TestRescore(t *testing.T) {
+	problem := &rescoreProblem{}
+	err := problem.schedule.SetPenaltySchedule(PenaltyScheduleConfig{Type: DynamicPenalty, C: 1, Alpha: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	results := make([]*Result, 0, 2)
+	for _, pos := range [][]float64{{2, 1}, {0, 5}} {
+		value, _, _, penalty, violations := problem.Eval(pos)
+		results = append(results, &Result{Position: pos, Value: value, Penalty: penalty, Violations: violations})
+	}
+
+	if !problem.schedule.UpdatePenalty(2, 10, results) {
+		t.Fatal("expected the dynamic factor to change")
+	}
+
+	problem.evaluations.Store(0)
+	Rescore(problem, results, results[:1], []*Result{nil})
+
+	if evaluations := problem.evaluations.Load(); evaluations != 2 {
+		t.Errorf("expected every result to be evaluated once, got %d evaluations", evaluations)
+	}
+	// 1 + 2 * 3
+	if results[0].Value[0] != 7 || results[0].Violation != 2 {
+		t.Errorf("expected the value 7 and violation 2, got %v and %v", results[0].Value[0], results[0].Violation)
+	}
+	if results[1].Value[0] != 5 {
+		t.Errorf("expected a feasible result to keep its value, got %v", results[1].Value[0])
+	}
+
+	err = problem.schedule.SetPenaltySchedule(PenaltyScheduleConfig{Type: StaticPenalty})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if problem.schedule.UpdatePenalty(3, 10, results) {
+		t.Error("expected the static schedule to keep its factors")
+	}
+}
@@ -15,7 +15,8 @@ type Problem interface {
 		values []float64,
 		valuesWithKey map[data.ObjectiveType]float64,
 		key []data.ObjectiveType,
-		penalty map[data.ConstraintType]float64)
+		penalty map[data.ConstraintType]float64,
+		violations map[data.ConstraintType]float64)
 	GetUpperBound() []float64
 	GetLowerBound() []float64
 	GetDimension() int
@@ -34,4 +35,21 @@ type Problem interface {
 	GetPhases() [][]string
 	GetLocationResult(input []float64) (map[string]data.Location, []data.Location, []data.Crane, error)
 	GetLayoutSize() (minX float64, maxX float64, minY float64, maxY float64, err error)
+	// Repair moves the facilities encoded in pos out of each other and inside
+	// the site when the problem repairs its positions. It writes the repaired
+	// position into pos and returns it. It is safe for concurrent use.
+	Repair(pos []float64) []float64
+	// Err returns the first error raised by an objective or constraint since
+	// the last ResetErr. See EvalErrors.
+	Err() error
+	ResetErr()
+	// SetPenaltySchedule, ResetPenalty, UpdatePenalty, PenaltyState,
+	// SetPenaltyState and PenaltyReport adapt the penalty coefficients of the
+	// constraints during a run. See AdaptivePenalty.
+	SetPenaltySchedule(config PenaltyScheduleConfig) error
+	ResetPenalty()
+	UpdatePenalty(iteration int, maxIterations int, agents []*Result) bool
+	PenaltyState() PenaltyState
+	SetPenaltyState(state PenaltyState)
+	PenaltyReport(constraints map[data.ConstraintType]data.Constrainter) PenaltyReport
 }
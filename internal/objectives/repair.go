@@ -0,0 +1,161 @@
+package objectives
+
+import (
+	"golang-moaha-construction/internal/data"
+	"math"
+)
+
+// RepairPasses is the number of times RepairLocations goes over the phases
+// before it gives up on the remaining overlaps.
+const RepairPasses = 20
+
+// RepairLocations nudges the non-fixed facilities of locations apart until no
+// two facilities of the same phase overlap, and clamps them inside a site of
+// layoutLength by layoutWidth. Coordinates are the centres of the facilities.
+//
+// An overlapping pair is pushed apart along the axis it overlaps the least,
+// each facility by half of the overlap, or the non-fixed one by all of it.
+// When clearance is not nil, facilities closer than clearance of the pair
+// count as overlapping. When step is greater than 0, every move is a multiple
+// of step so that positions on a grid stay on it. locations is updated in
+// place.
+func RepairLocations(
+	locations map[string]data.Location,
+	phases [][]string,
+	layoutLength, layoutWidth, step float64,
+	clearance func(first, second string) float64,
+) {
+	for pass := 0; pass < RepairPasses; pass++ {
+		clampLocations(locations, layoutLength, layoutWidth, step)
+		if !separateLocations(locations, phases, step, clearance) {
+			break
+		}
+	}
+
+	clampLocations(locations, layoutLength, layoutWidth, step)
+}
+
+// separateLocations pushes apart the overlapping pairs of every phase and
+// reports whether it found any.
+func separateLocations(
+	locations map[string]data.Location,
+	phases [][]string,
+	step float64,
+	clearance func(first, second string) float64,
+) bool {
+	found := false
+
+	for _, phase := range phases {
+		for i := 0; i < len(phase); i++ {
+			for j := i + 1; j < len(phase); j++ {
+				a, okA := locations[phase[i]]
+				b, okB := locations[phase[j]]
+				if !okA || !okB || (a.IsFixed && b.IsFixed) {
+					continue
+				}
+
+				gap := 0.0
+				if clearance != nil {
+					gap = clearance(phase[i], phase[j])
+				}
+
+				dx := b.Coordinate.X - a.Coordinate.X
+				dy := b.Coordinate.Y - a.Coordinate.Y
+				overlapX := (a.Length+b.Length)/2 + gap - math.Abs(dx)
+				overlapY := (a.Width+b.Width)/2 + gap - math.Abs(dy)
+				if overlapX <= 0 || overlapY <= 0 {
+					continue
+				}
+
+				found = true
+
+				// a moves by -shift and b by +shift along the axis
+				alongX := overlapX <= overlapY
+				overlap, direction := overlapY, math.Copysign(1, dy)
+				if alongX {
+					overlap, direction = overlapX, math.Copysign(1, dx)
+				}
+
+				shareA, shareB := overlap/2, overlap/2
+				if a.IsFixed {
+					shareA, shareB = 0, overlap
+				} else if b.IsFixed {
+					shareA, shareB = overlap, 0
+				}
+
+				shareA = roundUpToStep(shareA, step) * direction
+				shareB = roundUpToStep(shareB, step) * direction
+
+				if alongX {
+					a.Coordinate.X -= shareA
+					b.Coordinate.X += shareB
+				} else {
+					a.Coordinate.Y -= shareA
+					b.Coordinate.Y += shareB
+				}
+
+				locations[phase[i]] = a
+				locations[phase[j]] = b
+			}
+		}
+	}
+
+	return found
+}
+
+// clampLocations moves the non-fixed facilities back inside the site.
+func clampLocations(locations map[string]data.Location, layoutLength, layoutWidth, step float64) {
+	for symbol, loc := range locations {
+		if loc.IsFixed {
+			continue
+		}
+
+		loc.Coordinate.X = clampToStep(loc.Coordinate.X, loc.Length/2, layoutLength-loc.Length/2, step)
+		loc.Coordinate.Y = clampToStep(loc.Coordinate.Y, loc.Width/2, layoutWidth-loc.Width/2, step)
+		locations[symbol] = loc
+	}
+}
+
+// clampToStep moves value inside [lower, upper] by a multiple of step, or to
+// the bound when step is 0.
+func clampToStep(value, lower, upper, step float64) float64 {
+	if lower > upper {
+		// the facility is larger than the site
+		return (lower + upper) / 2
+	}
+
+	switch {
+	case value < lower:
+		if step <= 0 {
+			return lower
+		}
+		return value + math.Ceil((lower-value)/step)*step
+	case value > upper:
+		if step <= 0 {
+			return upper
+		}
+		return value - math.Ceil((value-upper)/step)*step
+	default:
+		return value
+	}
+}
+
+func roundUpToStep(value, step float64) float64 {
+	if step <= 0 || value == 0 {
+		return value
+	}
+
+	return math.Ceil(value/step) * step
+}
+
+// ClearanceOf returns the clearance required by the first constraint
+// implementing data.ClearanceProvider, or nil when none does.
+func ClearanceOf(constraints map[data.ConstraintType]data.Constrainter) func(first, second string) float64 {
+	for _, con := range constraints {
+		if provider, ok := con.(data.ClearanceProvider); ok {
+			return provider.ClearanceBetween
+		}
+	}
+
+	return nil
+}
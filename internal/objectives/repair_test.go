@@ -0,0 +1,73 @@
+package objectives
+
+import (
+	"golang-moaha-construction/internal/data"
+	"math"
+	"testing"
+)
+
+func overlapped(a, b data.Location) bool {
+	return (a.Length+b.Length)/2-math.Abs(a.Coordinate.X-b.Coordinate.X) > 0 &&
+		(a.Width+b.Width)/2-math.Abs(a.Coordinate.Y-b.Coordinate.Y) > 0
+}
+
+func TestRepairLocations(t *testing.T) {
+	locations := map[string]data.Location{
+		"TF1": {Symbol: "TF1", Coordinate: data.Coordinate{X: 10, Y: 10}, Length: 10, Width: 10, IsFixed: true},
+		"TF2": {Symbol: "TF2", Coordinate: data.Coordinate{X: 14, Y: 11}, Length: 6, Width: 4},
+		"TF3": {Symbol: "TF3", Coordinate: data.Coordinate{X: 15, Y: 12}, Length: 6, Width: 4},
+		// not in the same phase as TF1, only clamped
+		"TF4": {Symbol: "TF4", Coordinate: data.Coordinate{X: 99, Y: -3}, Length: 6, Width: 4},
+	}
+	phases := [][]string{{"TF1", "TF2", "TF3"}, {"TF4"}}
+
+	RepairLocations(locations, phases, 50, 40, 0, nil)
+
+	if locations["TF1"].Coordinate != (data.Coordinate{X: 10, Y: 10}) {
+		t.Errorf("expected the fixed facility to stay, got %+v", locations["TF1"].Coordinate)
+	}
+
+	for i, a := range phases[0] {
+		for _, b := range phases[0][i+1:] {
+			if overlapped(locations[a], locations[b]) {
+				t.Errorf("expected %s and %s apart, got %+v and %+v", a, b, locations[a].Coordinate, locations[b].Coordinate)
+			}
+		}
+	}
+
+	if locations["TF4"].Coordinate != (data.Coordinate{X: 47, Y: 2}) {
+		t.Errorf("expected TF4 clamped inside the site, got %+v", locations["TF4"].Coordinate)
+	}
+
+	grid := map[string]data.Location{
+		"TF1": {Symbol: "TF1", Coordinate: data.Coordinate{X: 3, Y: 2}, Length: 6, Width: 4},
+		"TF2": {Symbol: "TF2", Coordinate: data.Coordinate{X: 5, Y: 2}, Length: 6, Width: 4},
+	}
+
+	RepairLocations(grid, [][]string{{"TF1", "TF2"}}, 50, 40, 2, nil)
+
+	if overlapped(grid["TF1"], grid["TF2"]) {
+		t.Errorf("expected the facilities apart, got %+v and %+v", grid["TF1"].Coordinate, grid["TF2"].Coordinate)
+	}
+	for symbol, loc := range grid {
+		corner := loc.Coordinate.X - loc.Length/2
+		if math.Mod(corner, 2) != 0 || corner < 0 {
+			t.Errorf("expected %s on the grid inside the site, got %+v", symbol, loc.Coordinate)
+		}
+	}
+}
+
+func TestRepairLocationsClearance(t *testing.T) {
+	locations := map[string]data.Location{
+		"TF1": {Symbol: "TF1", Coordinate: data.Coordinate{X: 10, Y: 10}, Length: 4, Width: 4, IsFixed: true},
+		"TF2": {Symbol: "TF2", Coordinate: data.Coordinate{X: 15, Y: 10}, Length: 4, Width: 4},
+	}
+
+	RepairLocations(locations, [][]string{{"TF1", "TF2"}}, 50, 40, 0, func(first, second string) float64 {
+		return 3
+	})
+
+	if locations["TF2"].Coordinate != (data.Coordinate{X: 17, Y: 10}) {
+		t.Errorf("expected TF2 moved 3 away from TF1, got %+v", locations["TF2"].Coordinate)
+	}
+}
@@ -4,17 +4,43 @@ import (
 	"fmt"
 	"golang-moaha-construction/internal/data"
 	"golang-moaha-construction/internal/util"
+	"maps"
 	"math"
+	"slices"
 	"sort"
 	"strings"
 )
 
+// ConstraintHandling is how constraint violations take part when results are
+// compared.
+type ConstraintHandling string
+
+const (
+	// PenaltyHandling compares the penalized objective values only, the
+	// penalties being added to the values by the problem.
+	PenaltyHandling ConstraintHandling = "penalty"
+	// FeasibilityFirst follows the constrained domination of Deb: a feasible
+	// result beats an infeasible one, of two infeasible results the one with
+	// the lower total violation wins, and feasible results compare by Pareto
+	// dominance.
+	FeasibilityFirst ConstraintHandling = "feasibility-first"
+)
+
+// ConstraintHandlings lists the valid constraint handlings, the default first.
+var ConstraintHandlings = []ConstraintHandling{PenaltyHandling, FeasibilityFirst}
+
 type Result struct {
-	Idx              int
-	Position         []float64
-	Value            []float64
-	ValuesWithKey    map[data.ObjectiveType]float64
-	Penalty          map[data.ConstraintType]float64
+	Idx           int
+	Position      []float64
+	Value         []float64
+	ValuesWithKey map[data.ObjectiveType]float64
+	Penalty       map[data.ConstraintType]float64
+	// Violations is the amount every constraint is violated by, before the
+	// penalty coefficients and the schedule apply
+	Violations map[data.ConstraintType]float64
+	// Violation is the total constraint violation, 0 when the result is
+	// feasible. See TotalViolation.
+	Violation        float64
 	Key              []data.ObjectiveType
 	CrowdingDistance float64
 	Dominated        bool
@@ -47,6 +73,8 @@ func (agent *Result) CopyAgent() *Result {
 		Value:            util.CopyArray(agent.Value),
 		ValuesWithKey:    util.CopyMap(agent.ValuesWithKey),
 		Penalty:          util.CopyMap(agent.Penalty),
+		Violations:       util.CopyMap(agent.Violations),
+		Violation:        agent.Violation,
 		Key:              util.CopyArray(agent.Key),
 		CrowdingDistance: agent.CrowdingDistance,
 		Dominated:        agent.Dominated,
@@ -74,6 +102,48 @@ func (agent *Result) Dominates(other *Result) bool {
 	return anyConstraint
 }
 
+// TotalViolation sums the amounts the constraints are violated by in a fixed
+// order so that the same position always gives the same violation. The
+// amounts are raw, so that the penalty coefficients and schedule do not change
+// how infeasible results rank.
+func TotalViolation(violations map[data.ConstraintType]float64) float64 {
+	total := 0.0
+	for _, c := range slices.Sorted(maps.Keys(violations)) {
+		total += violations[c]
+	}
+
+	return total
+}
+
+// DominatesWith reports whether agent dominates other under handling. Any
+// handling but FeasibilityFirst is plain Pareto dominance, see Dominates.
+func (agent *Result) DominatesWith(other *Result, handling ConstraintHandling) bool {
+	if handling != FeasibilityFirst {
+		return agent.Dominates(other)
+	}
+
+	switch {
+	case agent.Violation == 0 && other.Violation == 0:
+		return agent.Dominates(other)
+	case agent.Violation == 0:
+		return true
+	case other.Violation == 0:
+		return false
+	default:
+		return agent.Violation < other.Violation
+	}
+}
+
+// Better reports whether agent is a better single-objective result than other
+// under handling, comparing the first value.
+func (agent *Result) Better(other *Result, handling ConstraintHandling) bool {
+	if handling == FeasibilityFirst && (agent.Violation > 0 || other.Violation > 0) {
+		return agent.Violation < other.Violation
+	}
+
+	return agent.Value[0] < other.Value[0]
+}
+
 func MergeAgents(a []*Result, b []*Result) []*Result {
 	res := make([]*Result, len(a)+len(b))
 	for i := 0; i < len(a); i++ {
@@ -88,7 +158,10 @@ func MergeAgents(a []*Result, b []*Result) []*Result {
 
 	return res
 }
-func DetermineDomination(agents []*Result) []*Result {
+
+// DetermineDomination marks every agent dominated by another one under
+// handling. Of agents with equal values only the last is kept.
+func DetermineDomination(agents []*Result, handling ConstraintHandling) []*Result {
 	// clear the dominated
 	for i := range agents {
 		agents[i].Dominated = false
@@ -97,9 +170,9 @@ func DetermineDomination(agents []*Result) []*Result {
 	// determine domination
 	for i := 0; i < len(agents)-1; i++ {
 		for j := i + 1; j < len(agents); j++ {
-			if agents[i].Dominates(agents[j]) {
+			if agents[i].DominatesWith(agents[j], handling) {
 				agents[j].Dominated = true
-			} else if agents[j].Dominates(agents[i]) {
+			} else if agents[j].DominatesWith(agents[i], handling) {
 				agents[i].Dominated = true
 				break
 			} else {
@@ -112,7 +185,7 @@ func DetermineDomination(agents []*Result) []*Result {
 					}
 				}
 
-				if allEqual {
+				if allEqual && agents[i].Violation == agents[j].Violation {
 					agents[i].Dominated = true
 				}
 			}
@@ -134,7 +207,7 @@ func GetNonDominatedAgents(agents []*Result) []*Result {
 	return res
 }
 
-func NonDominatedSort(agents []*Result) ([]*Result, [][]int) {
+func NonDominatedSort(agents []*Result, handling ConstraintHandling) ([]*Result, [][]int) {
 	// clear domination set and domination count
 	for i := range agents {
 		agents[i].DominationSet = make([]int, 0)
@@ -150,12 +223,12 @@ func NonDominatedSort(agents []*Result) ([]*Result, [][]int) {
 			p := agents[i]
 			q := agents[j]
 
-			if p.Dominates(q) {
+			if p.DominatesWith(q, handling) {
 				p.DominationSet = append(p.DominationSet, q.Idx)
 				q.DominatedCount += 1
 			}
 
-			if q.Dominates(p) {
+			if q.DominatesWith(p, handling) {
 				q.DominationSet = append(q.DominationSet, p.Idx)
 				p.DominatedCount += 1
 			}
@@ -199,8 +272,8 @@ func NonDominatedSort(agents []*Result) ([]*Result, [][]int) {
 }
 
 // FastNonDominatedSorting_Vectorized performs a fast non-dominated sorting algorithm
-// It returns both the ranks of each solution and the Pareto fronts
-func FastNonDominatedSorting_Vectorized(agents []*Result) ([]*Result, [][]int) {
+// under handling. It returns both the ranks of each solution and the Pareto fronts
+func FastNonDominatedSorting_Vectorized(agents []*Result, handling ConstraintHandling) ([]*Result, [][]int) {
 	// Initialization
 	Np := len(agents)
 	RANK := make([]int, Np)
@@ -213,9 +286,9 @@ func FastNonDominatedSorting_Vectorized(agents []*Result) ([]*Result, [][]int) {
 	// Check domination for all pairs
 	for i := 0; i < Np-1; i++ {
 		for j := i + 1; j < Np; j++ {
-			if agents[i].Dominates(agents[j]) {
+			if agents[i].DominatesWith(agents[j], handling) {
 				RANK[j]++
-			} else if agents[j].Dominates(agents[i]) {
+			} else if agents[j].DominatesWith(agents[i], handling) {
 				RANK[i]++
 			}
 		}
@@ -1,6 +1,7 @@
 package objectives
 
 import (
+	"golang-moaha-construction/internal/data"
 	"testing"
 )
 
@@ -375,11 +376,79 @@ func TestDominatesF2True(t *testing.T) {
 	}
 }
 
+func TestDominatesWithFeasibilityFirst(t *testing.T) {
+	feasible := &Result{Value: []float64{5, 5}}
+	betterFeasible := &Result{Value: []float64{4, 5}}
+	infeasible := &Result{Value: []float64{1, 1}, Violation: 2}
+	lessInfeasible := &Result{Value: []float64{9, 9}, Violation: 1}
+
+	if !feasible.DominatesWith(infeasible, FeasibilityFirst) || infeasible.DominatesWith(feasible, FeasibilityFirst) {
+		t.Errorf("Expected a feasible agent to dominate an infeasible one")
+	}
+
+	if !lessInfeasible.DominatesWith(infeasible, FeasibilityFirst) {
+		t.Errorf("Expected the lower violation to win")
+	}
+
+	if !betterFeasible.DominatesWith(feasible, FeasibilityFirst) || feasible.DominatesWith(betterFeasible, FeasibilityFirst) {
+		t.Errorf("Expected feasible agents to compare by Pareto dominance")
+	}
+
+	if !infeasible.DominatesWith(feasible, PenaltyHandling) {
+		t.Errorf("Expected the penalty handling to ignore the violation")
+	}
+
+	if !feasible.Better(infeasible, FeasibilityFirst) || !infeasible.Better(feasible, PenaltyHandling) {
+		t.Errorf("Expected Better to follow the handling")
+	}
+
+	agents := []*Result{infeasible, feasible, lessInfeasible, betterFeasible}
+	for i := range agents {
+		agents[i].Idx = i
+	}
+
+	_, paretoFront := NonDominatedSort(agents, FeasibilityFirst)
+	expected := [][]int{{3}, {1}, {2}, {0}}
+	if !equalParetoFronts(paretoFront, expected) {
+		t.Errorf("NonDominatedSort ParetoFront mismatch: got %v, expected %v", paretoFront, expected)
+	}
+}
+
+func TestTotalViolation_DeathPenalty(t *testing.T) {
+	const name data.ConstraintType = "Test"
+	con := testConstraint{alpha: 10, power: 2}
+
+	var p AdaptivePenalty
+	err := p.SetPenaltySchedule(PenaltyScheduleConfig{Type: DeathPenalty})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// both penalties are infinite, the violations still rank the results
+	results := make([]*Result, 0, 2)
+	for _, amount := range []float64{3, 1} {
+		violations := map[data.ConstraintType]float64{name: amount}
+		results = append(results, &Result{
+			Value:      []float64{1, 1},
+			Penalty:    map[data.ConstraintType]float64{name: p.Penalty(name, con, amount)},
+			Violations: violations,
+			Violation:  TotalViolation(violations),
+		})
+	}
+
+	if results[0].Violation != 3 || results[1].Violation != 1 {
+		t.Errorf("expected the raw violations 3 and 1, got %v and %v", results[0].Violation, results[1].Violation)
+	}
+	if !results[1].DominatesWith(results[0], FeasibilityFirst) || results[0].DominatesWith(results[1], FeasibilityFirst) {
+		t.Errorf("expected the lower violation to win under the death penalty")
+	}
+}
+
 func TestDetermineDominationValid(t *testing.T) {
 	var dominated = []bool{true, true, true, true, true, true, false, true, false, true, true, true, true, true, true, false, true, true, true, true, true, true, true, true, true, true, true, true, false, true, true, true, true, true, true, true, true, true, true, true, true, true, true, true, true, true, false, true, true, false, true, true, true, true, true, true, true, true, true, true, true, true, true, true, false, true, true, true, true, true, true, true, true, true, true, true, true, false, true, false, true, true, true, false, false, true, true, true, true, true, true, true, true, true, true, true, false, true, true, true}
 	agents := GenerateSampleData()
 
-	agents = DetermineDomination(agents)
+	agents = DetermineDomination(agents, PenaltyHandling)
 
 	for i := range dominated {
 		if agents[i].Dominated != dominated[i] {
@@ -407,7 +476,7 @@ var validValues = [][]float64{
 func TestDetermineDominationValidLength(t *testing.T) {
 	agents := GenerateSampleData()
 
-	agents = DetermineDomination(agents)
+	agents = DetermineDomination(agents, PenaltyHandling)
 	agents = GetNonDominatedAgents(agents)
 	if len(agents) != len(validValues) {
 		t.Errorf("Expected length of agents to be %d, got %d", len(validValues), len(agents))
@@ -418,7 +487,7 @@ func TestDetermineDominationValidLength(t *testing.T) {
 func TestDetermineDominationValidValues(t *testing.T) {
 	agents := GenerateSampleData()
 
-	agents = DetermineDomination(agents)
+	agents = DetermineDomination(agents, PenaltyHandling)
 	agents = GetNonDominatedAgents(agents)
 
 	for i := range validValues {
@@ -548,8 +617,8 @@ var paretoFrontIndices = [][]int{
 
 func TestNonDominatedSortRank(t *testing.T) {
 	agents := GenerateSampleData()
-	agents = DetermineDomination(agents)
-	agents, _ = NonDominatedSort(agents)
+	agents = DetermineDomination(agents, PenaltyHandling)
+	agents, _ = NonDominatedSort(agents, PenaltyHandling)
 
 	if len(agents) != len(ranking) {
 		t.Errorf("Expected length of agents to be %d, got %d", len(ranking), len(agents))
@@ -564,8 +633,8 @@ func TestNonDominatedSortRank(t *testing.T) {
 
 func TestNonDominatedSortDominationSet(t *testing.T) {
 	agents := GenerateSampleData()
-	agents = DetermineDomination(agents)
-	agents, _ = NonDominatedSort(agents)
+	agents = DetermineDomination(agents, PenaltyHandling)
+	agents, _ = NonDominatedSort(agents, PenaltyHandling)
 	if len(agents) != len(agentsDominationSet) {
 		t.Errorf("Expected length of agents to be %d, got %d", len(agentsDominationSet), len(agents))
 	}
@@ -584,8 +653,8 @@ func TestNonDominatedSortDominationSet(t *testing.T) {
 
 func TestNonDominatedSortParetoFrontRankLength(t *testing.T) {
 	agents := GenerateSampleData()
-	agents = DetermineDomination(agents)
-	_, rankIndices := NonDominatedSort(agents)
+	agents = DetermineDomination(agents, PenaltyHandling)
+	_, rankIndices := NonDominatedSort(agents, PenaltyHandling)
 
 	if len(rankIndices) != len(paretoFrontIndices) {
 		t.Errorf("Expected length of rank indices to be %d, got %d", len(paretoFrontIndices), len(rankIndices))
@@ -595,8 +664,8 @@ func TestNonDominatedSortParetoFrontRankLength(t *testing.T) {
 
 func TestNonDominatedSortSetEachRank(t *testing.T) {
 	agents := GenerateSampleData()
-	agents = DetermineDomination(agents)
-	_, rankIndices := NonDominatedSort(agents)
+	agents = DetermineDomination(agents, PenaltyHandling)
+	_, rankIndices := NonDominatedSort(agents, PenaltyHandling)
 
 	for i := range paretoFrontIndices {
 		if len(rankIndices[i]) != len(paretoFrontIndices[i]) {
@@ -640,7 +709,7 @@ func TestNonDominatedSorting(t *testing.T) {
 	}
 
 	// Test NonDominatedSort
-	sortedPop, paretoFront := NonDominatedSort(populations)
+	sortedPop, paretoFront := NonDominatedSort(populations, PenaltyHandling)
 	for i, pop := range sortedPop {
 		if pop.Rank != expectedRanks[i] {
 			t.Errorf("NonDominatedSort Rank mismatch for pop %d: got %d, expected %d", i, pop.Rank, expectedRanks[i])
@@ -654,7 +723,7 @@ func TestNonDominatedSorting(t *testing.T) {
 	}
 
 	// Test FastNonDominatedSorting_Vectorized
-	sortedPopFast, paretoFrontFast := FastNonDominatedSorting_Vectorized(populationsFast)
+	sortedPopFast, paretoFrontFast := FastNonDominatedSorting_Vectorized(populationsFast, PenaltyHandling)
 	for i, pop := range sortedPopFast {
 		if pop.Rank != expectedRanks[i] {
 			t.Errorf("FastNonDominatedSorting_Vectorized Rank mismatch for pop %d: got %d, expected %d", i, pop.Rank, expectedRanks[i])
@@ -0,0 +1,94 @@
+package schedule
+
+import (
+	"encoding/xml"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// mspdiDateLayouts are the layouts of the dates of an MSPDI file.
+var mspdiDateLayouts = []string{"2006-01-02T15:04:05", "2006-01-02T15:04"}
+
+type mspdiProject struct {
+	XMLName            xml.Name `xml:"Project"`
+	ExtendedAttributes []struct {
+		FieldID   string `xml:"FieldID"`
+		FieldName string `xml:"FieldName"`
+		Alias     string `xml:"Alias"`
+	} `xml:"ExtendedAttributes>ExtendedAttribute"`
+	Tasks []struct {
+		ID                 string `xml:"ID"`
+		Name               string `xml:"Name"`
+		Summary            string `xml:"Summary"`
+		Start              string `xml:"Start"`
+		Finish             string `xml:"Finish"`
+		ExtendedAttributes []struct {
+			FieldID string `xml:"FieldID"`
+			Value   string `xml:"Value"`
+		} `xml:"ExtendedAttribute"`
+	} `xml:"Tasks>Task"`
+}
+
+// ReadMSPDIFromFile reads the activities of a Microsoft Project XML (MSPDI)
+// export. An activity is tagged with facilities by a custom field, e.g.
+// Text1, whose alias or name is TagName. Summary tasks are skipped.
+func ReadMSPDIFromFile(filePath string) ([]Activity, error) {
+	content, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	var project mspdiProject
+	if err := xml.Unmarshal(content, &project); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrUnknownFormat, err)
+	}
+
+	tagFields := make(map[string]bool)
+	for _, attribute := range project.ExtendedAttributes {
+		if strings.EqualFold(attribute.Alias, TagName) || strings.EqualFold(attribute.FieldName, TagName) {
+			tagFields[attribute.FieldID] = true
+		}
+	}
+
+	activities := make([]Activity, 0, len(project.Tasks))
+	for _, task := range project.Tasks {
+		if task.Summary == "1" {
+			continue
+		}
+
+		var facilities []string
+		for _, attribute := range task.ExtendedAttributes {
+			if !tagFields[attribute.FieldID] {
+				continue
+			}
+			tagged, err := parseFacilities(attribute.Value)
+			if err != nil {
+				return nil, fmt.Errorf("task %s: %w", task.ID, err)
+			}
+			facilities = append(facilities, tagged...)
+		}
+		if len(facilities) == 0 {
+			continue
+		}
+
+		start, err := parseTime(task.Start, mspdiDateLayouts...)
+		if err != nil {
+			return nil, fmt.Errorf("task %s: %w", task.ID, err)
+		}
+		finish, err := parseTime(task.Finish, mspdiDateLayouts...)
+		if err != nil {
+			return nil, fmt.Errorf("task %s: %w", task.ID, err)
+		}
+
+		activities = append(activities, Activity{
+			ID:         task.ID,
+			Name:       task.Name,
+			Start:      start,
+			Finish:     finish,
+			Facilities: facilities,
+		})
+	}
+
+	return activities, nil
+}
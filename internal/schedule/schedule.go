@@ -0,0 +1,157 @@
+// Package schedule derives the construction phases from a project schedule
+// exported from Primavera P6 (XER) or Microsoft Project (MSPDI XML), whose
+// activities are tagged with the temporary facilities they need.
+package schedule
+
+import (
+	"errors"
+	"fmt"
+	"golang-moaha-construction/internal/util"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+)
+
+// TagName is the name of the user-defined field, activity code or extended
+// attribute that tags an activity with its facilities, e.g. "TF1, TF3".
+const TagName = "Facilities"
+
+var (
+	ErrUnknownFormat    = errors.New("unknown schedule format, expected a P6 XER or an MSPDI XML file")
+	ErrNoTaggedActivity = errors.New("no activity of the schedule is tagged with facilities")
+)
+
+// Activity is an activity of a schedule with the facilities it needs.
+type Activity struct {
+	ID         string
+	Name       string
+	Start      time.Time
+	Finish     time.Time
+	Facilities []string
+}
+
+// IsScheduleFile reports whether filePath is a schedule export that
+// ReadPhasesFromFile reads.
+func IsScheduleFile(filePath string) bool {
+	switch strings.ToLower(filepath.Ext(filePath)) {
+	case ".xer", ".xml":
+		return true
+	default:
+		return false
+	}
+}
+
+// ReadPhasesFromFile reads the activities of a schedule export and slices
+// them into phases. See Phases.
+func ReadPhasesFromFile(filePath string) ([][]string, []float64, error) {
+	activities, err := ReadActivitiesFromFile(filePath)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return Phases(activities)
+}
+
+// ReadActivitiesFromFile reads the activities tagged with facilities from a
+// P6 XER or an MSPDI XML file.
+func ReadActivitiesFromFile(filePath string) ([]Activity, error) {
+	switch strings.ToLower(filepath.Ext(filePath)) {
+	case ".xer":
+		return ReadXERFromFile(filePath)
+	case ".xml":
+		return ReadMSPDIFromFile(filePath)
+	default:
+		return nil, ErrUnknownFormat
+	}
+}
+
+// Phases slices the timeline of activities into phases wherever the set of
+// facilities needed by the running activities changes. A facility is active
+// in a phase when an activity tagged with it runs during the phase. It
+// returns the facilities of every phase, ordered by number, and the duration
+// of every phase in calendar days. Periods without any facility are not
+// phases, and a period with the same facilities as the previous phase extends
+// it.
+func Phases(activities []Activity) ([][]string, []float64, error) {
+	// milestones need no facility
+	tagged := make([]Activity, 0, len(activities))
+	boundaries := make([]time.Time, 0, 2*len(activities))
+	for _, activity := range activities {
+		if len(activity.Facilities) == 0 || !activity.Finish.After(activity.Start) {
+			continue
+		}
+		tagged = append(tagged, activity)
+		boundaries = append(boundaries, activity.Start, activity.Finish)
+	}
+	if len(boundaries) == 0 {
+		return nil, nil, ErrNoTaggedActivity
+	}
+
+	slices.SortFunc(boundaries, func(a, b time.Time) int {
+		return a.Compare(b)
+	})
+	boundaries = slices.CompactFunc(boundaries, time.Time.Equal)
+
+	phases := make([][]string, 0)
+	durations := make([]float64, 0)
+	for i := 0; i < len(boundaries)-1; i++ {
+		from, to := boundaries[i], boundaries[i+1]
+
+		active := make([]string, 0)
+		for _, activity := range tagged {
+			if activity.Start.Before(to) && activity.Finish.After(from) {
+				active = append(active, activity.Facilities...)
+			}
+		}
+		if len(active) == 0 {
+			continue
+		}
+
+		slices.SortFunc(active, func(a, b string) int {
+			return util.ExtractNumber(a) - util.ExtractNumber(b)
+		})
+		active = slices.Compact(active)
+
+		duration := to.Sub(from).Hours() / 24
+		// the same facilities as the previous phase, the phase goes on
+		if last := len(phases) - 1; last >= 0 && slices.Equal(phases[last], active) {
+			durations[last] += duration
+			continue
+		}
+
+		phases = append(phases, active)
+		durations = append(durations, duration)
+	}
+
+	return phases, durations, nil
+}
+
+// parseFacilities returns the facilities of a tag, the symbols separated by
+// commas, semicolons or spaces.
+func parseFacilities(tag string) ([]string, error) {
+	fields := strings.FieldsFunc(tag, func(r rune) bool {
+		return r == ',' || r == ';' || r == ' ' || r == '\t'
+	})
+
+	facilities := make([]string, 0, len(fields))
+	for _, field := range fields {
+		if err := util.ValidateTFNumber(field); err != nil {
+			return nil, err
+		}
+		facilities = append(facilities, strings.ToUpper(field))
+	}
+
+	return facilities, nil
+}
+
+// parseTime parses a schedule date with one of layouts.
+func parseTime(value string, layouts ...string) (time.Time, error) {
+	for _, layout := range layouts {
+		if t, err := time.Parse(layout, strings.TrimSpace(value)); err == nil {
+			return t, nil
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("invalid date %q", value)
+}
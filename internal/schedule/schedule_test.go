@@ -0,0 +1,99 @@
+package schedule
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestPhases(t *testing.T) {
+	day := func(d int) time.Time {
+		return time.Date(2025, 1, d, 0, 0, 0, 0, time.UTC)
+	}
+
+	activities := []Activity{
+		{ID: "A1", Start: day(1), Finish: day(11), Facilities: []string{"TF2", "TF1"}},
+		{ID: "A2", Start: day(6), Finish: day(16), Facilities: []string{"TF3"}},
+		// the same facilities as A1, no new phase when A1 ends
+		{ID: "A3", Start: day(11), Finish: day(13), Facilities: []string{"TF1", "TF2"}},
+		// untagged and milestones do not slice the timeline
+		{ID: "A4", Start: day(2), Finish: day(30)},
+		{ID: "A5", Start: day(8), Finish: day(8), Facilities: []string{"TF9"}},
+		// after a period without facilities
+		{ID: "A6", Start: day(20), Finish: day(25), Facilities: []string{"TF10"}},
+	}
+
+	phases, durations, err := Phases(activities)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedPhases := [][]string{{"TF1", "TF2"}, {"TF1", "TF2", "TF3"}, {"TF3"}, {"TF10"}}
+	expectedDurations := []float64{5, 7, 3, 5}
+	if !slices.EqualFunc(phases, expectedPhases, slices.Equal) {
+		t.Errorf("expected phases %v, got %v", expectedPhases, phases)
+	}
+	if !slices.Equal(durations, expectedDurations) {
+		t.Errorf("expected durations %v, got %v", expectedDurations, durations)
+	}
+
+	if _, _, err := Phases(activities[3:5]); err == nil {
+		t.Error("expected an error without a tagged activity")
+	}
+}
+
+func TestReadPhasesFromFile(t *testing.T) {
+	xer := "ERMHDR\t19.12\n" +
+		"%T\tUDFTYPE\n%F\tudf_type_id\ttable_name\tudf_type_label\n%R\t1\tTASK\tFacilities\n" +
+		"%T\tUDFVALUE\n%F\tudf_type_id\tfk_id\tudf_text\n%R\t1\t100\tTF1, tf2\n" +
+		"%T\tACTVTYPE\n%F\tactv_code_type_id\tactv_code_type\n%R\t5\tFacilities\n" +
+		"%T\tACTVCODE\n%F\tactv_code_id\tactv_code_type_id\tshort_name\n%R\t50\t5\tTF3\n" +
+		"%T\tTASKACTV\n%F\ttask_id\tactv_code_type_id\tactv_code_id\n%R\t101\t5\t50\n" +
+		"%T\tTASK\n%F\ttask_id\ttask_code\ttask_name\ttask_type\tearly_start_date\tearly_end_date\ttarget_start_date\ttarget_end_date\n" +
+		"%R\t100\tA1000\tExcavation\tTT_Task\t2025-01-01 00:00\t2025-01-11 00:00\t\t\n" +
+		"%R\t101\tA1010\tFoundations\tTT_Task\t\t\t2025-01-06 00:00\t2025-01-16 00:00\n" +
+		"%E\n"
+
+	mspdi := `<?xml version="1.0" encoding="UTF-8"?>
+<Project xmlns="http://schemas.microsoft.com/project">
+  <ExtendedAttributes>
+    <ExtendedAttribute><FieldID>188743731</FieldID><FieldName>Text1</FieldName><Alias>Facilities</Alias></ExtendedAttribute>
+  </ExtendedAttributes>
+  <Tasks>
+    <Task><ID>0</ID><Name>Project</Name><Summary>1</Summary><Start>2025-01-01T00:00:00</Start><Finish>2025-01-16T00:00:00</Finish></Task>
+    <Task><ID>1</ID><Name>Excavation</Name><Summary>0</Summary><Start>2025-01-01T00:00:00</Start><Finish>2025-01-11T00:00:00</Finish>
+      <ExtendedAttribute><FieldID>188743731</FieldID><Value>TF1; TF2</Value></ExtendedAttribute></Task>
+    <Task><ID>2</ID><Name>Foundations</Name><Summary>0</Summary><Start>2025-01-06T00:00:00</Start><Finish>2025-01-16T00:00:00</Finish>
+      <ExtendedAttribute><FieldID>188743731</FieldID><Value>TF3</Value></ExtendedAttribute></Task>
+  </Tasks>
+</Project>`
+
+	expectedPhases := [][]string{{"TF1", "TF2"}, {"TF1", "TF2", "TF3"}, {"TF3"}}
+	expectedDurations := []float64{5, 5, 5}
+
+	for name, content := range map[string]string{"schedule.xer": xer, "schedule.xml": mspdi} {
+		t.Run(name, func(t *testing.T) {
+			filePath := filepath.Join(t.TempDir(), name)
+			if err := os.WriteFile(filePath, []byte(content), 0o644); err != nil {
+				t.Fatal(err)
+			}
+
+			if !IsScheduleFile(filePath) {
+				t.Errorf("expected %s to be a schedule file", name)
+			}
+
+			phases, durations, err := ReadPhasesFromFile(filePath)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !slices.EqualFunc(phases, expectedPhases, slices.Equal) {
+				t.Errorf("expected phases %v, got %v", expectedPhases, phases)
+			}
+			if !slices.Equal(durations, expectedDurations) {
+				t.Errorf("expected durations %v, got %v", expectedDurations, durations)
+			}
+		})
+	}
+}
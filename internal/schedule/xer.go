@@ -0,0 +1,163 @@
+package schedule
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+// xerDateLayouts are the layouts of the dates of an XER file.
+var xerDateLayouts = []string{"2006-01-02 15:04", "2006-01-02 15:04:05", "2006-01-02"}
+
+// xerTable is a table of an XER file, a row being a map from field name to
+// value.
+type xerTable []map[string]string
+
+// ReadXERFromFile reads the activities of a Primavera P6 XER export. An
+// activity is tagged with facilities by a text user-defined field named
+// TagName, or by the values of an activity code type named TagName. Its dates
+// are the actual dates, else the early dates, else the planned dates. WBS
+// summaries are skipped.
+func ReadXERFromFile(filePath string) ([]Activity, error) {
+	tables, err := readXERTables(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	tags := make(map[string][]string)
+	addTag := func(taskID string, tag string) error {
+		facilities, err := parseFacilities(tag)
+		if err != nil {
+			return fmt.Errorf("activity %s: %w", taskID, err)
+		}
+		tags[taskID] = append(tags[taskID], facilities...)
+		return nil
+	}
+
+	// user-defined fields
+	udfTypes := make(map[string]bool)
+	for _, row := range tables["UDFTYPE"] {
+		if row["table_name"] == "TASK" && strings.EqualFold(row["udf_type_label"], TagName) {
+			udfTypes[row["udf_type_id"]] = true
+		}
+	}
+	for _, row := range tables["UDFVALUE"] {
+		if udfTypes[row["udf_type_id"]] {
+			if err := addTag(row["fk_id"], row["udf_text"]); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	// activity codes
+	codeTypes := make(map[string]bool)
+	for _, row := range tables["ACTVTYPE"] {
+		if strings.EqualFold(row["actv_code_type"], TagName) {
+			codeTypes[row["actv_code_type_id"]] = true
+		}
+	}
+	codes := make(map[string]string)
+	for _, row := range tables["ACTVCODE"] {
+		if codeTypes[row["actv_code_type_id"]] {
+			codes[row["actv_code_id"]] = row["short_name"]
+		}
+	}
+	for _, row := range tables["TASKACTV"] {
+		if code, ok := codes[row["actv_code_id"]]; ok {
+			if err := addTag(row["task_id"], code); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	activities := make([]Activity, 0, len(tables["TASK"]))
+	for _, row := range tables["TASK"] {
+		taskID := row["task_id"]
+		if row["task_type"] == "TT_WBS" || len(tags[taskID]) == 0 {
+			continue
+		}
+
+		start, err := xerDate(row, "act_start_date", "early_start_date", "target_start_date")
+		if err != nil {
+			return nil, fmt.Errorf("activity %s: %w", row["task_code"], err)
+		}
+		finish, err := xerDate(row, "act_end_date", "early_end_date", "target_end_date")
+		if err != nil {
+			return nil, fmt.Errorf("activity %s: %w", row["task_code"], err)
+		}
+
+		activities = append(activities, Activity{
+			ID:         row["task_code"],
+			Name:       row["task_name"],
+			Start:      start,
+			Finish:     finish,
+			Facilities: tags[taskID],
+		})
+	}
+
+	return activities, nil
+}
+
+// xerDate returns the first of fields set in row.
+func xerDate(row map[string]string, fields ...string) (time.Time, error) {
+	for _, field := range fields {
+		if value := strings.TrimSpace(row[field]); value != "" {
+			return parseTime(value, xerDateLayouts...)
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("none of %s is set", strings.Join(fields, ", "))
+}
+
+// readXERTables reads the tables of an XER file: a table starts with a %T
+// line naming it, then a %F line with the field names, then a %R line per
+// row, all separated by tabs.
+func readXERTables(filePath string) (map[string]xerTable, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	tables := make(map[string]xerTable)
+	var table string
+	var fields []string
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for lineIdx := 1; scanner.Scan(); lineIdx++ {
+		values := strings.Split(strings.TrimRight(scanner.Text(), "\r"), "\t")
+
+		switch values[0] {
+		case "%T":
+			if len(values) < 2 {
+				return nil, fmt.Errorf("line %d: expected the name of the table", lineIdx)
+			}
+			table, fields = values[1], nil
+		case "%F":
+			fields = values[1:]
+		case "%R":
+			if table == "" || fields == nil {
+				return nil, fmt.Errorf("line %d: a row outside of a table", lineIdx)
+			}
+			row := make(map[string]string, len(fields))
+			for i, field := range fields {
+				if i+1 < len(values) {
+					row[field] = values[i+1]
+				}
+			}
+			tables[table] = append(tables[table], row)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(tables) == 0 {
+		return nil, ErrUnknownFormat
+	}
+
+	return tables, nil
+}
@@ -0,0 +1,124 @@
+package statistics
+
+import (
+	"golang-moaha-construction/internal/util"
+	"math"
+)
+
+// Alpha is the significance level of Compare.
+const Alpha = 0.05
+
+// Summary describes the values of one algorithm.
+type Summary struct {
+	Runs   int
+	Best   float64
+	Worst  float64
+	Mean   float64
+	Median float64
+	Std    float64
+}
+
+// Pair compares algorithm A with algorithm B.
+type Pair struct {
+	A, B       int
+	RankSum    Test
+	SignedRank Test
+	// A12 is the probability that a run of A is better than a run of B
+	A12       float64
+	Magnitude string
+	// Outcome is 1 when A is significantly better than B by the rank-sum
+	// test, -1 when it is significantly worse and 0 otherwise
+	Outcome int
+	// Nemenyi tells whether the mean ranks of A and B differ by more than
+	// the critical difference
+	Nemenyi bool
+}
+
+// Comparison holds every test of one measure, such as the best value of an
+// objective or the hypervolume of the final archive.
+type Comparison struct {
+	Measure    string
+	Maximize   bool
+	Algorithms []string
+	Summaries  []Summary
+	Friedman   FriedmanResult
+	// Pairs holds every pair of algorithms once, A before B
+	Pairs []Pair
+}
+
+// Compare runs every test on samples, where samples[a][r] is the value of
+// algorithm a in run r. Runs with the same index are paired for the
+// signed-rank and Friedman tests, which only use the runs every algorithm
+// has.
+func Compare(measure string, maximize bool, algorithms []string, samples [][]float64) Comparison {
+	res := Comparison{
+		Measure:    measure,
+		Maximize:   maximize,
+		Algorithms: algorithms,
+		Summaries:  make([]Summary, len(samples)),
+		Pairs:      make([]Pair, 0, len(samples)*(len(samples)-1)/2),
+	}
+
+	runs := math.MaxInt
+	for a, values := range samples {
+		runs = min(runs, len(values))
+		res.Summaries[a] = summarize(values, maximize)
+	}
+
+	paired := make([][]float64, len(samples))
+	for a, values := range samples {
+		paired[a] = values[:runs]
+	}
+	res.Friedman = Friedman(paired, maximize)
+
+	for a := range samples {
+		for b := a + 1; b < len(samples); b++ {
+			pair := Pair{
+				A:          a,
+				B:          b,
+				RankSum:    RankSum(samples[a], samples[b]),
+				SignedRank: SignedRank(paired[a], paired[b]),
+				A12:        VarghaDelaney(samples[a], samples[b], maximize),
+			}
+			pair.Magnitude = Magnitude(pair.A12)
+
+			if pair.RankSum.PValue < Alpha {
+				switch {
+				case pair.A12 > 0.5:
+					pair.Outcome = 1
+				case pair.A12 < 0.5:
+					pair.Outcome = -1
+				}
+			}
+
+			if len(res.Friedman.MeanRanks) == len(samples) {
+				pair.Nemenyi = math.Abs(res.Friedman.MeanRanks[a]-res.Friedman.MeanRanks[b]) > res.Friedman.CriticalDifference
+			}
+
+			res.Pairs = append(res.Pairs, pair)
+		}
+	}
+
+	return res
+}
+
+func summarize(values []float64, maximize bool) Summary {
+	if len(values) == 0 {
+		return Summary{Best: math.NaN(), Worst: math.NaN(), Mean: math.NaN(), Median: math.NaN(), Std: math.NaN()}
+	}
+
+	lowest, _ := util.MinWithIdx(values)
+	highest, _ := util.MaxWithIdx(values)
+	if maximize {
+		lowest, highest = highest, lowest
+	}
+
+	return Summary{
+		Runs:   len(values),
+		Best:   lowest,
+		Worst:  highest,
+		Mean:   util.Mean(values),
+		Median: util.Median(values),
+		Std:    util.Std(values),
+	}
+}
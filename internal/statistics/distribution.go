@@ -0,0 +1,75 @@
+// Package statistics provides the non-parametric tests used to compare
+// optimizers over repeated runs.
+package statistics
+
+import (
+	"math"
+)
+
+// normalTwoSided returns the two-sided p-value of a standard normal z.
+func normalTwoSided(z float64) float64 {
+	return math.Erfc(math.Abs(z) / math.Sqrt2)
+}
+
+// chiSquareSurvival returns P(X >= x) for a chi-square distribution with df
+// degrees of freedom.
+func chiSquareSurvival(x float64, df int) float64 {
+	if x <= 0 {
+		return 1
+	}
+
+	return upperGamma(float64(df)/2, x/2)
+}
+
+// upperGamma is the regularized upper incomplete gamma function Q(a, x),
+// evaluated with the series for small x and the continued fraction otherwise.
+func upperGamma(a, x float64) float64 {
+	const (
+		epsilon    = 1e-15
+		iterations = 500
+		tiny       = 1e-300
+	)
+
+	lgamma, _ := math.Lgamma(a)
+	prefix := math.Exp(-x + a*math.Log(x) - lgamma)
+
+	if x < a+1 {
+		sum := 1 / a
+		term := sum
+		for n := 1; n < iterations; n++ {
+			term *= x / (a + float64(n))
+			sum += term
+			if math.Abs(term) < math.Abs(sum)*epsilon {
+				break
+			}
+		}
+
+		return max(0, 1-sum*prefix)
+	}
+
+	// modified Lentz's method
+	b := x + 1 - a
+	c := 1 / tiny
+	d := 1 / b
+	h := d
+	for n := 1; n < iterations; n++ {
+		an := -float64(n) * (float64(n) - a)
+		b += 2
+		d = an*d + b
+		if math.Abs(d) < tiny {
+			d = tiny
+		}
+		c = b + an/c
+		if math.Abs(c) < tiny {
+			c = tiny
+		}
+		d = 1 / d
+		delta := d * c
+		h *= delta
+		if math.Abs(delta-1) < epsilon {
+			break
+		}
+	}
+
+	return prefix * h
+}
@@ -0,0 +1,89 @@
+package statistics
+
+import (
+	"math"
+)
+
+// nemenyiQ05 holds the critical values q of the Nemenyi test at alpha 0.05 for
+// 2 to 20 algorithms, the studentized range statistic divided by sqrt(2).
+var nemenyiQ05 = []float64{
+	1.960, 2.343, 2.569, 2.728, 2.850, 2.949, 3.031, 3.102, 3.164,
+	3.219, 3.268, 3.313, 3.354, 3.391, 3.426, 3.458, 3.489, 3.517, 3.544,
+}
+
+// FriedmanResult is the outcome of the Friedman test with the Nemenyi
+// post-hoc critical difference.
+type FriedmanResult struct {
+	Statistic float64
+	PValue    float64
+	// MeanRanks holds the average rank of every algorithm over the runs,
+	// 1 being the best
+	MeanRanks []float64
+	// CriticalDifference is the Nemenyi critical difference at alpha 0.05,
+	// NaN for more than 20 algorithms. Two algorithms differ significantly
+	// when their mean ranks differ by more.
+	CriticalDifference float64
+}
+
+// Friedman performs the Friedman test of samples, where samples[a][r] is the
+// value of algorithm a in run r. Runs are the blocks, so every algorithm needs
+// the same number of runs.
+func Friedman(samples [][]float64, maximize bool) FriedmanResult {
+	k := len(samples)
+	if k < 2 || len(samples[0]) == 0 {
+		return FriedmanResult{Statistic: math.NaN(), PValue: math.NaN(), CriticalDifference: math.NaN()}
+	}
+
+	n := len(samples[0])
+	sums := make([]float64, k)
+	ties := 0.0
+
+	block := make([]float64, k)
+	for r := range n {
+		for a := range k {
+			block[a] = samples[a][r]
+			if maximize {
+				block[a] = -block[a]
+			}
+		}
+
+		ranks, t := rank(block)
+		for a, v := range ranks {
+			sums[a] += v
+		}
+		ties += t
+	}
+
+	kf, nf := float64(k), float64(n)
+
+	res := FriedmanResult{
+		MeanRanks:          make([]float64, k),
+		CriticalDifference: NemenyiCriticalDifference(k, n),
+	}
+
+	squares := 0.0
+	for a, sum := range sums {
+		res.MeanRanks[a] = sum / nf
+		squares += sum * sum
+	}
+
+	statistic := 12/(nf*kf*(kf+1))*squares - 3*nf*(kf+1)
+	if correction := 1 - ties/(nf*kf*(kf*kf-1)); correction > 0 {
+		statistic /= correction
+	}
+
+	res.Statistic = statistic
+	res.PValue = chiSquareSurvival(statistic, k-1)
+
+	return res
+}
+
+// NemenyiCriticalDifference returns the critical difference of mean ranks for
+// k algorithms over n runs at alpha 0.05.
+func NemenyiCriticalDifference(k, n int) float64 {
+	if k < 2 || k-2 >= len(nemenyiQ05) || n == 0 {
+		return math.NaN()
+	}
+
+	return nemenyiQ05[k-2] * math.Sqrt(float64(k*(k+1))/(6*float64(n)))
+}
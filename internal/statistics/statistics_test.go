@@ -0,0 +1,91 @@
+package statistics
+
+import (
+	"math"
+	"testing"
+)
+
+func TestRankSum(t *testing.T) {
+	// wilcox.test(x, y) in R: W = 35, p-value = 0.2544
+	x := []float64{0.80, 0.83, 1.89, 1.04, 1.45, 1.38, 1.91, 1.64, 0.73, 1.46}
+	y := []float64{1.15, 0.88, 0.90, 0.74, 1.21}
+
+	res := RankSum(x, y)
+	if res.Statistic != 35 {
+		t.Errorf("expected U 35, got %f", res.Statistic)
+	}
+	if !res.Exact || math.Abs(res.PValue-0.2544) > 1e-4 {
+		t.Errorf("expected exact p-value 0.2544, got %f (exact %v)", res.PValue, res.Exact)
+	}
+
+	// with ties the normal approximation is used
+	res = RankSum([]float64{1, 2, 2, 3}, []float64{2, 3, 4, 5})
+	if res.Exact {
+		t.Errorf("expected the normal approximation with ties")
+	}
+}
+
+func TestSignedRank(t *testing.T) {
+	// wilcox.test(x, y, paired = TRUE) in R: V = 40, p-value = 0.03906
+	x := []float64{1.83, 0.50, 1.62, 2.48, 1.68, 1.88, 1.55, 3.06, 1.30}
+	y := []float64{0.878, 0.647, 0.598, 2.05, 1.06, 1.29, 1.06, 3.14, 1.29}
+
+	res := SignedRank(x, y)
+	if res.Statistic != 40 {
+		t.Errorf("expected V 40, got %f", res.Statistic)
+	}
+	if !res.Exact || math.Abs(res.PValue-0.03906) > 1e-5 {
+		t.Errorf("expected exact p-value 0.03906, got %f (exact %v)", res.PValue, res.Exact)
+	}
+}
+
+func TestFriedman(t *testing.T) {
+	// the same order in every run gives ranks 1, 2, 3 and chi-square 8
+	samples := [][]float64{
+		{1, 2, 3, 4},
+		{5, 6, 7, 8},
+		{9, 10, 11, 12},
+	}
+
+	res := Friedman(samples, false)
+	if math.Abs(res.Statistic-8) > 1e-12 {
+		t.Errorf("expected statistic 8, got %f", res.Statistic)
+	}
+	if math.Abs(res.PValue-math.Exp(-4)) > 1e-9 {
+		t.Errorf("expected p-value %f, got %f", math.Exp(-4), res.PValue)
+	}
+	if res.MeanRanks[0] != 1 || res.MeanRanks[2] != 3 {
+		t.Errorf("expected mean ranks 1, 2, 3, got %v", res.MeanRanks)
+	}
+
+	res = Friedman(samples, true)
+	if res.MeanRanks[0] != 3 || res.MeanRanks[2] != 1 {
+		t.Errorf("expected mean ranks 3, 2, 1 when maximizing, got %v", res.MeanRanks)
+	}
+
+	if cd := NemenyiCriticalDifference(3, 4); math.Abs(cd-2.343*math.Sqrt(0.5)) > 1e-12 {
+		t.Errorf("expected critical difference %f, got %f", 2.343*math.Sqrt(0.5), cd)
+	}
+}
+
+func TestCompare(t *testing.T) {
+	better := []float64{1, 2, 3, 4, 5, 6, 7, 8}
+	worse := []float64{11, 12, 13, 14, 15, 16, 17, 18}
+
+	res := Compare("Cost", false, []string{"A", "B"}, [][]float64{better, worse})
+	pair := res.Pairs[0]
+	if pair.A12 != 1 || pair.Magnitude != "large" {
+		t.Errorf("expected A12 1 (large), got %f (%s)", pair.A12, pair.Magnitude)
+	}
+	if pair.Outcome != 1 {
+		t.Errorf("expected A to be significantly better, got outcome %d", pair.Outcome)
+	}
+	if res.Summaries[0].Best != 1 || res.Summaries[0].Worst != 8 {
+		t.Errorf("expected best 1 and worst 8, got %f and %f", res.Summaries[0].Best, res.Summaries[0].Worst)
+	}
+
+	res = Compare("HV", true, []string{"A", "B"}, [][]float64{better, worse})
+	if res.Pairs[0].Outcome != -1 {
+		t.Errorf("expected A to be significantly worse when maximizing, got outcome %d", res.Pairs[0].Outcome)
+	}
+}
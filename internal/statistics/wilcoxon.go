@@ -0,0 +1,277 @@
+package statistics
+
+import (
+	"math"
+	"slices"
+)
+
+// exactLimit is the sample size below which the Wilcoxon tests use the exact
+// distribution when there are no ties, as R's wilcox.test does.
+const exactLimit = 50
+
+// Test is the outcome of a two-sample test.
+type Test struct {
+	// Statistic is U for the rank-sum test and V, the rank sum of the
+	// positive differences, for the signed-rank test
+	Statistic float64
+	// Z is the normal approximation of Statistic, with continuity correction
+	Z      float64
+	PValue float64
+	// Exact tells whether PValue comes from the exact distribution
+	Exact bool
+	// EffectSize is r = |Z| / sqrt(N)
+	EffectSize float64
+}
+
+// RankSum performs the two-sided Wilcoxon rank-sum (Mann-Whitney U) test of
+// the independent samples a and b.
+func RankSum(a, b []float64) Test {
+	n1, n2 := len(a), len(b)
+	if n1 == 0 || n2 == 0 {
+		return Test{PValue: math.NaN(), Z: math.NaN(), EffectSize: math.NaN()}
+	}
+
+	pooled := append(slices.Clone(a), b...)
+	ranks, ties := rank(pooled)
+
+	w := 0.0
+	for i := range a {
+		w += ranks[i]
+	}
+	u := w - float64(n1*(n1+1))/2
+
+	n := float64(n1 + n2)
+	mean := float64(n1*n2) / 2
+	variance := float64(n1*n2) / 12 * ((n + 1) - ties/(n*(n-1)))
+	z := continuityZ(u, mean, variance)
+
+	res := Test{
+		Statistic:  u,
+		Z:          z,
+		PValue:     normalTwoSided(z),
+		EffectSize: math.Abs(z) / math.Sqrt(n),
+	}
+
+	if ties == 0 && n1 < exactLimit && n2 < exactLimit {
+		res.PValue = twoSided(rankSumDistribution(n1, n2), int(math.Round(u)))
+		res.Exact = true
+	}
+
+	return res
+}
+
+// SignedRank performs the two-sided Wilcoxon signed-rank test of the paired
+// samples a and b. Pairs with equal values are dropped.
+func SignedRank(a, b []float64) Test {
+	differences := make([]float64, 0, len(a))
+	for i := range min(len(a), len(b)) {
+		if d := a[i] - b[i]; d != 0 {
+			differences = append(differences, d)
+		}
+	}
+
+	n := len(differences)
+	if n == 0 {
+		return Test{PValue: 1}
+	}
+
+	absolute := make([]float64, n)
+	for i, d := range differences {
+		absolute[i] = math.Abs(d)
+	}
+	ranks, ties := rank(absolute)
+
+	v := 0.0
+	for i, d := range differences {
+		if d > 0 {
+			v += ranks[i]
+		}
+	}
+
+	nf := float64(n)
+	mean := nf * (nf + 1) / 4
+	variance := nf*(nf+1)*(2*nf+1)/24 - ties/48
+	z := continuityZ(v, mean, variance)
+
+	res := Test{
+		Statistic:  v,
+		Z:          z,
+		PValue:     normalTwoSided(z),
+		EffectSize: math.Abs(z) / math.Sqrt(nf),
+	}
+
+	if ties == 0 && n < exactLimit && n == min(len(a), len(b)) {
+		res.PValue = twoSided(signedRankDistribution(n), int(math.Round(v)))
+		res.Exact = true
+	}
+
+	return res
+}
+
+// VarghaDelaney returns the A12 effect size, the probability that a value of a
+// is better than a value of b, ties counting half.
+func VarghaDelaney(a, b []float64, maximize bool) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return math.NaN()
+	}
+
+	wins := 0.0
+	for _, x := range a {
+		for _, y := range b {
+			switch {
+			case x == y:
+				wins += 0.5
+			case (x < y) != maximize:
+				wins++
+			}
+		}
+	}
+
+	return wins / float64(len(a)*len(b))
+}
+
+// Magnitude classifies an A12 effect size with the thresholds of Vargha and
+// Delaney.
+func Magnitude(a12 float64) string {
+	switch d := math.Abs(a12 - 0.5); {
+	case math.IsNaN(d):
+		return ""
+	case d < 0.06:
+		return "negligible"
+	case d < 0.14:
+		return "small"
+	case d < 0.21:
+		return "medium"
+	default:
+		return "large"
+	}
+}
+
+// rank returns the ascending ranks of values, ties getting their average
+// rank, and the tie correction sum of t^3 - t over the groups of ties.
+func rank(values []float64) ([]float64, float64) {
+	order := make([]int, len(values))
+	for i := range order {
+		order[i] = i
+	}
+	slices.SortStableFunc(order, func(i, j int) int {
+		switch {
+		case values[i] < values[j]:
+			return -1
+		case values[i] > values[j]:
+			return 1
+		default:
+			return 0
+		}
+	})
+
+	ranks := make([]float64, len(values))
+	ties := 0.0
+	for i := 0; i < len(order); {
+		j := i
+		for j+1 < len(order) && values[order[j+1]] == values[order[i]] {
+			j++
+		}
+
+		average := float64(i+j)/2 + 1
+		for k := i; k <= j; k++ {
+			ranks[order[k]] = average
+		}
+
+		if t := float64(j - i + 1); t > 1 {
+			ties += t*t*t - t
+		}
+		i = j + 1
+	}
+
+	return ranks, ties
+}
+
+func continuityZ(statistic, mean, variance float64) float64 {
+	if variance <= 0 {
+		return 0
+	}
+
+	d := statistic - mean
+	switch {
+	case d > 0:
+		d = max(0, d-0.5)
+	case d < 0:
+		d = min(0, d+0.5)
+	}
+
+	return d / math.Sqrt(variance)
+}
+
+// twoSided returns the two-sided p-value of statistic under the probability
+// distribution p of an integer statistic.
+func twoSided(p []float64, statistic int) float64 {
+	lower, upper := 0.0, 0.0
+	for s, prob := range p {
+		if s <= statistic {
+			lower += prob
+		}
+		if s >= statistic {
+			upper += prob
+		}
+	}
+
+	return min(1, 2*min(lower, upper))
+}
+
+// rankSumDistribution returns the distribution of U for sample sizes n1 and
+// n2 without ties, counting the subsets of n1 ranks by their sum.
+func rankSumDistribution(n1, n2 int) []float64 {
+	maxU := n1 * n2
+	// counts[k][u] is the number of ways to pick k of the ranks seen so far
+	// with U = u
+	counts := make([][]float64, n1+1)
+	for k := range counts {
+		counts[k] = make([]float64, maxU+1)
+	}
+	counts[0][0] = 1
+
+	for r := 0; r < n1+n2; r++ {
+		for k := min(n1, r+1); k >= 1; k-- {
+			// picking rank r+1 as the k-th smallest adds r-(k-1) to U
+			shift := r - (k - 1)
+			if shift > n2 {
+				continue
+			}
+			for u := maxU; u >= shift; u-- {
+				counts[k][u] += counts[k-1][u-shift]
+			}
+		}
+	}
+
+	total := 0.0
+	for _, c := range counts[n1] {
+		total += c
+	}
+
+	p := make([]float64, maxU+1)
+	for u, c := range counts[n1] {
+		p[u] = c / total
+	}
+
+	return p
+}
+
+// signedRankDistribution returns the distribution of V for n pairs without
+// ties or zero differences.
+func signedRankDistribution(n int) []float64 {
+	maxV := n * (n + 1) / 2
+	p := make([]float64, maxV+1)
+	p[0] = 1
+
+	for r := 1; r <= n; r++ {
+		for v := maxV; v >= 0; v-- {
+			p[v] /= 2
+			if v >= r {
+				p[v] += p[v-r] / 2
+			}
+		}
+	}
+
+	return p
+}
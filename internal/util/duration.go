@@ -0,0 +1,81 @@
+package util
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// dateLayouts are the layouts ParsePhaseDuration accepts for a date typed as
+// text. A date cell of a spreadsheet read raw is a serial number of days.
+var dateLayouts = []string{
+	"2006-01-02",
+	"2006/01/02",
+	"1/2/2006",
+	"1/2/06",
+	"02-Jan-2006",
+	"2-Jan-06",
+}
+
+// unixEpochSerial is the spreadsheet serial number of 1970-01-01, serial
+// numbers count the days since 1899-12-30.
+const unixEpochSerial = 25569
+
+// ParsePhaseDuration returns the duration of a phase from cells: a single
+// duration, or the start and end dates of the phase in which case the
+// duration is the number of days between them. The duration must be
+// positive.
+func ParsePhaseDuration(cells []string) (float64, error) {
+	var values []string
+	for _, cell := range cells {
+		if cell = strings.TrimSpace(cell); cell != "" {
+			values = append(values, cell)
+		}
+	}
+
+	var duration float64
+	switch len(values) {
+	case 1:
+		d, err := strconv.ParseFloat(values[0], 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid duration %q: %w", values[0], err)
+		}
+		duration = d
+	case 2:
+		start, err := parseDay(values[0])
+		if err != nil {
+			return 0, err
+		}
+		end, err := parseDay(values[1])
+		if err != nil {
+			return 0, err
+		}
+		duration = end - start
+	default:
+		return 0, errors.New("expected a duration, or a start and an end date")
+	}
+
+	if duration <= 0 {
+		return 0, fmt.Errorf("the duration must be positive, got %g", duration)
+	}
+
+	return duration, nil
+}
+
+// parseDay returns s as a number of days since 1970-01-01, s is either a
+// spreadsheet serial number or a date in one of dateLayouts.
+func parseDay(s string) (float64, error) {
+	if serial, err := strconv.ParseFloat(s, 64); err == nil {
+		return serial - unixEpochSerial, nil
+	}
+
+	for _, layout := range dateLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return float64(t.Unix()) / (24 * 60 * 60), nil
+		}
+	}
+
+	return 0, fmt.Errorf("invalid date %q", s)
+}
@@ -0,0 +1,40 @@
+package util
+
+import "testing"
+
+func TestParsePhaseDuration(t *testing.T) {
+	testTable := []struct {
+		name     string
+		cells    []string
+		expected float64
+		wantErr  bool
+	}{
+		{name: "duration", cells: []string{" 14 "}, expected: 14},
+		{name: "dates", cells: []string{"2025-01-01", "2025-03-01"}, expected: 59},
+		{name: "serial numbers", cells: []string{"45658", "45688"}, expected: 30},
+		{name: "mixed", cells: []string{"1/1/2025", "2025-01-15"}, expected: 14},
+		{name: "serial start and text end", cells: []string{"45658", "2025-01-31"}, expected: 30},
+		{name: "text start and serial end", cells: []string{"2025-01-01", "45688"}, expected: 30},
+		{name: "empty", cells: []string{"", " "}, wantErr: true},
+		{name: "end before start", cells: []string{"2025-03-01", "2025-01-01"}, wantErr: true},
+		{name: "not a number", cells: []string{"two weeks"}, wantErr: true},
+	}
+
+	for _, test := range testTable {
+		t.Run(test.name, func(t *testing.T) {
+			duration, err := ParsePhaseDuration(test.cells)
+			if test.wantErr {
+				if err == nil {
+					t.Errorf("expected an error, got %f", duration)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if duration != test.expected {
+				t.Errorf("expected %f, got %f", test.expected, duration)
+			}
+		})
+	}
+}
@@ -1,14 +1,46 @@
 package util
 
-import "math/rand"
+import "math/rand/v2"
 
-func RandN(dim int) []int {
+// pcgStream is the fixed PCG increment used for seeded streams.
+const pcgStream = 0x9e3779b97f4a7c15
+
+// NewSeed returns a fresh seed for runs that did not ask for a specific one.
+func NewSeed() int64 {
+	return rand.Int64()
+}
+
+// NewRand returns a random stream seeded with seed. Two streams created with
+// the same seed produce the same sequence.
+func NewRand(seed int64) *rand.Rand {
+	r, _ := NewRandSource(seed)
+	return r
+}
+
+// NewRandSource is NewRand that also returns the source of the stream. Its
+// state can be saved with MarshalBinary and restored with UnmarshalBinary.
+func NewRandSource(seed int64) (*rand.Rand, *rand.PCG) {
+	src := rand.NewPCG(uint64(seed), pcgStream)
+	return rand.New(src), src
+}
+
+// SplitRand derives n independent streams from r, one per worker, so that
+// goroutines never share a stream and results do not depend on scheduling.
+func SplitRand(r *rand.Rand, n int) []*rand.Rand {
+	streams := make([]*rand.Rand, n)
+	for i := range streams {
+		streams[i] = rand.New(rand.NewPCG(r.Uint64(), r.Uint64()))
+	}
+	return streams
+}
+
+func RandN(r *rand.Rand, dim int) []int {
 	res := make([]int, dim)
 	for i := 0; i < dim; i++ {
 		res[i] = i
 	}
 
-	rand.Shuffle(dim, func(i, j int) {
+	r.Shuffle(dim, func(i, j int) {
 		res[i], res[j] = res[j], res[i]
 	})
 
@@ -19,7 +51,7 @@ func RandN(dim int) []int {
 // It takes a slice of probabilities as input and returns an index selected
 // based on those probabilities. The higher the probability, the more likely
 // the index is to be selected.
-func RouletteWheelSelection(p []float64) int {
+func RouletteWheelSelection(r *rand.Rand, p []float64) int {
 	if len(p) == 0 {
 		return -1
 	}
@@ -32,17 +64,17 @@ func RouletteWheelSelection(p []float64) int {
 
 	// If sum is 0, return a random index
 	if sum == 0 {
-		return rand.Intn(len(p))
+		return r.IntN(len(p))
 	}
 
 	// Generate a random value between 0 and sum
-	r := rand.Float64() * sum
+	pick := r.Float64() * sum
 
 	// Find the index corresponding to the random value
 	currentSum := 0.0
 	for i, prob := range p {
 		currentSum += prob
-		if r <= currentSum {
+		if pick <= currentSum {
 			return i
 		}
 	}
@@ -51,7 +83,7 @@ func RouletteWheelSelection(p []float64) int {
 	return len(p) - 1
 }
 
-func RandomSample(max int, n int) []int {
+func RandomSample(r *rand.Rand, max int, n int) []int {
 	// Ensure we don't try to sample more elements than available
 	if n > max {
 		n = max
@@ -64,7 +96,7 @@ func RandomSample(max int, n int) []int {
 	}
 
 	// Shuffle the indices
-	rand.Shuffle(len(indices), func(i, j int) {
+	r.Shuffle(len(indices), func(i, j int) {
 		indices[i], indices[j] = indices[j], indices[i]
 	})
 
@@ -1,6 +1,7 @@
 package util
 
 import (
+	"slices"
 	"testing"
 )
 
@@ -37,9 +38,11 @@ func TestRouletteWheelSelection(t *testing.T) {
 		},
 	}
 
+	r := NewRand(1)
+
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := RouletteWheelSelection(tt.probs)
+			result := RouletteWheelSelection(r, tt.probs)
 			
 			// Check if result is in the expected range
 			valid := false
@@ -68,9 +71,10 @@ func TestRouletteWheelSelectionDistribution(t *testing.T) {
 	probs := []float64{0.1, 0.2, 0.7}
 	iterations := 10000
 	counts := make([]int, len(probs))
+	r := NewRand(1)
 	
 	for i := 0; i < iterations; i++ {
-		idx := RouletteWheelSelection(probs)
+		idx := RouletteWheelSelection(r, probs)
 		if idx >= 0 && idx < len(counts) {
 			counts[idx]++
 		}
@@ -89,4 +93,54 @@ func TestRouletteWheelSelectionDistribution(t *testing.T) {
 			// This is just a warning, not a failure, as statistical tests can sometimes fail randomly
 		}
 	}
-}
\ No newline at end of file
+}
+
+func TestNewRandIsReproducible(t *testing.T) {
+	a := NewRand(42)
+	b := NewRand(42)
+
+	for i := 0; i < 100; i++ {
+		if x, y := a.Float64(), b.Float64(); x != y {
+			t.Fatalf("draw %d differs: %v != %v", i, x, y)
+		}
+	}
+
+	if slices.Equal(RandN(NewRand(1), 20), RandN(NewRand(2), 20)) {
+		t.Errorf("different seeds produced the same permutation")
+	}
+}
+
+func TestSplitRandIsReproducible(t *testing.T) {
+	a := SplitRand(NewRand(7), 4)
+	b := SplitRand(NewRand(7), 4)
+
+	for i := range a {
+		if x, y := a[i].Uint64(), b[i].Uint64(); x != y {
+			t.Errorf("stream %d differs: %v != %v", i, x, y)
+		}
+	}
+
+	if a[0].Uint64() == a[1].Uint64() {
+		t.Errorf("streams 0 and 1 are not independent")
+	}
+}
+
+func TestRandSourceStateRoundTrip(t *testing.T) {
+	r, src := NewRandSource(3)
+	r.Float64()
+
+	state, err := src.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := r.Float64()
+
+	restored, restoredSrc := NewRandSource(0)
+	if err := restoredSrc.UnmarshalBinary(state); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := restored.Float64(); got != want {
+		t.Errorf("restored stream draws %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,30 @@
+package util
+
+import (
+	"math"
+	"testing"
+)
+
+func TestDescriptiveStats(t *testing.T) {
+	values := []float64{4, 1, 3, 2}
+
+	if m := Mean(values); m != 2.5 {
+		t.Errorf("expected mean 2.5, got %f", m)
+	}
+
+	if m := Median(values); m != 2.5 {
+		t.Errorf("expected median 2.5, got %f", m)
+	}
+
+	if m := Median([]float64{3, 1, 2}); m != 2 {
+		t.Errorf("expected median 2, got %f", m)
+	}
+
+	if s := Std(values); math.Abs(s-math.Sqrt(5.0/3.0)) > 1e-12 {
+		t.Errorf("expected std %f, got %f", math.Sqrt(5.0/3.0), s)
+	}
+
+	if values[0] != 4 {
+		t.Errorf("expected Median not to sort its input, got %v", values)
+	}
+}
@@ -3,9 +3,11 @@ package main
 import (
 	"errors"
 	"golang-moaha-construction/internal/data"
+	"golang-moaha-construction/internal/objectives"
 	"golang-moaha-construction/internal/objectives/conslay_continuous"
 	"golang-moaha-construction/internal/objectives/conslay_grid"
 	"golang-moaha-construction/internal/objectives/conslay_predetermined"
+	"golang-moaha-construction/internal/schedule"
 )
 
 type ProblemInput struct {
@@ -18,6 +20,18 @@ type ProblemInput struct {
 	NumberOfLocations  *int                            `json:"numberOfLocations"`
 	NumberOfFacilities *int                            `json:"numberOfFacilities"`
 	FixedFacilities    *[]conslay_predetermined.LocFac `json:"fixedFacilities"`
+	// PenaltySchedule adapts the penalty coefficients of the constraints
+	// during a run, static when missing
+	PenaltySchedule *objectives.PenaltyScheduleConfig `json:"penaltySchedule,omitempty"`
+	// RepairLayout moves overlapping facilities apart and inside the site
+	// before every evaluation of a continuous or grid layout
+	RepairLayout bool `json:"repairLayout,omitempty"`
+	// FreeRotation rotates the facilities of a continuous layout by any angle
+	// instead of 0 or 90 degrees
+	FreeRotation bool `json:"freeRotation,omitempty"`
+	// DynamicLayout gives the non-fixed facilities of a continuous or grid
+	// layout a position in every phase they are active in
+	DynamicLayout bool `json:"dynamicLayout,omitempty"`
 }
 
 func (a *App) CreateProblem(
@@ -25,12 +39,16 @@ func (a *App) CreateProblem(
 ) error {
 
 	a.problemName = problemInput.ProblemName
+	a.problemInput = problemInput
 
 	switch problemInput.ProblemName {
 	case conslay_continuous.ContinuousConsLayoutName:
 		consLayoutConfigs := conslay_continuous.ConsLayConfigs{
 			ConsLayoutLength: *problemInput.LayoutLength,
 			ConsLayoutWidth:  *problemInput.LayoutWidth,
+			RepairLayout:     problemInput.RepairLayout,
+			FreeRotation:     problemInput.FreeRotation,
+			DynamicLayout:    problemInput.DynamicLayout,
 		}
 
 		// LOAD LOCATIONS
@@ -44,13 +62,14 @@ func (a *App) CreateProblem(
 		consLayoutConfigs.FixedLocations = fixedLocations
 
 		// LOAD PHASES
-		phases, err := conslay_continuous.ReadPhasesFromFile(*problemInput.PhasesFile)
+		phases, phaseDurations, err := readPhases(*problemInput.PhasesFile, conslay_continuous.ReadPhasesFromFile)
 
 		if err != nil {
 			return err
 		}
 
 		consLayoutConfigs.Phases = phases
+		consLayoutConfigs.PhaseDurations = phaseDurations
 
 		consLayObj, err := conslay_continuous.CreateConsLayFromConfig(consLayoutConfigs)
 		if err != nil {
@@ -58,12 +77,13 @@ func (a *App) CreateProblem(
 		}
 
 		a.problem = consLayObj
-		return nil
 	case conslay_grid.GridConsLayoutName:
 		consLayoutConfigs := conslay_grid.ConsLayConfigs{
 			ConsLayoutLength: *problemInput.LayoutLength,
 			ConsLayoutWidth:  *problemInput.LayoutWidth,
 			GridSize:         *problemInput.GridSize,
+			RepairLayout:     problemInput.RepairLayout,
+			DynamicLayout:    problemInput.DynamicLayout,
 		}
 
 		// LOAD LOCATIONS
@@ -77,13 +97,14 @@ func (a *App) CreateProblem(
 		consLayoutConfigs.FixedLocations = fixedLocations
 
 		// LOAD PHASES
-		phases, err := conslay_grid.ReadPhasesFromFile(*problemInput.PhasesFile)
+		phases, phaseDurations, err := readPhases(*problemInput.PhasesFile, conslay_grid.ReadPhasesFromFile)
 
 		if err != nil {
 			return err
 		}
 
 		consLayoutConfigs.Phases = phases
+		consLayoutConfigs.PhaseDurations = phaseDurations
 
 		consLayObj, err := conslay_grid.CreateConsLayFromConfig(consLayoutConfigs)
 		if err != nil {
@@ -91,7 +112,6 @@ func (a *App) CreateProblem(
 		}
 
 		a.problem = consLayObj
-		return nil
 	case conslay_predetermined.PredeterminedConsLayoutName:
 		numberOfLocations := *problemInput.NumberOfLocations
 		numberOfFacilities := *problemInput.NumberOfFacilities
@@ -108,10 +128,25 @@ func (a *App) CreateProblem(
 		}
 
 		a.problem = consLayObj
-		return nil
 	default:
 		return errors.New("not implemented")
 	}
+
+	if problemInput.PenaltySchedule != nil {
+		return a.problem.SetPenaltySchedule(*problemInput.PenaltySchedule)
+	}
+
+	return nil
+}
+
+// readPhases reads the phases and their durations from a schedule export, or
+// with readPhasesFromFile from a phases file.
+func readPhases(filePath string, readPhasesFromFile func(string) ([][]string, []float64, error)) ([][]string, []float64, error) {
+	if schedule.IsScheduleFile(filePath) {
+		return schedule.ReadPhasesFromFile(filePath)
+	}
+
+	return readPhasesFromFile(filePath)
 }
 
 func (a *App) ProblemInfo() (any, error) {
@@ -130,6 +165,10 @@ func (a *App) ProblemInfo() (any, error) {
 			FixedLocations    []data.Location          `json:"fixedLocations"`
 			NonFixedLocations []data.Location          `json:"nonFixedLocations"`
 			Phases            [][]string               `json:"phases"`
+			PhaseDurations    []float64                `json:"phaseDurations"`
+			RepairLayout      bool                     `json:"repairLayout"`
+			FreeRotation      bool                     `json:"freeRotation"`
+			DynamicLayout     bool                     `json:"dynamicLayout"`
 		}{
 			LayoutLength:      problemInfo.LayoutLength,
 			LayoutWidth:       problemInfo.LayoutWidth,
@@ -141,6 +180,10 @@ func (a *App) ProblemInfo() (any, error) {
 			NonFixedLocations: problemInfo.NonFixedLocations,
 			Name:              a.problemName,
 			Phases:            problemInfo.Phases,
+			PhaseDurations:    problemInfo.PhaseDurations,
+			RepairLayout:      problemInfo.RepairLayout,
+			FreeRotation:      problemInfo.FreeRotation,
+			DynamicLayout:     problemInfo.DynamicLayout,
 		}, nil
 	case conslay_grid.GridConsLayoutName:
 		problemInfo := a.problem.(*conslay_grid.ConsLay)
@@ -156,6 +199,9 @@ func (a *App) ProblemInfo() (any, error) {
 			FixedLocations    []data.Location          `json:"fixedLocations"`
 			NonFixedLocations []data.Location          `json:"nonFixedLocations"`
 			Phases            [][]string               `json:"phases"`
+			PhaseDurations    []float64                `json:"phaseDurations"`
+			RepairLayout      bool                     `json:"repairLayout"`
+			DynamicLayout     bool                     `json:"dynamicLayout"`
 		}{
 			LayoutLength:      problemInfo.LayoutLength,
 			LayoutWidth:       problemInfo.LayoutWidth,
@@ -167,7 +213,10 @@ func (a *App) ProblemInfo() (any, error) {
 			NonFixedLocations: problemInfo.NonFixedLocations,
 			Name:              a.problemName,
 			Phases:            problemInfo.Phases,
+			PhaseDurations:    problemInfo.PhaseDurations,
 			GridSize:          problemInfo.GridSize,
+			RepairLayout:      problemInfo.RepairLayout,
+			DynamicLayout:     problemInfo.DynamicLayout,
 		}, nil
 	case conslay_predetermined.PredeterminedConsLayoutName:
 		problemInfo := a.problem.(*conslay_predetermined.ConsLay)
@@ -0,0 +1,274 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"github.com/bytedance/sonic"
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// ProjectVersion is the schema version written into every project file.
+// Bump it whenever the layout of Project changes in a non-compatible way.
+const ProjectVersion = 1
+
+// Project is the on-disk representation of a complete setup.
+// File paths are stored relative to the project file when possible.
+type Project struct {
+	Version int `json:"version"`
+	RunInput
+}
+
+var ErrUnsupportedProjectVersion = errors.New("unsupported project version")
+
+// SaveProject asks for a location and saves the current setup as a project file.
+func (a *App) SaveProject() (string, error) {
+	now := time.Now()
+
+	selection, err := runtime.SaveFileDialog(a.ctx, runtime.SaveDialogOptions{
+		Title:           "Save Project",
+		DefaultFilename: fmt.Sprintf("project_%s.json", now.Format("20060102150405")),
+		Filters: []runtime.FileFilter{
+			{
+				DisplayName: "Project File (*.json)",
+				Pattern:     "*.json",
+			},
+		},
+		ShowHiddenFiles: false,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	// If user cancelled the dialog
+	if selection == "" {
+		return "", nil
+	}
+
+	err = a.saveProject(selection)
+	if err != nil {
+		return "", err
+	}
+
+	return selection, nil
+}
+
+// LoadProject asks for a project file and rebuilds the problem, objectives,
+// constraints and algorithm from it.
+func (a *App) LoadProject() (string, error) {
+	selection, err := runtime.OpenFileDialog(a.ctx, runtime.OpenDialogOptions{
+		Title: "Open Project",
+		Filters: []runtime.FileFilter{
+			{
+				DisplayName: "Project File (*.json)",
+				Pattern:     "*.json",
+			},
+		},
+		ShowHiddenFiles: false,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	// If user cancelled the dialog
+	if selection == "" {
+		return "", nil
+	}
+
+	_, err = a.loadProject(selection)
+	if err != nil {
+		return "", err
+	}
+
+	return selection, nil
+}
+
+func (a *App) saveProject(filePath string) error {
+	if a.problem == nil {
+		return errors.New("problem has not been created")
+	}
+
+	dir, err := filepath.Abs(filepath.Dir(filePath))
+	if err != nil {
+		return err
+	}
+
+	input, err := a.currentInput()
+	if err != nil {
+		return err
+	}
+
+	err = rewriteFilePaths(&input, func(path string) string {
+		return relativePath(dir, path)
+	})
+	if err != nil {
+		return err
+	}
+
+	projectBytes, err := sonic.ConfigStd.MarshalIndent(Project{
+		Version:  ProjectVersion,
+		RunInput: input,
+	}, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(filePath, projectBytes, 0644)
+}
+
+// loadProject reads a project file and runs it through the same steps as the UI.
+func (a *App) loadProject(filePath string) (Project, error) {
+	projectBytes, err := os.ReadFile(filePath)
+	if err != nil {
+		return Project{}, err
+	}
+
+	var project Project
+	err = sonic.Unmarshal(projectBytes, &project)
+	if err != nil {
+		return Project{}, fmt.Errorf("invalid project file: %w", err)
+	}
+
+	if project.Version < 1 || project.Version > ProjectVersion {
+		return Project{}, fmt.Errorf("%w: %d", ErrUnsupportedProjectVersion, project.Version)
+	}
+
+	dir, err := filepath.Abs(filepath.Dir(filePath))
+	if err != nil {
+		return Project{}, err
+	}
+
+	err = rewriteFilePaths(&project.RunInput, func(path string) string {
+		if path == "" || filepath.IsAbs(path) {
+			return path
+		}
+		return filepath.Join(dir, path)
+	})
+	if err != nil {
+		return Project{}, err
+	}
+
+	err = a.setup(project.RunInput)
+	if err != nil {
+		return Project{}, err
+	}
+
+	return project, nil
+}
+
+// currentInput returns the inputs the current setup was built from.
+func (a *App) currentInput() (RunInput, error) {
+	// deep copy so that rewriting paths does not touch the stored inputs
+	inputBytes, err := sonic.Marshal(RunInput{
+		Problem:     a.problemInput,
+		Objectives:  a.objectivesInput,
+		Constraints: a.constraintsInput,
+		Algorithm:   a.algorithmInput,
+	})
+	if err != nil {
+		return RunInput{}, err
+	}
+
+	var input RunInput
+	err = sonic.Unmarshal(inputBytes, &input)
+	if err != nil {
+		return RunInput{}, err
+	}
+
+	return input, nil
+}
+
+// rewriteFilePaths applies fn to every file path in input: the problem files, the
+// checkpoint file and every config field whose name ends with "FilePath".
+func rewriteFilePaths(input *RunInput, fn func(string) string) error {
+	if input.Problem.FacilitiesFile != nil {
+		path := fn(*input.Problem.FacilitiesFile)
+		input.Problem.FacilitiesFile = &path
+	}
+
+	if input.Problem.PhasesFile != nil {
+		path := fn(*input.Problem.PhasesFile)
+		input.Problem.PhasesFile = &path
+	}
+
+	if input.Algorithm.Checkpoint != nil {
+		input.Algorithm.Checkpoint.FilePath = fn(input.Algorithm.Checkpoint.FilePath)
+	}
+
+	for i := range input.Objectives {
+		config, err := toGenericConfig(input.Objectives[i].ObjectiveConfig)
+		if err != nil {
+			return fmt.Errorf("%s: %w", input.Objectives[i].ObjectiveName, err)
+		}
+		input.Objectives[i].ObjectiveConfig = rewriteConfigPaths(config, fn)
+	}
+
+	for i := range input.Constraints {
+		config, err := toGenericConfig(input.Constraints[i].ConstraintConfig)
+		if err != nil {
+			return fmt.Errorf("%s: %w", input.Constraints[i].ConstraintName, err)
+		}
+		input.Constraints[i].ConstraintConfig = rewriteConfigPaths(config, fn)
+	}
+
+	return nil
+}
+
+// toGenericConfig turns a config into plain maps and slices.
+func toGenericConfig(config any) (any, error) {
+	configBytes, err := sonic.Marshal(config)
+	if err != nil {
+		return nil, err
+	}
+
+	var generic any
+	err = sonic.Unmarshal(configBytes, &generic)
+	if err != nil {
+		return nil, err
+	}
+
+	return generic, nil
+}
+
+func rewriteConfigPaths(config any, fn func(string) string) any {
+	switch v := config.(type) {
+	case map[string]any:
+		for key, value := range v {
+			if path, ok := value.(string); ok && strings.HasSuffix(strings.ToLower(key), "filepath") {
+				v[key] = fn(path)
+				continue
+			}
+			v[key] = rewriteConfigPaths(value, fn)
+		}
+		return v
+	case []any:
+		for i := range v {
+			v[i] = rewriteConfigPaths(v[i], fn)
+		}
+		return v
+	default:
+		return v
+	}
+}
+
+// relativePath returns path relative to dir, or path unchanged if that is not possible.
+func relativePath(dir, path string) string {
+	if path == "" {
+		return path
+	}
+
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return path
+	}
+
+	rel, err := filepath.Rel(dir, absPath)
+	if err != nil {
+		return path
+	}
+
+	return filepath.ToSlash(rel)
+}
@@ -0,0 +1,267 @@
+package main
+
+import (
+	"errors"
+	"github.com/bytedance/sonic"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// testProject returns an unversioned project on the continuous sample data
+// with the safety objective, optimised by GA. Its paths are relative to the
+// directory writeTestProject copies the data into.
+func testProject() map[string]any {
+	return map[string]any{
+		"problem": map[string]any{
+			"problemName":        "Continuous Construction Layout",
+			"layoutLength":       120,
+			"layoutWidth":        95,
+			"facilitiesFilePath": "data/locations.xlsx",
+			"phasesFilePath":     "data/dynamicBuilding.xlsx",
+		},
+		"objectives": []any{
+			map[string]any{
+				"objectiveName": "Safety Objective",
+				"objectiveConfig": map[string]any{
+					"SafetyProximityMatrixFilePath": "data/safety_data.xlsx",
+					"AlphaSafetyPenalty":            100,
+				},
+			},
+		},
+		"constraints": []any{
+			map[string]any{
+				"constraintName":   "Overlap",
+				"constraintConfig": map[string]any{"AlphaOverLapPenalty": 100, "PowerDifferencePenalty": 1},
+			},
+		},
+		"algorithm": map[string]any{
+			"algorithmName":   "GA",
+			"algorithmConfig": map[string]any{"chromosome": 10, "generation": 5, "crossoverRate": 0.8, "mutationRate": 0.2, "elitismCount": 2},
+		},
+	}
+}
+
+// testMultiObjectiveProject adds the transport cost objective to testProject
+// and optimises it with algorithm.
+func testMultiObjectiveProject(algorithm string, config map[string]any) map[string]any {
+	project := testProject()
+	project["objectives"] = append(project["objectives"].([]any), map[string]any{
+		"objectiveName": "Transport Cost Objective",
+		"objectiveConfig": map[string]any{
+			"InteractionMatrixFilePath": "data/transport_cost_data.xlsx",
+			"AlphaTCPenalty":            100,
+		},
+	})
+	project["algorithm"] = map[string]any{"algorithmName": algorithm, "algorithmConfig": config}
+	return project
+}
+
+// writeTestProject copies the continuous sample data into dir and writes
+// project there.
+func writeTestProject(t *testing.T, dir string, project map[string]any) string {
+	t.Helper()
+
+	err := os.MkdirAll(filepath.Join(dir, "data"), 0755)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, name := range []string{"locations.xlsx", "dynamicBuilding.xlsx", "safety_data.xlsx", "transport_cost_data.xlsx"} {
+		content, err := os.ReadFile(filepath.Join("data/conslay/continuous", name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = os.WriteFile(filepath.Join(dir, "data", name), content, 0644)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	projectBytes, err := sonic.Marshal(project)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	filePath := filepath.Join(dir, "project.json")
+	err = os.WriteFile(filePath, projectBytes, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return filePath
+}
+
+// newTestApp sets up project in a temporary directory.
+func newTestApp(t *testing.T, project map[string]any) *App {
+	t.Helper()
+
+	project["version"] = ProjectVersion
+
+	app := NewApp()
+	_, err := app.loadProject(writeTestProject(t, t.TempDir(), project))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return app
+}
+
+func TestSaveLoadProject(t *testing.T) {
+	root := t.TempDir()
+	project := testProject()
+	project["version"] = ProjectVersion
+	projectPath := writeTestProject(t, filepath.Join(root, "project"), project)
+
+	app := NewApp()
+	_, err := app.loadProject(projectPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// relative paths are resolved against the project file
+	want := filepath.Join(root, "project", "data", "locations.xlsx")
+	if *app.problemInput.FacilitiesFile != want {
+		t.Errorf("expected facilities file %s, got %s", want, *app.problemInput.FacilitiesFile)
+	}
+
+	savedPath := filepath.Join(root, "saved", "project.json")
+	err = os.MkdirAll(filepath.Dir(savedPath), 0755)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = app.saveProject(savedPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	savedBytes, err := os.ReadFile(savedPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var saved Project
+	err = sonic.Unmarshal(savedBytes, &saved)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if saved.Version != ProjectVersion {
+		t.Errorf("expected version %d, got %d", ProjectVersion, saved.Version)
+	}
+
+	// paths are written relative to the saved project file
+	if got := *saved.Problem.FacilitiesFile; got != "../project/data/locations.xlsx" {
+		t.Errorf("expected a relative facilities file, got %s", got)
+	}
+	config := saved.Objectives[0].ObjectiveConfig.(map[string]any)
+	if got := config["SafetyProximityMatrixFilePath"]; got != "../project/data/safety_data.xlsx" {
+		t.Errorf("expected a relative safety matrix file, got %v", got)
+	}
+
+	reloaded := NewApp()
+	_, err = reloaded.loadProject(savedPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	before, err := app.currentInput()
+	if err != nil {
+		t.Fatal(err)
+	}
+	after, err := reloaded.currentInput()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(before, after) {
+		t.Errorf("expected the reloaded project to match the saved one\nbefore: %+v\nafter:  %+v", before, after)
+	}
+}
+
+func TestLoadProject_Unversioned(t *testing.T) {
+	dir := t.TempDir()
+	configPath := writeTestProject(t, dir, testProject())
+
+	_, err := NewApp().loadProject(configPath)
+	if !errors.Is(err, ErrUnsupportedProjectVersion) {
+		t.Errorf("expected %v, got %v", ErrUnsupportedProjectVersion, err)
+	}
+
+	// a plain run configuration resolves paths against the working directory
+	t.Chdir(dir)
+
+	app := NewApp()
+	err = app.loadRunConfig(configPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if app.algorithm == nil {
+		t.Error("expected the algorithm to be created")
+	}
+}
+
+func TestRewriteFilePaths(t *testing.T) {
+	dir := filepath.FromSlash("/projects/site")
+	facilities := filepath.FromSlash("/projects/site/data/locations.xlsx")
+	phases := filepath.FromSlash("/projects/phases.xlsx")
+
+	input := RunInput{
+		Problem: ProblemInput{
+			FacilitiesFile: &facilities,
+			PhasesFile:     &phases,
+		},
+		Objectives: []ObjectiveInput{
+			{
+				ObjectiveName: "Hoisting Objective",
+				ObjectiveConfig: map[string]any{
+					"ZM": 2,
+					"CraneLocations": []any{
+						map[string]any{
+							"Name":                 "TC1",
+							"HoistingTimeFilePath": filepath.FromSlash("/projects/site/hoisting/tc1.xlsx"),
+						},
+					},
+				},
+			},
+		},
+		Constraints: []ConstraintInput{
+			{
+				ConstraintName:   "Distance",
+				ConstraintConfig: map[string]any{"RulesFilePath": "", "Measure": "edge"},
+			},
+		},
+		Algorithm: AlgorithmInput{
+			Checkpoint: &CheckpointInput{FilePath: filepath.FromSlash("/projects/site/run.ckpt"), Every: 5},
+		},
+	}
+
+	err := rewriteFilePaths(&input, func(path string) string {
+		return relativePath(dir, path)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if *input.Problem.FacilitiesFile != "data/locations.xlsx" {
+		t.Errorf("expected data/locations.xlsx, got %s", *input.Problem.FacilitiesFile)
+	}
+	if *input.Problem.PhasesFile != "../phases.xlsx" {
+		t.Errorf("expected ../phases.xlsx, got %s", *input.Problem.PhasesFile)
+	}
+	if input.Algorithm.Checkpoint.FilePath != "run.ckpt" {
+		t.Errorf("expected run.ckpt, got %s", input.Algorithm.Checkpoint.FilePath)
+	}
+
+	crane := input.Objectives[0].ObjectiveConfig.(map[string]any)["CraneLocations"].([]any)[0].(map[string]any)
+	if crane["HoistingTimeFilePath"] != "hoisting/tc1.xlsx" {
+		t.Errorf("expected a nested path to be rewritten, got %v", crane["HoistingTimeFilePath"])
+	}
+	if crane["Name"] != "TC1" {
+		t.Errorf("expected other fields to be kept, got %v", crane["Name"])
+	}
+
+	rules := input.Constraints[0].ConstraintConfig.(map[string]any)
+	if rules["RulesFilePath"] != "" || rules["Measure"] != "edge" {
+		t.Errorf("expected an empty path and other fields to be kept, got %v", rules)
+	}
+}